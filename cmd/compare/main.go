@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+	"grid-trading/internal/engine"
+)
+
+// metric is one row of the comparison. lowerIsBetter flips the winner for
+// costs and risk measures; pct rows print with 4 decimals like gridbot's
+// summary.
+type metric struct {
+	name          string
+	objective     string
+	lowerIsBetter bool
+	pct           bool
+	value         func(engine.BacktestResult) decimal.Decimal
+}
+
+func (m metric) format(v decimal.Decimal) string {
+	if m.pct {
+		return v.StringFixed(4)
+	}
+	return v.String()
+}
+
+var metrics = []metric{
+	{name: "equity_return_pct", objective: "return", pct: true, value: func(r engine.BacktestResult) decimal.Decimal { return r.EquityReturnPct }},
+	{name: "total_return_pct", pct: true, value: func(r engine.BacktestResult) decimal.Decimal { return r.TotalReturnPct }},
+	{name: "profit_quote", objective: "profit", value: func(r engine.BacktestResult) decimal.Decimal { return r.ProfitQuote }},
+	{name: "max_drawdown_pct", objective: "drawdown", lowerIsBetter: true, pct: true, value: func(r engine.BacktestResult) decimal.Decimal { return r.MaxDrawdownPct }},
+	{name: "trades", objective: "trades", value: func(r engine.BacktestResult) decimal.Decimal { return decimal.NewFromInt(int64(r.Trades)) }},
+	{name: "fees_paid_quote", objective: "fees", lowerIsBetter: true, value: func(r engine.BacktestResult) decimal.Decimal { return r.FeesPaidQuote }},
+	{name: "max_locked_capital_quote", objective: "capital", lowerIsBetter: true, value: func(r engine.BacktestResult) decimal.Decimal { return r.MaxLockedCapital }},
+}
+
+func main() {
+	var configA, configB, dataPath, objective string
+	flag.StringVar(&configA, "a", "", "first backtest config yaml path")
+	flag.StringVar(&configB, "b", "", "second backtest config yaml path")
+	flag.StringVar(&dataPath, "data", "", "dataset for both runs (jsonl file or directory); defaults to config a's backtest.data_path")
+	flag.StringVar(&objective, "objective", "return", "metric that picks the winner: "+strings.Join(objectives(), " | "))
+	flag.Parse()
+
+	if configA == "" || configB == "" {
+		fatal("-a and -b are required")
+	}
+	if _, ok := objectiveMetric(objective); !ok {
+		fatal(fmt.Sprintf("unknown objective %q, want one of %s", objective, strings.Join(objectives(), ", ")))
+	}
+	cfgA, err := loadBacktestConfig(configA)
+	if err != nil {
+		fatal(err.Error())
+	}
+	cfgB, err := loadBacktestConfig(configB)
+	if err != nil {
+		fatal(err.Error())
+	}
+	if strings.TrimSpace(dataPath) == "" {
+		dataPath = cfgA.Backtest.DataPath
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	resA, err := runBacktest(ctx, cfgA, dataPath)
+	if err != nil {
+		fatal(fmt.Sprintf("a: %v", err))
+	}
+	resB, err := runBacktest(ctx, cfgB, dataPath)
+	if err != nil {
+		fatal(fmt.Sprintf("b: %v", err))
+	}
+	if err := writeComparison(os.Stdout, resA, resB, objective); err != nil {
+		fatal(err.Error())
+	}
+}
+
+func loadBacktestConfig(path string) (config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Mode != config.ModeBacktest {
+		return config.Config{}, fmt.Errorf("%s: compare requires mode=backtest", path)
+	}
+	if len(cfg.Backtest.Symbols) > 0 {
+		return config.Config{}, fmt.Errorf("%s: compare does not support backtest.symbols; use data_path", path)
+	}
+	return cfg, nil
+}
+
+// runBacktest runs cfg over dataPath the same way gridbot does in backtest
+// mode.
+func runBacktest(ctx context.Context, cfg config.Config, dataPath string) (engine.BacktestResult, error) {
+	runner, _, err := engine.NewBacktest(cfg, engine.ConfigBacktestLeg(cfg, dataPath))
+	if err != nil {
+		return engine.BacktestResult{}, err
+	}
+	return runner.Run(ctx)
+}
+
+func objectives() []string {
+	var names []string
+	for _, m := range metrics {
+		if m.objective != "" {
+			names = append(names, m.objective)
+		}
+	}
+	return names
+}
+
+func objectiveMetric(objective string) (metric, bool) {
+	objective = strings.ToLower(strings.TrimSpace(objective))
+	for _, m := range metrics {
+		if m.objective != "" && m.objective == objective {
+			return m, true
+		}
+	}
+	return metric{}, false
+}
+
+// better reports which run wins on m: "a", "b" or "tie".
+func better(m metric, a, b engine.BacktestResult) string {
+	cmp := m.value(a).Cmp(m.value(b))
+	if m.lowerIsBetter {
+		cmp = -cmp
+	}
+	switch {
+	case cmp > 0:
+		return "a"
+	case cmp < 0:
+		return "b"
+	default:
+		return "tie"
+	}
+}
+
+// winner picks the better run on objective.
+func winner(objective string, a, b engine.BacktestResult) (string, error) {
+	m, ok := objectiveMetric(objective)
+	if !ok {
+		return "", errors.New("unknown objective " + objective)
+	}
+	return better(m, a, b), nil
+}
+
+func writeComparison(w io.Writer, a, b engine.BacktestResult, objective string) error {
+	win, err := winner(objective, a, b)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "metric\ta\tb\tdelta(b-a)\tbetter")
+	for _, m := range metrics {
+		va, vb := m.value(a), m.value(b)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.name, m.format(va), m.format(vb), m.format(vb.Sub(va)), better(m, a, b))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	m, _ := objectiveMetric(objective)
+	_, err = fmt.Fprintf(w, "winner objective=%s metric=%s config=%s\n", strings.ToLower(strings.TrimSpace(objective)), m.name, win)
+	return err
+}
+
+func fatal(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCompareConfig(t *testing.T, dir, name, qty, dataPath string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	body := fmt.Sprintf(`
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  sell_ratio: "1.01"
+  levels: 4
+  shift_levels: 2
+  qty: %q
+
+backtest:
+  data_path: %s
+  initial_base: "1"
+  initial_quote: "1000"
+  fees:
+    maker_rate: "0"
+    taker_rate: "0"
+`, qty, dataPath)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestCompareRunsBothConfigsAndPicksWinnerOnReturn(t *testing.T) {
+	dir := t.TempDir()
+	dataPath := filepath.Join(dir, "ticks.jsonl")
+	var lines []string
+	for i, price := range []string{"100", "98.5", "100", "98.5", "100"} {
+		lines = append(lines, fmt.Sprintf(`{"time":%d,"close":%s}`, 1700000000+i*60, price))
+	}
+	if err := os.WriteFile(dataPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfgA, err := loadBacktestConfig(writeCompareConfig(t, dir, "a.yaml", "0.001", dataPath))
+	if err != nil {
+		t.Fatalf("load a: %v", err)
+	}
+	cfgB, err := loadBacktestConfig(writeCompareConfig(t, dir, "b.yaml", "0.01", dataPath))
+	if err != nil {
+		t.Fatalf("load b: %v", err)
+	}
+	resA, err := runBacktest(context.Background(), cfgA, dataPath)
+	if err != nil {
+		t.Fatalf("run a: %v", err)
+	}
+	resB, err := runBacktest(context.Background(), cfgB, dataPath)
+	if err != nil {
+		t.Fatalf("run b: %v", err)
+	}
+	if resA.Trades == 0 || resA.Trades != resB.Trades {
+		t.Fatalf("trades a=%d b=%d, want the same non-zero round trips", resA.Trades, resB.Trades)
+	}
+
+	// Same round trips at 10x the size: b earns more.
+	if got, err := winner("return", resA, resB); err != nil || got != "b" {
+		t.Fatalf("winner(return) = %q, %v; want b (a=%s b=%s)", got, err, resA.EquityReturnPct, resB.EquityReturnPct)
+	}
+	if got, err := winner("trades", resA, resB); err != nil || got != "tie" {
+		t.Fatalf("winner(trades) = %q, %v; want tie", got, err)
+	}
+	if _, err := winner("sharpe", resA, resB); err == nil {
+		t.Fatalf("winner(sharpe) error = nil, want unknown objective")
+	}
+
+	var out bytes.Buffer
+	if err := writeComparison(&out, resA, resB, "return"); err != nil {
+		t.Fatalf("writeComparison() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "winner objective=return metric=equity_return_pct config=b") {
+		t.Fatalf("output missing winner line:\n%s", out.String())
+	}
+	for _, m := range metrics {
+		if !strings.Contains(out.String(), m.name) {
+			t.Fatalf("output missing %s row:\n%s", m.name, out.String())
+		}
+	}
+}
@@ -12,26 +12,81 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"grid-trading/internal/alert"
-	"grid-trading/internal/backtest"
 	"grid-trading/internal/config"
-	"grid-trading/internal/core"
 	"grid-trading/internal/engine"
 	"grid-trading/internal/exchange/binance"
+	"grid-trading/internal/metrics"
 	"grid-trading/internal/safety"
+	"grid-trading/internal/status"
 	"grid-trading/internal/store"
 	"grid-trading/internal/strategy"
 )
 
+// Process exit codes, see usage().
+const (
+	exitOK          = 0
+	exitConfigError = 1
+	exitStopPrice   = 2
+	exitManual      = 3
+	exitFatalLocal  = 4
+)
+
 func main() {
+	exitCode := exitOK
+	defer func() {
+		if exitCode != exitOK {
+			os.Exit(exitCode)
+		}
+	}()
 	var configPath string
+	var normalizeEquity string
+	var printGrid bool
+	var qtyWarnPct string
+	var viz bool
+	var diffState bool
+	var checkGrid bool
+	var dumpOrders bool
+	var dumpLedger bool
+	var dumpFormat string
+	var exportState string
+	var importState string
+	var dryRun bool
+	var outCSV string
 	flag.StringVar(&configPath, "config", "config/config.yaml", "config yaml path")
+	flag.StringVar(&normalizeEquity, "normalize-equity", "", "backtest only: also report returns against this fixed quote notional, e.g. 10000")
+	flag.BoolVar(&printGrid, "print-grid", false, "print the planned initial grid and balance feasibility report, then exit without placing orders")
+	flag.StringVar(&qtyWarnPct, "qty-warn-pct", "10", "with -print-grid: flag levels whose qty moved by at least this percent during exchange-rule normalization")
+	flag.BoolVar(&viz, "viz", false, "with -print-grid: draw the grid as a price ladder (sells above the anchor, buys below, bars scaled by distance from the anchor) instead of one line per level")
+	flag.BoolVar(&diffState, "diff-state", false, "testnet/live only: print persisted open orders vs exchange open orders (only_in_state/only_on_exchange/mismatch), then exit without changing anything")
+	flag.BoolVar(&checkGrid, "check-grid", false, "testnet/live only: compare exchange open orders with the canonical grid for the persisted anchor/window (missing/wrong_side/duplicate/off_grid), then exit; exit code 1 when they diverge")
+	flag.BoolVar(&dumpOrders, "dump-orders", false, "testnet/live only: print the persisted open-order snapshot (level, side, price, qty, id) sorted by level, then exit without contacting the exchange")
+	flag.BoolVar(&dumpLedger, "dump-ledger", false, "testnet/live only: print the persisted trade ledger (with state.ledger_details: side, price, qty, level, origin, pnl_delta per fill), then exit without contacting the exchange")
+	flag.StringVar(&dumpFormat, "dump-format", store.ExportCSV, "with -dump-orders/-dump-ledger: csv | json")
+	flag.StringVar(&exportState, "export-state", "", "testnet/live only: write grid state, open orders, runtime status and trade ledger to this file (- for stdout) as one versioned json backup, then exit")
+	flag.StringVar(&importState, "import-state", "", "testnet/live only: replace the instance's state with a backup written by -export-state, then exit without contacting the exchange; takes the instance lock")
+	flag.StringVar(&outCSV, "out-csv", "", "backtest only: also write every fill (time, side, price, qty, running equity) and the summary metrics to this csv file")
+	flag.BoolVar(&dryRun, "dry-run", false, "testnet/live only: follow the real market and user stream but log order placements/cancels instead of sending them; state goes to a separate dry_run dir")
+	flag.Usage = usage
 	flag.Parse()
 
 	cfg, err := config.Load(configPath)
 	if err != nil {
 		fatal(err.Error())
 	}
+	if cfg.Grid.ShrunkFromLevels > 0 {
+		fmt.Fprintf(os.Stderr, "warning: grid levels shrunk from %d to %d (shift_levels %d) to fit exchange max_open_orders %d\n",
+			cfg.Grid.ShrunkFromLevels, cfg.Grid.Levels, cfg.Grid.ShiftLevels, cfg.Exchange.MaxOpenOrders)
+	}
+	if viz && !printGrid {
+		fatal("-viz requires -print-grid")
+	}
+	qtyWarn, err := decimal.NewFromString(strings.TrimSpace(qtyWarnPct))
+	if err != nil || qtyWarn.Cmp(decimal.Zero) <= 0 {
+		fatal("qty-warn-pct must be a positive number")
+	}
 	alerts := buildAlertManager(cfg)
 	if alerts != nil {
 		defer func() {
@@ -45,10 +100,33 @@ func main() {
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	inspectOnly := diffState || checkGrid || dumpOrders || dumpLedger || exportState != ""
+	if (inspectOnly || importState != "") && cfg.Mode == config.ModeBacktest {
+		fatal("-diff-state, -check-grid, -dump-orders, -dump-ledger, -export-state and -import-state require testnet or live mode")
+	}
+	if exportState != "" && importState != "" {
+		fatal("-export-state and -import-state are mutually exclusive")
+	}
+	if dryRun && cfg.Mode == config.ModeBacktest {
+		fatal("-dry-run requires testnet or live mode")
+	}
+	if outCSV != "" && cfg.Mode != config.ModeBacktest {
+		fatal("-out-csv requires backtest mode")
+	}
+	stateDir := filepath.Join(cfg.State.Dir, strings.ToLower(string(cfg.Mode)), cfg.Symbol, cfg.InstanceID)
+	if dryRun {
+		// Keep dry-run orders out of the real instance's state.
+		stateDir = filepath.Join(stateDir, "dry_run")
+	}
 	var st *store.Store
 	var instanceLock *store.InstanceLock
-	if cfg.Mode != config.ModeBacktest && cfg.State.Dir != "" {
-		stateDir := filepath.Join(cfg.State.Dir, strings.ToLower(string(cfg.Mode)), cfg.Symbol, cfg.InstanceID)
+	if cfg.Mode != config.ModeBacktest && cfg.State.Dir != "" && inspectOnly {
+		// Read-only: no instance lock, so it can inspect a running bot.
+		st, err = store.New(stateDir)
+		if err != nil {
+			fatal(err.Error())
+		}
+	} else if cfg.Mode != config.ModeBacktest && cfg.State.Dir != "" && !printGrid {
 		st, err = store.New(stateDir)
 		if err != nil {
 			fatal(err.Error())
@@ -70,28 +148,79 @@ func main() {
 			}
 		}()
 	}
+	if dumpOrders {
+		if st == nil {
+			fatal("-dump-orders needs state.dir")
+		}
+		if err := st.ExportOpenOrders(os.Stdout, dumpFormat); err != nil {
+			fatal(err.Error())
+		}
+		return
+	}
+	if dumpLedger {
+		if st == nil {
+			fatal("-dump-ledger needs state.dir")
+		}
+		if err := st.ExportTradeLedger(os.Stdout, dumpFormat); err != nil {
+			fatal(err.Error())
+		}
+		return
+	}
+	if exportState != "" {
+		if st == nil {
+			fatal("-export-state needs state.dir")
+		}
+		if err := exportStateBackup(st, exportState); err != nil {
+			fatal(err.Error())
+		}
+		return
+	}
+	if importState != "" {
+		if st == nil {
+			fatal("-import-state needs state.dir")
+		}
+		if err := importStateBackup(st, importState); err != nil {
+			fatal(err.Error())
+		}
+		fmt.Fprintf(os.Stderr, "imported %s into %s\n", importState, stateDir)
+		return
+	}
 	switch cfg.Mode {
 	case config.ModeBacktest:
-		feed, err := backtest.NewJSONLFeed(cfg.Backtest.DataPath)
+		if len(cfg.Backtest.Symbols) > 0 {
+			if printGrid {
+				fatal("-print-grid does not support backtest.symbols; use data_path")
+			}
+			if outCSV != "" {
+				fatal("-out-csv does not support backtest.symbols; use data_path")
+			}
+			runMultiSymbolBacktest(ctx, cfg)
+			return
+		}
+		leg := engine.ConfigBacktestLeg(cfg, cfg.Backtest.DataPath)
+		runner, strat, err := engine.NewBacktest(cfg, leg)
 		if err != nil {
 			fatal(err.Error())
 		}
-		ex := backtest.NewSimExchange(cfg.Symbol, core.Balance{
-			Base:  cfg.Backtest.InitialBase.Decimal,
-			Quote: cfg.Backtest.InitialQuote.Decimal,
-		}, core.Rules{})
-		if err := ex.SetFees(cfg.Backtest.Fees.MakerRate.Decimal, cfg.Backtest.Fees.TakerRate.Decimal); err != nil {
-			fatal(err.Error())
+		if printGrid {
+			tick, err := runner.Feed.Next()
+			_ = runner.Feed.Close()
+			if err != nil {
+				fatal(err.Error())
+			}
+			report := strat.Feasibility(tick.Price, leg.Balance)
+			report.QtyChangeWarnPct = qtyWarn
+			printFeasibility(report, true, viz)
+			return
 		}
-		rules := core.Rules{
-			MinQty:      cfg.Backtest.Rules.MinQty.Decimal,
-			MinNotional: cfg.Backtest.Rules.MinNotional.Decimal,
-			PriceTick:   cfg.Backtest.Rules.PriceTick.Decimal,
-			QtyStep:     cfg.Backtest.Rules.QtyStep.Decimal,
+		runner.RecordTrades = outCSV != ""
+		if strings.TrimSpace(normalizeEquity) != "" {
+			notional, err := decimal.NewFromString(strings.TrimSpace(normalizeEquity))
+			if err != nil || notional.Cmp(decimal.Zero) <= 0 {
+				fatal("normalize-equity must be a positive number")
+			}
+			runner.NormalizeEquityQuote = notional
 		}
-		strat := strategy.NewSpotDual(cfg.Symbol, cfg.Grid.StopPrice.Decimal, cfg.Grid.Ratio.Decimal, cfg.Grid.Levels, cfg.Grid.ShiftLevels, cfg.Grid.Qty.Decimal, cfg.Grid.MinQtyMultiple, rules, nil, ex)
-		applySpotDualTuning(strat, cfg)
-		runner := engine.BacktestRunner{Exchange: ex, Feed: feed, Strategy: strat}
 		result, err := runner.Run(ctx)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -100,6 +229,17 @@ func main() {
 			}
 			fatal(err.Error())
 		}
+		if outCSV != "" {
+			if err := writeBacktestCSV(outCSV, result); err != nil {
+				fatal(err.Error())
+			}
+		}
+		if result.SkippedTicks > 0 {
+			fmt.Fprintf(os.Stderr, "warning: skipped %d ticks with a zero or negative price\n", result.SkippedTicks)
+		}
+		if result.IlliquidTicks > 0 {
+			fmt.Fprintf(os.Stderr, "note: %d ticks below min_fill_volume filled no orders\n", result.IlliquidTicks)
+		}
 		fmt.Printf(
 			"summary instance=%s trades=%d market_buy_count=%d market_buy_qty=%s total_return_pct=%s equity_return_pct=%s profit_quote=%s max_locked_capital_quote=%s max_drawdown_pct=%s max_drawdown_quote=%s capital_drawdown_pct=%s max_capital_usage_pct=%s start_equity_quote=%s end_equity_quote=%s fees_paid_quote=%s final_base=%s final_quote=%s\n",
 			cfg.InstanceID,
@@ -120,6 +260,16 @@ func main() {
 			result.FinalBalance.Base.String(),
 			result.FinalBalance.Quote.String(),
 		)
+		if runner.NormalizeEquityQuote.Cmp(decimal.Zero) > 0 {
+			fmt.Printf(
+				"normalized instance=%s start_equity_quote=%s end_equity_quote=%s return_pct=%s max_drawdown_pct=%s\n",
+				cfg.InstanceID,
+				result.NormalizedStartEquityQuote.String(),
+				result.NormalizedEndEquityQuote.String(),
+				result.NormalizedReturnPct.StringFixed(4),
+				result.NormalizedMaxDrawdownPct.StringFixed(4),
+			)
+		}
 	case config.ModeTestnet, config.ModeLive:
 		client, err := binance.NewClient(cfg.Exchange, cfg.Symbol, cfg.InstanceID)
 		if err != nil {
@@ -142,16 +292,103 @@ func main() {
 			cfg.CircuitBreaker.ReconnectProbePasses,
 		)
 		breaker.SetAlerter(alerts)
+		var dryExec *safety.DryRunExecutor
 		exec := safety.NewGuardedExecutor(client, breaker)
+		if dryRun {
+			dryExec = safety.NewDryRunExecutor(client)
+			if st != nil {
+				if orders, _, err := st.LoadOpenOrders(); err != nil {
+					fatal(err.Error())
+				} else {
+					dryExec.Seed(orders)
+				}
+			}
+			exec = safety.NewGuardedExecutor(dryExec, breaker)
+			fmt.Fprintln(os.Stderr, "dry-run: orders are logged (event=dry_run_place/dry_run_cancel) and never sent")
+		}
 		strat := strategy.NewSpotDual(cfg.Symbol, cfg.Grid.StopPrice.Decimal, cfg.Grid.Ratio.Decimal, cfg.Grid.Levels, cfg.Grid.ShiftLevels, cfg.Grid.Qty.Decimal, cfg.Grid.MinQtyMultiple, rules, st, exec)
-		applySpotDualTuning(strat, cfg)
+		strat.ApplyGridConfig(cfg.Grid)
+		strat.SetCapitalBudget(cfg.Capital.QuoteBudget.Decimal)
+		strat.SetBalanceFloor(cfg.Capital.BalanceFloorQuote.Decimal, cfg.Capital.BalanceFloorBase.Decimal)
+		strat.SetStopCancelUntracked(cfg.Grid.StopCancelUntracked, client.ClientOrderPrefix())
+		strat.SetOrderAudit(cfg.State.OrderAudit)
+		strat.SetWindowChangeIntent(cfg.State.WindowIntent)
 		strat.SetAlerter(alerts)
+		resuming := false
 		if st != nil {
 			if state, ok, err := st.LoadGridState(); err != nil {
 				fatal(err.Error())
 			} else if ok {
 				strat.LoadState(state)
+				resuming = state.Initialized
+			}
+		}
+		if diffState {
+			if err := printStateDiff(ctx, client, strat, st, cfg.Symbol); err != nil {
+				fatal(err.Error())
+			}
+		}
+		if checkGrid {
+			if !resuming {
+				fatal("-check-grid needs an initialized grid in state.dir")
+			}
+			clean, err := printGridCheck(ctx, client, strat, cfg.Symbol)
+			if err != nil {
+				fatal(err.Error())
+			}
+			if !clean {
+				exitCode = exitConfigError
+			}
+		}
+		if inspectOnly {
+			return
+		}
+		if printGrid || !resuming {
+			report, err := liveFeasibility(ctx, client, strat, cfg.Symbol)
+			if err != nil {
+				if printGrid {
+					fatal(err.Error())
+				}
+				fmt.Fprintf(os.Stderr, "feasibility report failed: %v\n", err)
+			} else {
+				report.QtyChangeWarnPct = qtyWarn
+				printFeasibility(report, printGrid, viz)
+			}
+			if printGrid {
+				return
+			}
+		}
+		var registry *metrics.Registry
+		if cfg.Observability.Metrics.Enabled {
+			registry = metrics.NewRegistry(metrics.Labels{})
+			metricsServer, err := metrics.Serve(cfg.Observability.Metrics.ListenAddr, registry)
+			if err != nil {
+				fatal(fmt.Sprintf("metrics listen %s: %v", cfg.Observability.Metrics.ListenAddr, err))
 			}
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = metricsServer.Close(closeCtx)
+			}()
+			fmt.Fprintf(os.Stderr, "metrics: serving http://%s/metrics\n", metricsServer.Addr())
+		}
+		var statusBoard *status.Board
+		if cfg.Observability.Status.Enabled {
+			statusBoard = status.NewBoard()
+			statusBoard.SetProbeLimits(
+				time.Duration(cfg.Observability.Status.LivenessStaleSec)*time.Second,
+				time.Duration(cfg.Observability.Status.ReadyTickMaxAgeSec)*time.Second,
+			)
+			statusServer, err := status.Serve(cfg.Observability.Status.ListenAddr, st, statusBoard)
+			if err != nil {
+				fatal(fmt.Sprintf("status listen %s: %v", cfg.Observability.Status.ListenAddr, err))
+			}
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = statusServer.Close(closeCtx)
+			}()
+			fmt.Fprintf(os.Stderr, "status: serving http://%s/status (/healthz, /readyz)\n", statusServer.Addr())
 		}
 		runner := engine.LiveRunner{
 			Exchange:   client,
@@ -165,47 +402,222 @@ func main() {
 			Store:      st,
 			Breaker:    breaker,
 			Alerts:     alerts,
+			Metrics:    registry,
+			Pusher:     metrics.NewPusher(cfg.Observability.PushgatewayURL, cfg.Observability.PushgatewayJob, 3*time.Second),
+			Status:     statusBoard,
+
+			TickPriceSource:        string(cfg.Grid.TickPriceSource),
+			TickerMaxDivergencePct: cfg.Grid.TickerMaxDivergencePct.Decimal,
+			ReconcileMin:           time.Duration(cfg.Observability.Runtime.ReconcileMinIntervalSec) * time.Second,
+			ReconcileMax:           time.Duration(cfg.Observability.Runtime.ReconcileMaxIntervalSec) * time.Second,
+			ReconcileQuiet:         time.Duration(cfg.Observability.Runtime.ReconcileQuietSec) * time.Second,
+			RunSummary:             cfg.Observability.Runtime.RunSummary,
+			ReportInUSD:            cfg.Observability.Runtime.ReportInUSD,
+			USDRateSymbol:          cfg.Observability.Runtime.USDRateSymbol,
+			MaxRunTime:             time.Duration(cfg.Observability.Runtime.MaxRunSec) * time.Second,
+			MaxDrawdownPct:         cfg.CircuitBreaker.MaxDrawdownPct.Decimal,
+			RulesRefresh:           time.Duration(cfg.Exchange.RulesRefreshSec) * time.Second,
+			RESTTimeoutRetries:     restTimeoutRetries(cfg),
+			BalanceFloorCheck:      cfg.Capital.BalanceFloorEnabled(),
+			CancelOrdersOnExit:     cfg.State.CancelOrdersOnExit,
+			DrainTimeout:           time.Duration(cfg.State.DrainTimeoutSec) * time.Second,
+
+			DuplicateInstanceCheck: cfg.State.DuplicateInstanceCheck,
+			TwinSurplus:            cfg.State.TwinCheckSurplus,
+			TwinRounds:             cfg.State.TwinCheckRounds,
+			AdoptExistingOrders:    cfg.State.AdoptExistingOrders,
+			DryRun:                 dryExec,
 		}
-		if err := runner.Run(ctx); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return
-			}
-			fatal(err.Error())
+		err = runner.Run(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, err.Error())
 		}
+		exitCode = runExitCode(err, strat.Stopped())
 	default:
 		fatal("unknown mode")
 	}
 }
 
+func writeBacktestCSV(path string, result engine.BacktestResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := result.WriteCSV(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func liveFeasibility(ctx context.Context, client *binance.Client, strat *strategy.SpotDual, symbol string) (strategy.FeasibilityReport, error) {
+	price, err := client.TickerPrice(ctx, symbol)
+	if err != nil {
+		return strategy.FeasibilityReport{}, err
+	}
+	bal, err := client.Balances(ctx)
+	if err != nil {
+		return strategy.FeasibilityReport{}, err
+	}
+	return strat.Feasibility(price, bal), nil
+}
+
+func printStateDiff(ctx context.Context, client *binance.Client, strat *strategy.SpotDual, st *store.Store, symbol string) error {
+	if st == nil {
+		return errors.New("-diff-state requires state.dir")
+	}
+	persisted, _, err := st.LoadOpenOrders()
+	if err != nil {
+		return err
+	}
+	open, err := client.OpenOrders(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	return strat.DiffState(persisted, open).WriteReport(os.Stdout)
+}
+
+func printGridCheck(ctx context.Context, client *binance.Client, strat *strategy.SpotDual, symbol string) (bool, error) {
+	open, err := client.OpenOrders(ctx, symbol)
+	if err != nil {
+		return false, err
+	}
+	check := strat.CheckGrid(open)
+	if err := check.WriteReport(os.Stdout); err != nil {
+		return false, err
+	}
+	return check.Clean(), nil
+}
+
+func printFeasibility(report strategy.FeasibilityReport, withGrid, ladder bool) {
+	switch {
+	case withGrid && ladder:
+		_ = report.WriteLadder(os.Stdout)
+	case withGrid:
+		_ = report.WriteGrid(os.Stdout)
+	}
+	_ = report.WriteSummary(os.Stdout)
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprint(out, `
+Exit codes:
+  0  clean shutdown (signal or normal end)
+  1  config error or other startup/runtime failure
+  2  stop_price/floor_price or circuit_breaker.max_drawdown_pct reached, strategy stopped
+  3  manual intervention required (e.g. possible duplicate instance, reconcile risk)
+  4  fatal local error (state/ledger persistence)
+`)
+}
+
+func exportStateBackup(st *store.Store, path string) error {
+	if path == "-" {
+		return st.Export(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := st.Export(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func importStateBackup(st *store.Store, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return st.Import(f)
+}
+
+// runExitCode maps the live runner's result to the documented exit codes.
+// Run returns nil when the strategy stops itself, so stopped is checked first.
+func runExitCode(err error, stopped bool) int {
+	switch {
+	case errors.Is(err, engine.ErrManualIntervention):
+		return exitManual
+	case errors.Is(err, engine.ErrFatalLocal):
+		return exitFatalLocal
+	case stopped || errors.Is(err, strategy.ErrStopped) || errors.Is(err, engine.ErrMaxDrawdown):
+		return exitStopPrice
+	case err == nil || errors.Is(err, context.Canceled):
+		return exitOK
+	default:
+		return exitConfigError
+	}
+}
+
 func fatal(msg string) {
 	fmt.Fprintln(os.Stderr, msg)
-	os.Exit(1)
+	os.Exit(exitConfigError)
 }
 
 func buildAlertManager(cfg config.Config) *alert.Manager {
-	tg := cfg.Observability.Telegram
-	if !tg.Enabled {
+	var notifiers []alert.Notifier
+	if tg := cfg.Observability.Telegram; tg.Enabled {
+		notifiers = append(notifiers, alert.NewTelegramNotifier(
+			tg.Enabled,
+			tg.BotToken,
+			tg.ChatID,
+			tg.APIBaseURL,
+			time.Duration(tg.TimeoutSec)*time.Second,
+		))
+	}
+	if wh := cfg.Observability.Webhook; wh.Enabled {
+		notifiers = append(notifiers, alert.NewWebhookNotifier(wh.Enabled, wh.URL, time.Duration(wh.TimeoutSec)*time.Second))
+	}
+	if len(notifiers) == 0 {
 		return nil
 	}
-	notifier := alert.NewTelegramNotifier(
-		tg.Enabled,
-		tg.BotToken,
-		tg.ChatID,
-		tg.APIBaseURL,
-		time.Duration(tg.TimeoutSec)*time.Second,
-	)
-	return alert.NewManagerWithOptions(string(cfg.Mode), cfg.Symbol, notifier, alert.ManagerOptions{
+	retries := -1
+	if r := cfg.Observability.Runtime.AlertNotifyRetries; r != nil && *r > 0 {
+		retries = *r
+	}
+	return alert.NewManagerWithOptions(string(cfg.Mode), cfg.Symbol, notifiers, alert.ManagerOptions{
 		DropReportInterval: time.Duration(cfg.Observability.Runtime.AlertDropReportSec) * time.Second,
+		MaxRetries:         retries,
+		RetryBackoff:       time.Duration(cfg.Observability.Runtime.AlertNotifyRetryBackoffMs) * time.Millisecond,
 	})
 }
 
-func applySpotDualTuning(strat *strategy.SpotDual, cfg config.Config) {
-	if strat == nil {
-		return
+func restTimeoutRetries(cfg config.Config) int {
+	if cfg.Exchange.RESTTimeoutRetries == nil {
+		return 0
+	}
+	return *cfg.Exchange.RESTTimeoutRetries
+}
+
+// runMultiSymbolBacktest runs the configured grid over every
+// backtest.symbols entry, each against its own SimExchange seeded with the
+// same initial balances.
+func runMultiSymbolBacktest(ctx context.Context, cfg config.Config) {
+	legs := make([]engine.SymbolBacktest, 0, len(cfg.Backtest.Symbols))
+	for _, sym := range cfg.Backtest.Symbols {
+		runner, _, err := engine.NewBacktest(cfg, engine.SymbolBacktestLeg(cfg, sym))
+		if err != nil {
+			fatal(fmt.Sprintf("%s: %v", sym.Symbol, err))
+		}
+		legs = append(legs, engine.SymbolBacktest{
+			Symbol: sym.Symbol,
+			Runner: runner,
+		})
+	}
+	result, err := engine.RunMultiBacktest(ctx, legs)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Println("backtest canceled")
+			return
+		}
+		fatal(err.Error())
 	}
-	strat.SetSellRatio(cfg.Grid.SellRatio.Decimal)
-	if cfg.Grid.RatioStep != nil {
-		strat.SetRatioStep(cfg.Grid.RatioStep.Decimal)
+	if err := result.WriteSummary(os.Stdout, cfg.InstanceID); err != nil {
+		fatal(err.Error())
 	}
-	strat.SetRatioQtyMultiple(cfg.Grid.RatioQtyMultiple.Decimal)
 }
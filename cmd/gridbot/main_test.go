@@ -1,100 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
-	"github.com/shopspring/decimal"
-
-	"grid-trading/internal/config"
-	"grid-trading/internal/core"
+	"grid-trading/internal/engine"
 	"grid-trading/internal/strategy"
 )
 
-func ratioStepPtr(v string) *config.Decimal {
-	return &config.Decimal{Decimal: decimal.RequireFromString(v)}
-}
-
-func TestApplySpotDualTuningKeepsDefaultRatioStepWhenUnset(t *testing.T) {
-	strat := strategy.NewSpotDual(
-		"BTCUSDT",
-		decimal.Zero,
-		decimal.RequireFromString("1.01"),
-		20,
-		10,
-		decimal.RequireFromString("0.001"),
-		1,
-		core.Rules{},
-		nil,
-		nil,
-	)
-	want := strat.RatioStep
-	wantQtyMultiple := strat.RatioQtyMultiple
-	cfg := config.Config{
-		Grid: config.GridConfig{
-			SellRatio: config.Decimal{Decimal: decimal.RequireFromString("1.01")},
-		},
-	}
-
-	applySpotDualTuning(strat, cfg)
-
-	if !strat.RatioStep.Equal(want) {
-		t.Fatalf("ratio_step changed unexpectedly: got=%s want=%s", strat.RatioStep.String(), want.String())
-	}
-	if !strat.RatioQtyMultiple.Equal(wantQtyMultiple) {
-		t.Fatalf("ratio_qty_multiple changed unexpectedly: got=%s want=%s", strat.RatioQtyMultiple.String(), wantQtyMultiple.String())
-	}
-}
-
-func TestApplySpotDualTuningAllowsZeroRatioStep(t *testing.T) {
-	strat := strategy.NewSpotDual(
-		"BTCUSDT",
-		decimal.Zero,
-		decimal.RequireFromString("1.01"),
-		20,
-		10,
-		decimal.RequireFromString("0.001"),
-		1,
-		core.Rules{},
-		nil,
-		nil,
-	)
-	cfg := config.Config{
-		Grid: config.GridConfig{
-			SellRatio: config.Decimal{Decimal: decimal.RequireFromString("1.01")},
-			RatioStep: ratioStepPtr("0"),
-		},
+func TestRunExitCodeMapsErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		stopped bool
+		want    int
+	}{
+		{"clean", nil, false, exitOK},
+		{"canceled", context.Canceled, false, exitOK},
+		{"stopped", nil, true, exitStopPrice},
+		{"strategy stopped error", strategy.ErrStopped, false, exitStopPrice},
+		{"manual intervention", fmt.Errorf("%w: possible_duplicate_instance", engine.ErrManualIntervention), false, exitManual},
+		{"fatal local", fmt.Errorf("%w: trade ledger record: disk full", engine.ErrFatalLocal), false, exitFatalLocal},
+		{"max drawdown", fmt.Errorf("%w: equity 80 is 20.00%% below peak 100", engine.ErrMaxDrawdown), false, exitStopPrice},
+		{"other", errors.New("circuit open"), false, exitConfigError},
 	}
-
-	applySpotDualTuning(strat, cfg)
-
-	if !strat.RatioStep.Equal(decimal.Zero) {
-		t.Fatalf("ratio_step = %s, want 0", strat.RatioStep.String())
-	}
-}
-
-func TestApplySpotDualTuningSetsRatioQtyMultiple(t *testing.T) {
-	strat := strategy.NewSpotDual(
-		"BTCUSDT",
-		decimal.Zero,
-		decimal.RequireFromString("1.01"),
-		20,
-		10,
-		decimal.RequireFromString("0.001"),
-		1,
-		core.Rules{},
-		nil,
-		nil,
-	)
-	cfg := config.Config{
-		Grid: config.GridConfig{
-			SellRatio:        config.Decimal{Decimal: decimal.RequireFromString("1.01")},
-			RatioQtyMultiple: config.Decimal{Decimal: decimal.RequireFromString("1.2")},
-		},
-	}
-
-	applySpotDualTuning(strat, cfg)
-
-	if !strat.RatioQtyMultiple.Equal(decimal.RequireFromString("1.2")) {
-		t.Fatalf("ratio_qty_multiple = %s, want 1.2", strat.RatioQtyMultiple.String())
+	for _, tc := range cases {
+		if got := runExitCode(tc.err, tc.stopped); got != tc.want {
+			t.Fatalf("%s: runExitCode() = %d, want %d", tc.name, got, tc.want)
+		}
 	}
 }
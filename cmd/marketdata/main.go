@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -11,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +21,9 @@ import (
 const (
 	defaultBaseURL = "https://api.binance.com"
 	defaultOutDir  = "data/binance"
+
+	granularityKlines    = "klines"
+	granularityAggTrades = "aggtrades"
 )
 
 type kline struct {
@@ -31,37 +36,68 @@ type kline struct {
 	CloseTime int64
 }
 
+// aggTrade is one /api/v3/aggTrades row.
+type aggTrade struct {
+	ID    int64  `json:"a"`
+	Price string `json:"p"`
+	Qty   string `json:"q"`
+	Time  int64  `json:"T"`
+}
+
+// tickLine is one output row. Kline rows fill open/high/low/close; aggTrade
+// rows carry the print's price and qty (also as volume, so
+// backtest.min_fill_volume applies per trade) and the aggTrade id to resume
+// from.
 type tickLine struct {
-	Time      string `json:"time"`
-	Timestamp int64  `json:"timestamp"`
-	Symbol    string `json:"symbol"`
-	Interval  string `json:"interval"`
-	Open      string `json:"open"`
-	High      string `json:"high"`
-	Low       string `json:"low"`
-	Close     string `json:"close"`
-	Price     string `json:"price"`
-	Volume    string `json:"volume"`
+	Time       string `json:"time"`
+	Timestamp  int64  `json:"timestamp"`
+	Symbol     string `json:"symbol"`
+	Interval   string `json:"interval"`
+	Open       string `json:"open,omitempty"`
+	High       string `json:"high,omitempty"`
+	Low        string `json:"low,omitempty"`
+	Close      string `json:"close,omitempty"`
+	Price      string `json:"price"`
+	Volume     string `json:"volume"`
+	Qty        string `json:"qty,omitempty"`
+	AggTradeID int64  `json:"agg_trade_id,omitempty"`
 }
 
+// dateWriter writes one <date>.jsonl file per UTC day, or <date>.jsonl.gz
+// with gzip set. Appending to a .gz file adds a gzip member, which readers
+// decompress as one stream.
 type dateWriter struct {
 	root        string
+	appendMode  bool
+	gzip        bool
 	currentDate string
 	currentFile *os.File
+	currentGzip *gzip.Writer
 }
 
-func newDateWriter(root string) (*dateWriter, error) {
+func newDateWriter(root string, appendMode, gz bool) (*dateWriter, error) {
 	if err := os.MkdirAll(root, 0o755); err != nil {
 		return nil, err
 	}
-	return &dateWriter{root: root}, nil
+	return &dateWriter{root: root, appendMode: appendMode, gzip: gz}, nil
+}
+
+func (w *dateWriter) ext() string {
+	if w.gzip {
+		return ".jsonl.gz"
+	}
+	return ".jsonl"
 }
 
 func (w *dateWriter) write(date string, line []byte) error {
 	if err := w.rotate(date); err != nil {
 		return err
 	}
-	if _, err := w.currentFile.Write(append(line, '\n')); err != nil {
+	var out io.Writer = w.currentFile
+	if w.currentGzip != nil {
+		out = w.currentGzip
+	}
+	if _, err := out.Write(append(line, '\n')); err != nil {
 		return err
 	}
 	return nil
@@ -71,25 +107,23 @@ func (w *dateWriter) rotate(date string) error {
 	if date == w.currentDate && w.currentFile != nil {
 		return nil
 	}
-	if w.currentFile != nil {
-		if err := w.currentFile.Sync(); err != nil {
-			_ = w.currentFile.Close()
-			w.currentFile = nil
-			return err
-		}
-		if err := w.currentFile.Close(); err != nil {
-			w.currentFile = nil
-			return err
-		}
-		w.currentFile = nil
+	if err := w.close(); err != nil {
+		return err
+	}
+	path := filepath.Join(w.root, date+w.ext())
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if w.appendMode {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
 	}
-	path := filepath.Join(w.root, date+".jsonl")
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	f, err := os.OpenFile(path, flags, 0o644)
 	if err != nil {
 		return err
 	}
 	w.currentFile = f
 	w.currentDate = date
+	if w.gzip {
+		w.currentGzip = gzip.NewWriter(f)
+	}
 	return nil
 }
 
@@ -97,6 +131,15 @@ func (w *dateWriter) close() error {
 	if w == nil || w.currentFile == nil {
 		return nil
 	}
+	if w.currentGzip != nil {
+		err := w.currentGzip.Close()
+		w.currentGzip = nil
+		if err != nil {
+			_ = w.currentFile.Close()
+			w.currentFile = nil
+			return err
+		}
+	}
 	if err := w.currentFile.Sync(); err != nil {
 		_ = w.currentFile.Close()
 		w.currentFile = nil
@@ -107,31 +150,108 @@ func (w *dateWriter) close() error {
 	return err
 }
 
+// lastWrittenLine returns the last row in the newest file with ext under
+// dir. A partial last line left by a crash is cut off so appended rows start
+// on a fresh line; a .gz file cut short by a crash cannot be repaired and is
+// reported instead.
+func lastWrittenLine(dir, ext string) (tickLine, bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+	if err != nil || len(files) == 0 {
+		return tickLine{}, false, err
+	}
+	sort.Strings(files)
+	for i := len(files) - 1; i >= 0; i-- {
+		data, err := readDataFile(files[i])
+		if err != nil {
+			return tickLine{}, false, err
+		}
+		if strings.HasSuffix(files[i], ".gz") {
+			if len(data) > 0 && data[len(data)-1] != '\n' {
+				return tickLine{}, false, fmt.Errorf("resume: %s ends mid-line; delete it and rerun", files[i])
+			}
+		} else if cut := bytes.LastIndexByte(data, '\n') + 1; cut < len(data) {
+			if err := os.Truncate(files[i], int64(cut)); err != nil {
+				return tickLine{}, false, err
+			}
+			data = data[:cut]
+		}
+		lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+		last := bytes.TrimSpace(lines[len(lines)-1])
+		if len(last) == 0 {
+			continue
+		}
+		var row tickLine
+		if err := json.Unmarshal(last, &row); err != nil {
+			return tickLine{}, false, fmt.Errorf("resume: parse last line of %s: %w", files[i], err)
+		}
+		if row.Timestamp <= 0 {
+			return tickLine{}, false, fmt.Errorf("resume: last line of %s has no timestamp", files[i])
+		}
+		return row, true, nil
+	}
+	return tickLine{}, false, nil
+}
+
+func readDataFile(path string) ([]byte, error) {
+	if !strings.HasSuffix(path, ".gz") {
+		return os.ReadFile(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("resume: %s: %w; delete it and rerun", path, err)
+	}
+	defer gz.Close()
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("resume: %s: %w; delete it and rerun", path, err)
+	}
+	return data, nil
+}
+
 func main() {
 	var (
-		baseURL  string
-		symbol   string
-		interval string
-		months   int
-		startRaw string
-		endRaw   string
-		outDir   string
-		timeout  int
+		baseURL     string
+		symbol      string
+		interval    string
+		granularity string
+		months      int
+		startRaw    string
+		endRaw      string
+		outDir      string
+		timeout     int
+		resume      bool
+		gz          bool
 	)
 
 	flag.StringVar(&baseURL, "base-url", defaultBaseURL, "exchange REST base url")
 	flag.StringVar(&symbol, "symbol", "BTCUSDT", "symbol, e.g. BTCUSDT")
 	flag.StringVar(&interval, "interval", "1m", "kline interval, e.g. 1m/5m/15m/1h")
+	flag.StringVar(&granularity, "granularity", granularityKlines, "klines | aggtrades (every aggregated trade print, written under <symbol>/aggtrades; -interval is ignored)")
 	flag.IntVar(&months, "months", 6, "how many months to fetch back from now")
 	flag.StringVar(&startRaw, "start", "", "start time (YYYY-MM-DD or RFC3339, UTC)")
 	flag.StringVar(&endRaw, "end", "", "end time (YYYY-MM-DD or RFC3339, UTC), inclusive for date")
 	flag.StringVar(&outDir, "out-dir", defaultOutDir, "output root dir")
 	flag.IntVar(&timeout, "timeout-sec", 20, "http timeout seconds")
+	flag.BoolVar(&gz, "gzip", false, "write gzip-compressed <date>.jsonl.gz files (backtest reads them directly)")
+	flag.BoolVar(&resume, "resume", false, "continue after the last row already written for symbol/interval, appending instead of truncating")
 	flag.Parse()
 
 	symbol = strings.ToUpper(strings.TrimSpace(symbol))
 	interval = strings.TrimSpace(interval)
 	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	granularity = strings.ToLower(strings.TrimSpace(granularity))
+	switch granularity {
+	case granularityKlines:
+	case granularityAggTrades:
+		interval = granularityAggTrades
+	default:
+		fatal(fmt.Sprintf("unknown granularity %q, want klines or aggtrades", granularity))
+	}
 	if symbol == "" || interval == "" || baseURL == "" {
 		fatal("base-url/symbol/interval are required")
 	}
@@ -141,10 +261,25 @@ func main() {
 	}
 
 	targetDir := filepath.Join(outDir, symbol, interval)
-	writer, err := newDateWriter(targetDir)
+	writer, err := newDateWriter(targetDir, resume, gz)
 	if err != nil {
 		fatal(err.Error())
 	}
+	var last tickLine
+	resumed := false
+	if resume {
+		row, ok, err := lastWrittenLine(targetDir, writer.ext())
+		if err != nil {
+			fatal(err.Error())
+		}
+		if ok {
+			last, resumed = row, true
+			if resumeAt := time.UnixMilli(row.Timestamp + 1).UTC(); resumeAt.After(start) {
+				start = resumeAt
+			}
+			fmt.Printf("resuming after last=%s\n", time.UnixMilli(row.Timestamp).UTC().Format(time.RFC3339))
+		}
+	}
 	defer func() {
 		if closeErr := writer.close(); closeErr != nil {
 			fmt.Fprintf(os.Stderr, "close writer failed: %v\n", closeErr)
@@ -152,29 +287,55 @@ func main() {
 	}()
 
 	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	fmt.Printf("fetching symbol=%s interval=%s from=%s to=%s\n", symbol, interval, start.Format(time.RFC3339), end.Add(-time.Millisecond).Format(time.RFC3339))
+
+	var total, requests int
+	if granularity == granularityAggTrades {
+		fromID := int64(-1)
+		if resumed && last.AggTradeID > 0 {
+			fromID = last.AggTradeID + 1
+		}
+		total, requests, err = fetchAggTradeRange(client, baseURL, symbol, start, end, fromID, writer)
+	} else {
+		lastWritten := int64(-1)
+		if resumed {
+			lastWritten = last.Timestamp
+		}
+		total, requests, err = fetchKlineRange(client, baseURL, symbol, interval, start, end, lastWritten, writer)
+	}
+	if err != nil {
+		fatal(err.Error())
+	}
+	fmt.Printf("done: records=%d requests=%d output=%s\n", total, requests, targetDir)
+}
 
+func writeTickLine(writer *dateWriter, line tickLine) error {
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	return writer.write(time.UnixMilli(line.Timestamp).UTC().Format("2006-01-02"), encoded)
+}
+
+func fetchKlineRange(client *http.Client, baseURL, symbol, interval string, start, end time.Time, lastWritten int64, writer *dateWriter) (int, int, error) {
 	startMs := start.UnixMilli()
 	endMs := end.UnixMilli()
 	total := 0
 	requests := 0
-
-	fmt.Printf("fetching symbol=%s interval=%s from=%s to=%s\n", symbol, interval, start.Format(time.RFC3339), end.Add(-time.Millisecond).Format(time.RFC3339))
-
 	for startMs < endMs {
 		batch, err := fetchKlines(client, baseURL, symbol, interval, startMs, endMs-1, 1000)
 		if err != nil {
-			fatal(err.Error())
+			return total, requests, err
 		}
 		if len(batch) == 0 {
 			break
 		}
 		requests++
 		for _, k := range batch {
-			if k.OpenTime >= endMs {
+			if k.OpenTime >= endMs || k.OpenTime <= lastWritten {
 				continue
 			}
 			ts := time.UnixMilli(k.OpenTime).UTC()
-			date := ts.Format("2006-01-02")
 			line := tickLine{
 				Time:      ts.Format(time.RFC3339),
 				Timestamp: k.OpenTime,
@@ -187,12 +348,8 @@ func main() {
 				Price:     k.Close,
 				Volume:    k.Volume,
 			}
-			encoded, err := json.Marshal(line)
-			if err != nil {
-				fatal(err.Error())
-			}
-			if err := writer.write(date, encoded); err != nil {
-				fatal(err.Error())
+			if err := writeTickLine(writer, line); err != nil {
+				return total, requests, err
 			}
 			total++
 			startMs = k.OpenTime + 1
@@ -202,19 +359,116 @@ func main() {
 		}
 		time.Sleep(120 * time.Millisecond)
 	}
+	return total, requests, nil
+}
 
-	fmt.Printf("done: records=%d requests=%d output=%s\n", total, requests, targetDir)
+// fetchAggTradeRange writes every aggTrade in [start, end), paging by fromId.
+// A negative fromID looks up the first trade at or after start.
+func fetchAggTradeRange(client *http.Client, baseURL, symbol string, start, end time.Time, fromID int64, writer *dateWriter) (int, int, error) {
+	endMs := end.UnixMilli()
+	total := 0
+	requests := 0
+	if fromID < 0 {
+		id, ok, n, err := firstAggTradeID(client, baseURL, symbol, start, end)
+		requests += n
+		if err != nil || !ok {
+			return total, requests, err
+		}
+		fromID = id
+	}
+	for {
+		batch, err := fetchAggTrades(client, baseURL, symbol, url.Values{"fromId": {strconv.FormatInt(fromID, 10)}}, 1000)
+		if err != nil {
+			return total, requests, err
+		}
+		requests++
+		if len(batch) == 0 {
+			return total, requests, nil
+		}
+		for _, tr := range batch {
+			if tr.Time >= endMs {
+				return total, requests, nil
+			}
+			line := tickLine{
+				Time:       time.UnixMilli(tr.Time).UTC().Format(time.RFC3339Nano),
+				Timestamp:  tr.Time,
+				Symbol:     symbol,
+				Interval:   granularityAggTrades,
+				Price:      tr.Price,
+				Volume:     tr.Qty,
+				Qty:        tr.Qty,
+				AggTradeID: tr.ID,
+			}
+			if err := writeTickLine(writer, line); err != nil {
+				return total, requests, err
+			}
+			total++
+			fromID = tr.ID + 1
+		}
+		if requests%20 == 0 {
+			fmt.Printf("progress: requests=%d records=%d last=%s\n", requests, total, time.UnixMilli(batch[len(batch)-1].Time).UTC().Format(time.RFC3339))
+		}
+		time.Sleep(120 * time.Millisecond)
+	}
+}
+
+// firstAggTradeID finds the id of the first aggTrade in [start, end). The
+// API caps a startTime/endTime query at one hour, so it steps an hour at a
+// time through quiet stretches.
+func firstAggTradeID(client *http.Client, baseURL, symbol string, start, end time.Time) (int64, bool, int, error) {
+	requests := 0
+	for from := start; from.Before(end); from = from.Add(time.Hour) {
+		to := from.Add(time.Hour)
+		if to.After(end) {
+			to = end
+		}
+		batch, err := fetchAggTrades(client, baseURL, symbol, url.Values{
+			"startTime": {strconv.FormatInt(from.UnixMilli(), 10)},
+			"endTime":   {strconv.FormatInt(to.UnixMilli()-1, 10)},
+		}, 1)
+		requests++
+		if err != nil {
+			return 0, false, requests, err
+		}
+		if len(batch) > 0 {
+			return batch[0].ID, true, requests, nil
+		}
+		time.Sleep(120 * time.Millisecond)
+	}
+	return 0, false, requests, nil
 }
 
 func fetchKlines(client *http.Client, baseURL, symbol, interval string, startMs, endMs int64, limit int) ([]kline, error) {
-	endpoint := baseURL + "/api/v3/klines"
 	values := url.Values{}
 	values.Set("symbol", symbol)
 	values.Set("interval", interval)
 	values.Set("startTime", strconv.FormatInt(startMs, 10))
 	values.Set("endTime", strconv.FormatInt(endMs, 10))
 	values.Set("limit", strconv.Itoa(limit))
+	body, err := getWithRetry(client, baseURL+"/api/v3/klines", values)
+	if err != nil {
+		return nil, err
+	}
+	return parseKlines(body)
+}
+
+func fetchAggTrades(client *http.Client, baseURL, symbol string, values url.Values, limit int) ([]aggTrade, error) {
+	values.Set("symbol", symbol)
+	values.Set("limit", strconv.Itoa(limit))
+	body, err := getWithRetry(client, baseURL+"/api/v3/aggTrades", values)
+	if err != nil {
+		return nil, err
+	}
+	var trades []aggTrade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
 
+// getWithRetry GETs endpoint, retrying network errors, 429 and 5xx with a
+// growing pause.
+func getWithRetry(client *http.Client, endpoint string, values url.Values) ([]byte, error) {
 	var lastErr error
 	for attempt := 0; attempt < 5; attempt++ {
 		req, err := http.NewRequest(http.MethodGet, endpoint+"?"+values.Encode(), nil)
@@ -238,10 +492,10 @@ func fetchKlines(client *http.Client, baseURL, symbol, interval string, startMs,
 		if resp.StatusCode/100 != 2 {
 			return nil, fmt.Errorf("status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
 		}
-		return parseKlines(body)
+		return body, nil
 	}
 	if lastErr == nil {
-		lastErr = errors.New("fetch klines failed")
+		lastErr = errors.New("fetch " + endpoint + " failed")
 	}
 	return nil, lastErr
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"grid-trading/internal/backtest"
+)
+
+func readFeed(t *testing.T, path string) []backtest.Tick {
+	t.Helper()
+	feed, err := backtest.NewJSONLFeed(path)
+	if err != nil {
+		t.Fatalf("NewJSONLFeed() error = %v", err)
+	}
+	defer feed.Close()
+	var ticks []backtest.Tick
+	for {
+		tick, err := feed.Next()
+		if errors.Is(err, io.EOF) {
+			return ticks
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		ticks = append(ticks, tick)
+	}
+}
+
+func TestFetchAggTradeRangeWritesRowsTheBacktestReads(t *testing.T) {
+	start := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 2, 0, 1, 0, 0, time.UTC)
+	trades := []aggTrade{
+		{ID: 10, Price: "100.5", Qty: "0.2", Time: start.Add(59 * time.Minute).UnixMilli()},
+		{ID: 11, Price: "100.6", Qty: "0.05", Time: start.Add(59*time.Minute + 30*time.Second).UnixMilli()},
+		{ID: 12, Price: "100.4", Qty: "1", Time: end.Add(-50 * time.Second).UnixMilli()},
+		{ID: 13, Price: "100.9", Qty: "3", Time: end.UnixMilli()},
+	}
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/aggTrades" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		queries = append(queries, q.Encode())
+		out := []aggTrade{}
+		if from := q.Get("fromId"); from != "" {
+			id, _ := strconv.ParseInt(from, 10, 64)
+			for _, tr := range trades {
+				if tr.ID >= id {
+					out = append(out, tr)
+				}
+			}
+		} else {
+			lo, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+			hi, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+			for _, tr := range trades {
+				if tr.Time >= lo && tr.Time <= hi {
+					out = append(out, tr)
+					break
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	for _, gz := range []bool{false, true} {
+		dir := t.TempDir()
+		writer, err := newDateWriter(dir, false, gz)
+		if err != nil {
+			t.Fatalf("newDateWriter() error = %v", err)
+		}
+		queries = nil
+		total, requests, err := fetchAggTradeRange(srv.Client(), srv.URL, "BTCUSDT", start, end, -1, writer)
+		if err != nil {
+			t.Fatalf("fetchAggTradeRange(gzip=%v) error = %v", gz, err)
+		}
+		if err := writer.close(); err != nil {
+			t.Fatalf("close() error = %v", err)
+		}
+		if total != 3 || requests != 2 {
+			t.Fatalf("gzip=%v total=%d requests=%d, want 3 rows in 2 requests", gz, total, requests)
+		}
+		if len(queries) != 2 || !strings.Contains(queries[0], "startTime=") || !strings.Contains(queries[1], "fromId=10") {
+			t.Fatalf("gzip=%v queries = %v, want a startTime lookup then fromId=10", gz, queries)
+		}
+
+		row, ok, err := lastWrittenLine(dir, writer.ext())
+		if err != nil || !ok {
+			t.Fatalf("lastWrittenLine() ok=%v err=%v", ok, err)
+		}
+		if row.AggTradeID != 12 || row.Interval != granularityAggTrades || row.Qty != "1" {
+			t.Fatalf("last row = %+v, want agg trade 12 with qty 1", row)
+		}
+
+		ticks := readFeed(t, dir)
+		if len(ticks) != 3 {
+			t.Fatalf("gzip=%v feed ticks = %d, want 3", gz, len(ticks))
+		}
+		for i, tick := range ticks {
+			tr := trades[i]
+			if !tick.Time.Equal(time.UnixMilli(tr.Time)) || tick.Price.String() != tr.Price || !tick.HasVolume || tick.Volume.String() != tr.Qty {
+				t.Fatalf("gzip=%v tick %d = %+v, want time %d price %s volume %s", gz, i, tick, tr.Time, tr.Price, tr.Qty)
+			}
+		}
+	}
+}
+
+func TestResumeCutsPartialLineAndContinuesAfterLastRow(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minute := func(i int) int64 { return day.Add(time.Duration(i) * time.Minute).UnixMilli() }
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		lo, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+		hi, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+		rows := [][]any{}
+		for i := 0; i < 10; i++ {
+			if open := minute(i); open >= lo && open <= hi {
+				price := strconv.Itoa(100 + i)
+				rows = append(rows, []any{open, price, price, price, price, "1", open + 59999})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(rows)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writer, err := newDateWriter(dir, false, false)
+	if err != nil {
+		t.Fatalf("newDateWriter() error = %v", err)
+	}
+	if _, _, err := fetchKlineRange(srv.Client(), srv.URL, "BTCUSDT", "1m", day, day.Add(3*time.Minute), -1, writer); err != nil {
+		t.Fatalf("first fetchKlineRange() error = %v", err)
+	}
+	if err := writer.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+
+	// A crash mid-write leaves a partial last row behind.
+	path := filepath.Join(dir, "2024-01-01.jsonl")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	if _, err := f.WriteString(`{"time":"2024-01-01T00:03:00Z","timestamp":17`); err != nil {
+		t.Fatalf("WriteString() error = %v", err)
+	}
+	_ = f.Close()
+
+	writer, err = newDateWriter(dir, true, false)
+	if err != nil {
+		t.Fatalf("newDateWriter() error = %v", err)
+	}
+	last, ok, err := lastWrittenLine(dir, writer.ext())
+	if err != nil || !ok {
+		t.Fatalf("lastWrittenLine() ok=%v err=%v", ok, err)
+	}
+	if last.Timestamp != minute(2) {
+		t.Fatalf("last timestamp = %d, want %d", last.Timestamp, minute(2))
+	}
+	resumeAt := time.UnixMilli(last.Timestamp + 1).UTC()
+	total, _, err := fetchKlineRange(srv.Client(), srv.URL, "BTCUSDT", "1m", resumeAt, day.Add(5*time.Minute), last.Timestamp, writer)
+	if err != nil {
+		t.Fatalf("resumed fetchKlineRange() error = %v", err)
+	}
+	if err := writer.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("resumed rows = %d, want 2", total)
+	}
+
+	// The feed skips lines it cannot parse, so check the raw rows too.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("file has %d rows after resume, want 5:\n%s", len(lines), data)
+	}
+	for i, line := range lines {
+		var row tickLine
+		if err := json.Unmarshal([]byte(line), &row); err != nil || row.Timestamp != minute(i) {
+			t.Fatalf("row %d = %q (err %v), want timestamp %d", i, line, err, minute(i))
+		}
+	}
+
+	ticks := readFeed(t, dir)
+	var got []string
+	for _, tick := range ticks {
+		got = append(got, tick.Time.UTC().Format("15:04")+"="+tick.Price.String())
+	}
+	if want := "00:00=100,00:01=101,00:02=102,00:03=103,00:04=104"; strings.Join(got, ",") != want {
+		t.Fatalf("rows after resume = %s, want %s", strings.Join(got, ","), want)
+	}
+}
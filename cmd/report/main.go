@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/store"
+)
+
+func main() {
+	var stateDir, symbol string
+	flag.StringVar(&stateDir, "state", "", "instance state dir, e.g. state/live/BTCUSDT/default")
+	flag.StringVar(&symbol, "symbol", "", "only count fills for this symbol; empty counts all")
+	flag.Parse()
+
+	if strings.TrimSpace(stateDir) == "" {
+		fatal("-state is required")
+	}
+	if _, err := os.Stat(stateDir); err != nil {
+		fatal(err.Error())
+	}
+	st, err := store.New(stateDir)
+	if err != nil {
+		fatal(err.Error())
+	}
+	trades, err := st.LoadTrades()
+	if err != nil {
+		fatal(err.Error())
+	}
+	if err := writeReport(os.Stdout, store.DailyPnL(trades, strings.ToUpper(strings.TrimSpace(symbol)))); err != nil {
+		fatal(err.Error())
+	}
+}
+
+// writeReport prints one row per day and a total row. Net PnL is realized
+// PnL less the quote-valued fees; fees in other assets are listed as is.
+func writeReport(w io.Writer, days []store.DayPnL) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "day\tbuys\tsells\tbuy_qty\tsell_qty\trealized_pnl_quote\tfee_quote\tnet_pnl_quote\tother_fees\tunmatched_sell_qty")
+	total := store.DayPnL{Day: "total", BuyQty: decimal.Zero, SellQty: decimal.Zero, RealizedPnL: decimal.Zero, UnmatchedSellQty: decimal.Zero, FeeQuote: decimal.Zero}
+	for _, d := range days {
+		writeRow(tw, d)
+		total.Buys += d.Buys
+		total.Sells += d.Sells
+		total.BuyQty = total.BuyQty.Add(d.BuyQty)
+		total.SellQty = total.SellQty.Add(d.SellQty)
+		total.RealizedPnL = total.RealizedPnL.Add(d.RealizedPnL)
+		total.UnmatchedSellQty = total.UnmatchedSellQty.Add(d.UnmatchedSellQty)
+		total.FeeQuote = total.FeeQuote.Add(d.FeeQuote)
+		for asset, fee := range d.OtherFees {
+			if total.OtherFees == nil {
+				total.OtherFees = make(map[string]decimal.Decimal)
+			}
+			total.OtherFees[asset] = total.OtherFees[asset].Add(fee)
+		}
+	}
+	writeRow(tw, total)
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, d store.DayPnL) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", d.Day, d.Buys, d.Sells, d.BuyQty, d.SellQty, d.RealizedPnL, d.FeeQuote, d.RealizedPnL.Sub(d.FeeQuote), otherFees(d.OtherFees), d.UnmatchedSellQty)
+}
+
+// otherFees formats fees by asset as "BNB=0.01,..." in asset order, or "-".
+func otherFees(fees map[string]decimal.Decimal) string {
+	if len(fees) == 0 {
+		return "-"
+	}
+	assets := make([]string, 0, len(fees))
+	for asset := range fees {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	parts := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		parts = append(parts, asset+"="+fees[asset].String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func fatal(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
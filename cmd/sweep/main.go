@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+
+	"grid-trading/internal/config"
+	"grid-trading/internal/sweep"
+)
+
+// spec is the sweep file: a base backtest config plus the values to try.
+// A relative config path is resolved against the spec's directory.
+type spec struct {
+	Config      string           `yaml:"config"`
+	DataPath    string           `yaml:"data_path"`
+	Ratio       []config.Decimal `yaml:"ratio"`
+	Levels      []int            `yaml:"levels"`
+	ShiftLevels []int            `yaml:"shift_levels"`
+}
+
+func main() {
+	var specPath, dataPath string
+	var top int
+	flag.StringVar(&specPath, "spec", "", "sweep spec yaml path")
+	flag.StringVar(&dataPath, "data", "", "dataset for every run (jsonl file or directory); overrides the spec and backtest.data_path")
+	flag.IntVar(&top, "top", 0, "print only the best N runs; 0 prints all")
+	flag.Parse()
+
+	if specPath == "" {
+		fatal("-spec is required")
+	}
+	sp, err := loadSpec(specPath)
+	if err != nil {
+		fatal(err.Error())
+	}
+	base, err := loadBacktestConfig(sp.Config)
+	if err != nil {
+		fatal(err.Error())
+	}
+	if strings.TrimSpace(dataPath) == "" {
+		dataPath = sp.DataPath
+	}
+	if strings.TrimSpace(dataPath) == "" {
+		dataPath = base.Backtest.DataPath
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	results, err := sweep.Run(ctx, base, dataPath, sp.grid())
+	if err != nil {
+		fatal(err.Error())
+	}
+	if top > 0 && top < len(results) {
+		results = results[:top]
+	}
+	if err := writeResults(os.Stdout, results); err != nil {
+		fatal(err.Error())
+	}
+}
+
+func loadSpec(path string) (spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spec{}, err
+	}
+	var sp spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&sp); err != nil {
+		return spec{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if strings.TrimSpace(sp.Config) == "" {
+		return spec{}, fmt.Errorf("%s: config is required", path)
+	}
+	if !filepath.IsAbs(sp.Config) {
+		sp.Config = filepath.Join(filepath.Dir(path), sp.Config)
+	}
+	for _, r := range sp.Ratio {
+		if r.Cmp(decimal.NewFromInt(1)) <= 0 {
+			return spec{}, fmt.Errorf("%s: ratio %s must be > 1", path, r)
+		}
+	}
+	return sp, nil
+}
+
+func (sp spec) grid() sweep.Grid {
+	g := sweep.Grid{Levels: sp.Levels, ShiftLevels: sp.ShiftLevels}
+	for _, r := range sp.Ratio {
+		g.Ratios = append(g.Ratios, r.Decimal)
+	}
+	return g
+}
+
+func loadBacktestConfig(path string) (config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Mode != config.ModeBacktest {
+		return config.Config{}, fmt.Errorf("%s: sweep requires mode=backtest", path)
+	}
+	if len(cfg.Backtest.Symbols) > 0 {
+		return config.Config{}, fmt.Errorf("%s: sweep does not support backtest.symbols; use data_path", path)
+	}
+	return cfg, nil
+}
+
+func writeResults(w io.Writer, results []sweep.Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "rank\tratio\tlevels\tshift_levels\ttotal_return_pct\tequity_return_pct\tprofit_quote\tmax_drawdown_pct\ttrades\terror")
+	for i, r := range results {
+		p := r.Params
+		if r.Err != nil {
+			fmt.Fprintf(tw, "-\t%s\t%d\t%d\t\t\t\t\t\t%v\n", p.Ratio, p.Levels, p.ShiftLevels, r.Err)
+			continue
+		}
+		res := r.Result
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%d\t\n",
+			i+1, p.Ratio, p.Levels, p.ShiftLevels,
+			res.TotalReturnPct.StringFixed(4), res.EquityReturnPct.StringFixed(4), res.ProfitQuote,
+			res.MaxDrawdownPct.StringFixed(4), res.Trades)
+	}
+	return tw.Flush()
+}
+
+func fatal(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -47,6 +48,7 @@ type selectedChecks struct {
 	stream    bool
 	reconnect bool
 	bootstrap bool
+	fills     bool
 }
 
 func main() {
@@ -63,7 +65,7 @@ func main() {
 	flag.IntVar(&streamWait, "stream-wait-sec", 10, "wait seconds for user stream checks")
 	flag.StringVar(&outJSONPath, "out-json", "", "optional output report path")
 	flag.BoolVar(&allowLiveRun, "allow-live", false, "allow running checks when mode=live")
-	flag.StringVar(&checkFlag, "check", "default", "checks to run: default | all | bootstrap | comma list (preflight,lifecycle,stream,reconnect,bootstrap)")
+	flag.StringVar(&checkFlag, "check", "default", "checks to run: default | all | bootstrap | fills | comma list (preflight,lifecycle,stream,reconnect,bootstrap,fills)")
 	flag.Parse()
 
 	cfg, err := config.Load(configPath)
@@ -136,29 +138,7 @@ func main() {
 	}
 
 	run := func(name string, fn func() (string, error)) {
-		start := time.Now()
-		detail, err := fn()
-		cr := checkResult{
-			Name:       name,
-			DurationMs: time.Since(start).Milliseconds(),
-			Detail:     detail,
-		}
-		if err != nil {
-			cr.Status = statusFail
-			cr.Error = err.Error()
-		} else {
-			cr.Status = statusPass
-		}
-		r.Checks = append(r.Checks, cr)
-		if cr.Status == statusPass {
-			fmt.Printf("[PASS] %s (%dms)", name, cr.DurationMs)
-			if cr.Detail != "" {
-				fmt.Printf(" - %s", cr.Detail)
-			}
-			fmt.Println()
-		} else {
-			fmt.Printf("[FAIL] %s (%dms) - %s\n", name, cr.DurationMs, cr.Error)
-		}
+		r.run(os.Stdout, name, fn)
 	}
 
 	if checks.preflight {
@@ -324,13 +304,22 @@ func main() {
 		})
 	}
 
+	if checks.fills {
+		run("user_stream_fill", func() (string, error) {
+			if err := loadMarketContext(); err != nil {
+				return "", err
+			}
+			return runFillCheck(ctx, cfg, client, rules, lastQuote, time.Duration(streamWait)*time.Second)
+		})
+	}
+
 	// cleanup: if lifecycle order still exists, best-effort cancel
 	if placedID != "" {
 		_ = client.CancelOrder(context.Background(), cfg.Symbol, placedID)
 	}
 
 	r.FinishedAt = time.Now().UTC()
-	printSummary(r)
+	printSummary(os.Stdout, r)
 
 	if outJSONPath != "" {
 		if err := writeReport(outJSONPath, r); err != nil {
@@ -339,11 +328,46 @@ func main() {
 		fmt.Printf("report written: %s\n", outJSONPath)
 	}
 
+	if r.failed() {
+		os.Exit(1)
+	}
+}
+
+// run times fn, records its outcome as check name and prints a PASS/FAIL
+// line for it.
+func (r *report) run(out io.Writer, name string, fn func() (string, error)) {
+	start := time.Now()
+	detail, err := fn()
+	cr := checkResult{
+		Name:       name,
+		DurationMs: time.Since(start).Milliseconds(),
+		Detail:     detail,
+	}
+	if err != nil {
+		cr.Status = statusFail
+		cr.Error = err.Error()
+	} else {
+		cr.Status = statusPass
+	}
+	r.Checks = append(r.Checks, cr)
+	if cr.Status == statusPass {
+		fmt.Fprintf(out, "[PASS] %s (%dms)", name, cr.DurationMs)
+		if cr.Detail != "" {
+			fmt.Fprintf(out, " - %s", cr.Detail)
+		}
+		fmt.Fprintln(out)
+	} else {
+		fmt.Fprintf(out, "[FAIL] %s (%dms) - %s\n", name, cr.DurationMs, cr.Error)
+	}
+}
+
+func (r report) failed() bool {
 	for _, c := range r.Checks {
 		if c.Status == statusFail {
-			os.Exit(1)
+			return true
 		}
 	}
+	return false
 }
 
 func parseCheckFlag(raw string) (selectedChecks, error) {
@@ -363,6 +387,7 @@ func parseCheckFlag(raw string) (selectedChecks, error) {
 			stream:    true,
 			reconnect: true,
 			bootstrap: true,
+			fills:     true,
 		}, nil
 	}
 
@@ -383,11 +408,13 @@ func parseCheckFlag(raw string) (selectedChecks, error) {
 			out.reconnect = true
 		case "bootstrap", "strategy_bootstrap", "strategy_bootstrap_distribution":
 			out.bootstrap = true
+		case "fills", "fill", "user_stream_fill":
+			out.fills = true
 		default:
 			return selectedChecks{}, fmt.Errorf("unknown check: %s", name)
 		}
 	}
-	if !out.preflight && !out.lifecycle && !out.stream && !out.reconnect && !out.bootstrap {
+	if !out.preflight && !out.lifecycle && !out.stream && !out.reconnect && !out.bootstrap && !out.fills {
 		return selectedChecks{}, errors.New("no checks selected")
 	}
 	return out, nil
@@ -491,6 +518,97 @@ func runBootstrapDistributionCheck(ctx context.Context, cfg config.Config, clien
 	), nil
 }
 
+// runFillCheck buys a tiny qty with a limit 1% above the ask so it fills at
+// once, and waits up to wait for the user stream to report the order FILLED.
+// The acquired base is sold back at market best-effort.
+func runFillCheck(ctx context.Context, cfg config.Config, client *binance.Client, rules core.Rules, quote decimal.Decimal, wait time.Duration) (string, error) {
+	_, ask, err := client.BookTicker(ctx, cfg.Symbol)
+	if err != nil {
+		return "", err
+	}
+	if ask.Cmp(decimal.Zero) <= 0 {
+		return "", errors.New("missing ask price")
+	}
+	price := ask.Mul(decimal.RequireFromString("1.01"))
+	if rules.PriceTick.Cmp(decimal.Zero) > 0 {
+		price = price.Div(rules.PriceTick).Ceil().Mul(rules.PriceTick)
+	}
+	qty, err := buildTinyLimitQty(cfg, rules, price)
+	if err != nil {
+		return "", err
+	}
+	if notional := price.Mul(qty); quote.Cmp(notional) < 0 {
+		return "", fmt.Errorf("insufficient quote for fill check: need=%s have=%s", notional.String(), quote.String())
+	}
+
+	sctx, scancel := context.WithTimeout(ctx, wait)
+	defer scancel()
+	stream, err := client.NewUserStream(sctx, time.Duration(cfg.Exchange.UserStreamKeepaliveSec)*time.Second)
+	if err != nil {
+		return "", err
+	}
+	trades, errs := stream.Trades(sctx, cfg.Symbol)
+
+	placed, err := client.PlaceOrder(ctx, core.Order{
+		Symbol: cfg.Symbol,
+		Side:   core.Buy,
+		Type:   core.Limit,
+		Price:  price,
+		Qty:    qty,
+	})
+	if err != nil {
+		return "", err
+	}
+	filledQty, filledQuote := decimal.Zero, decimal.Zero
+	defer func() {
+		if filledQty.Cmp(decimal.Zero) > 0 {
+			sellBackBestEffort(cfg.Symbol, filledQty, rules, client)
+		}
+	}()
+	for {
+		select {
+		case <-sctx.Done():
+			_ = client.CancelOrder(context.Background(), cfg.Symbol, placed.ID)
+			return "", fmt.Errorf("no FILLED executionReport for order %s within %s (filled_qty=%s)", placed.ID, wait, filledQty.String())
+		case err, ok := <-errs:
+			if ok && err != nil {
+				return "", err
+			}
+		case t, ok := <-trades:
+			if !ok {
+				return "", errors.New("trades channel closed unexpectedly")
+			}
+			if t.OrderID != placed.ID || t.Qty.Cmp(decimal.Zero) <= 0 {
+				continue
+			}
+			filledQty = filledQty.Add(t.Qty)
+			filledQuote = filledQuote.Add(t.Price.Mul(t.Qty))
+			if t.Status != core.OrderFilled {
+				continue
+			}
+			return fmt.Sprintf("id=%s limit=%s qty=%s filled_qty=%s avg_price=%s",
+				placed.ID, price.String(), qty.String(), filledQty.String(), filledQuote.Div(filledQty).String()), nil
+		}
+	}
+}
+
+// sellBackBestEffort market-sells qty, capped at the free base balance since
+// fees may have been taken in base.
+func sellBackBestEffort(symbol string, qty decimal.Decimal, rules core.Rules, client *binance.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if bal, err := client.Balances(ctx); err == nil && bal.Base.Cmp(qty) < 0 {
+		qty = bal.Base
+	}
+	if rules.QtyStep.Cmp(decimal.Zero) > 0 {
+		qty = core.RoundDown(qty, rules.QtyStep)
+	}
+	if qty.Cmp(decimal.Zero) <= 0 {
+		return
+	}
+	_, _ = client.PlaceOrder(ctx, core.Order{Symbol: symbol, Side: core.Sell, Type: core.Market, Qty: qty})
+}
+
 func diffOrders(before, after []core.Order) []core.Order {
 	beforeIDs := make(map[string]struct{}, len(before))
 	for _, ord := range before {
@@ -563,6 +681,9 @@ func buildTinyLimitQty(cfg config.Config, rules core.Rules, price decimal.Decima
 			qty = minByMulti
 		}
 	}
+	if err := core.CheckMinNotionalFundable(price, cfg.Grid.MaxOrderQty.Decimal, rules); err != nil {
+		return decimal.Zero, err
+	}
 	if rules.MinNotional.Cmp(decimal.Zero) > 0 {
 		minNotionalQty := rules.MinNotional.Div(price)
 		if minNotionalQty.Cmp(qty) > 0 {
@@ -605,7 +726,7 @@ func roundQtyUp(qty, step decimal.Decimal) decimal.Decimal {
 	return qty.Div(step).Ceil().Mul(step)
 }
 
-func printSummary(r report) {
+func printSummary(out io.Writer, r report) {
 	pass := 0
 	fail := 0
 	for _, c := range r.Checks {
@@ -615,7 +736,7 @@ func printSummary(r report) {
 			fail++
 		}
 	}
-	fmt.Printf("\nsummary mode=%s symbol=%s pass=%d fail=%d duration=%s\n",
+	fmt.Fprintf(out, "\nsummary mode=%s symbol=%s pass=%d fail=%d duration=%s\n",
 		r.Mode,
 		r.Symbol,
 		pass,
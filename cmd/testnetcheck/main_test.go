@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+	"grid-trading/internal/core"
+	"grid-trading/internal/exchange/binance"
+)
+
+// fakeExchange answers the REST and WS-API calls the fills check makes. A
+// placed BUY is reported FILLED on the user stream when fill is set.
+type fakeExchange struct {
+	fill bool
+
+	mu       sync.Mutex
+	placed   []map[string]any
+	canceled []string
+	streams  chan *websocket.Conn
+}
+
+func newFakeExchange(t *testing.T, fill bool) (*fakeExchange, *binance.Client) {
+	t.Helper()
+	fx := &fakeExchange{fill: fill, streams: make(chan *websocket.Conn, 1)}
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/ticker/bookTicker" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"symbol": "BTCUSDT", "bidPrice": "99.99", "askPrice": "100"})
+	}))
+	t.Cleanup(rest.Close)
+	ws := httptest.NewServer(http.HandlerFunc(fx.serveWS))
+	t.Cleanup(func() {
+		ws.CloseClientConnections()
+		ws.Close()
+	})
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         "ws://" + strings.TrimPrefix(ws.URL, "http://"),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "check",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	t.Cleanup(func() { client.Close() })
+	return fx, client
+}
+
+func (fx *fakeExchange) serveWS(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		var req struct {
+			ID     string         `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		result := map[string]any{}
+		switch req.Method {
+		case "order.place":
+			fx.mu.Lock()
+			fx.placed = append(fx.placed, req.Params)
+			id := int64(9000 + len(fx.placed))
+			fx.mu.Unlock()
+			result = map[string]any{"orderId": id, "status": "NEW", "transactTime": time.Now().UnixMilli()}
+			if fx.fill && req.Params["side"] == "BUY" {
+				go fx.reportFill(id, req.Params)
+			}
+		case "order.cancel":
+			fx.mu.Lock()
+			fx.canceled = append(fx.canceled, fmt.Sprint(req.Params["orderId"]))
+			fx.mu.Unlock()
+		}
+		if err := conn.WriteJSON(map[string]any{"id": req.ID, "status": 200, "result": result}); err != nil {
+			return
+		}
+		if req.Method == "userDataStream.subscribe.signature" {
+			// The stream conn is written by reportFill from here on; keep
+			// reading so it notices the client going away.
+			fx.streams <- conn
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (fx *fakeExchange) reportFill(orderID int64, params map[string]any) {
+	conn := <-fx.streams
+	qty := params["quantity"].(string)
+	now := time.Now().UnixMilli()
+	_ = conn.WriteJSON(map[string]any{
+		"e": "executionReport", "E": now, "s": "BTCUSDT", "i": orderID, "S": "BUY",
+		"x": "TRADE", "X": "FILLED", "p": params["price"], "q": qty,
+		"L": "100", "l": qty, "z": qty, "T": now, "t": orderID,
+	})
+}
+
+func fillCheckConfig() (config.Config, core.Rules) {
+	var cfg config.Config
+	cfg.Symbol = "BTCUSDT"
+	cfg.Grid.Qty = config.Decimal{Decimal: decimal.RequireFromString("0.001")}
+	cfg.Grid.MinQtyMultiple = 1
+	rules := core.Rules{
+		PriceTick:   decimal.RequireFromString("0.01"),
+		QtyStep:     decimal.RequireFromString("0.001"),
+		MinQty:      decimal.RequireFromString("0.001"),
+		MinNotional: decimal.RequireFromString("5"),
+	}
+	return cfg, rules
+}
+
+func TestRunFillCheckPassesOnFilledReport(t *testing.T) {
+	fx, client := newFakeExchange(t, true)
+	cfg, rules := fillCheckConfig()
+
+	detail, err := runFillCheck(context.Background(), cfg, client, rules, decimal.NewFromInt(1000), 3*time.Second)
+	if err != nil {
+		t.Fatalf("runFillCheck() error = %v", err)
+	}
+	for _, want := range []string{"limit=101", "qty=0.05", "filled_qty=0.05", "avg_price=100"} {
+		if !strings.Contains(detail, want) {
+			t.Fatalf("detail = %q, want it to contain %q", detail, want)
+		}
+	}
+	fx.mu.Lock()
+	defer fx.mu.Unlock()
+	if len(fx.placed) != 2 || fx.placed[1]["side"] != "SELL" || fx.placed[1]["type"] != "MARKET" || fx.placed[1]["quantity"] != "0.05" {
+		t.Fatalf("placed = %v, want the buy then a 0.05 market sell back", fx.placed)
+	}
+}
+
+func TestRunFillCheckFailsAndCancelsWithoutFilledReport(t *testing.T) {
+	fx, client := newFakeExchange(t, false)
+	cfg, rules := fillCheckConfig()
+
+	_, err := runFillCheck(context.Background(), cfg, client, rules, decimal.NewFromInt(1000), 500*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no FILLED executionReport for order 9001") {
+		t.Fatalf("runFillCheck() error = %v, want missing FILLED report", err)
+	}
+	fx.mu.Lock()
+	defer fx.mu.Unlock()
+	if len(fx.placed) != 1 || len(fx.canceled) != 1 || fx.canceled[0] != "9001" {
+		t.Fatalf("placed = %v canceled = %v, want the buy placed and canceled", fx.placed, fx.canceled)
+	}
+}
+
+func TestRunFillCheckFailsOnInsufficientQuote(t *testing.T) {
+	fx, client := newFakeExchange(t, true)
+	cfg, rules := fillCheckConfig()
+
+	_, err := runFillCheck(context.Background(), cfg, client, rules, decimal.NewFromInt(1), time.Second)
+	if err == nil || !strings.Contains(err.Error(), "insufficient quote for fill check") {
+		t.Fatalf("runFillCheck() error = %v, want insufficient quote", err)
+	}
+	if len(fx.placed) != 0 {
+		t.Fatalf("placed = %v, want no order", fx.placed)
+	}
+}
+
+func TestReportRunRecordsPassAndFail(t *testing.T) {
+	var out bytes.Buffer
+	r := report{Mode: config.ModeTestnet, Symbol: "BTCUSDT"}
+	r.run(&out, "exchange_preflight", func() (string, error) { return "price=100", nil })
+	if r.failed() {
+		t.Fatalf("failed() = true after a passing check")
+	}
+	r.run(&out, "user_stream_fill", func() (string, error) { return "", errors.New("no FILLED executionReport") })
+	if !r.failed() {
+		t.Fatalf("failed() = false after a failing check")
+	}
+	printSummary(&out, r)
+
+	if len(r.Checks) != 2 || r.Checks[0].Status != statusPass || r.Checks[1].Status != statusFail || r.Checks[1].Error != "no FILLED executionReport" {
+		t.Fatalf("checks = %+v, want a pass then a fail", r.Checks)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"[PASS] exchange_preflight (",
+		" - price=100\n",
+		"[FAIL] user_stream_fill (",
+		" - no FILLED executionReport\n",
+		"summary mode=testnet symbol=BTCUSDT pass=1 fail=1",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q, got:\n%s", want, got)
+		}
+	}
+}
@@ -2,8 +2,10 @@ package alert
 
 import (
 	"context"
+	"errors"
 	"log"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,6 +16,23 @@ type Notifier interface {
 	Notify(ctx context.Context, msg string) error
 }
 
+// EventNotifier is implemented by transports that want the structured alert
+// rather than the rendered text; the manager prefers it over Notify.
+type EventNotifier interface {
+	NotifyEvent(ctx context.Context, ev Event) error
+}
+
+// Event is one alert as handed to an EventNotifier.
+type Event struct {
+	Event  string            `json:"event"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Mode   string            `json:"mode"`
+	Symbol string            `json:"symbol"`
+	TS     time.Time         `json:"ts"`
+	// Text is the message text-only transports send.
+	Text string `json:"text"`
+}
+
 type Alerter interface {
 	Important(event string, fields map[string]string)
 }
@@ -21,21 +40,31 @@ type Alerter interface {
 const (
 	defaultAlertQueueSize     = 128
 	defaultDropReportInterval = time.Minute
+	defaultNotifyRetries      = 2
+	defaultNotifyRetryBackoff = time.Second
+	maxNotifyRetries          = 5
 )
 
 type ManagerOptions struct {
 	QueueSize          int
 	DropReportInterval time.Duration
+	// MaxRetries < 0 disables retries; 0 uses the default.
+	MaxRetries   int
+	RetryBackoff time.Duration
 }
 
+// Manager fans alerts out to its notifiers. Each notifier has its own queue
+// and sender, so a slow or failing transport never delays the others; drop
+// counts are shared across all of them.
 type Manager struct {
 	mode                 string
 	symbol               string
-	notifier             Notifier
-	queue                chan alertEvent
+	sinks                []*sink
 	stop                 chan struct{}
 	done                 chan struct{}
 	dropReportInterval   time.Duration
+	maxRetries           int
+	retryBackoff         time.Duration
 	droppedTotal         uint64
 	droppedSinceReported uint64
 	wg                   sync.WaitGroup
@@ -43,22 +72,38 @@ type Manager struct {
 	closed               bool
 }
 
+type sink struct {
+	name     string
+	notifier Notifier
+	queue    chan alertEvent
+	// retries holds alerts waiting out their retry backoff; only the sink's
+	// loop touches it.
+	retries []pendingAlert
+}
+
+type pendingAlert struct {
+	ev      alertEvent
+	msg     string
+	attempt int
+	backoff time.Duration
+	due     time.Time
+}
+
 type alertEvent struct {
 	event  string
 	fields map[string]string
+	at     time.Time
 }
 
 func NewManager(mode, symbol string, notifier Notifier) *Manager {
-	return NewManagerWithOptions(mode, symbol, notifier, ManagerOptions{
+	return NewManagerWithOptions(mode, symbol, []Notifier{notifier}, ManagerOptions{
 		QueueSize:          defaultAlertQueueSize,
 		DropReportInterval: defaultDropReportInterval,
 	})
 }
 
-func NewManagerWithOptions(mode, symbol string, notifier Notifier, opts ManagerOptions) *Manager {
-	if notifier == nil {
-		return nil
-	}
+// NewManagerWithOptions returns nil when notifiers holds no non-nil entry.
+func NewManagerWithOptions(mode, symbol string, notifiers []Notifier, opts ManagerOptions) *Manager {
 	queueSize := opts.QueueSize
 	if queueSize <= 0 {
 		queueSize = defaultAlertQueueSize
@@ -67,17 +112,47 @@ func NewManagerWithOptions(mode, symbol string, notifier Notifier, opts ManagerO
 	if reportInterval < 0 {
 		reportInterval = 0
 	}
+	maxRetries := opts.MaxRetries
+	switch {
+	case maxRetries < 0:
+		maxRetries = 0
+	case maxRetries == 0:
+		maxRetries = defaultNotifyRetries
+	case maxRetries > maxNotifyRetries:
+		maxRetries = maxNotifyRetries
+	}
+	retryBackoff := opts.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = defaultNotifyRetryBackoff
+	}
+	var sinks []*sink
+	for _, n := range notifiers {
+		if n == nil {
+			continue
+		}
+		sinks = append(sinks, &sink{
+			name:     notifierName(n, len(sinks)),
+			notifier: n,
+			queue:    make(chan alertEvent, queueSize),
+		})
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
 	m := &Manager{
 		mode:               mode,
 		symbol:             symbol,
-		notifier:           notifier,
-		queue:              make(chan alertEvent, queueSize),
+		sinks:              sinks,
 		stop:               make(chan struct{}),
 		done:               make(chan struct{}),
 		dropReportInterval: reportInterval,
+		maxRetries:         maxRetries,
+		retryBackoff:       retryBackoff,
+	}
+	for _, sk := range m.sinks {
+		m.wg.Add(1)
+		go m.loop(sk)
 	}
-	m.wg.Add(1)
-	go m.loop()
 	if m.dropReportInterval > 0 {
 		m.wg.Add(1)
 		go m.dropReportLoop()
@@ -90,35 +165,37 @@ func NewManagerWithOptions(mode, symbol string, notifier Notifier, opts ManagerO
 }
 
 func (m *Manager) Important(event string, fields map[string]string) {
-	if m == nil || m.notifier == nil {
+	if m == nil || len(m.sinks) == 0 {
 		return
 	}
 	ev := alertEvent{
 		event:  event,
 		fields: cloneFields(fields),
+		at:     time.Now().UTC(),
 	}
 	m.mu.RLock()
+	defer m.mu.RUnlock()
 	if m.closed {
-		m.mu.RUnlock()
 		return
 	}
-	select {
-	case m.queue <- ev:
-		m.mu.RUnlock()
-		return
-	default:
+	for _, sk := range m.sinks {
+		select {
+		case sk.queue <- ev:
+			continue
+		default:
+		}
 		droppedTotal := atomic.AddUint64(&m.droppedTotal, 1)
 		droppedInWindow := atomic.AddUint64(&m.droppedSinceReported, 1)
-		m.mu.RUnlock()
 		// Report first dropped alert in a window immediately; periodic summary emits total drops in window.
 		if droppedInWindow == 1 {
 			log.Printf(
-				"level=WARN event=alert_queue_dropped target_event=%q reason=%q dropped_total=%d queue_len=%d queue_cap=%d",
+				"level=WARN event=alert_queue_dropped target_event=%q transport=%s reason=%q dropped_total=%d queue_len=%d queue_cap=%d",
 				event,
+				sk.name,
 				"queue_full",
 				droppedTotal,
-				len(m.queue),
-				cap(m.queue),
+				len(sk.queue),
+				cap(sk.queue),
 			)
 		}
 	}
@@ -146,22 +223,36 @@ func (m *Manager) Close(ctx context.Context) error {
 	}
 }
 
-func (m *Manager) loop() {
+// loop sends a sink's alerts in order. A transient failure is parked with
+// its backoff instead of slept on, so later alerts go out meanwhile; on stop
+// the queue is drained and the loop exits once the parked retries are done.
+func (m *Manager) loop(sk *sink) {
 	defer m.wg.Done()
+	stop := m.stop
 	for {
+		if stop == nil && len(sk.retries) == 0 {
+			m.reportDroppedSummary()
+			return
+		}
+		var retry <-chan time.Time
+		if len(sk.retries) > 0 {
+			retry = time.After(time.Until(sk.nextRetry()))
+		}
 		select {
-		case ev := <-m.queue:
-			m.send(ev)
-		case <-m.stop:
-			for {
+		case ev := <-sk.queue:
+			m.send(sk, ev)
+		case <-retry:
+			m.retryDue(sk, time.Now())
+		case <-stop:
+			for drained := false; !drained; {
 				select {
-				case ev := <-m.queue:
-					m.send(ev)
+				case ev := <-sk.queue:
+					m.send(sk, ev)
 				default:
-					m.reportDroppedSummary()
-					return
+					drained = true
 				}
 			}
+			stop = nil
 		}
 	}
 }
@@ -187,13 +278,18 @@ func (m *Manager) reportDroppedSummary() {
 		return
 	}
 	droppedTotal := atomic.LoadUint64(&m.droppedTotal)
+	queued := 0
+	for _, sk := range m.sinks {
+		queued += len(sk.queue)
+	}
 	log.Printf(
-		"level=WARN event=alert_queue_dropped_report dropped_since_last=%d dropped_total=%d report_interval_sec=%d queue_len=%d queue_cap=%d",
+		"level=WARN event=alert_queue_dropped_report dropped_since_last=%d dropped_total=%d report_interval_sec=%d queue_len=%d queue_cap=%d transports=%d",
 		dropped,
 		droppedTotal,
 		int64(m.dropReportInterval/time.Second),
-		len(m.queue),
-		cap(m.queue),
+		queued,
+		cap(m.sinks[0].queue)*len(m.sinks),
+		len(m.sinks),
 	)
 }
 
@@ -204,19 +300,107 @@ func (m *Manager) droppedStats() (uint64, uint64) {
 	return atomic.LoadUint64(&m.droppedTotal), atomic.LoadUint64(&m.droppedSinceReported)
 }
 
-func (m *Manager) send(ev alertEvent) {
-	msg := m.buildMessage(ev.event, ev.fields)
+func (m *Manager) send(sk *sink, ev alertEvent) {
+	m.attempt(sk, pendingAlert{
+		ev:      ev,
+		msg:     m.buildMessage(ev.event, ev.fields, ev.at),
+		backoff: m.retryBackoff,
+	})
+}
+
+func (m *Manager) attempt(sk *sink, p pendingAlert) {
+	err := m.notifyOnce(sk.notifier, p.ev, p.msg)
+	if err == nil {
+		return
+	}
+	if p.attempt >= m.maxRetries || !isTransientNotifyError(err) {
+		droppedTotal := atomic.AddUint64(&m.droppedTotal, 1)
+		atomic.AddUint64(&m.droppedSinceReported, 1)
+		log.Printf(
+			"level=ERROR event=alert_notify_failed target_event=%q transport=%s attempts=%d dropped_total=%d err=%q",
+			p.ev.event,
+			sk.name,
+			p.attempt+1,
+			droppedTotal,
+			err.Error(),
+		)
+		return
+	}
+	log.Printf("level=WARN event=alert_notify_retry target_event=%q transport=%s attempt=%d backoff=%s err=%q", p.ev.event, sk.name, p.attempt+1, p.backoff, err.Error())
+	p.attempt++
+	p.due = time.Now().Add(p.backoff)
+	p.backoff *= 2
+	sk.retries = append(sk.retries, p)
+}
+
+func (sk *sink) nextRetry() time.Time {
+	next := sk.retries[0].due
+	for _, p := range sk.retries[1:] {
+		if p.due.Before(next) {
+			next = p.due
+		}
+	}
+	return next
+}
+
+// retryDue re-attempts the parked alerts whose backoff has passed, oldest
+// first.
+func (m *Manager) retryDue(sk *sink, now time.Time) {
+	var due []pendingAlert
+	kept := sk.retries[:0]
+	for _, p := range sk.retries {
+		if p.due.After(now) {
+			kept = append(kept, p)
+		} else {
+			due = append(due, p)
+		}
+	}
+	sk.retries = kept
+	for _, p := range due {
+		m.attempt(sk, p)
+	}
+}
+
+func (m *Manager) notifyOnce(n Notifier, ev alertEvent, msg string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
-	if err := m.notifier.Notify(ctx, msg); err != nil {
-		log.Printf("level=ERROR event=alert_notify_failed target_event=%q err=%q", ev.event, err.Error())
+	if en, ok := n.(EventNotifier); ok {
+		return en.NotifyEvent(ctx, Event{
+			Event:  ev.event,
+			Fields: ev.fields,
+			Mode:   m.mode,
+			Symbol: m.symbol,
+			TS:     ev.at,
+			Text:   msg,
+		})
+	}
+	return n.Notify(ctx, msg)
+}
+
+// notifierName labels a transport in logs: its Name() if it has one,
+// otherwise its position.
+func notifierName(n Notifier, idx int) string {
+	if named, ok := n.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return "notifier" + strconv.Itoa(idx)
+}
+
+func isTransientNotifyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var transient interface{ Transient() bool }
+	if errors.As(err, &transient) {
+		return transient.Transient()
 	}
+	return true
 }
 
-func (m *Manager) buildMessage(event string, fields map[string]string) string {
+func (m *Manager) buildMessage(event string, fields map[string]string, at time.Time) string {
 	lines := []string{
 		"[grid-trading] important",
-		"time: " + time.Now().UTC().Format(time.RFC3339),
+		"time: " + at.UTC().Format(time.RFC3339),
 		"mode: " + m.mode,
 		"symbol: " + m.symbol,
 		"event: " + event,
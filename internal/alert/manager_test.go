@@ -3,7 +3,10 @@ package alert
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
@@ -14,6 +17,9 @@ type notifierSpy struct {
 	block   <-chan struct{}
 	entered chan struct{}
 	once    sync.Once
+	failN   int
+	err     error
+	calls   int
 
 	mu   sync.Mutex
 	msgs []string
@@ -33,11 +39,33 @@ func (n *notifierSpy) Notify(ctx context.Context, msg string) error {
 		}
 	}
 	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls++
+	if n.calls <= n.failN {
+		return n.err
+	}
 	n.msgs = append(n.msgs, msg)
-	n.mu.Unlock()
 	return nil
 }
 
+// syncBuffer lets a test read log output while manager goroutines write it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 func (n *notifierSpy) count() int {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -123,7 +151,7 @@ func TestManagerTracksDroppedCountAndPendingWindow(t *testing.T) {
 		block:   block,
 		entered: make(chan struct{}),
 	}
-	m := NewManagerWithOptions("live", "BTCUSDT", spy, ManagerOptions{
+	m := NewManagerWithOptions("live", "BTCUSDT", []Notifier{spy}, ManagerOptions{
 		QueueSize:          1,
 		DropReportInterval: 0,
 	})
@@ -161,7 +189,7 @@ func TestManagerTracksDroppedCountAndPendingWindow(t *testing.T) {
 }
 
 func TestManagerPeriodicDroppedReportEmitsAndResetsWindow(t *testing.T) {
-	var logs bytes.Buffer
+	var logs syncBuffer
 	origOutput := log.Writer()
 	origFlags := log.Flags()
 	log.SetOutput(&logs)
@@ -176,7 +204,7 @@ func TestManagerPeriodicDroppedReportEmitsAndResetsWindow(t *testing.T) {
 		block:   block,
 		entered: make(chan struct{}),
 	}
-	m := NewManagerWithOptions("live", "BTCUSDT", spy, ManagerOptions{
+	m := NewManagerWithOptions("live", "BTCUSDT", []Notifier{spy}, ManagerOptions{
 		QueueSize:          1,
 		DropReportInterval: 40 * time.Millisecond,
 	})
@@ -219,3 +247,165 @@ func TestManagerPeriodicDroppedReportEmitsAndResetsWindow(t *testing.T) {
 		t.Fatalf("Close() error = %v", err)
 	}
 }
+
+func TestManagerRetriesTransientNotifierFailure(t *testing.T) {
+	spy := &notifierSpy{
+		failN: 1,
+		err:   &StatusError{Service: "telegram", StatusCode: 502, Body: "bad gateway"},
+	}
+	m := NewManagerWithOptions("live", "BTCUSDT", []Notifier{spy}, ManagerOptions{
+		MaxRetries:   2,
+		RetryBackoff: 5 * time.Millisecond,
+	})
+	m.Important("runner_started", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if spy.count() != 1 {
+		t.Fatalf("delivered count = %d, want 1", spy.count())
+	}
+	if total, _ := m.droppedStats(); total != 0 {
+		t.Fatalf("dropped total = %d, want 0", total)
+	}
+}
+
+// flakyFirstNotifier fails the first attempt of each message containing
+// "flaky" and records deliveries in order.
+type flakyFirstNotifier struct {
+	mu        sync.Mutex
+	failed    map[string]bool
+	delivered []string
+}
+
+func (n *flakyFirstNotifier) Notify(_ context.Context, msg string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if strings.Contains(msg, "flaky") && !n.failed[msg] {
+		n.failed[msg] = true
+		return &StatusError{Service: "telegram", StatusCode: 503, Body: "unavailable"}
+	}
+	for _, line := range strings.Split(msg, "\n") {
+		if strings.HasPrefix(line, "event: ") {
+			n.delivered = append(n.delivered, strings.TrimPrefix(line, "event: "))
+		}
+	}
+	return nil
+}
+
+func (n *flakyFirstNotifier) events() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.delivered...)
+}
+
+func TestManagerRetryBackoffDoesNotHoldUpQueuedAlerts(t *testing.T) {
+	n := &flakyFirstNotifier{failed: map[string]bool{}}
+	m := NewManagerWithOptions("live", "BTCUSDT", []Notifier{n}, ManagerOptions{
+		MaxRetries:   2,
+		RetryBackoff: 300 * time.Millisecond,
+	})
+	m.Important("flaky_event", nil)
+	m.Important("next_event", nil)
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) && len(n.events()) == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := n.events(); len(got) != 1 || got[0] != "next_event" {
+		t.Fatalf("delivered within the backoff = %v, want [next_event]", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := n.events(); len(got) != 2 || got[1] != "flaky_event" {
+		t.Fatalf("delivered = %v, want the retried flaky_event after next_event", got)
+	}
+	if total, _ := m.droppedStats(); total != 0 {
+		t.Fatalf("dropped total = %d, want 0", total)
+	}
+}
+
+func TestManagerCountsDropAfterNonTransientFailure(t *testing.T) {
+	spy := &notifierSpy{
+		failN: 10,
+		err:   &StatusError{Service: "telegram", StatusCode: 400, Body: "bad request"},
+	}
+	m := NewManagerWithOptions("live", "BTCUSDT", []Notifier{spy}, ManagerOptions{
+		MaxRetries:         3,
+		RetryBackoff:       time.Millisecond,
+		DropReportInterval: 0,
+	})
+	m.Important("runner_started", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	spy.mu.Lock()
+	calls := spy.calls
+	spy.mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("notify calls = %d, want 1 for non-transient failure", calls)
+	}
+	if total, _ := m.droppedStats(); total != 1 {
+		t.Fatalf("dropped total = %d, want 1", total)
+	}
+}
+
+func TestManagerFansOutToWebhookWhileOtherTransportBlocks(t *testing.T) {
+	got := make(chan Event, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev Event
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		got <- ev
+	}))
+	defer srv.Close()
+
+	block := make(chan struct{})
+	stuck := &notifierSpy{block: block, entered: make(chan struct{})}
+	m := NewManagerWithOptions("live", "BTCUSDT", []Notifier{
+		stuck,
+		NewWebhookNotifier(true, srv.URL, time.Second),
+	}, ManagerOptions{DropReportInterval: 0})
+	if m == nil {
+		t.Fatalf("NewManagerWithOptions() returned nil")
+	}
+
+	m.Important("fill_gap", map[string]string{"level": "-3"})
+	select {
+	case <-stuck.entered:
+	case <-time.After(time.Second):
+		t.Fatalf("first transport did not receive the alert")
+	}
+	select {
+	case ev := <-got:
+		if ev.Event != "fill_gap" || ev.Mode != "live" || ev.Symbol != "BTCUSDT" || ev.Fields["level"] != "-3" {
+			t.Fatalf("webhook payload = %+v", ev)
+		}
+		if ev.TS.IsZero() || !strings.Contains(ev.Text, "event: fill_gap") {
+			t.Fatalf("webhook payload missing ts/text: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("webhook was held up by the blocked transport")
+	}
+
+	close(block)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if stuck.count() != 1 {
+		t.Fatalf("blocked transport delivered %d alerts, want 1", stuck.count())
+	}
+}
@@ -32,6 +32,8 @@ func NewTelegramNotifier(enabled bool, botToken, chatID, baseURL string, timeout
 	}
 }
 
+func (t *TelegramNotifier) Name() string { return "telegram" }
+
 func (t *TelegramNotifier) Notify(ctx context.Context, msg string) error {
 	if t == nil || !t.enabled {
 		return nil
@@ -58,7 +60,11 @@ func (t *TelegramNotifier) Notify(ctx context.Context, msg string) error {
 
 	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("telegram status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		return &StatusError{
+			Service:    "telegram",
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(respBody)),
+		}
 	}
 	if len(respBody) == 0 {
 		return nil
@@ -73,6 +79,20 @@ func (t *TelegramNotifier) Notify(ctx context.Context, msg string) error {
 	return nil
 }
 
+type StatusError struct {
+	Service    string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s status=%d body=%s", e.Service, e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
 type telegramSendMessageRequest struct {
 	ChatID string `json:"chat_id"`
 	Text   string `json:"text"`
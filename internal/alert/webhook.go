@@ -0,0 +1,68 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookNotifier POSTs each alert as JSON {event, fields, mode, symbol, ts,
+// text} to a URL, e.g. a Slack incoming webhook, which shows text.
+type WebhookNotifier struct {
+	enabled bool
+	url     string
+	client  *http.Client
+}
+
+func NewWebhookNotifier(enabled bool, url string, timeout time.Duration) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &WebhookNotifier{
+		enabled: enabled,
+		url:     strings.TrimSpace(url),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *WebhookNotifier) Name() string { return "webhook" }
+
+// Notify posts a text-only payload; the manager uses NotifyEvent.
+func (w *WebhookNotifier) Notify(ctx context.Context, msg string) error {
+	return w.NotifyEvent(ctx, Event{TS: time.Now().UTC(), Text: msg})
+}
+
+func (w *WebhookNotifier) NotifyEvent(ctx context.Context, ev Event) error {
+	if w == nil || !w.enabled {
+		return nil
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{
+			Service:    "webhook",
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(respBody)),
+		}
+	}
+	return nil
+}
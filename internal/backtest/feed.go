@@ -2,8 +2,10 @@ package backtest
 
 import (
 	"bufio"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -18,6 +20,10 @@ import (
 type Tick struct {
 	Time  time.Time
 	Price decimal.Decimal
+	// Volume is the bar/trade volume when the data line carries one;
+	// HasVolume is false for price-only data.
+	Volume    decimal.Decimal
+	HasVolume bool
 }
 
 type Feed interface {
@@ -29,9 +35,29 @@ type JSONLFeed struct {
 	paths   []string
 	index   int
 	file    *os.File
+	gz      *gzip.Reader
 	scanner *bufio.Scanner
+	line    int
+
+	// lastTime/lastPath remember the previous file's final tick so a data
+	// set whose files overlap or are out of order fails at the seam.
+	lastTime    time.Time
+	lastPath    string
+	seamPending bool
+
+	rejectNonPositive bool
 }
 
+// SetRejectNonPositive makes Next fail on a zero or negative price instead of
+// passing it on for the runner to skip.
+func (f *JSONLFeed) SetRejectNonPositive(reject bool) {
+	f.rejectNonPositive = reject
+}
+
+// NewJSONLFeed streams ticks from path, which may be a single file, a
+// directory (every *.jsonl and *.jsonl.gz in it, in lexical order) or a glob
+// pattern such as data/binance/BTCUSDT/1m/2024-0*.jsonl. Files are read one
+// at a time; gzip-compressed files are detected and decompressed.
 func NewJSONLFeed(path string) (*JSONLFeed, error) {
 	paths, err := resolveJSONLPaths(path)
 	if err != nil {
@@ -48,6 +74,10 @@ func (f *JSONLFeed) Close() error {
 	if f.file == nil {
 		return nil
 	}
+	if f.gz != nil {
+		_ = f.gz.Close()
+		f.gz = nil
+	}
 	err := f.file.Close()
 	f.file = nil
 	f.scanner = nil
@@ -67,11 +97,14 @@ func (f *JSONLFeed) Next() (Tick, error) {
 			}
 			_ = f.Close()
 			f.index++
+			f.line = 0
+			f.seamPending = !f.lastTime.IsZero()
 			if f.index >= len(f.paths) {
 				return Tick{}, io.EOF
 			}
 			continue
 		}
+		f.line++
 		line := strings.TrimSpace(f.scanner.Text())
 		if line == "" {
 			continue
@@ -101,7 +134,23 @@ func (f *JSONLFeed) Next() (Tick, error) {
 		if !hasPrice {
 			continue
 		}
-		return Tick{Time: ts, Price: price}, nil
+		if f.rejectNonPositive && price.Cmp(decimal.Zero) <= 0 {
+			return Tick{}, fmt.Errorf("%s:%d: non-positive price %s", f.paths[f.index], f.line, price)
+		}
+		if f.seamPending {
+			f.seamPending = false
+			if ts.Before(f.lastTime) {
+				return Tick{}, fmt.Errorf("%s:%d: time %s is before %s at the end of %s; data files must be time-ordered",
+					f.paths[f.index], f.line, ts.UTC().Format(time.RFC3339), f.lastTime.UTC().Format(time.RFC3339), f.lastPath)
+			}
+		}
+		f.lastTime = ts
+		f.lastPath = f.paths[f.index]
+		tick := Tick{Time: ts, Price: price}
+		if v, found := first(raw, "volume", "vol", "v"); found {
+			tick.Volume, tick.HasVolume = parseDecimalValue(v)
+		}
+		return tick, nil
 	}
 }
 
@@ -113,7 +162,18 @@ func (f *JSONLFeed) openCurrent() error {
 	if err != nil {
 		return err
 	}
-	scanner := bufio.NewScanner(file)
+	br := bufio.NewReader(file)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("%s: %w", f.paths[f.index], err)
+		}
+		f.gz = gz
+		r = gz
+	}
+	scanner := bufio.NewScanner(r)
 	buf := make([]byte, 0, 1024*1024)
 	scanner.Buffer(buf, 10*1024*1024)
 	f.file = file
@@ -122,6 +182,23 @@ func (f *JSONLFeed) openCurrent() error {
 }
 
 func resolveJSONLPaths(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("data path pattern %q: %w", path, err)
+		}
+		paths := make([]string, 0, len(matches))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				paths = append(paths, m)
+			}
+		}
+		sort.Strings(paths)
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no files match %q", path)
+		}
+		return paths, nil
+	}
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
@@ -139,7 +216,8 @@ func resolveJSONLPaths(path string) ([]string, error) {
 			continue
 		}
 		name := e.Name()
-		if !strings.HasSuffix(strings.ToLower(name), ".jsonl") {
+		lower := strings.ToLower(name)
+		if !strings.HasSuffix(lower, ".jsonl") && !strings.HasSuffix(lower, ".jsonl.gz") {
 			continue
 		}
 		paths = append(paths, filepath.Join(path, name))
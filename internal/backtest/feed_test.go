@@ -0,0 +1,78 @@
+package backtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLFeedReadsPlainAndGzippedFilesAlike(t *testing.T) {
+	dir := t.TempDir()
+	day1 := `{"time":1704067200000,"close":"100","volume":"1.5"}` + "\n" +
+		`{"time":1704067260000,"close":"101","volume":"2"}` + "\n"
+	day2 := `{"t":1704153600,"p":"102"}` + "\n" +
+		"\n" +
+		`not json` + "\n" +
+		`{"time":"2024-01-02T00:01:00Z","price":"103","v":"0.25"}` + "\n"
+
+	if err := os.WriteFile(filepath.Join(dir, "2024-01-01.jsonl"), []byte(day1), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	if _, err := zw.Write([]byte(day2)); err != nil {
+		t.Fatalf("gzip Write() error = %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip Close() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2024-01-02.jsonl.gz"), gz.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	feed, err := NewJSONLFeed(dir)
+	if err != nil {
+		t.Fatalf("NewJSONLFeed() error = %v", err)
+	}
+	defer feed.Close()
+	var got []string
+	for {
+		tick, err := feed.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		vol := "-"
+		if tick.HasVolume {
+			vol = tick.Volume.String()
+		}
+		got = append(got, tick.Time.UTC().Format(time.RFC3339)+" "+tick.Price.String()+" "+vol)
+	}
+	want := []string{
+		"2024-01-01T00:00:00Z 100 1.5",
+		"2024-01-01T00:01:00Z 101 2",
+		"2024-01-02T00:00:00Z 102 -",
+		"2024-01-02T00:01:00Z 103 0.25",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("ticks =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestJSONLFeedRejectsCorruptGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.jsonl.gz")
+	if err := os.WriteFile(path, []byte{0x1f, 0x8b, 0x00, 0x01}, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := NewJSONLFeed(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("NewJSONLFeed() error = %v, want gzip error naming %s", err, path)
+	}
+}
@@ -25,6 +25,13 @@ type SimExchange struct {
 	feePaid     decimal.Decimal
 	marketBuyN  int
 	marketBuyQ  decimal.Decimal
+
+	minFillVolume decimal.Decimal
+	illiquidTicks int
+
+	// clock is the time of the latest tick; orders are stamped with it so
+	// their age follows the data rather than the wall clock.
+	clock time.Time
 }
 
 func NewSimExchange(symbol string, balance core.Balance, rules core.Rules) *SimExchange {
@@ -52,6 +59,22 @@ func (s *SimExchange) SetFees(makerRate, takerRate decimal.Decimal) error {
 	return nil
 }
 
+// SetMinFillVolume stops limit orders from filling on a tick whose volume is
+// below min; they stay open for the next tick with enough volume. Ticks
+// without a volume field always match. 0 disables.
+func (s *SimExchange) SetMinFillVolume(min decimal.Decimal) error {
+	if min.Cmp(decimal.Zero) < 0 {
+		return errors.New("min fill volume must be >= 0")
+	}
+	s.minFillVolume = min
+	return nil
+}
+
+// IlliquidTicks counts ticks that skipped matching for low volume.
+func (s *SimExchange) IlliquidTicks() int {
+	return s.illiquidTicks
+}
+
 type Snapshot struct {
 	FreeBase      decimal.Decimal
 	FreeQuote     decimal.Decimal
@@ -105,15 +128,20 @@ func (s *SimExchange) PlaceOrder(ctx context.Context, order core.Order) (core.Or
 		return core.Order{}, errors.New("invalid order price")
 	}
 	order.Price = price
+	if order.PostOnly && s.lastPrice.Cmp(decimal.Zero) > 0 {
+		if (order.Side == core.Buy && price.Cmp(s.lastPrice) >= 0) || (order.Side == core.Sell && price.Cmp(s.lastPrice) <= 0) {
+			return core.Order{}, fmt.Errorf("%w: post-only %s at %s crosses last price %s", core.ErrWouldMatch, order.Side, price, s.lastPrice)
+		}
+	}
 	s.orderSeq++
 	order.ID = fmt.Sprintf("bt-%d", s.orderSeq)
-	order.CreatedAt = time.Now()
+	order.CreatedAt = s.now()
 	if order.Type == core.Market {
 		if err := s.applyMarketFill(&order); err != nil {
 			return core.Order{}, err
 		}
 		order.Status = core.OrderFilled
-		filledAt := time.Now()
+		filledAt := order.CreatedAt
 		order.FilledAt = &filledAt
 		return order, nil
 	}
@@ -179,8 +207,33 @@ func (s *SimExchange) MarketBuyStats() (int, decimal.Decimal) {
 	return s.marketBuyN, s.marketBuyQ
 }
 
+// SetClock sets the time new orders are stamped with until the next match.
+func (s *SimExchange) SetClock(ts time.Time) {
+	s.clock = ts
+}
+
+func (s *SimExchange) now() time.Time {
+	if s.clock.IsZero() {
+		return time.Now()
+	}
+	return s.clock
+}
+
+// MatchTick is Match with the tick's volume checked against the minimum
+// fill volume first.
+func (s *SimExchange) MatchTick(tick Tick) []core.Trade {
+	if s.minFillVolume.Cmp(decimal.Zero) > 0 && tick.HasVolume && tick.Volume.Cmp(s.minFillVolume) < 0 {
+		s.lastPrice = tick.Price
+		s.clock = tick.Time
+		s.illiquidTicks++
+		return nil
+	}
+	return s.Match(tick.Price, tick.Time)
+}
+
 func (s *SimExchange) Match(price decimal.Decimal, ts time.Time) []core.Trade {
 	s.lastPrice = price
+	s.clock = ts
 	trades := make([]core.Trade, 0)
 	for id, ord := range s.openOrders {
 		if shouldFill(ord, price) {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net"
 	"net/url"
 	"os"
 	"strings"
@@ -15,7 +16,14 @@ import (
 type Mode string
 
 type GridMode string
+type GridBias string
+type AmendmentAction string
+type LevelMapping string
+type TickPriceSource string
 type UserStreamAuth string
+type InvalidPriceAction string
+type OrderSlotAction string
+type StopInventoryAction string
 
 const (
 	ModeBacktest Mode = "backtest"
@@ -27,16 +35,53 @@ const (
 	GridGeo GridMode = "geometric"
 )
 
+const (
+	GridBiasBuyDip    GridBias = "buy_dip"
+	GridBiasSellRally GridBias = "sell_rally"
+)
+
+const (
+	AmendmentRealign AmendmentAction = "realign"
+	AmendmentReplace AmendmentAction = "replace"
+)
+
+const (
+	LevelMappingExact   LevelMapping = "exact"
+	LevelMappingNearest LevelMapping = "nearest"
+)
+
+const (
+	TickPriceLast TickPriceSource = "last"
+	TickPriceMid  TickPriceSource = "mid"
+	TickPriceMark TickPriceSource = "mark"
+)
+
 const (
 	UserStreamAuthSignature UserStreamAuth = "signature"
 	UserStreamAuthSession   UserStreamAuth = "session"
 )
 
+const (
+	InvalidPriceSkip  InvalidPriceAction = "skip"
+	InvalidPriceError InvalidPriceAction = "error"
+)
+
+const (
+	StopHold       StopInventoryAction = "hold"
+	StopMarketSell StopInventoryAction = "market_sell"
+)
+
+const (
+	OrderSlotRefuse OrderSlotAction = "refuse"
+	OrderSlotShrink OrderSlotAction = "shrink"
+)
+
 type Config struct {
 	Mode           Mode                 `yaml:"mode"`
 	Symbol         string               `yaml:"symbol"`
 	InstanceID     string               `yaml:"instance_id"`
 	Grid           GridConfig           `yaml:"grid"`
+	Capital        CapitalConfig        `yaml:"capital"`
 	Backtest       BacktestConfig       `yaml:"backtest"`
 	Exchange       ExchangeConfig       `yaml:"exchange"`
 	State          StateConfig          `yaml:"state"`
@@ -46,6 +91,9 @@ type Config struct {
 
 type GridConfig struct {
 	StopPrice        Decimal  `yaml:"stop_price"`
+	FloorPrice       Decimal  `yaml:"floor_price"`
+	TakeProfitPrice  Decimal  `yaml:"take_profit_price"`
+	AnchorPrice      Decimal  `yaml:"anchor_price"`
 	Ratio            Decimal  `yaml:"ratio"`
 	RatioStep        *Decimal `yaml:"ratio_step"`
 	RatioQtyMultiple Decimal  `yaml:"ratio_qty_multiple"`
@@ -53,8 +101,86 @@ type GridConfig struct {
 	Levels           int      `yaml:"levels"`
 	ShiftLevels      int      `yaml:"shift_levels"`
 	Mode             GridMode `yaml:"mode"`
+	Bias             GridBias `yaml:"bias"`
 	Qty              Decimal  `yaml:"qty"`
+	BuyQty           *Decimal `yaml:"buy_qty"`
+	BuyQtyGrowth     Decimal  `yaml:"buy_qty_growth"`
+	SellQty          *Decimal `yaml:"sell_qty"`
 	MinQtyMultiple   int64    `yaml:"min_qty_multiple"`
+	MaxOrderQty      Decimal  `yaml:"max_order_qty"`
+
+	StopCancelUntracked       bool                        `yaml:"stop_cancel_untracked"`
+	BootstrapMarketBuy        *bool                       `yaml:"bootstrap_market_buy"`
+	BootstrapRetrySec         *int64                      `yaml:"bootstrap_retry_sec"`
+	BootstrapSettleMs         int64                       `yaml:"bootstrap_settle_ms"`
+	ShiftUpCooldownSec        int64                       `yaml:"shift_up_cooldown_sec"`
+	BootstrapReanchorPct      Decimal                     `yaml:"bootstrap_reanchor_pct"`
+	SellInventoryGuard        *bool                       `yaml:"sell_inventory_guard"`
+	MakerRetry                bool                        `yaml:"maker_retry"`
+	PostOnly                  bool                        `yaml:"post_only"`
+	SnapAnchorToTick          bool                        `yaml:"snap_anchor_to_tick"`
+	SweepDustOnStop           bool                        `yaml:"sweep_dust_on_stop"`
+	OnStop                    StopInventoryAction         `yaml:"on_stop"`
+	OnStopMaxSlippagePct      *Decimal                    `yaml:"on_stop_max_slippage_pct"`
+	ExternalAmendment         AmendmentAction             `yaml:"external_amendment"`
+	LevelMapping              LevelMapping                `yaml:"level_mapping"`
+	TickPriceSource           TickPriceSource             `yaml:"tick_price_source"`
+	TickerMaxDivergencePct    *Decimal                    `yaml:"ticker_max_divergence_pct"`
+	OversizedFillTolerancePct *Decimal                    `yaml:"oversized_fill_tolerance_pct"`
+	SuspiciousSnapshotPct     *Decimal                    `yaml:"suspicious_snapshot_pct"`
+	RebuildMinIntervalSec     int                         `yaml:"rebuild_min_interval_sec"`
+	MinHoldSec                int64                       `yaml:"min_hold_sec"`
+	MaxRestingBuys            int                         `yaml:"max_resting_buys"`
+	MaxRestingSells           int                         `yaml:"max_resting_sells"`
+	VolatilityPause           VolatilityPauseConfig       `yaml:"volatility_pause"`
+	InventoryAdaptiveSell     InventoryAdaptiveSellConfig `yaml:"inventory_adaptive_sell"`
+	AutoBalance               AutoBalanceConfig           `yaml:"auto_balance"`
+	AdaptiveShift             AdaptiveShiftConfig         `yaml:"adaptive_shift"`
+	OrderTTL                  OrderTTLConfig              `yaml:"order_ttl"`
+	Recenter                  RecenterConfig              `yaml:"recenter"`
+
+	// ShrunkFromLevels is the configured levels value when
+	// exchange.max_open_orders_action=shrink had to cut the grid; 0 otherwise.
+	ShrunkFromLevels int `yaml:"-"`
+}
+
+type OrderTTLConfig struct {
+	Enabled    bool  `yaml:"enabled"`
+	TTLSec     int64 `yaml:"ttl_sec"`
+	StaggerSec int64 `yaml:"stagger_sec"`
+}
+
+// RecenterConfig rebuilds the grid around the current price once price has
+// stayed beyond the window by DriftMultiple for SustainSec.
+type RecenterConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	DriftMultiple Decimal `yaml:"drift_multiple"`
+	SustainSec    int64   `yaml:"sustain_sec"`
+}
+
+type AdaptiveShiftConfig struct {
+	Enabled   bool  `yaml:"enabled"`
+	WindowSec int64 `yaml:"window_sec"`
+	MaxShifts int   `yaml:"max_shifts"`
+}
+
+type AutoBalanceConfig struct {
+	Enabled      bool    `yaml:"enabled"`
+	TolerancePct Decimal `yaml:"tolerance_pct"`
+}
+
+type InventoryAdaptiveSellConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Sensitivity Decimal `yaml:"sensitivity"`
+	MinFactor   Decimal `yaml:"min_factor"`
+	MaxFactor   Decimal `yaml:"max_factor"`
+}
+
+type VolatilityPauseConfig struct {
+	Enabled      bool    `yaml:"enabled"`
+	WindowSec    int64   `yaml:"window_sec"`
+	ThresholdPct Decimal `yaml:"threshold_pct"`
+	CooldownSec  int64   `yaml:"cooldown_sec"`
 }
 
 type BacktestConfig struct {
@@ -63,6 +189,27 @@ type BacktestConfig struct {
 	InitialQuote Decimal       `yaml:"initial_quote"`
 	Fees         BacktestFees  `yaml:"fees"`
 	Rules        BacktestRules `yaml:"rules"`
+	// Symbols runs the same grid over each symbol's dataset with its own
+	// isolated balances; when set, data_path is ignored.
+	Symbols []BacktestSymbol `yaml:"symbols"`
+	// InvalidPrice decides what a zero/negative price line in the data does:
+	// skip drops and counts it, error aborts the backtest.
+	InvalidPrice InvalidPriceAction `yaml:"invalid_price"`
+	// MinFillVolume keeps limit orders from filling on ticks whose volume is
+	// below it; 0 disables.
+	MinFillVolume Decimal `yaml:"min_fill_volume"`
+}
+
+type BacktestSymbol struct {
+	Symbol   string `yaml:"symbol"`
+	DataPath string `yaml:"data_path"`
+	// Prices and base quantities do not carry across symbols, so each leg
+	// sets its own; zero keeps the shared value, which a single symbol may use.
+	StopPrice    Decimal       `yaml:"stop_price"`
+	Qty          Decimal       `yaml:"qty"`
+	InitialBase  Decimal       `yaml:"initial_base"`
+	InitialQuote Decimal       `yaml:"initial_quote"`
+	Rules        BacktestRules `yaml:"rules"`
 }
 
 type BacktestFees struct {
@@ -80,6 +227,9 @@ type BacktestRules struct {
 type ExchangeConfig struct {
 	APIKey                 string         `yaml:"api_key"`
 	APISecret              string         `yaml:"api_secret"`
+	APIKeyEnv              string         `yaml:"api_key_env"`
+	APISecretEnv           string         `yaml:"api_secret_env"`
+	CredentialsFile        string         `yaml:"credentials_file"`
 	RestBaseURL            string         `yaml:"rest_base_url"`
 	WSBaseURL              string         `yaml:"ws_base_url"`
 	UserStreamAuth         UserStreamAuth `yaml:"user_stream_auth"`
@@ -88,12 +238,38 @@ type ExchangeConfig struct {
 	HTTPTimeoutSec         int64          `yaml:"http_timeout_sec"`
 	UserStreamKeepaliveSec int64          `yaml:"user_stream_keepalive_sec"`
 	OrderWSKeepaliveSec    int64          `yaml:"order_ws_keepalive_sec"`
+	UserStreamSilenceSec   int64          `yaml:"user_stream_silence_sec"`
+	UserStreamBacklogMax   int            `yaml:"user_stream_backlog_max"`
+	RulesRefreshSec        int64          `yaml:"rules_refresh_sec"`
+	RESTTimeoutRetries     *int           `yaml:"rest_timeout_retries"`
+	WSDialTimeoutSec       int64          `yaml:"ws_dial_timeout_sec"`
+	WSDialRetries          *int           `yaml:"ws_dial_retries"`
+	CancelConcurrency      int            `yaml:"cancel_concurrency"`
+	MaxRequestsPer10s      int            `yaml:"max_requests_per_10s"`
+	// MaxOpenOrders is the exchange's per-symbol open order cap; the grid
+	// (levels + shift_levels) plus OpenOrderHeadroom must fit under it.
+	MaxOpenOrders     int             `yaml:"max_open_orders"`
+	OpenOrderHeadroom *int            `yaml:"open_order_headroom"`
+	OpenOrderAction   OrderSlotAction `yaml:"max_open_orders_action"`
 }
 
 type StateConfig struct {
-	Dir          string `yaml:"dir"`
-	LockTakeover *bool  `yaml:"lock_takeover"`
-	LockStaleSec int64  `yaml:"lock_stale_sec"`
+	Dir           string `yaml:"dir"`
+	LockTakeover  *bool  `yaml:"lock_takeover"`
+	LockStaleSec  int64  `yaml:"lock_stale_sec"`
+	OrderAudit    bool   `yaml:"order_audit"`
+	WindowIntent  bool   `yaml:"window_change_intent"`
+	LedgerDetails bool   `yaml:"ledger_details"`
+
+	// CancelOrdersOnExit cancels all open orders on SIGTERM/SIGINT, within
+	// DrainTimeoutSec (0 = 30s).
+	CancelOrdersOnExit bool  `yaml:"cancel_orders_on_exit"`
+	DrainTimeoutSec    int64 `yaml:"drain_timeout_sec"`
+
+	DuplicateInstanceCheck bool `yaml:"duplicate_instance_check"`
+	AdoptExistingOrders    bool `yaml:"adopt_existing_orders"`
+	TwinCheckSurplus       int  `yaml:"twin_check_surplus"`
+	TwinCheckRounds        int  `yaml:"twin_check_rounds"`
 }
 
 type CircuitBreakerConfig struct {
@@ -103,11 +279,53 @@ type CircuitBreakerConfig struct {
 	MaxReconnectFailures int   `yaml:"max_reconnect_failures"`
 	ReconnectCooldownSec int64 `yaml:"reconnect_cooldown_sec"`
 	ReconnectProbePasses int   `yaml:"reconnect_probe_passes"`
+
+	MaxDrawdownPct Decimal `yaml:"max_drawdown_pct"`
+}
+
+// CapitalConfig caps how much capital the grid may deploy.
+type CapitalConfig struct {
+	// QuoteBudget bounds the summed notional of resting buys; 0 disables.
+	QuoteBudget Decimal `yaml:"quote_budget"`
+	// BalanceFloorQuote/Base pause new placements while the free balance
+	// checked on each heartbeat is below them; 0 disables.
+	BalanceFloorQuote Decimal `yaml:"balance_floor_quote"`
+	BalanceFloorBase  Decimal `yaml:"balance_floor_base"`
+}
+
+// BalanceFloorEnabled reports whether either balance floor is set.
+func (c CapitalConfig) BalanceFloorEnabled() bool {
+	return c.BalanceFloorQuote.Cmp(decimal.Zero) > 0 || c.BalanceFloorBase.Cmp(decimal.Zero) > 0
 }
 
 type ObservabilityConfig struct {
-	Telegram TelegramConfig `yaml:"telegram"`
-	Runtime  RuntimeConfig  `yaml:"runtime"`
+	Telegram       TelegramConfig `yaml:"telegram"`
+	Webhook        WebhookConfig  `yaml:"webhook"`
+	Runtime        RuntimeConfig  `yaml:"runtime"`
+	PushgatewayURL string         `yaml:"pushgateway_url"`
+	PushgatewayJob string         `yaml:"pushgateway_job"`
+	Metrics        MetricsConfig  `yaml:"metrics"`
+	Status         StatusConfig   `yaml:"status"`
+}
+
+// MetricsConfig serves the runner's metrics on listen_addr/metrics for
+// Prometheus to scrape.
+type MetricsConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ListenAddr string `yaml:"listen_addr"`
+}
+
+// StatusConfig serves the runtime status and grid snapshot as JSON on
+// listen_addr/status, with /healthz and /readyz probes alongside.
+type StatusConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ListenAddr string `yaml:"listen_addr"`
+	// LivenessStaleSec fails /healthz once the runner loop has not woken
+	// for this long.
+	LivenessStaleSec int64 `yaml:"liveness_stale_sec"`
+	// ReadyTickMaxAgeSec fails /readyz once the last tick price is older
+	// than this.
+	ReadyTickMaxAgeSec int64 `yaml:"ready_tick_max_age_sec"`
 }
 
 type TelegramConfig struct {
@@ -118,10 +336,37 @@ type TelegramConfig struct {
 	TimeoutSec int64  `yaml:"timeout_sec"`
 }
 
+// WebhookConfig posts alerts as JSON to url (e.g. a Slack incoming webhook),
+// alongside Telegram when both are enabled.
+type WebhookConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	URL        string `yaml:"url"`
+	TimeoutSec int64  `yaml:"timeout_sec"`
+}
+
+// reconcileCeilingSec is the longest the live loop can go between periodic
+// reconciles.
+func (rt RuntimeConfig) reconcileCeilingSec() int64 {
+	return max(rt.ReconcileIntervalSec, rt.ReconcileMaxIntervalSec)
+}
+
 type RuntimeConfig struct {
-	HeartbeatSec         int64 `yaml:"heartbeat_sec"`
-	ReconcileIntervalSec int64 `yaml:"reconcile_interval_sec"`
-	AlertDropReportSec   int64 `yaml:"alert_drop_report_sec"`
+	HeartbeatSec         int64  `yaml:"heartbeat_sec"`
+	ReconcileIntervalSec int64  `yaml:"reconcile_interval_sec"`
+	AlertDropReportSec   int64  `yaml:"alert_drop_report_sec"`
+	RunSummary           bool   `yaml:"run_summary"`
+	ReportInUSD          bool   `yaml:"report_in_usd"`
+	USDRateSymbol        string `yaml:"usd_rate_symbol"`
+	MaxRunSec            int64  `yaml:"max_run_sec"`
+
+	ReconcileMinIntervalSec int64 `yaml:"reconcile_min_interval_sec"`
+	ReconcileMaxIntervalSec int64 `yaml:"reconcile_max_interval_sec"`
+	// ReconcileQuietSec skips periodic reconciles this long after the grid
+	// is bootstrapped; 0 disables.
+	ReconcileQuietSec int64 `yaml:"reconcile_quiet_sec"`
+
+	AlertNotifyRetries        *int  `yaml:"alert_notify_retries"`
+	AlertNotifyRetryBackoffMs int64 `yaml:"alert_notify_retry_backoff_ms"`
 }
 
 func Load(path string) (Config, error) {
@@ -129,6 +374,13 @@ func Load(path string) (Config, error) {
 	if err != nil {
 		return Config{}, err
 	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Config{}, err
+	}
+	if err := checkKnownFields(&doc); err != nil {
+		return Config{}, err
+	}
 	var cfg Config
 	dec := yaml.NewDecoder(bytes.NewReader(data))
 	dec.KnownFields(true)
@@ -142,6 +394,9 @@ func Load(path string) (Config, error) {
 		return Config{}, err
 	}
 	cfg.normalize()
+	if err := cfg.resolveCredentials(); err != nil {
+		return Config{}, err
+	}
 	cfg.applyDefaults()
 	if err := cfg.Validate(); err != nil {
 		return Config{}, err
@@ -149,21 +404,97 @@ func Load(path string) (Config, error) {
 	return cfg, nil
 }
 
+type credentialsFile struct {
+	APIKey    string `yaml:"api_key"`
+	APISecret string `yaml:"api_secret"`
+}
+
+// resolveCredentials fills api_key/api_secret from env vars or a separate
+// credentials file so secrets can stay out of the main config. Exactly one
+// source may be configured.
+func (c *Config) resolveCredentials() error {
+	ex := &c.Exchange
+	sources := 0
+	if ex.APIKey != "" || ex.APISecret != "" {
+		sources++
+	}
+	if ex.APIKeyEnv != "" || ex.APISecretEnv != "" {
+		sources++
+	}
+	if ex.CredentialsFile != "" {
+		sources++
+	}
+	if sources > 1 {
+		return fmt.Errorf("exchange credentials: set only one of api_key/api_secret, api_key_env/api_secret_env, or credentials_file")
+	}
+	if c.Mode == ModeBacktest {
+		return nil
+	}
+	switch {
+	case ex.APIKeyEnv != "" || ex.APISecretEnv != "":
+		if ex.APIKeyEnv == "" || ex.APISecretEnv == "" {
+			return fmt.Errorf("exchange api_key_env and api_secret_env must be set together")
+		}
+		ex.APIKey = strings.TrimSpace(os.Getenv(ex.APIKeyEnv))
+		ex.APISecret = strings.TrimSpace(os.Getenv(ex.APISecretEnv))
+		if ex.APIKey == "" {
+			return fmt.Errorf("exchange api_key_env %s is empty or unset", ex.APIKeyEnv)
+		}
+		if ex.APISecret == "" {
+			return fmt.Errorf("exchange api_secret_env %s is empty or unset", ex.APISecretEnv)
+		}
+	case ex.CredentialsFile != "":
+		data, err := os.ReadFile(ex.CredentialsFile)
+		if err != nil {
+			return fmt.Errorf("exchange credentials_file: %w", err)
+		}
+		var creds credentialsFile
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&creds); err != nil {
+			return fmt.Errorf("exchange credentials_file %s: %w", ex.CredentialsFile, err)
+		}
+		ex.APIKey = strings.TrimSpace(creds.APIKey)
+		ex.APISecret = strings.TrimSpace(creds.APISecret)
+	}
+	return nil
+}
+
 func (c *Config) normalize() {
 	c.Mode = Mode(strings.ToLower(strings.TrimSpace(string(c.Mode))))
 	c.Symbol = strings.ToUpper(strings.TrimSpace(c.Symbol))
 	c.InstanceID = strings.ToLower(strings.TrimSpace(c.InstanceID))
 	c.Grid.Mode = GridMode(strings.ToLower(strings.TrimSpace(string(c.Grid.Mode))))
+	c.Grid.Bias = GridBias(strings.ToLower(strings.TrimSpace(string(c.Grid.Bias))))
+	c.Grid.ExternalAmendment = AmendmentAction(strings.ToLower(strings.TrimSpace(string(c.Grid.ExternalAmendment))))
+	c.Grid.LevelMapping = LevelMapping(strings.ToLower(strings.TrimSpace(string(c.Grid.LevelMapping))))
+	c.Grid.TickPriceSource = TickPriceSource(strings.ToLower(strings.TrimSpace(string(c.Grid.TickPriceSource))))
+	c.Grid.OnStop = StopInventoryAction(strings.ToLower(strings.TrimSpace(string(c.Grid.OnStop))))
 	c.Exchange.APIKey = strings.TrimSpace(c.Exchange.APIKey)
 	c.Exchange.APISecret = strings.TrimSpace(c.Exchange.APISecret)
+	c.Exchange.APIKeyEnv = strings.TrimSpace(c.Exchange.APIKeyEnv)
+	c.Exchange.APISecretEnv = strings.TrimSpace(c.Exchange.APISecretEnv)
+	c.Exchange.CredentialsFile = strings.TrimSpace(c.Exchange.CredentialsFile)
 	c.Exchange.RestBaseURL = strings.TrimSpace(c.Exchange.RestBaseURL)
 	c.Exchange.WSBaseURL = strings.TrimSpace(c.Exchange.WSBaseURL)
 	c.Exchange.WSEd25519KeyPath = strings.TrimSpace(c.Exchange.WSEd25519KeyPath)
+	c.Exchange.OpenOrderAction = OrderSlotAction(strings.ToLower(strings.TrimSpace(string(c.Exchange.OpenOrderAction))))
 	c.State.Dir = strings.TrimSpace(c.State.Dir)
 	c.Backtest.DataPath = strings.TrimSpace(c.Backtest.DataPath)
+	c.Backtest.InvalidPrice = InvalidPriceAction(strings.ToLower(strings.TrimSpace(string(c.Backtest.InvalidPrice))))
+	for i := range c.Backtest.Symbols {
+		c.Backtest.Symbols[i].Symbol = strings.ToUpper(strings.TrimSpace(c.Backtest.Symbols[i].Symbol))
+		c.Backtest.Symbols[i].DataPath = strings.TrimSpace(c.Backtest.Symbols[i].DataPath)
+	}
 	c.Observability.Telegram.BotToken = strings.TrimSpace(c.Observability.Telegram.BotToken)
 	c.Observability.Telegram.ChatID = strings.TrimSpace(c.Observability.Telegram.ChatID)
 	c.Observability.Telegram.APIBaseURL = strings.TrimSpace(c.Observability.Telegram.APIBaseURL)
+	c.Observability.Webhook.URL = strings.TrimSpace(c.Observability.Webhook.URL)
+	c.Observability.Runtime.USDRateSymbol = strings.ToUpper(strings.TrimSpace(c.Observability.Runtime.USDRateSymbol))
+	c.Observability.PushgatewayURL = strings.TrimSpace(c.Observability.PushgatewayURL)
+	c.Observability.PushgatewayJob = strings.TrimSpace(c.Observability.PushgatewayJob)
+	c.Observability.Metrics.ListenAddr = strings.TrimSpace(c.Observability.Metrics.ListenAddr)
+	c.Observability.Status.ListenAddr = strings.TrimSpace(c.Observability.Status.ListenAddr)
 	auth := strings.ToLower(strings.TrimSpace(string(c.Exchange.UserStreamAuth)))
 	if auth == "apikey" {
 		auth = "session"
@@ -181,6 +512,38 @@ func (c *Config) applyDefaults() {
 	if c.Grid.Mode == "" {
 		c.Grid.Mode = GridGeo
 	}
+	if c.Grid.Bias == "" {
+		c.Grid.Bias = GridBiasBuyDip
+	}
+	if c.Grid.ExternalAmendment == "" {
+		c.Grid.ExternalAmendment = AmendmentRealign
+	}
+	if c.Grid.LevelMapping == "" {
+		c.Grid.LevelMapping = LevelMappingExact
+	}
+	if c.Grid.TickPriceSource == "" {
+		c.Grid.TickPriceSource = TickPriceLast
+	}
+	if c.Grid.SellInventoryGuard == nil {
+		enabled := true
+		c.Grid.SellInventoryGuard = &enabled
+	}
+	if c.Grid.BootstrapRetrySec == nil {
+		retry := int64(60)
+		c.Grid.BootstrapRetrySec = &retry
+	}
+	if c.Grid.OnStop == "" {
+		c.Grid.OnStop = StopHold
+	}
+	if c.Grid.OnStopMaxSlippagePct == nil {
+		c.Grid.OnStopMaxSlippagePct = &Decimal{Decimal: decimal.NewFromInt(1)}
+	}
+	if c.Grid.TickerMaxDivergencePct == nil {
+		c.Grid.TickerMaxDivergencePct = &Decimal{Decimal: decimal.NewFromInt(10)}
+	}
+	if c.Backtest.InvalidPrice == "" {
+		c.Backtest.InvalidPrice = InvalidPriceSkip
+	}
 	if c.Grid.MinQtyMultiple == 0 {
 		c.Grid.MinQtyMultiple = 1
 	}
@@ -190,12 +553,61 @@ func (c *Config) applyDefaults() {
 	if c.Grid.RatioQtyMultiple.Cmp(decimal.Zero) == 0 {
 		c.Grid.RatioQtyMultiple = Decimal{Decimal: decimal.NewFromInt(1)}
 	}
+	if c.Grid.BuyQtyGrowth.Cmp(decimal.Zero) == 0 {
+		c.Grid.BuyQtyGrowth = Decimal{Decimal: decimal.NewFromInt(1)}
+	}
 	if c.Grid.ShiftLevels == 0 && c.Grid.Levels > 0 {
 		c.Grid.ShiftLevels = c.Grid.Levels / 2
 		if c.Grid.ShiftLevels < 1 {
 			c.Grid.ShiftLevels = 1
 		}
 	}
+	if c.Grid.VolatilityPause.Enabled {
+		if c.Grid.VolatilityPause.WindowSec == 0 {
+			c.Grid.VolatilityPause.WindowSec = 300
+		}
+		if c.Grid.VolatilityPause.CooldownSec == 0 {
+			c.Grid.VolatilityPause.CooldownSec = 600
+		}
+	}
+	if c.Grid.InventoryAdaptiveSell.Enabled {
+		if c.Grid.InventoryAdaptiveSell.Sensitivity.Cmp(decimal.Zero) == 0 {
+			c.Grid.InventoryAdaptiveSell.Sensitivity = Decimal{Decimal: decimal.RequireFromString("0.5")}
+		}
+		if c.Grid.InventoryAdaptiveSell.MinFactor.Cmp(decimal.Zero) == 0 {
+			c.Grid.InventoryAdaptiveSell.MinFactor = Decimal{Decimal: decimal.RequireFromString("0.5")}
+		}
+		if c.Grid.InventoryAdaptiveSell.MaxFactor.Cmp(decimal.Zero) == 0 {
+			c.Grid.InventoryAdaptiveSell.MaxFactor = Decimal{Decimal: decimal.RequireFromString("1.5")}
+		}
+	}
+	if c.Grid.OrderTTL.Enabled {
+		if c.Grid.OrderTTL.TTLSec == 0 {
+			c.Grid.OrderTTL.TTLSec = 86400
+		}
+		if c.Grid.OrderTTL.StaggerSec == 0 {
+			c.Grid.OrderTTL.StaggerSec = 30
+		}
+	}
+	if c.Grid.Recenter.Enabled {
+		if c.Grid.Recenter.DriftMultiple.Cmp(decimal.Zero) == 0 {
+			c.Grid.Recenter.DriftMultiple = Decimal{Decimal: decimal.RequireFromString("1.1")}
+		}
+		if c.Grid.Recenter.SustainSec == 0 {
+			c.Grid.Recenter.SustainSec = 3600
+		}
+	}
+	if c.Grid.AdaptiveShift.Enabled {
+		if c.Grid.AdaptiveShift.WindowSec == 0 {
+			c.Grid.AdaptiveShift.WindowSec = 600
+		}
+		if c.Grid.AdaptiveShift.MaxShifts == 0 {
+			c.Grid.AdaptiveShift.MaxShifts = 2
+		}
+	}
+	if c.Grid.AutoBalance.Enabled && c.Grid.AutoBalance.TolerancePct.Cmp(decimal.Zero) == 0 {
+		c.Grid.AutoBalance.TolerancePct = Decimal{Decimal: decimal.NewFromInt(20)}
+	}
 	if c.Exchange.UserStreamAuth == "" {
 		c.Exchange.UserStreamAuth = UserStreamAuthSignature
 	}
@@ -229,6 +641,10 @@ func (c *Config) applyDefaults() {
 	if c.State.Dir == "" {
 		c.State.Dir = "state"
 	}
+	if c.Grid.BootstrapMarketBuy == nil {
+		enabled := true
+		c.Grid.BootstrapMarketBuy = &enabled
+	}
 	if c.State.LockTakeover == nil {
 		enabled := true
 		c.State.LockTakeover = &enabled
@@ -236,18 +652,64 @@ func (c *Config) applyDefaults() {
 	if c.State.LockStaleSec == 0 {
 		c.State.LockStaleSec = 600
 	}
+	if c.State.TwinCheckRounds == 0 {
+		c.State.TwinCheckRounds = 3
+	}
 	if c.Observability.Telegram.APIBaseURL == "" {
 		c.Observability.Telegram.APIBaseURL = "https://api.telegram.org"
 	}
 	if c.Observability.Telegram.TimeoutSec == 0 {
 		c.Observability.Telegram.TimeoutSec = 10
 	}
+	if c.Observability.Webhook.TimeoutSec == 0 {
+		c.Observability.Webhook.TimeoutSec = 10
+	}
 	if c.Observability.Runtime.ReconcileIntervalSec == 0 {
 		c.Observability.Runtime.ReconcileIntervalSec = 60
 	}
 	if c.Observability.Runtime.AlertDropReportSec == 0 {
 		c.Observability.Runtime.AlertDropReportSec = 60
 	}
+	if c.Observability.Metrics.Enabled && c.Observability.Metrics.ListenAddr == "" {
+		c.Observability.Metrics.ListenAddr = "127.0.0.1:9108"
+	}
+	if c.Observability.Status.Enabled && c.Observability.Status.ListenAddr == "" {
+		c.Observability.Status.ListenAddr = "127.0.0.1:9109"
+	}
+	if c.Observability.Status.Enabled {
+		// Ticks and loop wake-ups come at least once per reconcile.
+		wake := c.Observability.Runtime.reconcileCeilingSec()
+		if c.Observability.Status.LivenessStaleSec == 0 {
+			c.Observability.Status.LivenessStaleSec = max(300, 3*wake)
+		}
+		if c.Observability.Status.ReadyTickMaxAgeSec == 0 {
+			c.Observability.Status.ReadyTickMaxAgeSec = 3 * wake
+		}
+	}
+	if c.Observability.PushgatewayURL != "" && c.Observability.PushgatewayJob == "" {
+		c.Observability.PushgatewayJob = "gridbot"
+	}
+	if c.Exchange.RESTTimeoutRetries == nil {
+		retries := 2
+		c.Exchange.RESTTimeoutRetries = &retries
+	}
+	if c.Exchange.WSDialTimeoutSec == 0 {
+		c.Exchange.WSDialTimeoutSec = 10
+	}
+	if c.Exchange.CancelConcurrency == 0 {
+		c.Exchange.CancelConcurrency = 5
+	}
+	if c.Exchange.WSDialRetries == nil {
+		retries := 2
+		c.Exchange.WSDialRetries = &retries
+	}
+	if c.Observability.Runtime.AlertNotifyRetries == nil {
+		retries := 2
+		c.Observability.Runtime.AlertNotifyRetries = &retries
+	}
+	if c.Observability.Runtime.AlertNotifyRetryBackoffMs == 0 {
+		c.Observability.Runtime.AlertNotifyRetryBackoffMs = 1000
+	}
 	if c.Exchange.RestBaseURL == "" {
 		switch c.Mode {
 		case ModeTestnet:
@@ -264,159 +726,554 @@ func (c *Config) applyDefaults() {
 			c.Exchange.WSBaseURL = "wss://ws-api.binance.com/ws-api/v3"
 		}
 	}
+	if c.Exchange.OpenOrderHeadroom == nil {
+		headroom := 2
+		c.Exchange.OpenOrderHeadroom = &headroom
+	}
+	if c.Exchange.OpenOrderAction == "" {
+		c.Exchange.OpenOrderAction = OrderSlotRefuse
+	}
+	if c.Mode != ModeBacktest && c.Exchange.OpenOrderAction == OrderSlotShrink {
+		c.shrinkToOrderSlots()
+	}
+}
+
+// GridOrderSlots is the number of open orders a full grid rests on the
+// exchange plus the headroom kept free for counter orders in flight.
+func (c Config) GridOrderSlots() int {
+	headroom := 0
+	if c.Exchange.OpenOrderHeadroom != nil {
+		headroom = *c.Exchange.OpenOrderHeadroom
+	}
+	return c.Grid.Levels + c.Grid.ShiftLevels + headroom
+}
+
+// shrinkToOrderSlots trims levels first, keeping shift_levels <= levels, so
+// the grid fits under max_open_orders. Grids too small to split are left for
+// Validate to reject.
+func (c *Config) shrinkToOrderSlots() {
+	if c.Exchange.MaxOpenOrders <= 0 || c.GridOrderSlots() <= c.Exchange.MaxOpenOrders {
+		return
+	}
+	slots := c.Exchange.MaxOpenOrders - (c.GridOrderSlots() - c.Grid.Levels - c.Grid.ShiftLevels)
+	if slots < 2 {
+		return
+	}
+	c.Grid.ShrunkFromLevels = c.Grid.Levels
+	levels := slots - c.Grid.ShiftLevels
+	if levels < c.Grid.ShiftLevels {
+		c.Grid.ShiftLevels = slots / 2
+		levels = slots - c.Grid.ShiftLevels
+	}
+	c.Grid.Levels = levels
 }
 
 func (c Config) Validate() error {
+	var v validator
 	switch c.Mode {
 	case ModeBacktest, ModeTestnet, ModeLive:
 	default:
-		return fmt.Errorf("mode must be backtest, testnet, or live")
+		v.addf("mode", "must be backtest, testnet, or live, got %q", c.Mode)
 	}
 	if c.Symbol == "" {
-		return fmt.Errorf("symbol is required")
-	}
-	if !isValidSymbol(c.Symbol) {
-		return fmt.Errorf("symbol must match [A-Z0-9], length 6..20")
+		v.addf("symbol", "is required")
+	} else if !isValidSymbol(c.Symbol) {
+		v.addf("symbol", "must match [A-Z0-9], length 6..20, got %q", c.Symbol)
 	}
 	if !isValidInstanceID(c.InstanceID) {
-		return fmt.Errorf("instance_id must match [a-z0-9_-], length 1..24")
+		v.addf("instance_id", "must match [a-z0-9_-], length 1..24, got %q", c.InstanceID)
 	}
 	if c.Grid.Levels < 1 {
-		return fmt.Errorf("levels must be >= 1")
+		v.addf("grid.levels", "must be >= 1, got %d", c.Grid.Levels)
 	}
 	if c.Grid.Mode != GridGeo {
-		return fmt.Errorf("grid mode must be geometric")
+		v.addf("grid.mode", "must be geometric, got %q", c.Grid.Mode)
+	}
+	switch c.Grid.Bias {
+	case GridBiasBuyDip, GridBiasSellRally:
+	default:
+		v.addf("grid.bias", "must be buy_dip or sell_rally, got %q", c.Grid.Bias)
+	}
+	switch c.Grid.ExternalAmendment {
+	case AmendmentRealign, AmendmentReplace:
+	default:
+		v.addf("grid.external_amendment", "must be realign or replace, got %q", c.Grid.ExternalAmendment)
+	}
+	switch c.Grid.LevelMapping {
+	case LevelMappingExact, LevelMappingNearest:
+	default:
+		v.addf("grid.level_mapping", "must be exact or nearest, got %q", c.Grid.LevelMapping)
+	}
+	switch c.Grid.TickPriceSource {
+	case TickPriceLast, TickPriceMid:
+	case TickPriceMark:
+		v.addf("grid.tick_price_source", "mark needs a futures market; spot supports last or mid")
+	default:
+		v.addf("grid.tick_price_source", "must be last or mid, got %q", c.Grid.TickPriceSource)
 	}
 	if c.Grid.ShiftLevels < 1 || c.Grid.ShiftLevels > c.Grid.Levels {
-		return fmt.Errorf("shift_levels must be between 1 and levels")
+		v.addf("grid.shift_levels", "must be between 1 and levels (%d), got %d", c.Grid.Levels, c.Grid.ShiftLevels)
 	}
 	if c.Grid.StopPrice.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("grid stop_price must be >= 0")
+		v.addf("grid.stop_price", "must be >= 0, got %s", c.Grid.StopPrice)
 	}
-	if c.Grid.Ratio.Cmp(decimal.Zero) <= 0 {
-		return fmt.Errorf("grid ratio must be > 0")
+	if c.Grid.FloorPrice.Cmp(decimal.Zero) < 0 {
+		v.addf("grid.floor_price", "must be >= 0, got %s", c.Grid.FloorPrice)
 	}
-	if c.Grid.Ratio.Cmp(decimal.NewFromInt(1)) <= 0 {
-		return fmt.Errorf("grid ratio must be > 1")
+	if c.Grid.FloorPrice.Cmp(decimal.Zero) > 0 && c.Grid.StopPrice.Cmp(decimal.Zero) > 0 && c.Grid.FloorPrice.Cmp(c.Grid.StopPrice.Decimal) >= 0 {
+		v.addf("grid.floor_price", "must be < stop_price (%s), got %s", c.Grid.StopPrice, c.Grid.FloorPrice)
+	}
+	if tp := c.Grid.TakeProfitPrice; tp.Cmp(decimal.Zero) < 0 {
+		v.addf("grid.take_profit_price", "must be >= 0, got %s", tp)
+	} else if tp.Cmp(decimal.Zero) > 0 {
+		if c.Grid.StopPrice.Cmp(decimal.Zero) > 0 && tp.Cmp(c.Grid.StopPrice.Decimal) >= 0 {
+			v.addf("grid.take_profit_price", "must be < stop_price (%s), got %s", c.Grid.StopPrice, tp)
+		}
+		if tp.Cmp(c.Grid.FloorPrice.Decimal) <= 0 {
+			v.addf("grid.take_profit_price", "must be > floor_price (%s), got %s", c.Grid.FloorPrice, tp)
+		}
+	}
+	if ap := c.Grid.AnchorPrice; ap.Cmp(decimal.Zero) < 0 {
+		v.addf("grid.anchor_price", "must be >= 0, got %s", ap)
+	} else if ap.Cmp(decimal.Zero) > 0 {
+		if c.Grid.StopPrice.Cmp(decimal.Zero) > 0 && ap.Cmp(c.Grid.StopPrice.Decimal) >= 0 {
+			v.addf("grid.anchor_price", "must be < stop_price (%s), got %s", c.Grid.StopPrice, ap)
+		}
+		if ap.Cmp(c.Grid.FloorPrice.Decimal) <= 0 {
+			v.addf("grid.anchor_price", "must be > floor_price (%s), got %s", c.Grid.FloorPrice, ap)
+		}
+		if c.Grid.BootstrapReanchorPct.Cmp(decimal.Zero) > 0 {
+			v.addf("grid.anchor_price", "cannot be combined with bootstrap_reanchor_pct")
+		}
 	}
-	if c.Grid.SellRatio.Cmp(decimal.Zero) <= 0 {
-		return fmt.Errorf("grid sell_ratio must be > 0")
+	if c.Grid.Ratio.Cmp(decimal.NewFromInt(1)) <= 0 {
+		v.addf("grid.ratio", "must be > 1, got %s", c.Grid.Ratio)
 	}
-	if c.Grid.SellRatio.Cmp(decimal.NewFromInt(1)) <= 0 {
-		return fmt.Errorf("grid sell_ratio must be > 1")
+	// A sell_ratio equal to ratio usually just defaulted to it; one error is enough.
+	if c.Grid.SellRatio.Cmp(decimal.NewFromInt(1)) <= 0 && !c.Grid.SellRatio.Equal(c.Grid.Ratio.Decimal) {
+		v.addf("grid.sell_ratio", "must be > 1, got %s", c.Grid.SellRatio)
 	}
 	if c.Grid.RatioStep != nil && c.Grid.RatioStep.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("grid ratio_step must be >= 0")
+		v.addf("grid.ratio_step", "must be >= 0, got %s", c.Grid.RatioStep)
 	}
 	if c.Grid.RatioQtyMultiple.Cmp(decimal.Zero) <= 0 {
-		return fmt.Errorf("grid ratio_qty_multiple must be > 0")
+		v.addf("grid.ratio_qty_multiple", "must be > 0, got %s", c.Grid.RatioQtyMultiple)
+	}
+	if c.Grid.Qty.Cmp(decimal.Zero) <= 0 && !c.multiSymbolBacktest() {
+		v.addf("grid.qty", "must be > 0, got %s", c.Grid.Qty)
 	}
-	if c.Grid.Qty.Cmp(decimal.Zero) <= 0 {
-		return fmt.Errorf("qty must be > 0")
+	if c.Grid.BuyQty != nil && c.Grid.BuyQty.Cmp(decimal.Zero) <= 0 {
+		v.addf("grid.buy_qty", "must be > 0 when set, got %s", c.Grid.BuyQty)
+	}
+	if c.Grid.BuyQtyGrowth.Cmp(decimal.NewFromInt(1)) < 0 {
+		v.addf("grid.buy_qty_growth", "must be >= 1, got %s", c.Grid.BuyQtyGrowth)
+	}
+	if c.Grid.SellQty != nil && c.Grid.SellQty.Cmp(decimal.Zero) <= 0 {
+		v.addf("grid.sell_qty", "must be > 0 when set, got %s", c.Grid.SellQty)
 	}
 	if c.Grid.MinQtyMultiple < 1 {
-		return fmt.Errorf("min_qty_multiple must be >= 1")
+		v.addf("grid.min_qty_multiple", "must be >= 1, got %d", c.Grid.MinQtyMultiple)
+	}
+	largestQty := c.Grid.Qty.Decimal
+	for _, q := range []*Decimal{c.Grid.BuyQty, c.Grid.SellQty} {
+		if q != nil && q.Cmp(largestQty) > 0 {
+			largestQty = q.Decimal
+		}
+	}
+	if maxQty := c.Grid.MaxOrderQty.Decimal; maxQty.Cmp(decimal.Zero) < 0 || (maxQty.Cmp(decimal.Zero) > 0 && maxQty.Cmp(largestQty) < 0) {
+		v.addf("grid.max_order_qty", "must be 0 or >= qty, buy_qty and sell_qty (%s), got %s", largestQty, maxQty)
+	}
+	if retry := c.Grid.BootstrapRetrySec; retry != nil && (*retry < 0 || *retry > 86400) {
+		v.addf("grid.bootstrap_retry_sec", "must be between 0 and 86400, got %d", *retry)
+	}
+	if c.Grid.BootstrapSettleMs < 0 || c.Grid.BootstrapSettleMs > 60000 {
+		v.addf("grid.bootstrap_settle_ms", "must be between 0 and 60000, got %d", c.Grid.BootstrapSettleMs)
+	}
+	if c.Grid.ShiftUpCooldownSec < 0 || c.Grid.ShiftUpCooldownSec > 86400 {
+		v.addf("grid.shift_up_cooldown_sec", "must be between 0 and 86400, got %d", c.Grid.ShiftUpCooldownSec)
+	}
+	if pct := c.Grid.BootstrapReanchorPct.Decimal; pct.Cmp(decimal.Zero) < 0 || pct.Cmp(decimal.NewFromInt(100)) >= 0 {
+		v.addf("grid.bootstrap_reanchor_pct", "must be >= 0 and < 100, got %s", pct)
+	}
+	if c.Grid.OnStop != StopHold && c.Grid.OnStop != StopMarketSell {
+		v.addf("grid.on_stop", "must be hold or market_sell, got %q", c.Grid.OnStop)
+	}
+	if slip := c.Grid.OnStopMaxSlippagePct; slip != nil && (slip.Cmp(decimal.Zero) < 0 || slip.Cmp(decimal.NewFromInt(50)) >= 0) {
+		v.addf("grid.on_stop_max_slippage_pct", "must be >= 0 and < 50, got %s", slip)
+	}
+	if pct := c.Grid.TickerMaxDivergencePct; pct != nil && (pct.Cmp(decimal.Zero) < 0 || pct.Cmp(decimal.NewFromInt(100)) > 0) {
+		v.addf("grid.ticker_max_divergence_pct", "must be between 0 and 100, got %s", pct)
+	}
+	if pct := c.Grid.SuspiciousSnapshotPct; pct != nil && (pct.Cmp(decimal.Zero) < 0 || pct.Cmp(decimal.NewFromInt(100)) > 0) {
+		v.addf("grid.suspicious_snapshot_pct", "must be between 0 and 100, got %s", pct)
+	}
+	if tol := c.Grid.OversizedFillTolerancePct; tol != nil && !tol.Equal(decimal.NewFromInt(-1)) && (tol.Cmp(decimal.Zero) < 0 || tol.Cmp(decimal.NewFromInt(1000)) > 0) {
+		v.addf("grid.oversized_fill_tolerance_pct", "must be -1 (disabled) or between 0 and 1000, got %s", tol)
+	}
+	if c.Grid.RebuildMinIntervalSec < 0 || c.Grid.RebuildMinIntervalSec > 604800 {
+		v.addf("grid.rebuild_min_interval_sec", "must be between 0 and 604800, got %d", c.Grid.RebuildMinIntervalSec)
+	}
+	if c.Capital.QuoteBudget.Cmp(decimal.Zero) < 0 {
+		v.addf("capital.quote_budget", "must be >= 0, got %s", c.Capital.QuoteBudget)
+	}
+	if c.State.DrainTimeoutSec < 0 || c.State.DrainTimeoutSec > 600 {
+		v.addf("state.drain_timeout_sec", "must be between 0 and 600, got %d", c.State.DrainTimeoutSec)
+	}
+	if c.Capital.BalanceFloorQuote.Cmp(decimal.Zero) < 0 {
+		v.addf("capital.balance_floor_quote", "must be >= 0, got %s", c.Capital.BalanceFloorQuote)
+	}
+	if c.Capital.BalanceFloorBase.Cmp(decimal.Zero) < 0 {
+		v.addf("capital.balance_floor_base", "must be >= 0, got %s", c.Capital.BalanceFloorBase)
+	}
+	if c.Capital.BalanceFloorEnabled() && c.Mode != ModeBacktest && c.Observability.Runtime.HeartbeatSec == 0 {
+		v.addf("capital.balance_floor_quote", "balance floors require observability.runtime.heartbeat_sec > 0")
+	}
+	if c.Grid.MaxRestingBuys < 0 {
+		v.addf("grid.max_resting_buys", "must be >= 0, got %d", c.Grid.MaxRestingBuys)
+	}
+	if c.Grid.MaxRestingSells < 0 {
+		v.addf("grid.max_resting_sells", "must be >= 0, got %d", c.Grid.MaxRestingSells)
+	}
+	if c.Grid.MinHoldSec < 0 || c.Grid.MinHoldSec > 86400 {
+		v.addf("grid.min_hold_sec", "must be between 0 and 86400, got %d", c.Grid.MinHoldSec)
+	}
+	if vp := c.Grid.VolatilityPause; vp.Enabled {
+		if vp.WindowSec < 1 || vp.WindowSec > 86400 {
+			v.addf("grid.volatility_pause.window_sec", "must be between 1 and 86400, got %d", vp.WindowSec)
+		}
+		if vp.ThresholdPct.Cmp(decimal.Zero) <= 0 {
+			v.addf("grid.volatility_pause.threshold_pct", "must be > 0, got %s", vp.ThresholdPct)
+		}
+		if vp.CooldownSec < 0 || vp.CooldownSec > 86400 {
+			v.addf("grid.volatility_pause.cooldown_sec", "must be between 0 and 86400, got %d", vp.CooldownSec)
+		}
+	}
+	if ttl := c.Grid.OrderTTL; ttl.Enabled {
+		if ttl.TTLSec < 60 || ttl.TTLSec > 30*86400 {
+			v.addf("grid.order_ttl.ttl_sec", "must be between 60 and 2592000, got %d", ttl.TTLSec)
+		}
+		if ttl.StaggerSec < 1 || ttl.StaggerSec > 86400 {
+			v.addf("grid.order_ttl.stagger_sec", "must be between 1 and 86400, got %d", ttl.StaggerSec)
+		}
+	}
+	if rc := c.Grid.Recenter; rc.Enabled {
+		if rc.DriftMultiple.Cmp(decimal.NewFromInt(1)) <= 0 {
+			v.addf("grid.recenter.drift_multiple", "must be > 1, got %s", rc.DriftMultiple)
+		}
+		if rc.SustainSec < 1 || rc.SustainSec > 604800 {
+			v.addf("grid.recenter.sustain_sec", "must be between 1 and 604800, got %d", rc.SustainSec)
+		}
+	}
+	if as := c.Grid.AdaptiveShift; as.Enabled {
+		if as.WindowSec < 1 || as.WindowSec > 86400 {
+			v.addf("grid.adaptive_shift.window_sec", "must be between 1 and 86400, got %d", as.WindowSec)
+		}
+		if as.MaxShifts < 1 || as.MaxShifts > 100 {
+			v.addf("grid.adaptive_shift.max_shifts", "must be between 1 and 100, got %d", as.MaxShifts)
+		}
+	}
+	if ab := c.Grid.AutoBalance; ab.Enabled {
+		if ab.TolerancePct.Cmp(decimal.Zero) <= 0 || ab.TolerancePct.Cmp(decimal.NewFromInt(100)) > 0 {
+			v.addf("grid.auto_balance.tolerance_pct", "must be > 0 and <= 100, got %s", ab.TolerancePct)
+		}
+	}
+	if ias := c.Grid.InventoryAdaptiveSell; ias.Enabled {
+		if ias.Sensitivity.Cmp(decimal.Zero) <= 0 || ias.Sensitivity.Cmp(decimal.NewFromInt(5)) > 0 {
+			v.addf("grid.inventory_adaptive_sell.sensitivity", "must be > 0 and <= 5, got %s", ias.Sensitivity)
+		}
+		if ias.MinFactor.Cmp(decimal.RequireFromString("0.1")) < 0 || ias.MinFactor.Cmp(decimal.NewFromInt(1)) > 0 {
+			v.addf("grid.inventory_adaptive_sell.min_factor", "must be between 0.1 and 1, got %s", ias.MinFactor)
+		}
+		if ias.MaxFactor.Cmp(decimal.NewFromInt(1)) < 0 || ias.MaxFactor.Cmp(decimal.NewFromInt(3)) > 0 {
+			v.addf("grid.inventory_adaptive_sell.max_factor", "must be between 1 and 3, got %s", ias.MaxFactor)
+		}
 	}
 	if c.Backtest.Fees.MakerRate.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("backtest fees.maker_rate must be >= 0")
+		v.addf("backtest.fees.maker_rate", "must be >= 0, got %s", c.Backtest.Fees.MakerRate)
 	}
 	if c.Backtest.Fees.TakerRate.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("backtest fees.taker_rate must be >= 0")
+		v.addf("backtest.fees.taker_rate", "must be >= 0, got %s", c.Backtest.Fees.TakerRate)
 	}
 	if c.Backtest.Rules.MinQty.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("backtest rules.min_qty must be >= 0")
+		v.addf("backtest.rules.min_qty", "must be >= 0, got %s", c.Backtest.Rules.MinQty)
 	}
 	if c.Backtest.Rules.MinNotional.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("backtest rules.min_notional must be >= 0")
+		v.addf("backtest.rules.min_notional", "must be >= 0, got %s", c.Backtest.Rules.MinNotional)
 	}
 	if c.Backtest.Rules.PriceTick.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("backtest rules.price_tick must be >= 0")
+		v.addf("backtest.rules.price_tick", "must be >= 0, got %s", c.Backtest.Rules.PriceTick)
 	}
 	if c.Backtest.Rules.QtyStep.Cmp(decimal.Zero) < 0 {
-		return fmt.Errorf("backtest rules.qty_step must be >= 0")
+		v.addf("backtest.rules.qty_step", "must be >= 0, got %s", c.Backtest.Rules.QtyStep)
 	}
-	if c.CircuitBreaker.Enabled {
-		if c.CircuitBreaker.MaxPlaceFailures < 1 {
-			return fmt.Errorf("circuit_breaker.max_place_failures must be >= 1")
+	if cb := c.CircuitBreaker; cb.Enabled {
+		if cb.MaxPlaceFailures < 1 {
+			v.addf("circuit_breaker.max_place_failures", "must be >= 1, got %d", cb.MaxPlaceFailures)
 		}
-		if c.CircuitBreaker.MaxCancelFailures < 1 {
-			return fmt.Errorf("circuit_breaker.max_cancel_failures must be >= 1")
+		if cb.MaxCancelFailures < 1 {
+			v.addf("circuit_breaker.max_cancel_failures", "must be >= 1, got %d", cb.MaxCancelFailures)
 		}
-		if c.CircuitBreaker.MaxReconnectFailures < 1 {
-			return fmt.Errorf("circuit_breaker.max_reconnect_failures must be >= 1")
+		if cb.MaxReconnectFailures < 1 {
+			v.addf("circuit_breaker.max_reconnect_failures", "must be >= 1, got %d", cb.MaxReconnectFailures)
 		}
-		if c.CircuitBreaker.ReconnectCooldownSec < 1 || c.CircuitBreaker.ReconnectCooldownSec > 3600 {
-			return fmt.Errorf("circuit_breaker.reconnect_cooldown_sec must be between 1 and 3600")
+		if cb.ReconnectCooldownSec < 1 || cb.ReconnectCooldownSec > 3600 {
+			v.addf("circuit_breaker.reconnect_cooldown_sec", "must be between 1 and 3600, got %d", cb.ReconnectCooldownSec)
 		}
-		if c.CircuitBreaker.ReconnectProbePasses < 1 || c.CircuitBreaker.ReconnectProbePasses > 20 {
-			return fmt.Errorf("circuit_breaker.reconnect_probe_passes must be between 1 and 20")
+		if cb.ReconnectProbePasses < 1 || cb.ReconnectProbePasses > 20 {
+			v.addf("circuit_breaker.reconnect_probe_passes", "must be between 1 and 20, got %d", cb.ReconnectProbePasses)
 		}
 	}
-	if c.Observability.Runtime.HeartbeatSec < 0 || c.Observability.Runtime.HeartbeatSec > 3600 {
-		return fmt.Errorf("observability.runtime.heartbeat_sec must be between 0 and 3600")
+	if dd := c.CircuitBreaker.MaxDrawdownPct; dd.Cmp(decimal.Zero) < 0 || dd.Cmp(decimal.NewFromInt(100)) >= 0 {
+		v.addf("circuit_breaker.max_drawdown_pct", "must be >= 0 and < 100, got %s", dd)
 	}
-	if c.Observability.Runtime.ReconcileIntervalSec < 0 || c.Observability.Runtime.ReconcileIntervalSec > 3600 {
-		return fmt.Errorf("observability.runtime.reconcile_interval_sec must be between 0 and 3600")
+	rt := c.Observability.Runtime
+	if rt.HeartbeatSec < 0 || rt.HeartbeatSec > 3600 {
+		v.addf("observability.runtime.heartbeat_sec", "must be between 0 and 3600, got %d", rt.HeartbeatSec)
 	}
-	if c.Observability.Runtime.ReconcileIntervalSec > 0 && c.Observability.Runtime.ReconcileIntervalSec < 10 {
-		return fmt.Errorf("observability.runtime.reconcile_interval_sec must be 0 or >= 10")
+	if rt.ReconcileIntervalSec < 0 || rt.ReconcileIntervalSec > 3600 {
+		v.addf("observability.runtime.reconcile_interval_sec", "must be between 0 and 3600, got %d", rt.ReconcileIntervalSec)
+	} else if rt.ReconcileIntervalSec > 0 && rt.ReconcileIntervalSec < 10 {
+		v.addf("observability.runtime.reconcile_interval_sec", "must be 0 or >= 10, got %d", rt.ReconcileIntervalSec)
 	}
-	if c.Observability.Runtime.AlertDropReportSec < 0 || c.Observability.Runtime.AlertDropReportSec > 3600 {
-		return fmt.Errorf("observability.runtime.alert_drop_report_sec must be between 0 and 3600")
+	if rt.ReconcileQuietSec < 0 || rt.ReconcileQuietSec > 3600 {
+		v.addf("observability.runtime.reconcile_quiet_sec", "must be between 0 and 3600, got %d", rt.ReconcileQuietSec)
 	}
-	if c.Observability.Telegram.Enabled {
-		if c.Observability.Telegram.BotToken == "" {
-			return fmt.Errorf("observability.telegram.bot_token is required when telegram enabled")
+	if rt.ReconcileMinIntervalSec != 0 || rt.ReconcileMaxIntervalSec != 0 {
+		if rt.ReconcileIntervalSec == 0 {
+			v.addf("observability.runtime.reconcile_min_interval_sec", "reconcile_min/max_interval_sec require reconcile_interval_sec > 0")
+		}
+		if rt.ReconcileMinIntervalSec < 10 || rt.ReconcileMinIntervalSec > 3600 {
+			v.addf("observability.runtime.reconcile_min_interval_sec", "must be between 10 and 3600, got %d", rt.ReconcileMinIntervalSec)
 		}
-		if c.Observability.Telegram.ChatID == "" {
-			return fmt.Errorf("observability.telegram.chat_id is required when telegram enabled")
+		if rt.ReconcileMaxIntervalSec < rt.ReconcileMinIntervalSec || rt.ReconcileMaxIntervalSec > 3600 {
+			v.addf("observability.runtime.reconcile_max_interval_sec", "must be between reconcile_min_interval_sec and 3600, got %d", rt.ReconcileMaxIntervalSec)
 		}
-		if c.Observability.Telegram.TimeoutSec < 1 || c.Observability.Telegram.TimeoutSec > 120 {
-			return fmt.Errorf("observability.telegram.timeout_sec must be between 1 and 120")
+	}
+	if rt.MaxRunSec < 0 {
+		v.addf("observability.runtime.max_run_sec", "must be >= 0, got %d", rt.MaxRunSec)
+	}
+	if rt.AlertDropReportSec < 0 || rt.AlertDropReportSec > 3600 {
+		v.addf("observability.runtime.alert_drop_report_sec", "must be between 0 and 3600, got %d", rt.AlertDropReportSec)
+	}
+	if retries := rt.AlertNotifyRetries; retries != nil && (*retries < 0 || *retries > 5) {
+		v.addf("observability.runtime.alert_notify_retries", "must be between 0 and 5, got %d", *retries)
+	}
+	if rt.AlertNotifyRetryBackoffMs < 0 || rt.AlertNotifyRetryBackoffMs > 60000 {
+		v.addf("observability.runtime.alert_notify_retry_backoff_ms", "must be between 0 and 60000, got %d", rt.AlertNotifyRetryBackoffMs)
+	}
+	if c.Observability.PushgatewayURL != "" {
+		if err := validateURL(c.Observability.PushgatewayURL, "http", "https"); err != nil {
+			v.addf("observability.pushgateway_url", "%v", err)
 		}
-		if err := validateURL(c.Observability.Telegram.APIBaseURL, "http", "https"); err != nil {
-			return fmt.Errorf("observability.telegram.api_base_url %v", err)
+		if rt.HeartbeatSec == 0 {
+			v.addf("observability.pushgateway_url", "requires observability.runtime.heartbeat_sec > 0")
 		}
 	}
-	if c.State.LockStaleSec < 0 || c.State.LockStaleSec > 86400 {
-		return fmt.Errorf("state.lock_stale_sec must be between 0 and 86400")
+	if m := c.Observability.Metrics; m.Enabled {
+		if _, port, err := net.SplitHostPort(m.ListenAddr); err != nil || port == "" {
+			v.addf("observability.metrics.listen_addr", "must be host:port, got %q", m.ListenAddr)
+		}
 	}
-	if c.Mode == ModeBacktest && c.Backtest.DataPath == "" {
-		return fmt.Errorf("backtest data_path is required")
+	if sc := c.Observability.Status; sc.Enabled {
+		if _, port, err := net.SplitHostPort(sc.ListenAddr); err != nil || port == "" {
+			v.addf("observability.status.listen_addr", "must be host:port, got %q", sc.ListenAddr)
+		}
+		if c.State.Dir == "" {
+			v.addf("observability.status.enabled", "requires state.dir for the runtime status")
+		}
+		wake := rt.reconcileCeilingSec()
+		if sc.LivenessStaleSec <= wake || sc.LivenessStaleSec > 86400 {
+			v.addf("observability.status.liveness_stale_sec", "must be above the reconcile interval (%ds) and at most 86400, got %d", wake, sc.LivenessStaleSec)
+		}
+		if sc.ReadyTickMaxAgeSec <= wake || sc.ReadyTickMaxAgeSec > 86400 {
+			v.addf("observability.status.ready_tick_max_age_sec", "must be above the reconcile interval (%ds) and at most 86400, got %d", wake, sc.ReadyTickMaxAgeSec)
+		}
 	}
-	if c.Mode != ModeBacktest {
-		if c.Exchange.APIKey == "" || c.Exchange.APISecret == "" {
-			return fmt.Errorf("exchange api_key/api_secret are required for %s mode", c.Mode)
+	if tg := c.Observability.Telegram; tg.Enabled {
+		if tg.BotToken == "" {
+			v.addf("observability.telegram.bot_token", "is required when telegram enabled")
 		}
-		if c.Exchange.RestBaseURL == "" || c.Exchange.WSBaseURL == "" {
-			return fmt.Errorf("exchange rest_base_url/ws_base_url are required for %s mode", c.Mode)
+		if tg.ChatID == "" {
+			v.addf("observability.telegram.chat_id", "is required when telegram enabled")
 		}
-		if c.Exchange.RecvWindowMs < 1 || c.Exchange.RecvWindowMs > 60000 {
-			return fmt.Errorf("exchange recv_window_ms must be between 1 and 60000")
+		if tg.TimeoutSec < 1 || tg.TimeoutSec > 120 {
+			v.addf("observability.telegram.timeout_sec", "must be between 1 and 120, got %d", tg.TimeoutSec)
 		}
-		if c.Exchange.HTTPTimeoutSec < 1 || c.Exchange.HTTPTimeoutSec > 120 {
-			return fmt.Errorf("exchange http_timeout_sec must be between 1 and 120")
+		if err := validateURL(tg.APIBaseURL, "http", "https"); err != nil {
+			v.addf("observability.telegram.api_base_url", "%v", err)
 		}
-		if c.Exchange.UserStreamKeepaliveSec < 1 || c.Exchange.UserStreamKeepaliveSec > 3600 {
-			return fmt.Errorf("exchange user_stream_keepalive_sec must be between 1 and 3600")
+	}
+	if wh := c.Observability.Webhook; wh.Enabled {
+		if wh.URL == "" {
+			v.addf("observability.webhook.url", "is required when webhook enabled")
+		} else if err := validateURL(wh.URL, "http", "https"); err != nil {
+			v.addf("observability.webhook.url", "%v", err)
 		}
-		if c.Exchange.OrderWSKeepaliveSec < 1 || c.Exchange.OrderWSKeepaliveSec > 300 {
-			return fmt.Errorf("exchange order_ws_keepalive_sec must be between 1 and 300")
+		if wh.TimeoutSec < 1 || wh.TimeoutSec > 120 {
+			v.addf("observability.webhook.timeout_sec", "must be between 1 and 120, got %d", wh.TimeoutSec)
 		}
-		if err := validateURL(c.Exchange.RestBaseURL, "http", "https"); err != nil {
-			return fmt.Errorf("exchange rest_base_url %v", err)
+	}
+	if c.State.LockStaleSec < 0 || c.State.LockStaleSec > 86400 {
+		v.addf("state.lock_stale_sec", "must be between 0 and 86400, got %d", c.State.LockStaleSec)
+	}
+	if c.State.TwinCheckSurplus < 0 || c.State.TwinCheckSurplus > 1000 {
+		v.addf("state.twin_check_surplus", "must be between 0 and 1000, got %d", c.State.TwinCheckSurplus)
+	}
+	if c.State.TwinCheckRounds < 1 || c.State.TwinCheckRounds > 100 {
+		v.addf("state.twin_check_rounds", "must be between 1 and 100, got %d", c.State.TwinCheckRounds)
+	}
+	if c.Backtest.InvalidPrice != InvalidPriceSkip && c.Backtest.InvalidPrice != InvalidPriceError {
+		v.addf("backtest.invalid_price", "must be skip or error, got %q", c.Backtest.InvalidPrice)
+	}
+	if c.Backtest.MinFillVolume.Cmp(decimal.Zero) < 0 {
+		v.addf("backtest.min_fill_volume", "must be >= 0, got %s", c.Backtest.MinFillVolume)
+	}
+	if c.Mode == ModeBacktest && c.Backtest.DataPath == "" && len(c.Backtest.Symbols) == 0 {
+		v.addf("backtest.data_path", "is required")
+	}
+	seenSymbols := make(map[string]bool, len(c.Backtest.Symbols))
+	for i, sym := range c.Backtest.Symbols {
+		path := fmt.Sprintf("backtest.symbols[%d]", i)
+		if sym.Symbol == "" || sym.DataPath == "" {
+			v.addf(path, "requires symbol and data_path")
+			continue
 		}
-		if err := validateURL(c.Exchange.WSBaseURL, "ws", "wss"); err != nil {
-			return fmt.Errorf("exchange ws_base_url %v", err)
+		if seenSymbols[sym.Symbol] {
+			v.addf(path, "duplicate symbol %s", sym.Symbol)
 		}
-		if c.Exchange.UserStreamAuth != UserStreamAuthSignature && c.Exchange.UserStreamAuth != UserStreamAuthSession {
-			return fmt.Errorf("exchange user_stream_auth must be signature or session")
+		seenSymbols[sym.Symbol] = true
+		if c.multiSymbolBacktest() && sym.Qty.Cmp(decimal.Zero) <= 0 {
+			v.addf(path+".qty", "must be > 0 when backtesting more than one symbol, got %s", sym.Qty)
 		}
-		if c.Exchange.UserStreamAuth == UserStreamAuthSession && c.Exchange.WSEd25519KeyPath == "" {
-			return fmt.Errorf("exchange ws_ed25519_private_key_path is required for session auth")
+		if sym.StopPrice.Cmp(decimal.Zero) < 0 || sym.InitialBase.Cmp(decimal.Zero) < 0 || sym.InitialQuote.Cmp(decimal.Zero) < 0 {
+			v.addf(path, "stop_price, initial_base and initial_quote must be >= 0")
 		}
 	}
-	return nil
+	if c.multiSymbolBacktest() {
+		c.validateSharedAcrossSymbols(&v)
+	}
+	if c.Mode == ModeTestnet || c.Mode == ModeLive {
+		c.validateExchange(&v)
+	}
+	return v.err()
+}
+
+func (c Config) multiSymbolBacktest() bool {
+	return c.Mode == ModeBacktest && len(c.Backtest.Symbols) > 1
+}
+
+// validateSharedAcrossSymbols rejects shared settings that are absolute
+// prices or base quantities, since one value cannot fit several symbols.
+func (c Config) validateSharedAcrossSymbols(v *validator) {
+	n := len(c.Backtest.Symbols)
+	if c.Grid.StopPrice.Cmp(decimal.Zero) != 0 {
+		v.addf("grid.stop_price", "cannot be shared by %d backtest.symbols; set stop_price per symbol", n)
+	}
+	if c.Backtest.InitialBase.Cmp(decimal.Zero) != 0 {
+		v.addf("backtest.initial_base", "cannot be shared by %d backtest.symbols; set initial_base per symbol", n)
+	}
+	if !c.Backtest.Rules.IsZero() {
+		v.addf("backtest.rules", "cannot be shared by %d backtest.symbols; set rules per symbol", n)
+	}
+	unsupported := []struct {
+		path  string
+		value *Decimal
+	}{
+		{"grid.floor_price", &c.Grid.FloorPrice},
+		{"grid.take_profit_price", &c.Grid.TakeProfitPrice},
+		{"grid.anchor_price", &c.Grid.AnchorPrice},
+		{"grid.buy_qty", c.Grid.BuyQty},
+		{"grid.sell_qty", c.Grid.SellQty},
+		{"grid.max_order_qty", &c.Grid.MaxOrderQty},
+	}
+	for _, f := range unsupported {
+		if f.value != nil && f.value.Cmp(decimal.Zero) != 0 {
+			v.addf(f.path, "is an absolute price or base qty and cannot be shared by %d backtest.symbols", n)
+		}
+	}
+}
+
+// IsZero reports whether no rule is set.
+func (r BacktestRules) IsZero() bool {
+	for _, d := range []Decimal{r.MinQty, r.MinNotional, r.PriceTick, r.QtyStep} {
+		if d.Cmp(decimal.Zero) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Config) validateExchange(v *validator) {
+	ex := c.Exchange
+	if ex.APIKey == "" {
+		v.addf("exchange.api_key", "is required for %s mode", c.Mode)
+	}
+	if ex.APISecret == "" {
+		v.addf("exchange.api_secret", "is required for %s mode", c.Mode)
+	}
+	if ex.RestBaseURL == "" {
+		v.addf("exchange.rest_base_url", "is required for %s mode", c.Mode)
+	} else if err := validateURL(ex.RestBaseURL, "http", "https"); err != nil {
+		v.addf("exchange.rest_base_url", "%v", err)
+	}
+	if ex.WSBaseURL == "" {
+		v.addf("exchange.ws_base_url", "is required for %s mode", c.Mode)
+	} else if err := validateURL(ex.WSBaseURL, "ws", "wss"); err != nil {
+		v.addf("exchange.ws_base_url", "%v", err)
+	}
+	if ex.RecvWindowMs < 1 || ex.RecvWindowMs > 60000 {
+		v.addf("exchange.recv_window_ms", "must be between 1 and 60000, got %d", ex.RecvWindowMs)
+	}
+	if ex.HTTPTimeoutSec < 1 || ex.HTTPTimeoutSec > 120 {
+		v.addf("exchange.http_timeout_sec", "must be between 1 and 120, got %d", ex.HTTPTimeoutSec)
+	}
+	if ex.UserStreamKeepaliveSec < 1 || ex.UserStreamKeepaliveSec > 3600 {
+		v.addf("exchange.user_stream_keepalive_sec", "must be between 1 and 3600, got %d", ex.UserStreamKeepaliveSec)
+	}
+	if ex.OrderWSKeepaliveSec < 1 || ex.OrderWSKeepaliveSec > 300 {
+		v.addf("exchange.order_ws_keepalive_sec", "must be between 1 and 300, got %d", ex.OrderWSKeepaliveSec)
+	}
+	if ex.UserStreamSilenceSec < 0 || ex.UserStreamSilenceSec > 3600 {
+		v.addf("exchange.user_stream_silence_sec", "must be between 0 and 3600, got %d", ex.UserStreamSilenceSec)
+	}
+	if ex.UserStreamBacklogMax < 0 || ex.UserStreamBacklogMax > 100000 {
+		v.addf("exchange.user_stream_backlog_max", "must be between 0 and 100000, got %d", ex.UserStreamBacklogMax)
+	}
+	if retries := ex.RESTTimeoutRetries; retries != nil && (*retries < 0 || *retries > 5) {
+		v.addf("exchange.rest_timeout_retries", "must be between 0 and 5, got %d", *retries)
+	}
+	if ex.WSDialTimeoutSec < 1 || ex.WSDialTimeoutSec > 120 {
+		v.addf("exchange.ws_dial_timeout_sec", "must be between 1 and 120, got %d", ex.WSDialTimeoutSec)
+	}
+	if retries := ex.WSDialRetries; retries != nil && (*retries < 0 || *retries > 5) {
+		v.addf("exchange.ws_dial_retries", "must be between 0 and 5, got %d", *retries)
+	}
+	if ex.MaxRequestsPer10s < 0 || ex.MaxRequestsPer10s > 100000 {
+		v.addf("exchange.max_requests_per_10s", "must be between 0 and 100000, got %d", ex.MaxRequestsPer10s)
+	}
+	if ex.CancelConcurrency < 1 || ex.CancelConcurrency > 20 {
+		v.addf("exchange.cancel_concurrency", "must be between 1 and 20, got %d", ex.CancelConcurrency)
+	}
+	if ex.RulesRefreshSec != 0 && (ex.RulesRefreshSec < 60 || ex.RulesRefreshSec > 7*86400) {
+		v.addf("exchange.rules_refresh_sec", "must be 0 or between 60 and 604800, got %d", ex.RulesRefreshSec)
+	}
+	if ex.MaxOpenOrders < 0 {
+		v.addf("exchange.max_open_orders", "must be >= 0, got %d", ex.MaxOpenOrders)
+	}
+	if headroom := ex.OpenOrderHeadroom; headroom != nil && (*headroom < 0 || *headroom > 100) {
+		v.addf("exchange.open_order_headroom", "must be between 0 and 100, got %d", *headroom)
+	}
+	if ex.OpenOrderAction != OrderSlotRefuse && ex.OpenOrderAction != OrderSlotShrink {
+		v.addf("exchange.max_open_orders_action", "must be refuse or shrink, got %q", ex.OpenOrderAction)
+	}
+	if ex.MaxOpenOrders > 0 && c.GridOrderSlots() > ex.MaxOpenOrders {
+		v.addf("exchange.max_open_orders", "grid needs %d open order slots (levels %d + shift_levels %d + open_order_headroom) but exchange max_open_orders is %d", c.GridOrderSlots(), c.Grid.Levels, c.Grid.ShiftLevels, ex.MaxOpenOrders)
+	}
+	if ex.UserStreamAuth != UserStreamAuthSignature && ex.UserStreamAuth != UserStreamAuthSession {
+		v.addf("exchange.user_stream_auth", "must be signature or session, got %q", ex.UserStreamAuth)
+	} else if ex.UserStreamAuth == UserStreamAuthSession && ex.WSEd25519KeyPath == "" {
+		v.addf("exchange.ws_ed25519_private_key_path", "is required for session auth")
+	}
 }
 
 func isValidInstanceID(v string) bool {
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -39,6 +41,9 @@ backtest:
 	if cfg.Grid.Mode != GridGeo {
 		t.Fatalf("grid.mode = %q, want %q", cfg.Grid.Mode, GridGeo)
 	}
+	if cfg.Grid.Bias != GridBiasBuyDip {
+		t.Fatalf("grid.bias = %q, want %q", cfg.Grid.Bias, GridBiasBuyDip)
+	}
 	if !cfg.Grid.SellRatio.Equal(cfg.Grid.Ratio.Decimal) {
 		t.Fatalf("grid.sell_ratio = %s, want %s", cfg.Grid.SellRatio.String(), cfg.Grid.Ratio.String())
 	}
@@ -94,8 +99,8 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "grid mode must be geometric") {
-		t.Fatalf("Load() error = %q, want contains %q", err.Error(), "grid mode must be geometric")
+	if !strings.Contains(err.Error(), "grid.mode: must be geometric") {
+		t.Fatalf("Load() error = %q, want contains %q", err.Error(), "grid.mode: must be geometric")
 	}
 }
 
@@ -129,7 +134,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "field inventory not found") {
+	if !strings.Contains(err.Error(), "grid.inventory: unknown field") {
 		t.Fatalf("Load() error = %q, want unknown field inventory message", err.Error())
 	}
 }
@@ -163,7 +168,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "field high not found") {
+	if !strings.Contains(err.Error(), "grid.high: unknown field") {
 		t.Fatalf("Load() error = %q, want unknown field high message", err.Error())
 	}
 }
@@ -197,7 +202,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "grid sell_ratio must be > 1") {
+	if !strings.Contains(err.Error(), "grid.sell_ratio: must be > 1") {
 		t.Fatalf("Load() error = %q, want sell_ratio validation", err.Error())
 	}
 }
@@ -231,7 +236,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "grid ratio_step must be >= 0") {
+	if !strings.Contains(err.Error(), "grid.ratio_step: must be >= 0") {
 		t.Fatalf("Load() error = %q, want ratio_step validation", err.Error())
 	}
 }
@@ -374,7 +379,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "grid ratio_qty_multiple must be > 0") {
+	if !strings.Contains(err.Error(), "grid.ratio_qty_multiple: must be > 0") {
 		t.Fatalf("Load() error = %q, want ratio_qty_multiple validation", err.Error())
 	}
 }
@@ -409,7 +414,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "field regime not found") {
+	if !strings.Contains(err.Error(), "grid.regime: unknown field") {
 		t.Fatalf("Load() error = %q, want unknown removed regime field message", err.Error())
 	}
 }
@@ -443,7 +448,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "field unknown_field not found") {
+	if !strings.Contains(err.Error(), "grid.unknown_field: unknown field") {
 		t.Fatalf("Load() error = %q, want unknown field message", err.Error())
 	}
 }
@@ -513,7 +518,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "mode must be backtest, testnet, or live") {
+	if !strings.Contains(err.Error(), "mode: must be backtest, testnet, or live") {
 		t.Fatalf("Load() error = %q, want mode validation", err.Error())
 	}
 }
@@ -550,7 +555,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "observability.runtime.heartbeat_sec must be between 0 and 3600") {
+	if !strings.Contains(err.Error(), "observability.runtime.heartbeat_sec: must be between 0 and 3600") {
 		t.Fatalf("Load() error = %q, want heartbeat validation", err.Error())
 	}
 }
@@ -587,7 +592,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "observability.runtime.reconcile_interval_sec must be 0 or >= 10") {
+	if !strings.Contains(err.Error(), "observability.runtime.reconcile_interval_sec: must be 0 or >= 10") {
 		t.Fatalf("Load() error = %q, want reconcile interval validation", err.Error())
 	}
 }
@@ -624,7 +629,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "observability.runtime.alert_drop_report_sec must be between 0 and 3600") {
+	if !strings.Contains(err.Error(), "observability.runtime.alert_drop_report_sec: must be between 0 and 3600") {
 		t.Fatalf("Load() error = %q, want alert_drop_report_sec validation", err.Error())
 	}
 }
@@ -662,7 +667,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "exchange ws_base_url scheme must be ws or wss") {
+	if !strings.Contains(err.Error(), "exchange.ws_base_url: scheme must be ws or wss") {
 		t.Fatalf("Load() error = %q, want ws url scheme validation", err.Error())
 	}
 }
@@ -771,7 +776,7 @@ backtest:
 	if err == nil {
 		t.Fatalf("Load() error = nil, want error")
 	}
-	if !strings.Contains(err.Error(), "state.lock_stale_sec must be between 0 and 86400") {
+	if !strings.Contains(err.Error(), "state.lock_stale_sec: must be between 0 and 86400") {
 		t.Fatalf("Load() error = %q, want state lock stale validation", err.Error())
 	}
 }
@@ -815,6 +820,190 @@ backtest:
 	}
 }
 
+func TestLoadVolatilityPauseAppliesDefaults(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  levels: 20
+  qty: "0.001"
+  volatility_pause:
+    enabled: true
+    threshold_pct: "4"
+
+backtest:
+  data_path: data/binance/BTCUSDT/1m
+`)
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	vp := cfg.Grid.VolatilityPause
+	if vp.WindowSec != 300 {
+		t.Fatalf("grid.volatility_pause.window_sec = %d, want 300", vp.WindowSec)
+	}
+	if vp.CooldownSec != 600 {
+		t.Fatalf("grid.volatility_pause.cooldown_sec = %d, want 600", vp.CooldownSec)
+	}
+	if !vp.ThresholdPct.Equal(decimal.NewFromInt(4)) {
+		t.Fatalf("grid.volatility_pause.threshold_pct = %s, want 4", vp.ThresholdPct.String())
+	}
+}
+
+func TestLoadRejectsVolatilityPauseWithoutThreshold(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  levels: 20
+  qty: "0.001"
+  volatility_pause:
+    enabled: true
+
+backtest:
+  data_path: data/binance/BTCUSDT/1m
+`)
+
+	_, err := Load(cfgPath)
+	if err == nil {
+		t.Fatalf("Load() error = nil, want error")
+	}
+	if !strings.Contains(err.Error(), "grid.volatility_pause.threshold_pct: must be > 0") {
+		t.Fatalf("Load() error = %q, want volatility_pause threshold validation", err.Error())
+	}
+}
+
+func TestLoadRejectsFloorPriceAboveStopPrice(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  stop_price: "100"
+  floor_price: "100"
+  ratio: "1.01"
+  levels: 20
+  qty: "0.001"
+
+backtest:
+  data_path: data/binance/BTCUSDT/1m
+`)
+
+	_, err := Load(cfgPath)
+	if err == nil {
+		t.Fatalf("Load() error = nil, want error")
+	}
+	if !strings.Contains(err.Error(), "grid.floor_price: must be < stop_price") {
+		t.Fatalf("Load() error = %q, want floor_price validation", err.Error())
+	}
+}
+
+func TestLoadOversizedFillToleranceAcceptsMinusOneToDisable(t *testing.T) {
+	const tmpl = `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  stop_price: "100"
+  ratio: "1.01"
+  levels: 20
+  qty: "0.001"
+  oversized_fill_tolerance_pct: "%s"
+
+backtest:
+  data_path: data/binance/BTCUSDT/1m
+`
+	cfg, err := Load(writeTempConfig(t, fmt.Sprintf(tmpl, "-1")))
+	if err != nil {
+		t.Fatalf("Load() error = %v, want -1 accepted", err)
+	}
+	if tol := cfg.Grid.OversizedFillTolerancePct; tol == nil || !tol.Equal(decimal.NewFromInt(-1)) {
+		t.Fatalf("oversized_fill_tolerance_pct = %v, want -1", tol)
+	}
+
+	_, err = Load(writeTempConfig(t, fmt.Sprintf(tmpl, "-2")))
+	if err == nil || !strings.Contains(err.Error(), "grid.oversized_fill_tolerance_pct: must be -1 (disabled) or between 0 and 1000") {
+		t.Fatalf("Load() error = %v, want oversized_fill_tolerance_pct validation", err)
+	}
+}
+
+func TestLoadMultiSymbolBacktestNeedsPerSymbolPricesAndQty(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  stop_price: "120000"
+  ratio: "1.01"
+  levels: 20
+  qty: "0.001"
+
+backtest:
+  initial_base: "1"
+  rules:
+    qty_step: "0.001"
+  symbols:
+    - symbol: BTCUSDT
+      data_path: data/btc
+      qty: "0.001"
+    - symbol: ETHUSDT
+      data_path: data/eth
+`)
+
+	_, err := Load(cfgPath)
+	if err == nil {
+		t.Fatalf("Load() error = nil, want error")
+	}
+	for _, want := range []string{
+		"grid.stop_price: cannot be shared by 2 backtest.symbols",
+		"backtest.initial_base: cannot be shared by 2 backtest.symbols",
+		"backtest.rules: cannot be shared by 2 backtest.symbols",
+		"backtest.symbols[1].qty: must be > 0 when backtesting more than one symbol",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Load() error = %q, want %q", err.Error(), want)
+		}
+	}
+	if strings.Contains(err.Error(), "backtest.symbols[0].qty") {
+		t.Fatalf("Load() error = %q, want symbols[0] qty accepted", err.Error())
+	}
+
+	cfgPath = writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  levels: 20
+
+backtest:
+  initial_quote: "1000"
+  symbols:
+    - symbol: BTCUSDT
+      data_path: data/btc
+      stop_price: "120000"
+      qty: "0.001"
+      initial_base: "0.01"
+      rules:
+        qty_step: "0.00001"
+    - symbol: ETHUSDT
+      data_path: data/eth
+      qty: "0.01"
+`)
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load() per-symbol config error = %v", err)
+	}
+	if got := cfg.Backtest.Symbols[0]; !got.StopPrice.Equal(decimal.NewFromInt(120000)) || !got.Rules.QtyStep.Equal(decimal.RequireFromString("0.00001")) {
+		t.Fatalf("symbols[0] = %+v, want its own stop_price and rules", got)
+	}
+}
+
 func writeTempConfig(t *testing.T, content string) string {
 	t.Helper()
 	path := filepath.Join(t.TempDir(), "config.yaml")
@@ -823,3 +1012,121 @@ func writeTempConfig(t *testing.T, content string) string {
 	}
 	return path
 }
+
+func TestLoadReadsCredentialsFromEnv(t *testing.T) {
+	t.Setenv("GRIDBOT_TEST_API_KEY", "env-key")
+	t.Setenv("GRIDBOT_TEST_API_SECRET", "env-secret")
+	base := `
+mode: testnet
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  levels: 20
+  shift_levels: 10
+  qty: "0.001"
+
+exchange:
+  api_key_env: GRIDBOT_TEST_API_KEY
+  api_secret_env: GRIDBOT_TEST_API_SECRET
+`
+	cfg, err := Load(writeTempConfig(t, base))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Exchange.APIKey != "env-key" || cfg.Exchange.APISecret != "env-secret" {
+		t.Fatalf("credentials = %q/%q, want env-key/env-secret", cfg.Exchange.APIKey, cfg.Exchange.APISecret)
+	}
+
+	_, err = Load(writeTempConfig(t, base+"  api_key: \"k\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "set only one of") {
+		t.Fatalf("Load() with inline and env credentials error = %v, want single-source rejection", err)
+	}
+}
+
+func TestLoadRejectsGridExceedingMaxOpenOrders(t *testing.T) {
+	base := `
+mode: testnet
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  levels: 20
+  shift_levels: 10
+  qty: "0.001"
+
+exchange:
+  api_key: "k"
+  api_secret: "s"
+  max_open_orders: 25
+`
+	_, err := Load(writeTempConfig(t, base))
+	if err == nil {
+		t.Fatalf("Load() error = nil, want max_open_orders rejection")
+	}
+	if !strings.Contains(err.Error(), "grid needs 32 open order slots") {
+		t.Fatalf("Load() error = %q, want open order slot validation", err.Error())
+	}
+
+	cfg, err := Load(writeTempConfig(t, base+"  max_open_orders_action: shrink\n"))
+	if err != nil {
+		t.Fatalf("Load() with shrink error = %v", err)
+	}
+	if cfg.Grid.Levels != 13 || cfg.Grid.ShiftLevels != 10 || cfg.Grid.ShrunkFromLevels != 20 {
+		t.Fatalf("shrunk grid = levels %d shift %d from %d, want 13/10 from 20", cfg.Grid.Levels, cfg.Grid.ShiftLevels, cfg.Grid.ShrunkFromLevels)
+	}
+}
+
+func TestLoadReportsAllValidationErrorsAtOnce(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  ratio: "0.99"
+  levels: 20
+  shift_levels: 30
+  qty: "0"
+
+backtest:
+  data_path: data/binance/BTCUSDT/1m
+  fees:
+    maker_rate: "-0.001"
+`)
+
+	_, err := Load(cfgPath)
+	if err == nil {
+		t.Fatalf("Load() error = nil, want validation errors")
+	}
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Load() error = %T, want ValidationError", err)
+	}
+	for _, want := range []string{
+		"grid.ratio: must be > 1, got 0.99",
+		"grid.shift_levels: must be between 1 and levels (20), got 30",
+		"grid.qty: must be > 0, got 0",
+		"backtest.fees.maker_rate: must be >= 0, got -0.001",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Load() error = %q, want it to contain %q", err.Error(), want)
+		}
+	}
+	if len(verr.Problems) != 4 {
+		t.Fatalf("problems = %q, want 4", verr.Problems)
+	}
+
+	_, err = Load(writeTempConfig(t, `
+mode: backtest
+symbol: BTCUSDT
+grid:
+  ratio: "1.01"
+  ratoi: "1.02"
+backtest:
+  fee:
+    maker_rate: "0"
+`))
+	if err == nil || !strings.Contains(err.Error(), "grid.ratoi: unknown field (line 5)") || !strings.Contains(err.Error(), "backtest.fee: unknown field (line 7)") {
+		t.Fatalf("Load() error = %v, want both unknown fields by path", err)
+	}
+}
@@ -11,6 +11,9 @@ var (
 	ErrOrderNotFound = errors.New("order not found")
 	// ErrOrderRejected indicates the order was rejected by exchange.
 	ErrOrderRejected = errors.New("order rejected")
+	// ErrWouldMatch indicates a maker-only order was rejected because it
+	// would have matched immediately.
+	ErrWouldMatch = errors.New("order would immediately match")
 	// ErrOrderExpired indicates the order has expired on exchange.
 	ErrOrderExpired = errors.New("order expired")
 )
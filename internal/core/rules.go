@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/shopspring/decimal"
 )
@@ -10,12 +11,18 @@ var (
 	ErrInvalidOrder     = errors.New("invalid order")
 	ErrBelowMinQty      = errors.New("qty below min")
 	ErrBelowMinNotional = errors.New("notional below min")
+	// ErrMinNotionalUnfundable means the qty needed to reach min notional at
+	// the order price is above the configured per-order maximum.
+	ErrMinNotionalUnfundable = errors.New("min_notional_unfundable")
 )
 
 func NormalizeOrder(order Order, rules Rules) (Order, error) {
 	if order.Qty.Cmp(decimal.Zero) <= 0 {
 		return order, ErrInvalidOrder
 	}
+	if order.PostOnly && order.Type != Limit {
+		return order, ErrInvalidOrder
+	}
 	if rules.QtyStep.Cmp(decimal.Zero) > 0 {
 		order.Qty = RoundDown(order.Qty, rules.QtyStep)
 	}
@@ -45,6 +52,23 @@ func NormalizeOrder(order Order, rules Rules) (Order, error) {
 	return order, nil
 }
 
+// CheckMinNotionalFundable returns ErrMinNotionalUnfundable when the qty the
+// min-notional floor implies at price is above maxQty, instead of letting
+// NormalizeOrder size the order up past it. maxQty <= 0 disables the check.
+func CheckMinNotionalFundable(price, maxQty decimal.Decimal, rules Rules) error {
+	if maxQty.Cmp(decimal.Zero) <= 0 || rules.MinNotional.Cmp(decimal.Zero) <= 0 || price.Cmp(decimal.Zero) <= 0 {
+		return nil
+	}
+	need := roundUp(rules.MinNotional.Div(price), rules.QtyStep)
+	if need.Cmp(maxQty) > 0 {
+		return fmt.Errorf(
+			"%w: min_notional %s at price %s needs qty %s, above max %s",
+			ErrMinNotionalUnfundable, rules.MinNotional, price, need, maxQty,
+		)
+	}
+	return nil
+}
+
 func ensureMinNotionalQty(price, qty decimal.Decimal, rules Rules) decimal.Decimal {
 	out := qty
 	if rules.MinNotional.Cmp(decimal.Zero) > 0 && price.Cmp(decimal.Zero) > 0 {
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -29,8 +30,22 @@ const (
 	OrderCanceled        OrderStatus = "CANCELED"
 	OrderRejected        OrderStatus = "REJECTED"
 	OrderExpired         OrderStatus = "EXPIRED"
+	// OrderPendingCancel is a cancel the exchange accepted but has not
+	// finished; the order stays open and can still fill until it reports
+	// CANCELED.
+	OrderPendingCancel OrderStatus = "PENDING_CANCEL"
 )
 
+// ParseOrderStatus maps an exchange order status to OrderStatus. PENDING_NEW
+// (accepted, not yet on the book) is tracked as NEW.
+func ParseOrderStatus(raw string) OrderStatus {
+	status := OrderStatus(strings.ToUpper(strings.TrimSpace(raw)))
+	if status == "PENDING_NEW" {
+		return OrderNew
+	}
+	return status
+}
+
 type Order struct {
 	ID        string
 	ClientID  string
@@ -43,8 +58,20 @@ type Order struct {
 	CreatedAt time.Time
 	FilledAt  *time.Time
 	GridIndex int
+	// Origin is why the strategy placed the order (OriginBootstrap,
+	// OriginCounter, OriginShift); empty when unknown, e.g. adopted orders.
+	Origin string `json:",omitempty"`
+	// PostOnly limit orders are rejected with ErrWouldMatch instead of
+	// taking liquidity.
+	PostOnly bool `json:",omitempty"`
 }
 
+const (
+	OriginBootstrap = "bootstrap"
+	OriginCounter   = "counter"
+	OriginShift     = "shift"
+)
+
 type Trade struct {
 	OrderID string
 	TradeID string
@@ -54,6 +81,14 @@ type Trade struct {
 	Qty     decimal.Decimal
 	Status  OrderStatus
 	Time    time.Time
+	// CumQty/CumQuote are the order's executed qty and quote through this
+	// fill, when the source reports them; zero otherwise.
+	CumQty   decimal.Decimal
+	CumQuote decimal.Decimal
+	// Fee is the commission charged for this fill in FeeAsset, when the
+	// source reports it; fills recovered by reconcile carry none.
+	Fee      decimal.Decimal
+	FeeAsset string
 }
 
 type Rules struct {
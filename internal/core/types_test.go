@@ -0,0 +1,20 @@
+package core
+
+import "testing"
+
+func TestParseOrderStatusMapsTransitionalStatuses(t *testing.T) {
+	cases := map[string]OrderStatus{
+		"NEW":              OrderNew,
+		"PENDING_NEW":      OrderNew,
+		" pending_new ":    OrderNew,
+		"PENDING_CANCEL":   OrderPendingCancel,
+		"PARTIALLY_FILLED": OrderPartiallyFilled,
+		"FILLED":           OrderFilled,
+		"CANCELED":         OrderCanceled,
+	}
+	for raw, want := range cases {
+		if got := ParseOrderStatus(raw); got != want {
+			t.Fatalf("ParseOrderStatus(%q) = %s, want %s", raw, got, want)
+		}
+	}
+}
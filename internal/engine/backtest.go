@@ -2,8 +2,11 @@ package engine
 
 import (
 	"context"
+	"encoding/csv"
 	"errors"
 	"io"
+	"strconv"
+	"time"
 
 	"github.com/shopspring/decimal"
 
@@ -16,6 +19,12 @@ type BacktestRunner struct {
 	Exchange *backtest.SimExchange
 	Feed     backtest.Feed
 	Strategy strategy.Strategy
+
+	NormalizeEquityQuote decimal.Decimal
+
+	// RecordTrades keeps every fill in the result's TradeLog, e.g. for
+	// WriteCSV.
+	RecordTrades bool
 }
 
 type BacktestResult struct {
@@ -37,6 +46,29 @@ type BacktestResult struct {
 	MaxCapitalUsagePct  decimal.Decimal
 	FeesPaidQuote       decimal.Decimal
 	DailyPnLQuoteSeries []DailyPnL
+	// SkippedTicks counts feed ticks dropped for a zero or negative price.
+	SkippedTicks int
+	// IlliquidTicks counts ticks below the minimum fill volume, on which no
+	// limit order filled.
+	IlliquidTicks int
+
+	NormalizedStartEquityQuote decimal.Decimal
+	NormalizedEndEquityQuote   decimal.Decimal
+	NormalizedReturnPct        decimal.Decimal
+	NormalizedMaxDrawdownPct   decimal.Decimal
+
+	// TradeLog holds each fill with the equity right after it when the
+	// runner records trades.
+	TradeLog []BacktestTrade
+}
+
+// BacktestTrade is one fill and the quote equity at its price after it.
+type BacktestTrade struct {
+	Time        time.Time
+	Side        core.Side
+	Price       decimal.Decimal
+	Qty         decimal.Decimal
+	EquityQuote decimal.Decimal
 }
 
 type DailyPnL struct {
@@ -106,8 +138,13 @@ func (r *BacktestRunner) Run(ctx context.Context) (BacktestResult, error) {
 			}
 			return result, err
 		}
+		if tick.Price.Cmp(decimal.Zero) <= 0 {
+			result.SkippedTicks++
+			continue
+		}
 		if first {
 			result.StartPrice = tick.Price
+			r.Exchange.SetClock(tick.Time)
 			if err := r.Strategy.Init(ctx, tick.Price); err != nil {
 				if errors.Is(err, strategy.ErrStopped) {
 					stopped = true
@@ -118,9 +155,18 @@ func (r *BacktestRunner) Run(ctx context.Context) (BacktestResult, error) {
 			recordSnapshot(tick)
 			first = false
 		}
-		trades := r.Exchange.Match(tick.Price, tick.Time)
+		trades := r.Exchange.MatchTick(tick)
 		for _, trade := range trades {
 			result.Trades++
+			if r.RecordTrades {
+				result.TradeLog = append(result.TradeLog, BacktestTrade{
+					Time:        trade.Time,
+					Side:        trade.Side,
+					Price:       trade.Price,
+					Qty:         trade.Qty,
+					EquityQuote: r.Exchange.Snapshot(trade.Price).EquityQuote,
+				})
+			}
 			if err := r.Strategy.OnFill(ctx, trade); err != nil {
 				if errors.Is(err, strategy.ErrStopped) {
 					stopped = true
@@ -146,6 +192,7 @@ func (r *BacktestRunner) Run(ctx context.Context) (BacktestResult, error) {
 	}
 	bal, _ := r.Exchange.Balances(ctx)
 	result.FinalBalance = bal
+	result.IlliquidTicks = r.Exchange.IlliquidTicks()
 	result.MarketBuyCount, result.MarketBuyQty = r.Exchange.MarketBuyStats()
 	if result.EndPrice.Cmp(decimal.Zero) > 0 {
 		result.EndEquityQuote = bal.Quote.Add(bal.Base.Mul(result.EndPrice))
@@ -164,6 +211,14 @@ func (r *BacktestRunner) Run(ctx context.Context) (BacktestResult, error) {
 		// Capital-denominated drawdown: max equity drop over peak locked capital.
 		result.CapitalDrawdownPct = result.MaxDrawdownQuote.Div(maxLockedCapital).Mul(decimal.NewFromInt(100))
 	}
+	if r.NormalizeEquityQuote.Cmp(decimal.Zero) > 0 {
+		// Same trade sequence yields the same normalized figures regardless of initial balances.
+		notional := r.NormalizeEquityQuote
+		result.NormalizedStartEquityQuote = notional
+		result.NormalizedEndEquityQuote = notional.Add(result.ProfitQuote)
+		result.NormalizedReturnPct = result.ProfitQuote.Div(notional).Mul(decimal.NewFromInt(100))
+		result.NormalizedMaxDrawdownPct = result.MaxDrawdownQuote.Div(notional).Mul(decimal.NewFromInt(100))
+	}
 	prevClose := result.StartEquityQuote
 	for _, day := range dayOrder {
 		closeEquity := dailyClose[day]
@@ -176,3 +231,46 @@ func (r *BacktestRunner) Run(ctx context.Context) (BacktestResult, error) {
 	}
 	return result, nil
 }
+
+// WriteCSV writes one row per logged trade (time, side, price, qty, running
+// equity) followed by summary rows. Summary rows keep the five columns,
+// "summary,<metric>,<value>,,", so the file parses as a single table.
+func (res BacktestResult) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "side", "price", "qty", "equity_quote"}); err != nil {
+		return err
+	}
+	for _, t := range res.TradeLog {
+		row := []string{t.Time.UTC().Format(time.RFC3339Nano), string(t.Side), t.Price.String(), t.Qty.String(), t.EquityQuote.String()}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	summary := [][2]string{
+		{"trades", strconv.Itoa(res.Trades)},
+		{"market_buy_count", strconv.Itoa(res.MarketBuyCount)},
+		{"market_buy_qty", res.MarketBuyQty.String()},
+		{"start_price", res.StartPrice.String()},
+		{"end_price", res.EndPrice.String()},
+		{"start_equity_quote", res.StartEquityQuote.String()},
+		{"end_equity_quote", res.EndEquityQuote.String()},
+		{"profit_quote", res.ProfitQuote.String()},
+		{"total_return_pct", res.TotalReturnPct.StringFixed(4)},
+		{"equity_return_pct", res.EquityReturnPct.StringFixed(4)},
+		{"max_locked_capital_quote", res.MaxLockedCapital.String()},
+		{"max_drawdown_pct", res.MaxDrawdownPct.StringFixed(4)},
+		{"max_drawdown_quote", res.MaxDrawdownQuote.String()},
+		{"capital_drawdown_pct", res.CapitalDrawdownPct.StringFixed(4)},
+		{"max_capital_usage_pct", res.MaxCapitalUsagePct.StringFixed(4)},
+		{"fees_paid_quote", res.FeesPaidQuote.String()},
+		{"final_base", res.FinalBalance.Base.String()},
+		{"final_quote", res.FinalBalance.Quote.String()},
+	}
+	for _, kv := range summary {
+		if err := cw.Write([]string{"summary", kv[0], kv[1], "", ""}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
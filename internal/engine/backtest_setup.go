@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/backtest"
+	"grid-trading/internal/config"
+	"grid-trading/internal/core"
+	"grid-trading/internal/strategy"
+)
+
+// BacktestLeg is what differs between the symbols of one backtest config;
+// everything else comes from the shared grid and backtest sections.
+type BacktestLeg struct {
+	Symbol    string
+	DataPath  string
+	StopPrice decimal.Decimal
+	Qty       decimal.Decimal
+	Balance   core.Balance
+	Rules     core.Rules
+}
+
+// ConfigBacktestLeg is cfg's own symbol run over dataPath.
+func ConfigBacktestLeg(cfg config.Config, dataPath string) BacktestLeg {
+	return BacktestLeg{
+		Symbol:    cfg.Symbol,
+		DataPath:  dataPath,
+		StopPrice: cfg.Grid.StopPrice.Decimal,
+		Qty:       cfg.Grid.Qty.Decimal,
+		Balance: core.Balance{
+			Base:  cfg.Backtest.InitialBase.Decimal,
+			Quote: cfg.Backtest.InitialQuote.Decimal,
+		},
+		Rules: backtestRules(cfg.Backtest.Rules),
+	}
+}
+
+// SymbolBacktestLeg resolves a backtest symbol's own values over the shared
+// ones; config validation keeps the shared ones unset for several symbols.
+func SymbolBacktestLeg(cfg config.Config, sym config.BacktestSymbol) BacktestLeg {
+	pick := func(own, shared config.Decimal) decimal.Decimal {
+		if own.Cmp(decimal.Zero) > 0 {
+			return own.Decimal
+		}
+		return shared.Decimal
+	}
+	rules := cfg.Backtest.Rules
+	if !sym.Rules.IsZero() {
+		rules = sym.Rules
+	}
+	return BacktestLeg{
+		Symbol:    sym.Symbol,
+		DataPath:  sym.DataPath,
+		StopPrice: pick(sym.StopPrice, cfg.Grid.StopPrice),
+		Qty:       pick(sym.Qty, cfg.Grid.Qty),
+		Balance: core.Balance{
+			Base:  pick(sym.InitialBase, cfg.Backtest.InitialBase),
+			Quote: pick(sym.InitialQuote, cfg.Backtest.InitialQuote),
+		},
+		Rules: backtestRules(rules),
+	}
+}
+
+// NewBacktest opens leg's feed and wires a SimExchange and SpotDual for it
+// from cfg. The runner owns the feed; close it yourself only if you never
+// call Run.
+func NewBacktest(cfg config.Config, leg BacktestLeg) (BacktestRunner, *strategy.SpotDual, error) {
+	feed, err := backtest.NewJSONLFeed(leg.DataPath)
+	if err != nil {
+		return BacktestRunner{}, nil, err
+	}
+	feed.SetRejectNonPositive(cfg.Backtest.InvalidPrice == config.InvalidPriceError)
+	ex := backtest.NewSimExchange(leg.Symbol, leg.Balance, core.Rules{})
+	if err := ex.SetFees(cfg.Backtest.Fees.MakerRate.Decimal, cfg.Backtest.Fees.TakerRate.Decimal); err != nil {
+		_ = feed.Close()
+		return BacktestRunner{}, nil, err
+	}
+	if err := ex.SetMinFillVolume(cfg.Backtest.MinFillVolume.Decimal); err != nil {
+		_ = feed.Close()
+		return BacktestRunner{}, nil, err
+	}
+	strat := strategy.NewSpotDual(leg.Symbol, leg.StopPrice, cfg.Grid.Ratio.Decimal, cfg.Grid.Levels, cfg.Grid.ShiftLevels, leg.Qty, cfg.Grid.MinQtyMultiple, leg.Rules, nil, ex)
+	strat.ApplyGridConfig(cfg.Grid)
+	strat.SetCapitalBudget(cfg.Capital.QuoteBudget.Decimal)
+	return BacktestRunner{Exchange: ex, Feed: feed, Strategy: strat}, strat, nil
+}
+
+func backtestRules(r config.BacktestRules) core.Rules {
+	return core.Rules{
+		MinQty:      r.MinQty.Decimal,
+		MinNotional: r.MinNotional.Decimal,
+		PriceTick:   r.PriceTick.Decimal,
+		QtyStep:     r.QtyStep.Decimal,
+	}
+}
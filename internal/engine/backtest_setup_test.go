@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+)
+
+func TestSymbolBacktestLegPrefersPerSymbolValues(t *testing.T) {
+	dec := func(v string) config.Decimal { return config.Decimal{Decimal: decimal.RequireFromString(v)} }
+	cfg := config.Config{
+		Grid: config.GridConfig{Qty: dec("0.001")},
+		Backtest: config.BacktestConfig{
+			InitialQuote: dec("1000"),
+			Rules:        config.BacktestRules{QtyStep: dec("0.001")},
+		},
+	}
+
+	own := SymbolBacktestLeg(cfg, config.BacktestSymbol{
+		Symbol:      "ETHUSDT",
+		StopPrice:   dec("5000"),
+		Qty:         dec("0.05"),
+		InitialBase: dec("2"),
+		Rules:       config.BacktestRules{QtyStep: dec("0.0001")},
+	})
+	if !own.StopPrice.Equal(decimal.NewFromInt(5000)) || !own.Qty.Equal(decimal.RequireFromString("0.05")) ||
+		!own.Balance.Base.Equal(decimal.NewFromInt(2)) || !own.Rules.QtyStep.Equal(decimal.RequireFromString("0.0001")) {
+		t.Fatalf("leg = %+v, want the symbol's own stop, qty, base and rules", own)
+	}
+	if !own.Balance.Quote.Equal(decimal.NewFromInt(1000)) {
+		t.Fatalf("quote = %s, want the shared 1000", own.Balance.Quote)
+	}
+
+	shared := SymbolBacktestLeg(cfg, config.BacktestSymbol{Symbol: "BTCUSDT"})
+	if !shared.Qty.Equal(decimal.RequireFromString("0.001")) || !shared.Rules.QtyStep.Equal(decimal.RequireFromString("0.001")) || !shared.StopPrice.IsZero() {
+		t.Fatalf("leg = %+v, want the shared qty and rules", shared)
+	}
+}
+
+func TestNewBacktestAppliesBacktestSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dirty.jsonl")
+	if err := os.WriteFile(path, []byte(`{"time":1700000000,"close":100}`+"\n"+`{"time":1700000060,"close":0}`+"\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	dec := func(v string) config.Decimal { return config.Decimal{Decimal: decimal.RequireFromString(v)} }
+	cfg := config.Config{
+		Symbol: "BTCUSDT",
+		Grid:   config.GridConfig{Ratio: dec("1.01"), Levels: 4, ShiftLevels: 2, Qty: dec("0.01"), MinQtyMultiple: 1},
+		Backtest: config.BacktestConfig{
+			InitialBase:  dec("1"),
+			InitialQuote: dec("1000"),
+			InvalidPrice: config.InvalidPriceError,
+			Fees:         config.BacktestFees{MakerRate: dec("0.001"), TakerRate: dec("0.001")},
+		},
+	}
+
+	runner, strat, err := NewBacktest(cfg, ConfigBacktestLeg(cfg, path))
+	if err != nil {
+		t.Fatalf("NewBacktest() error = %v", err)
+	}
+	if runner.Strategy != strat {
+		t.Fatalf("runner strategy = %v, want the returned SpotDual", runner.Strategy)
+	}
+	if _, err := runner.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "dirty.jsonl:2") {
+		t.Fatalf("Run() error = %v, want invalid_price: error to reject dirty.jsonl:2", err)
+	}
+
+	cfg.Backtest.Fees.MakerRate = dec("-0.001")
+	if _, _, err := NewBacktest(cfg, ConfigBacktestLeg(cfg, path)); err == nil {
+		t.Fatal("NewBacktest() error = nil, want negative fee rejected")
+	}
+}
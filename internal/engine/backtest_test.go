@@ -3,7 +3,12 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -326,6 +331,95 @@ func TestBacktestRunnerUsesMaxLockedCapitalForTotalReturnPct(t *testing.T) {
 	}
 }
 
+func TestBacktestRunnerWriteCSVListsTradesAndSummary(t *testing.T) {
+	t0 := time.Unix(50, 0).UTC()
+	feed := &multiTickFeed{
+		ticks: []backtest.Tick{
+			{Time: t0, Price: decimal.NewFromInt(100)},
+			{Time: t0.Add(time.Minute), Price: decimal.NewFromInt(120)},
+		},
+	}
+	ex := backtest.NewSimExchange(
+		"BTCUSDT",
+		core.Balance{Base: decimal.Zero, Quote: decimal.NewFromInt(1000)},
+		core.Rules{},
+	)
+	runner := BacktestRunner{
+		Exchange:     ex,
+		Feed:         feed,
+		Strategy:     &buyAndHoldLimitStrategy{ex: ex, qty: decimal.NewFromInt(1)},
+		RecordTrades: true,
+	}
+	res, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var out strings.Builder
+	if err := res.WriteCSV(&out); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "time,side,price,qty,equity_quote" {
+		t.Fatalf("header = %q", lines[0])
+	}
+	if want := "1970-01-01T00:00:50Z,BUY,100,1,1000"; lines[1] != want {
+		t.Fatalf("trade row = %q, want %q", lines[1], want)
+	}
+	if !strings.HasPrefix(lines[2], "summary,trades,1,") {
+		t.Fatalf("first summary row = %q, want trades=1", lines[2])
+	}
+	if !strings.Contains(out.String(), "summary,profit_quote,20,,\n") {
+		t.Fatalf("csv missing profit_quote summary:\n%s", out.String())
+	}
+}
+
+func TestBacktestRunnerNormalizeEquityIgnoresInitialBalances(t *testing.T) {
+	run := func(initialQuote int64) BacktestResult {
+		t0 := time.Unix(60, 0).UTC()
+		feed := &multiTickFeed{
+			ticks: []backtest.Tick{
+				{Time: t0, Price: decimal.NewFromInt(100)},
+				{Time: t0.Add(time.Minute), Price: decimal.NewFromInt(80)},
+				{Time: t0.Add(2 * time.Minute), Price: decimal.NewFromInt(120)},
+			},
+		}
+		ex := backtest.NewSimExchange(
+			"BTCUSDT",
+			core.Balance{Base: decimal.Zero, Quote: decimal.NewFromInt(initialQuote)},
+			core.Rules{},
+		)
+		runner := BacktestRunner{
+			Exchange:             ex,
+			Feed:                 feed,
+			Strategy:             &buyAndHoldLimitStrategy{ex: ex, qty: decimal.NewFromInt(1)},
+			NormalizeEquityQuote: decimal.NewFromInt(10000),
+		}
+		res, err := runner.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		return res
+	}
+
+	small := run(1000)
+	large := run(5000)
+	if small.EquityReturnPct.Equal(large.EquityReturnPct) {
+		t.Fatalf("absolute EquityReturnPct should differ, both = %s", small.EquityReturnPct)
+	}
+	if !small.NormalizedReturnPct.Equal(large.NormalizedReturnPct) {
+		t.Fatalf("NormalizedReturnPct = %s vs %s, want equal", small.NormalizedReturnPct, large.NormalizedReturnPct)
+	}
+	if !small.NormalizedReturnPct.Equal(decimal.RequireFromString("0.2")) {
+		t.Fatalf("NormalizedReturnPct = %s, want 0.2", small.NormalizedReturnPct)
+	}
+	if small.NormalizedReturnPct.Equal(small.EquityReturnPct) {
+		t.Fatalf("NormalizedReturnPct should differ from absolute EquityReturnPct %s", small.EquityReturnPct)
+	}
+	if !small.NormalizedEndEquityQuote.Equal(decimal.NewFromInt(10020)) {
+		t.Fatalf("NormalizedEndEquityQuote = %s, want 10020", small.NormalizedEndEquityQuote)
+	}
+}
+
 type singleTickFeed struct {
 	tick        backtest.Tick
 	emitted     bool
@@ -364,3 +458,219 @@ func (f *multiTickFeed) Close() error {
 	f.closeCalled = true
 	return nil
 }
+
+func TestBacktestRunnerSkipsNonPositivePriceLines(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, closes ...string) string {
+		path := filepath.Join(dir, name)
+		var lines []string
+		for i, c := range closes {
+			lines = append(lines, fmt.Sprintf(`{"time":%d,"close":%s}`, 1700000000+i*60, c))
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return path
+	}
+	run := func(path string) (BacktestResult, []core.Order) {
+		feed, err := backtest.NewJSONLFeed(path)
+		if err != nil {
+			t.Fatalf("NewJSONLFeed() error = %v", err)
+		}
+		ex := backtest.NewSimExchange(
+			"BTCUSDT",
+			core.Balance{Base: decimal.NewFromInt(1), Quote: decimal.NewFromInt(1000)},
+			core.Rules{},
+		)
+		strat := strategy.NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.01"), 4, 2, decimal.RequireFromString("0.01"), 1, core.Rules{}, nil, ex)
+		runner := BacktestRunner{Exchange: ex, Feed: feed, Strategy: strat}
+		res, err := runner.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		open, err := ex.OpenOrders(context.Background(), "BTCUSDT")
+		if err != nil {
+			t.Fatalf("OpenOrders() error = %v", err)
+		}
+		sort.Slice(open, func(i, j int) bool { return open[i].Price.LessThan(open[j].Price) })
+		return res, open
+	}
+
+	clean, cleanOpen := run(write("clean.jsonl", "100", "100.5"))
+	dirty, dirtyOpen := run(write("dirty.jsonl", "100", "0", "100.5"))
+	if dirty.SkippedTicks != 1 || clean.SkippedTicks != 0 {
+		t.Fatalf("SkippedTicks = %d (clean %d), want 1 (clean 0)", dirty.SkippedTicks, clean.SkippedTicks)
+	}
+	if !dirty.EndPrice.Equal(clean.EndPrice) || !dirty.EndEquityQuote.Equal(clean.EndEquityQuote) {
+		t.Fatalf("dirty end = %s/%s, want %s/%s", dirty.EndPrice, dirty.EndEquityQuote, clean.EndPrice, clean.EndEquityQuote)
+	}
+	if len(dirtyOpen) != len(cleanOpen) || len(cleanOpen) == 0 {
+		t.Fatalf("open orders = %d, want %d (non-zero)", len(dirtyOpen), len(cleanOpen))
+	}
+	for i := range cleanOpen {
+		if cleanOpen[i].Side != dirtyOpen[i].Side || !cleanOpen[i].Price.Equal(dirtyOpen[i].Price) {
+			t.Fatalf("open order %d = %s@%s, want %s@%s", i, dirtyOpen[i].Side, dirtyOpen[i].Price, cleanOpen[i].Side, cleanOpen[i].Price)
+		}
+	}
+
+	strict, err := backtest.NewJSONLFeed(filepath.Join(dir, "dirty.jsonl"))
+	if err != nil {
+		t.Fatalf("NewJSONLFeed() error = %v", err)
+	}
+	defer strict.Close()
+	strict.SetRejectNonPositive(true)
+	if _, err := strict.Next(); err != nil {
+		t.Fatalf("first Next() error = %v", err)
+	}
+	if _, err := strict.Next(); err == nil || !strings.Contains(err.Error(), "dirty.jsonl:2") {
+		t.Fatalf("strict Next() error = %v, want non-positive price at dirty.jsonl:2", err)
+	}
+}
+
+func TestBacktestRunnerMinFillVolumeSkipsIlliquidTicks(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, lines ...string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		return path
+	}
+	run := func(path string) BacktestResult {
+		feed, err := backtest.NewJSONLFeed(path)
+		if err != nil {
+			t.Fatalf("NewJSONLFeed() error = %v", err)
+		}
+		ex := backtest.NewSimExchange(
+			"BTCUSDT",
+			core.Balance{Base: decimal.NewFromInt(1), Quote: decimal.NewFromInt(1000)},
+			core.Rules{},
+		)
+		if err := ex.SetMinFillVolume(decimal.NewFromInt(1)); err != nil {
+			t.Fatalf("SetMinFillVolume() error = %v", err)
+		}
+		strat := strategy.NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.01"), 4, 2, decimal.RequireFromString("0.01"), 1, core.Rules{}, nil, ex)
+		runner := BacktestRunner{Exchange: ex, Feed: feed, Strategy: strat}
+		res, err := runner.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		return res
+	}
+
+	// 98.5 crosses the first buy level (~99.01) below the 100 anchor.
+	illiquid := run(write("illiquid.jsonl",
+		`{"time":1700000000,"close":100,"volume":5}`,
+		`{"time":1700000060,"close":98.5,"volume":0}`,
+	))
+	if illiquid.Trades != 0 || illiquid.IlliquidTicks != 1 {
+		t.Fatalf("zero-volume cross: trades=%d illiquid_ticks=%d, want 0 and 1", illiquid.Trades, illiquid.IlliquidTicks)
+	}
+
+	recovered := run(write("recovered.jsonl",
+		`{"time":1700000000,"close":100,"volume":5}`,
+		`{"time":1700000060,"close":98.5,"volume":0}`,
+		`{"time":1700000120,"close":98.5,"volume":3}`,
+	))
+	if recovered.Trades != 1 || recovered.IlliquidTicks != 1 {
+		t.Fatalf("next liquid tick: trades=%d illiquid_ticks=%d, want 1 and 1", recovered.Trades, recovered.IlliquidTicks)
+	}
+}
+
+func TestBacktestRunnerOrderTTLAgesOnDataTime(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	refreshAt := start.Add(2 * time.Hour)
+	feed := &multiTickFeed{ticks: []backtest.Tick{
+		{Time: start, Price: decimal.NewFromInt(100)},
+		{Time: start.Add(30 * time.Minute), Price: decimal.NewFromInt(100)},
+		{Time: refreshAt, Price: decimal.NewFromInt(100)},
+	}}
+	ex := backtest.NewSimExchange(
+		"BTCUSDT",
+		core.Balance{Base: decimal.NewFromInt(1), Quote: decimal.NewFromInt(1000)},
+		core.Rules{},
+	)
+	strat := strategy.NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.01"), 4, 2, decimal.RequireFromString("0.01"), 1, core.Rules{}, nil, ex)
+	strat.SetOrderTTL(time.Hour, time.Minute)
+	runner := BacktestRunner{Exchange: ex, Feed: feed, Strategy: strat}
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	open, err := ex.OpenOrders(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("OpenOrders() error = %v", err)
+	}
+	refreshed := 0
+	for _, ord := range open {
+		switch {
+		case ord.CreatedAt.Equal(refreshAt):
+			refreshed++
+		case !ord.CreatedAt.Equal(start):
+			t.Fatalf("order %s created at %s, want the first tick or the refresh tick", ord.ID, ord.CreatedAt)
+		}
+	}
+	if len(open) == 0 || refreshed != 1 {
+		t.Fatalf("refreshed orders = %d of %d, want exactly 1", refreshed, len(open))
+	}
+}
+
+func TestJSONLFeedReadsGlobAcrossFilesAndRejectsBackwardSeam(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, start int, closes ...string) {
+		var lines []string
+		for i, c := range closes {
+			lines = append(lines, fmt.Sprintf(`{"time":%d,"close":%s}`, start+i*60, c))
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	readAll := func(feed *backtest.JSONLFeed) ([]string, error) {
+		defer feed.Close()
+		var prices []string
+		for {
+			tick, err := feed.Next()
+			if errors.Is(err, io.EOF) {
+				return prices, nil
+			}
+			if err != nil {
+				return prices, err
+			}
+			prices = append(prices, tick.Price.String())
+		}
+	}
+
+	write("2024-01-02.jsonl", 1704153600, "102", "103")
+	write("2024-01-01.jsonl", 1704067200, "100", "101")
+	write("notes.txt", 0, "1")
+	feed, err := backtest.NewJSONLFeed(filepath.Join(dir, "2024-01-*.jsonl"))
+	if err != nil {
+		t.Fatalf("NewJSONLFeed(glob) error = %v", err)
+	}
+	prices, err := readAll(feed)
+	if err != nil {
+		t.Fatalf("read glob feed error = %v", err)
+	}
+	if got := strings.Join(prices, ","); got != "100,101,102,103" {
+		t.Fatalf("glob prices = %s, want 100,101,102,103", got)
+	}
+
+	if _, err := backtest.NewJSONLFeed(filepath.Join(dir, "2023-*.jsonl")); err == nil {
+		t.Fatalf("NewJSONLFeed(no match) error = nil, want error")
+	}
+
+	// 2024-01-03 starts before 2024-01-02 ends.
+	write("2024-01-03.jsonl", 1704153600, "104")
+	feed, err = backtest.NewJSONLFeed(dir)
+	if err != nil {
+		t.Fatalf("NewJSONLFeed(dir) error = %v", err)
+	}
+	prices, err = readAll(feed)
+	if err == nil || !strings.Contains(err.Error(), "2024-01-03.jsonl:1") || !strings.Contains(err.Error(), "time-ordered") {
+		t.Fatalf("read dir feed error = %v, want backward seam at 2024-01-03.jsonl:1", err)
+	}
+	if got := strings.Join(prices, ","); got != "100,101,102,103" {
+		t.Fatalf("prices before seam error = %s, want 100,101,102,103", got)
+	}
+}
@@ -0,0 +1,30 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"grid-trading/internal/strategy"
+)
+
+// checkBalanceFloor hands the free account balance to a BalanceWatcher
+// strategy on the heartbeat; funds locked in resting orders do not count. A
+// balance read failure skips the round.
+func (r *LiveRunner) checkBalanceFloor(ctx context.Context) error {
+	watcher, ok := r.Strategy.(strategy.BalanceWatcher)
+	if !r.BalanceFloorCheck || !ok || r.Exchange == nil {
+		return nil
+	}
+	bal, err := r.Exchange.FreeBalances(ctx)
+	if err != nil {
+		r.logf("WARN", "balance_floor_check_skipped", "err=%q", err.Error())
+		return nil
+	}
+	if err := watcher.ObserveBalance(ctx, bal); err != nil {
+		if errors.Is(err, strategy.ErrStopped) {
+			return err
+		}
+		r.logf("WARN", "balance_floor_resume_failed", "err=%q", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+	"grid-trading/internal/exchange/binance"
+)
+
+type balanceWatcherSpy struct {
+	liveStrategySpy
+	observed []core.Balance
+}
+
+func (s *balanceWatcherSpy) ObserveBalance(_ context.Context, bal core.Balance) error {
+	s.observed = append(s.observed, bal)
+	return nil
+}
+
+func TestCheckBalanceFloorPassesFreeBalance(t *testing.T) {
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			// Most of the quote sits in resting buys.
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "BTC", "free": "0.5", "locked": "2"},
+				{"asset": "USDT", "free": "5", "locked": "900"},
+			}})
+		default:
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "BTCUSDT",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+
+	strat := &balanceWatcherSpy{}
+	runner := LiveRunner{Exchange: client, Strategy: strat, Symbol: "BTCUSDT", BalanceFloorCheck: true}
+	if err := runner.checkBalanceFloor(context.Background()); err != nil {
+		t.Fatalf("checkBalanceFloor() error = %v", err)
+	}
+	if len(strat.observed) != 1 {
+		t.Fatalf("observed = %v, want one balance", strat.observed)
+	}
+	if got := strat.observed[0]; !got.Quote.Equal(decimal.NewFromInt(5)) || !got.Base.Equal(decimal.RequireFromString("0.5")) {
+		t.Fatalf("observed balance = %+v, want free quote 5 and free base 0.5", got)
+	}
+}
@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"grid-trading/internal/strategy"
+)
+
+const defaultDrainTimeout = 30 * time.Second
+
+// drainOnExit cancels the strategy's open orders when the runner stops on
+// context cancellation. It runs on a fresh context bounded by DrainTimeout
+// so a hanging exchange cannot hold up shutdown.
+func (r *LiveRunner) drainOnExit() {
+	canceler, ok := r.Strategy.(strategy.OrderCanceler)
+	if !r.CancelOrdersOnExit || !ok {
+		return
+	}
+	timeout := r.DrainTimeout
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	canceled, err := canceler.CancelAllOrders(ctx)
+	fields := map[string]string{
+		"symbol":   r.Symbol,
+		"canceled": strconv.Itoa(canceled),
+		"timeout":  timeout.String(),
+	}
+	if reporter, ok := r.Strategy.(strategy.GridStatusReporter); ok {
+		fields["remaining"] = strconv.Itoa(reporter.GridStatus().OpenOrders)
+	}
+	if err != nil {
+		fields["err"] = err.Error()
+		r.logf("WARN", "graceful_drain", "canceled=%d remaining=%s err=%q", canceled, fields["remaining"], err.Error())
+	} else {
+		r.logf("INFO", "graceful_drain", "canceled=%d remaining=%s", canceled, fields["remaining"])
+	}
+	r.alertImportant("graceful_drain", fields)
+}
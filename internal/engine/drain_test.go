@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+	"grid-trading/internal/strategy"
+)
+
+// drainStrategy cancels its orders, or hangs until ctx is done when block is
+// set.
+type drainStrategy struct {
+	open  int
+	block bool
+}
+
+func (s *drainStrategy) Init(context.Context, decimal.Decimal) error { return nil }
+func (s *drainStrategy) OnFill(context.Context, core.Trade) error    { return nil }
+
+func (s *drainStrategy) CancelAllOrders(ctx context.Context) (int, error) {
+	if s.block {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	n := s.open
+	s.open = 0
+	return n, nil
+}
+
+func (s *drainStrategy) GridStatus() strategy.GridStatus {
+	return strategy.GridStatus{OpenOrders: s.open}
+}
+
+func TestLiveRunnerDrainOnExitCancelsOrdersWithinTimeout(t *testing.T) {
+	alerts := &runnerAlertRecorder{}
+	runner := LiveRunner{
+		Strategy:           &drainStrategy{open: 7},
+		Symbol:             "BTCUSDT",
+		Alerts:             alerts,
+		CancelOrdersOnExit: true,
+	}
+	runner.drainOnExit()
+	fields, ok := alerts.find("graceful_drain")
+	if !ok {
+		t.Fatalf("missing graceful_drain alert, got %v", alerts.events)
+	}
+	if fields["canceled"] != "7" || fields["remaining"] != "0" || fields["err"] != "" {
+		t.Fatalf("graceful_drain = %v, want canceled=7 remaining=0", fields)
+	}
+
+	// A hanging exchange cannot hold shutdown past the drain timeout.
+	alerts = &runnerAlertRecorder{}
+	runner = LiveRunner{
+		Strategy:           &drainStrategy{open: 3, block: true},
+		Symbol:             "BTCUSDT",
+		Alerts:             alerts,
+		CancelOrdersOnExit: true,
+		DrainTimeout:       50 * time.Millisecond,
+	}
+	start := time.Now()
+	runner.drainOnExit()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("drainOnExit() took %s, want it bounded by the 50ms timeout", elapsed)
+	}
+	fields, ok = alerts.find("graceful_drain")
+	if !ok || fields["canceled"] != "0" || fields["remaining"] != "3" || fields["err"] == "" {
+		t.Fatalf("graceful_drain = %v, %v; want canceled=0 remaining=3 with the timeout error", fields, ok)
+	}
+
+	// Off by default.
+	alerts = &runnerAlertRecorder{}
+	runner = LiveRunner{Strategy: &drainStrategy{open: 2}, Alerts: alerts}
+	runner.drainOnExit()
+	if _, ok := alerts.find("graceful_drain"); ok {
+		t.Fatalf("graceful_drain sent with cancel_orders_on_exit off")
+	}
+}
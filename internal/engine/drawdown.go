@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/strategy"
+)
+
+// ErrMaxDrawdown stops the live runner once equity has fallen
+// MaxDrawdownPct below its peak for the run.
+var ErrMaxDrawdown = errors.New("max drawdown reached")
+
+// checkDrawdown marks account equity (quote + base at price, as in the
+// backtest) against the peak, which is kept in the runtime status so a
+// restart does not re-arm lower. It is the whole account, not the grid's
+// PnL: deposits raise the peak and withdrawals count as drawdown. A balance read failure skips the check
+// rather than stopping the bot. On a hit the strategy is stopped first so its
+// buys are not left resting.
+func (r *LiveRunner) checkDrawdown(ctx context.Context, price decimal.Decimal) error {
+	if r.MaxDrawdownPct.Sign() <= 0 || r.Exchange == nil || price.Sign() <= 0 {
+		return nil
+	}
+	bal, err := r.Exchange.Balances(ctx)
+	if err != nil {
+		r.logf("WARN", "drawdown_check_skipped", "err=%q", err.Error())
+		return nil
+	}
+	equity := bal.Quote.Add(bal.Base.Mul(price))
+	if equity.Cmp(r.peakEquity) > 0 {
+		r.peakEquity = equity
+		r.savePeakEquity()
+	}
+	if r.peakEquity.Sign() <= 0 {
+		return nil
+	}
+	drawdown := r.peakEquity.Sub(equity).Div(r.peakEquity).Mul(decimal.NewFromInt(100))
+	r.Metrics.Set("gridbot_equity_drawdown_pct", drawdown.InexactFloat64())
+	if drawdown.Cmp(r.MaxDrawdownPct) < 0 {
+		return nil
+	}
+	r.logf("ERROR", "max_drawdown_hit", "peak_equity=%s equity=%s drawdown_pct=%s threshold_pct=%s", r.peakEquity.String(), equity.String(), drawdown.StringFixed(4), r.MaxDrawdownPct.String())
+	r.alertImportant("max_drawdown_hit", map[string]string{
+		"symbol":        r.Symbol,
+		"price":         price.String(),
+		"peak_equity":   r.peakEquity.String(),
+		"equity":        equity.String(),
+		"drawdown_pct":  drawdown.StringFixed(4),
+		"threshold_pct": r.MaxDrawdownPct.String(),
+	})
+	if stopper, ok := r.Strategy.(strategy.Stopper); ok {
+		if err := stopper.Stop(ctx, "max_drawdown"); err != nil && !errors.Is(err, strategy.ErrStopped) {
+			r.logf("ERROR", "max_drawdown_stop_failed", "err=%q", err.Error())
+		}
+	}
+	return fmt.Errorf("%w: equity %s is %s%% below peak %s", ErrMaxDrawdown, equity.String(), drawdown.StringFixed(2), r.peakEquity.String())
+}
+
+// loadPeakEquity picks up the peak a previous run of this symbol persisted.
+func (r *LiveRunner) loadPeakEquity() decimal.Decimal {
+	if r.Store == nil {
+		return decimal.Zero
+	}
+	status, ok, err := r.Store.LoadRuntimeStatus()
+	if err != nil || !ok || status.Symbol != r.Symbol {
+		return decimal.Zero
+	}
+	return status.PeakEquity
+}
+
+func (r *LiveRunner) savePeakEquity() {
+	if r.Store == nil {
+		return
+	}
+	status, ok, err := r.Store.LoadRuntimeStatus()
+	if err != nil || !ok {
+		return
+	}
+	status.PeakEquity = r.peakEquity
+	status.UpdatedAt = time.Time{}
+	if err := r.Store.SaveRuntimeStatus(status); err != nil {
+		r.logf("WARN", "runtime_status_write_failed", "err=%q", err.Error())
+	}
+}
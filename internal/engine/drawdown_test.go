@@ -0,0 +1,212 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/exchange/binance"
+	"grid-trading/internal/store"
+)
+
+type stoppingStrategySpy struct {
+	liveStrategySpy
+	stopReasons []string
+}
+
+func (s *stoppingStrategySpy) Stop(_ context.Context, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopReasons = append(s.stopReasons, reason)
+	return nil
+}
+
+func TestLiveRunnerStopsOnMaxDrawdown(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+	var priceCalls atomic.Int64
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			// Startup marks equity at 10 BTC * 100 = 1000; every reconcile
+			// after that sees 70, a 30% drawdown.
+			price := "70"
+			if priceCalls.Add(1) == 1 {
+				price = "100"
+			}
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": price})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "BTC", "free": "10", "locked": "0"},
+				{"asset": "USDT", "free": "0", "locked": "0"},
+			}})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	alerts := &runnerAlertRecorder{}
+	strat := &stoppingStrategySpy{}
+	runner := LiveRunner{
+		Exchange:       client,
+		Strategy:       strat,
+		Symbol:         "BTCUSDT",
+		Reconcile:      50 * time.Millisecond,
+		Store:          st,
+		Alerts:         alerts,
+		MaxDrawdownPct: decimal.NewFromInt(20),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = runner.Run(ctx)
+	if !errors.Is(err, ErrMaxDrawdown) {
+		t.Fatalf("Run() error = %v, want ErrMaxDrawdown", err)
+	}
+
+	fields, ok := alerts.find("max_drawdown_hit")
+	if !ok {
+		t.Fatalf("missing max_drawdown_hit alert, got %v", alerts.events)
+	}
+	if fields["peak_equity"] != "1000" || fields["equity"] != "700" || fields["drawdown_pct"] != "30.0000" || fields["threshold_pct"] != "20" {
+		t.Fatalf("max_drawdown_hit fields = %v", fields)
+	}
+	if _, ok := alerts.find("user_stream_disconnected"); ok {
+		t.Fatalf("drawdown stop must not be treated as a disconnect, got %v", alerts.events)
+	}
+	status, ok, err := st.LoadRuntimeStatus()
+	if err != nil || !ok {
+		t.Fatalf("LoadRuntimeStatus() ok=%v err=%v", ok, err)
+	}
+	if status.State != "stopped" || status.LastError == "" || !status.PeakEquity.Equal(decimal.NewFromInt(1000)) {
+		t.Fatalf("status = %s last_error %q peak %s, want stopped with the drawdown error and peak 1000", status.State, status.LastError, status.PeakEquity)
+	}
+	if len(strat.stopReasons) != 1 || strat.stopReasons[0] != "max_drawdown" {
+		t.Fatalf("strategy stop reasons = %v, want one max_drawdown stop", strat.stopReasons)
+	}
+
+	// A restart marks against the persisted peak, not the lower equity it
+	// starts at.
+	restartAlerts := &runnerAlertRecorder{}
+	restart := LiveRunner{
+		Exchange:       client,
+		Strategy:       &stoppingStrategySpy{},
+		Symbol:         "BTCUSDT",
+		Reconcile:      50 * time.Millisecond,
+		Store:          st,
+		Alerts:         restartAlerts,
+		MaxDrawdownPct: decimal.NewFromInt(20),
+	}
+	if err := restart.Run(ctx); !errors.Is(err, ErrMaxDrawdown) {
+		t.Fatalf("Run() after restart error = %v, want ErrMaxDrawdown", err)
+	}
+	if fields, ok := restartAlerts.find("max_drawdown_hit"); !ok || fields["peak_equity"] != "1000" {
+		t.Fatalf("max_drawdown_hit after restart = %v (ok %v), want peak 1000", fields, ok)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestCheckDrawdownCountsWithdrawalsAgainstAccountEquity(t *testing.T) {
+	var withdrawn atomic.Bool
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			quote := "1000"
+			if withdrawn.Load() {
+				quote = "700"
+			}
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "BTC", "free": "0", "locked": "0"},
+				{"asset": "USDT", "free": quote, "locked": "0"},
+			}})
+		default:
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "BTCUSDT",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+
+	strat := &stoppingStrategySpy{}
+	runner := LiveRunner{Exchange: client, Strategy: strat, Symbol: "BTCUSDT", MaxDrawdownPct: decimal.NewFromInt(20)}
+	price := decimal.NewFromInt(100)
+	if err := runner.checkDrawdown(context.Background(), price); err != nil {
+		t.Fatalf("checkDrawdown() before withdrawal error = %v", err)
+	}
+	// Equity is the whole account, so moving 300 USDT out at a flat price
+	// reads as a 30% drawdown.
+	withdrawn.Store(true)
+	if err := runner.checkDrawdown(context.Background(), price); !errors.Is(err, ErrMaxDrawdown) {
+		t.Fatalf("checkDrawdown() after withdrawal error = %v, want ErrMaxDrawdown", err)
+	}
+	if len(strat.stopReasons) != 1 {
+		t.Fatalf("stop reasons = %v, want one max_drawdown stop", strat.stopReasons)
+	}
+}
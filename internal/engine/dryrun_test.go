@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+	"grid-trading/internal/exchange/binance"
+	"grid-trading/internal/safety"
+	"grid-trading/internal/store"
+	"grid-trading/internal/strategy"
+)
+
+func TestLiveRunnerDryRunLogsOrdersWithoutSending(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "BTC", "free": "10", "locked": "0"},
+				{"asset": "USDT", "free": "10000", "locked": "0"},
+			}})
+		default:
+			// Orders and open-order listings must never reach the exchange.
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST call: %s %s", r.Method, r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	dry := safety.NewDryRunExecutor(client)
+	exec := safety.NewGuardedExecutor(dry, nil)
+	strat := strategy.NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.1"), 4, 2, decimal.NewFromInt(1), 1, core.Rules{}, st, exec)
+	runner := LiveRunner{
+		Exchange:  client,
+		Strategy:  strat,
+		Symbol:    "BTCUSDT",
+		Reconcile: 30 * time.Millisecond,
+		Store:     st,
+		DryRun:    dry,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(300*time.Millisecond, cancel)
+	if err := runner.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	open, err := dry.OpenOrders(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("OpenOrders() error = %v", err)
+	}
+	if len(open) != 6 {
+		t.Fatalf("dry-run book = %d orders, want 6: %+v", len(open), open)
+	}
+	for _, ord := range open {
+		if !strings.HasPrefix(ord.ID, "dry-") {
+			t.Fatalf("order id = %q, want synthetic dry- id", ord.ID)
+		}
+	}
+	// Periodic reconciles saw the book and left it alone.
+	if last := open[len(open)-1].ID; last != "dry-6" {
+		t.Fatalf("last dry-run id = %s, want dry-6 (no re-placement)", last)
+	}
+	persisted, ok, err := st.LoadOpenOrders()
+	if err != nil || !ok {
+		t.Fatalf("LoadOpenOrders() ok=%v err=%v", ok, err)
+	}
+	if len(persisted) != 6 {
+		t.Fatalf("persisted open orders = %d, want 6", len(persisted))
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
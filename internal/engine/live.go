@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -15,7 +19,9 @@ import (
 	"grid-trading/internal/alert"
 	"grid-trading/internal/core"
 	"grid-trading/internal/exchange/binance"
+	"grid-trading/internal/metrics"
 	"grid-trading/internal/safety"
+	"grid-trading/internal/status"
 	"grid-trading/internal/store"
 	"grid-trading/internal/strategy"
 )
@@ -23,8 +29,15 @@ import (
 var ErrManualIntervention = errors.New("manual intervention required")
 var ErrFatalLocal = errors.New("fatal local error")
 
+var errMaxRunTime = errors.New("max runtime reached")
+
 const liveSeenTrackerMaxEntries = 10000
 
+const (
+	TickPriceLast = "last"
+	TickPriceMid  = "mid"
+)
+
 type LiveRunner struct {
 	Exchange   *binance.Client
 	Strategy   strategy.Strategy
@@ -37,6 +50,126 @@ type LiveRunner struct {
 	Store      *store.Store
 	Breaker    *safety.Breaker
 	Alerts     alert.Alerter
+	Metrics    *metrics.Registry
+	Pusher     *metrics.Pusher
+
+	// Status receives the strategy's grid snapshot whenever the state
+	// metrics refresh, for the /status endpoint, plus the loop, tick and
+	// stream liveness /healthz and /readyz answer from.
+	Status *status.Board
+
+	// TickPriceSource selects the price fed to OnTick and resync:
+	// "last" (default, last trade) or "mid" (best bid/ask midpoint).
+	TickPriceSource string
+
+	// TickerMaxDivergencePct cross-checks a "last" price against the book
+	// ticker mid and drops it when it is further than this percent away;
+	// 0 disables.
+	TickerMaxDivergencePct decimal.Decimal
+
+	// ReconcileMin/ReconcileMax enable the adaptive reconcile interval;
+	// Reconcile is then the starting interval.
+	ReconcileMin time.Duration
+	ReconcileMax time.Duration
+
+	// ReconcileQuiet skips periodic reconciles for this long after the
+	// strategy bootstraps a grid, while its placements settle on the
+	// exchange. Reconnect reconciles still run; 0 disables.
+	ReconcileQuiet time.Duration
+
+	// RulesRefresh re-fetches the symbol filters on this interval and hands
+	// changes to RulesAware strategies; 0 disables.
+	RulesRefresh time.Duration
+
+	// DuplicateInstanceCheck refuses to start when the exchange holds open
+	// orders with this instance's clientOrderId prefix that local state does
+	// not know, unless AdoptExistingOrders is set.
+	DuplicateInstanceCheck bool
+	AdoptExistingOrders    bool
+
+	// TwinSurplus raises possible_twin_instance when the exchange holds at
+	// least this many more prefixed open orders than the strategy tracks on
+	// TwinRounds reconciles in a row, as when a second process shares the
+	// API key and instance id; 0 disables.
+	TwinSurplus int
+	TwinRounds  int
+
+	// RESTTimeoutRetries retries a price/open-orders REST call that hit its
+	// per-call timeout before the failure is treated as a connection problem.
+	RESTTimeoutRetries int
+
+	// RunSummary sends a run_summary alert on exit (fills, realized PnL,
+	// duration, final balances, remaining orders) and keeps it in the final
+	// runtime status.
+	RunSummary bool
+
+	// BacklogDrainMax bounds how many fills that happened before a
+	// reconnected stream opened (the exchange's queued backlog) are applied
+	// before a reconcile runs; 0 disables the bound.
+	BacklogDrainMax int
+
+	// CancelOrdersOnExit cancels the strategy's open orders when Run stops on
+	// context cancellation (SIGTERM, max run time), within DrainTimeout
+	// (default 30s).
+	CancelOrdersOnExit bool
+	DrainTimeout       time.Duration
+
+	// BalanceFloorCheck reads the account balance on every heartbeat and
+	// hands it to BalanceWatcher strategies, which pause placements below
+	// their balance floor.
+	BalanceFloorCheck bool
+
+	// LedgerDetails records each fill's side, price, qty, grid level, order
+	// origin and realized PnL delta in the trade ledger, not just its key.
+	LedgerDetails bool
+
+	// ReportInUSD adds USD figures (rate, realized PnL, equity) to the run
+	// summary. The quote->USD rate is 1 for USD stablecoin quotes, else the
+	// price of USDRateSymbol, which defaults to the quote asset + "USDT".
+	ReportInUSD   bool
+	USDRateSymbol string
+
+	// MaxRunTime stops the runner cleanly once it has run this long, with a
+	// max_runtime_reached alert; 0 runs until cancelled.
+	MaxRunTime time.Duration
+
+	// MaxDrawdownPct stops the runner with ErrMaxDrawdown when equity falls
+	// this many percent below its peak for the run; 0 disables.
+	MaxDrawdownPct decimal.Decimal
+
+	// DryRun, when set, is the executor the strategy places through: open
+	// orders for reconcile come from its book instead of the exchange, and
+	// orders missing from it are dropped without querying the exchange.
+	DryRun *safety.DryRunExecutor
+
+	peakEquity        decimal.Decimal
+	reconcileSchedule *reconcileSchedule
+	stats             *runStats
+	runSummary        map[string]string
+	streamedFills     map[string]orderFill
+	reconciledFills   map[string]reconciledFill
+	twinStreak        int
+	twinAlerted       bool
+	tickerSuspect     bool
+	jitter            *rand.Rand
+	lastTickAt        time.Time
+	pushing           atomic.Bool
+	pushWG            sync.WaitGroup
+}
+
+// maxReconnectBackoff caps the doubling reconnect backoff.
+const maxReconnectBackoff = 30 * time.Second
+
+// reconnectWait picks a wait in [0, backoff] (full jitter) so instances that
+// lost their streams together do not all reconnect at the same moment.
+func (r *LiveRunner) reconnectWait(backoff time.Duration) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+	if r.jitter == nil {
+		r.jitter = rand.New(rand.NewSource(time.Now().UnixNano() ^ int64(os.Getpid())))
+	}
+	return time.Duration(r.jitter.Int63n(int64(backoff) + 1))
 }
 
 func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
@@ -45,6 +178,14 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 	reconnectAttempts := 0
 	disconnectStartedAt := time.Time{}
 	startedAt := time.Now().UTC()
+	r.initMetrics()
+	r.stats = newRunStats()
+	r.peakEquity = r.loadPeakEquity()
+	if r.MaxRunTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, r.MaxRunTime, errMaxRunTime)
+		defer cancel()
+	}
 
 	r.persistRuntimeStatus("starting", startedAt, reconnectAttempts, disconnectStartedAt, nil)
 	defer func() {
@@ -52,10 +193,41 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 		if errors.Is(err, context.Canceled) {
 			err = nil
 		}
+		maxRunTimeReached := errors.Is(context.Cause(ctx), errMaxRunTime) && (err == nil || errors.Is(err, context.DeadlineExceeded))
+		if maxRunTimeReached {
+			err = nil
+			runErr = nil
+			r.logf("INFO", "max_runtime_reached", "max_run=%s", r.MaxRunTime)
+			r.alertImportant("max_runtime_reached", map[string]string{
+				"symbol":   r.Symbol,
+				"max_run":  r.MaxRunTime.String(),
+				"duration": time.Since(startedAt).Round(time.Second).String(),
+			})
+		}
+		if err == nil && ctx.Err() != nil {
+			r.drainOnExit()
+		}
+		if r.RunSummary {
+			r.runSummary = r.buildRunSummary(startedAt, err)
+			if maxRunTimeReached {
+				r.runSummary["reason"] = "max_runtime_reached"
+			}
+			r.logf("INFO", "run_summary", "trades=%s realized_pnl=%s duration=%s", r.runSummary["trades"], r.runSummary["realized_pnl"], r.runSummary["duration"])
+			r.alertImportant("run_summary", r.runSummary)
+		}
 		r.persistRuntimeStatus("stopped", startedAt, reconnectAttempts, disconnectStartedAt, err)
+		if err == nil {
+			r.deletePushedMetrics()
+		}
 	}()
 
+	if err := r.checkDuplicateInstance(ctx); err != nil {
+		runErr = err
+		return runErr
+	}
+
 	for {
+		r.Status.MarkLoop(time.Now().UTC())
 		reconnect := reconnectAttempts > 0
 		if reconnect && r.Breaker != nil {
 			if allowErr := r.Breaker.AllowReconnect(); allowErr != nil {
@@ -64,6 +236,7 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 				if rem := r.Breaker.ReconnectCooldownRemaining(); rem > wait {
 					wait = rem
 				}
+				r.Status.MarkLoop(time.Now().UTC().Add(wait))
 				select {
 				case <-time.After(wait):
 				case <-ctx.Done():
@@ -80,7 +253,7 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 				return runErr
 			}
 			if errors.Is(err, ErrFatalLocal) {
-				log.Printf("level=ERROR event=runner_stopped reason=%q", err.Error())
+				r.logf("ERROR", "runner_stopped", "reason=%q", err.Error())
 				r.alertImportant("runner_stopped", map[string]string{
 					"reason": err.Error(),
 				})
@@ -91,6 +264,14 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 				runErr = err
 				return runErr
 			}
+			if errors.Is(err, ErrMaxDrawdown) {
+				r.logf("ERROR", "runner_stopped", "reason=%q", err.Error())
+				r.alertImportant("runner_stopped", map[string]string{
+					"reason": err.Error(),
+				})
+				runErr = err
+				return runErr
+			}
 			if disconnectStartedAt.IsZero() {
 				disconnectStartedAt = time.Now().UTC()
 				r.alertImportant("user_stream_disconnected", map[string]string{
@@ -99,7 +280,7 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 			}
 			if errors.Is(err, ErrManualIntervention) {
 				r.persistRuntimeStatus("degraded", startedAt, reconnectAttempts, disconnectStartedAt, err)
-				log.Printf("level=ERROR event=runner_stopped reason=%q", err.Error())
+				r.logf("ERROR", "runner_stopped", "reason=%q", err.Error())
 				r.alertImportant("runner_stopped", map[string]string{
 					"reason": err.Error(),
 				})
@@ -111,6 +292,7 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 				return runErr
 			}
 			nextAttempts := reconnectAttempts + 1
+			r.Metrics.Inc("gridbot_reconnects_total")
 			r.persistRuntimeStatus("degraded", startedAt, nextAttempts, disconnectStartedAt, err)
 			var trip error
 			if r.Breaker != nil {
@@ -122,23 +304,21 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 				return runErr
 			}
 			reconnectAttempts = nextAttempts
-			wait := backoff
+			wait := r.reconnectWait(backoff)
 			if trip != nil && errors.Is(trip, safety.ErrCircuitOpen) && r.Breaker != nil {
 				if rem := r.Breaker.ReconnectCooldownRemaining(); rem > wait {
 					wait = rem
 				}
 			}
+			r.Status.MarkLoop(time.Now().UTC().Add(wait))
 			select {
 			case <-time.After(wait):
 			case <-ctx.Done():
 				runErr = ctx.Err()
 				return runErr
 			}
-			if backoff < 30*time.Second {
-				backoff *= 2
-				if backoff > 30*time.Second {
-					backoff = 30 * time.Second
-				}
+			if backoff < maxReconnectBackoff {
+				backoff = min(backoff*2, maxReconnectBackoff)
 			}
 			continue
 		}
@@ -148,10 +328,13 @@ func (r *LiveRunner) Run(ctx context.Context) (runErr error) {
 }
 
 func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTracker, reconnectAttempts *int, disconnectStartedAt *time.Time, backoff *time.Duration, startedAt time.Time) error {
-	price, err := r.Exchange.TickerPrice(ctx, r.Symbol)
+	price, err := r.tickPrice(ctx)
 	if err != nil {
 		return err
 	}
+	if err := r.checkDrawdown(ctx, price); err != nil {
+		return err
+	}
 
 	persisted, skipPersistedReconcile, err := r.loadPersistedForResync(reconnect)
 	if err != nil {
@@ -164,11 +347,14 @@ func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTrac
 		}
 		return err
 	}
+	r.updateStateMetrics()
 
 	stream, err := r.Exchange.NewUserStream(ctx, r.Keepalive)
 	if err != nil {
 		return err
 	}
+	r.Status.SetStreamConnected(true)
+	defer r.Status.SetStreamConnected(false)
 	if reconnect && disconnectStartedAt != nil && !disconnectStartedAt.IsZero() {
 		down := time.Since(*disconnectStartedAt).Round(time.Second)
 		attempts := 0
@@ -190,19 +376,34 @@ func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTrac
 	}
 	r.Breaker.ResetReconnect()
 	trades, errs := stream.Trades(ctx, r.Symbol)
+	streamOpenedAt := time.Now().UTC()
+	backlogApplied := 0
 	var heartbeat <-chan time.Time
 	if r.Heartbeat > 0 {
 		ticker := time.NewTicker(r.Heartbeat)
 		defer ticker.Stop()
 		heartbeat = ticker.C
 	}
-	var reconcileTick <-chan time.Time
-	if r.Reconcile > 0 {
-		ticker := time.NewTicker(r.Reconcile)
+	schedule := r.schedule()
+	if reconnect {
+		schedule.NoteReconnect()
+	}
+	var rulesTick <-chan time.Time
+	if r.RulesRefresh > 0 {
+		ticker := time.NewTicker(r.RulesRefresh)
 		defer ticker.Stop()
-		reconcileTick = ticker.C
+		rulesTick = ticker.C
+	}
+	var reconcileTimer *time.Timer
+	var reconcileTick <-chan time.Time
+	if interval := schedule.Interval(); interval > 0 {
+		reconcileTimer = time.NewTimer(interval)
+		defer reconcileTimer.Stop()
+		reconcileTick = reconcileTimer.C
+		r.Metrics.Set("gridbot_reconcile_interval_seconds", interval.Seconds())
 	}
 	for {
+		r.Status.MarkLoop(time.Now().UTC())
 		select {
 		case trade, ok := <-trades:
 			if !ok {
@@ -215,7 +416,24 @@ func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTrac
 			if dup {
 				continue
 			}
-			if err := r.Strategy.OnFill(ctx, trade); err != nil {
+			if r.alreadyReconciled(trade) {
+				// The resync before this stream opened already applied it.
+				r.Metrics.Inc("gridbot_stream_fills_already_reconciled_total")
+				r.logf("INFO", "stream_fill_already_reconciled", "order_id=%s trade_id=%s cum_qty=%s", trade.OrderID, trade.TradeID, trade.CumQty)
+				r.forgetReconciledFill(trade)
+				if err := r.recordTradeLedger(trade, store.TradeLedgerEntry{}); err != nil {
+					return fmt.Errorf("%w: trade ledger record: %v", ErrFatalLocal, err)
+				}
+				continue
+			}
+			tracked, trackedOK := r.trackedOrder(trade.OrderID)
+			pnlBefore := r.stats.RealizedPnL()
+			err = r.Strategy.OnFill(ctx, trade)
+			if err == nil || errors.Is(err, strategy.ErrStopped) {
+				r.stats.Record(trade)
+				r.noteStreamedFill(trade)
+			}
+			if err != nil {
 				if errors.Is(err, strategy.ErrStopped) {
 					r.alertImportant("manual_intervention_required", map[string]string{
 						"reason": "strategy_stopped",
@@ -225,9 +443,28 @@ func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTrac
 				}
 				return fmt.Errorf("%w: strategy on_fill: %v", ErrFatalLocal, err)
 			}
-			if err := r.recordTradeLedger(trade); err != nil {
+			entry := store.TradeLedgerEntry{}
+			if r.LedgerDetails {
+				entry = ledgerDetails(trade, tracked, trackedOK, r.stats.RealizedPnL().Sub(pnlBefore))
+			}
+			if err := r.recordTradeLedger(trade, entry); err != nil {
 				return fmt.Errorf("%w: trade ledger record: %v", ErrFatalLocal, err)
 			}
+			r.Metrics.Inc("gridbot_trades_total")
+			schedule.NoteFill()
+			if reconnect && r.BacklogDrainMax > 0 && !trade.Time.IsZero() && trade.Time.Before(streamOpenedAt) {
+				backlogApplied++
+				if backlogApplied >= r.BacklogDrainMax {
+					backlogApplied = 0
+					r.logf("INFO", "backlog_drain_yield", "applied=%d", r.BacklogDrainMax)
+					if err := r.periodicReconcile(ctx, seen); err != nil {
+						if errors.Is(err, strategy.ErrStopped) {
+							return nil
+						}
+						return err
+					}
+				}
+			}
 		case err, ok := <-errs:
 			if ok && err != nil {
 				return err
@@ -242,6 +479,14 @@ func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTrac
 				downSince = *disconnectStartedAt
 			}
 			r.persistRuntimeStatus("running", startedAt, attempts, downSince, nil)
+			if err := r.checkBalanceFloor(ctx); err != nil {
+				if errors.Is(err, strategy.ErrStopped) {
+					return nil
+				}
+				return err
+			}
+			r.updateStateMetrics()
+			r.pushMetrics(ctx)
 		case <-reconcileTick:
 			if err := r.periodicReconcile(ctx, seen); err != nil {
 				if errors.Is(err, strategy.ErrStopped) {
@@ -249,20 +494,345 @@ func (r *LiveRunner) runOnce(ctx context.Context, reconnect bool, seen *seenTrac
 				}
 				return err
 			}
+			r.updateStateMetrics()
+			next := schedule.Advance()
+			reconcileTimer.Reset(next)
+			r.Metrics.Set("gridbot_reconcile_interval_seconds", next.Seconds())
+		case <-rulesTick:
+			r.refreshRules(ctx)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
+func (r *LiveRunner) checkDuplicateInstance(ctx context.Context) error {
+	if !r.DuplicateInstanceCheck || r.Exchange == nil {
+		return nil
+	}
+	prefix := r.Exchange.ClientOrderPrefix()
+	if prefix == "" {
+		return nil
+	}
+	open, err := r.openOrders(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]struct{})
+	if r.Store != nil {
+		persisted, _, err := r.Store.LoadOpenOrders()
+		if err != nil {
+			return fmt.Errorf("%w: load open orders: %v", ErrFatalLocal, err)
+		}
+		for _, ord := range persisted {
+			known[ord.ID] = struct{}{}
+		}
+	}
+	unknown := make([]string, 0)
+	for _, ord := range open {
+		if !strings.HasPrefix(ord.ClientID, prefix) {
+			continue
+		}
+		if _, ok := known[ord.ID]; ok {
+			continue
+		}
+		unknown = append(unknown, ord.ID)
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	fields := map[string]string{
+		"symbol":          r.Symbol,
+		"client_prefix":   prefix,
+		"unknown_orders":  strconv.Itoa(len(unknown)),
+		"first_order_ids": strings.Join(unknown[:min(len(unknown), 5)], ","),
+	}
+	if r.AdoptExistingOrders {
+		r.logf("WARN", "existing_orders_adopted", "count=%d", len(unknown))
+		r.alertImportant("existing_orders_adopted", fields)
+		return nil
+	}
+	r.logf("ERROR", "possible_duplicate_instance", "count=%d prefix=%q", len(unknown), prefix)
+	r.alertImportant("possible_duplicate_instance", fields)
+	return fmt.Errorf("%w: possible_duplicate_instance: %d open orders with prefix %q are not in local state (set state.adopt_existing_orders to take them over)", ErrManualIntervention, len(unknown), prefix)
+}
+
+// checkTwinInstance compares the open orders carrying this instance's
+// clientOrderId prefix with what the strategy tracks, before reconcile
+// touches either. It alerts once per sustained surplus and re-arms when the
+// surplus clears.
+func (r *LiveRunner) checkTwinInstance(open []core.Order) {
+	if r.TwinSurplus <= 0 || r.Exchange == nil {
+		return
+	}
+	reporter, ok := r.Strategy.(strategy.GridStatusReporter)
+	if !ok {
+		return
+	}
+	prefix := r.Exchange.ClientOrderPrefix()
+	if prefix == "" {
+		return
+	}
+	prefixed := 0
+	for _, ord := range open {
+		if strings.HasPrefix(ord.ClientID, prefix) {
+			prefixed++
+		}
+	}
+	tracked := reporter.GridStatus().OpenOrders
+	surplus := prefixed - tracked
+	if surplus < r.TwinSurplus {
+		r.twinStreak = 0
+		r.twinAlerted = false
+		return
+	}
+	r.twinStreak++
+	if r.twinStreak < max(r.TwinRounds, 1) || r.twinAlerted {
+		return
+	}
+	r.twinAlerted = true
+	r.logf("ERROR", "possible_twin_instance", "prefix=%q prefixed=%d tracked=%d rounds=%d", prefix, prefixed, tracked, r.twinStreak)
+	r.alertImportant("possible_twin_instance", map[string]string{
+		"symbol":          r.Symbol,
+		"client_prefix":   prefix,
+		"prefixed_orders": strconv.Itoa(prefixed),
+		"tracked_orders":  strconv.Itoa(tracked),
+		"surplus":         strconv.Itoa(surplus),
+		"rounds":          strconv.Itoa(r.twinStreak),
+	})
+}
+
+// refreshRules runs on the event loop, so the swap never lands in the middle
+// of a fill or reconcile. Fetch failures keep the current rules.
+func (r *LiveRunner) refreshRules(ctx context.Context) {
+	aware, ok := r.Strategy.(strategy.RulesAware)
+	if !ok || r.Exchange == nil {
+		return
+	}
+	rules, err := r.Exchange.RefreshRules(ctx, r.Symbol)
+	if err != nil {
+		r.logf("WARN", "rules_refresh_failed", "err=%q", err.Error())
+		return
+	}
+	prev := aware.Rules()
+	if rulesEqual(prev, rules) {
+		return
+	}
+	aware.SetRules(rules)
+	// The strategy may hold back part of the change, e.g. a tick change
+	// while orders rest; report what it applied.
+	rules = aware.Rules()
+	if rulesEqual(prev, rules) {
+		return
+	}
+	r.logf("WARN", "symbol_rules_changed", "price_tick=%s->%s qty_step=%s->%s min_qty=%s->%s min_notional=%s->%s",
+		prev.PriceTick, rules.PriceTick, prev.QtyStep, rules.QtyStep, prev.MinQty, rules.MinQty, prev.MinNotional, rules.MinNotional)
+	r.alertImportant("symbol_rules_changed", map[string]string{
+		"symbol":           r.Symbol,
+		"old_price_tick":   prev.PriceTick.String(),
+		"new_price_tick":   rules.PriceTick.String(),
+		"old_qty_step":     prev.QtyStep.String(),
+		"new_qty_step":     rules.QtyStep.String(),
+		"old_min_qty":      prev.MinQty.String(),
+		"new_min_qty":      rules.MinQty.String(),
+		"old_min_notional": prev.MinNotional.String(),
+		"new_min_notional": rules.MinNotional.String(),
+	})
+}
+
+func rulesEqual(a, b core.Rules) bool {
+	return a.MinQty.Equal(b.MinQty) &&
+		a.MinNotional.Equal(b.MinNotional) &&
+		a.PriceTick.Equal(b.PriceTick) &&
+		a.QtyStep.Equal(b.QtyStep)
+}
+
+func (r *LiveRunner) schedule() *reconcileSchedule {
+	if r.reconcileSchedule == nil {
+		r.reconcileSchedule = newReconcileSchedule(r.Reconcile, r.ReconcileMin, r.ReconcileMax)
+	}
+	return r.reconcileSchedule
+}
+
+// errInvalidTickPrice marks a zero or negative price from the exchange; the
+// tick is dropped rather than fed to the strategy.
+var errInvalidTickPrice = errors.New("invalid tick price")
+
+func (r *LiveRunner) tickPrice(ctx context.Context) (decimal.Decimal, error) {
+	price, err := r.fetchTickPrice(ctx)
+	if err != nil {
+		if errors.Is(err, errInvalidTickPrice) {
+			r.Metrics.Inc("gridbot_invalid_tick_prices_total")
+			r.logf("WARN", "invalid_tick_price", "source=%s err=%q", r.TickPriceSource, err.Error())
+		}
+		return decimal.Zero, err
+	}
+	r.Metrics.Set("gridbot_last_tick_price", price.InexactFloat64())
+	r.lastTickAt = time.Now().UTC()
+	r.Status.MarkTick(r.lastTickAt)
+	return price, nil
+}
+
+func (r *LiveRunner) fetchTickPrice(ctx context.Context) (decimal.Decimal, error) {
+	if r.TickPriceSource != TickPriceMid {
+		var price decimal.Decimal
+		err := r.retryOnCallTimeout(ctx, "ticker_price", func() error {
+			var err error
+			price, err = r.Exchange.TickerPrice(ctx, r.Symbol)
+			return err
+		})
+		if err != nil {
+			return decimal.Zero, err
+		}
+		if r.TickerMaxDivergencePct.Cmp(decimal.Zero) > 0 {
+			if err := r.checkTickerAgainstBook(ctx, price); err != nil {
+				return decimal.Zero, err
+			}
+		}
+		if price.Cmp(decimal.Zero) <= 0 {
+			return decimal.Zero, fmt.Errorf("%w: ticker price %s", errInvalidTickPrice, price)
+		}
+		return price, nil
+	}
+	var bid, ask decimal.Decimal
+	err := r.retryOnCallTimeout(ctx, "book_ticker", func() error {
+		var err error
+		bid, ask, err = r.Exchange.BookTicker(ctx, r.Symbol)
+		return err
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return midPrice(bid, ask)
+}
+
+func (r *LiveRunner) openOrders(ctx context.Context) ([]core.Order, error) {
+	if r.DryRun != nil {
+		return r.DryRun.OpenOrders(ctx, r.Symbol)
+	}
+	var open []core.Order
+	err := r.retryOnCallTimeout(ctx, "open_orders", func() error {
+		var err error
+		open, err = r.Exchange.OpenOrders(ctx, r.Symbol)
+		return err
+	})
+	return open, err
+}
+
+// retryOnCallTimeout re-runs call when it failed only because that single
+// request timed out; a cancelled run context or any other error is returned
+// as is so the caller's reconnect handling still applies.
+func (r *LiveRunner) retryOnCallTimeout(ctx context.Context, op string, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil || ctx.Err() != nil || !isCallTimeout(err) || attempt >= r.RESTTimeoutRetries {
+			return err
+		}
+		r.Metrics.Inc("gridbot_rest_timeout_retries_total")
+		r.logf("WARN", "rest_call_timeout_retry", "op=%s attempt=%d err=%q", op, attempt+1, err.Error())
+	}
+}
+
+func isCallTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// checkTickerAgainstBook rejects a last price that is zero or wildly off the
+// book ticker mid, as the ticker endpoint has served during outages. The
+// alert fires once per suspect stretch.
+func (r *LiveRunner) checkTickerAgainstBook(ctx context.Context, price decimal.Decimal) error {
+	var bid, ask decimal.Decimal
+	err := r.retryOnCallTimeout(ctx, "book_ticker", func() error {
+		var err error
+		bid, ask, err = r.Exchange.BookTicker(ctx, r.Symbol)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	mid, err := midPrice(bid, ask)
+	if err != nil {
+		return err
+	}
+	divergence := price.Sub(mid).Abs().Div(mid).Mul(decimal.NewFromInt(100))
+	if price.Cmp(decimal.Zero) > 0 && divergence.Cmp(r.TickerMaxDivergencePct) <= 0 {
+		r.tickerSuspect = false
+		return nil
+	}
+	r.Metrics.Inc("gridbot_ticker_price_suspect_total")
+	r.logf("WARN", "ticker_price_suspect", "ticker=%s book_mid=%s divergence_pct=%s max_pct=%s",
+		price, mid, divergence.StringFixed(2), r.TickerMaxDivergencePct)
+	if !r.tickerSuspect {
+		r.tickerSuspect = true
+		r.alertImportant("ticker_price_suspect", map[string]string{
+			"ticker":         price.String(),
+			"book_mid":       mid.String(),
+			"divergence_pct": divergence.StringFixed(2),
+			"max_pct":        r.TickerMaxDivergencePct.String(),
+		})
+	}
+	return fmt.Errorf("%w: ticker price %s diverges %s%% from book mid %s", errInvalidTickPrice, price, divergence.StringFixed(2), mid)
+}
+
+func midPrice(bid, ask decimal.Decimal) (decimal.Decimal, error) {
+	if bid.Cmp(decimal.Zero) <= 0 || ask.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero, fmt.Errorf("%w: book ticker bid=%s ask=%s", errInvalidTickPrice, bid, ask)
+	}
+	return bid.Add(ask).Div(decimal.NewFromInt(2)), nil
+}
+
 func (r *LiveRunner) periodicReconcile(ctx context.Context, seen *seenTracker) error {
-	price, err := r.Exchange.TickerPrice(ctx, r.Symbol)
+	price, err := r.tickPrice(ctx)
 	if err != nil {
+		if errors.Is(err, errInvalidTickPrice) {
+			// Skip this round; the next interval fetches a fresh price.
+			return nil
+		}
+		return err
+	}
+	if err := r.checkDrawdown(ctx, price); err != nil {
 		return err
 	}
+	if tickAware, ok := r.Strategy.(strategy.TickAware); ok {
+		if err := tickAware.OnTick(ctx, price, time.Now().UTC()); err != nil {
+			if errors.Is(err, strategy.ErrStopped) {
+				return err
+			}
+			if errors.Is(err, strategy.ErrFatal) {
+				return fmt.Errorf("%w: strategy on_tick: %v", ErrFatalLocal, err)
+			}
+			// Exchange hiccups inside a tick are retried on the next interval.
+			r.logf("WARN", "strategy_tick_failed", "err=%q", err.Error())
+			r.alertImportant("strategy_tick_failed", map[string]string{
+				"err":         err.Error(),
+				"next_action": "retry_next_reconcile",
+			})
+		}
+	}
+	if r.inBootstrapQuiet() {
+		r.Metrics.Inc("gridbot_reconcile_quiet_skips_total")
+		r.logf("INFO", "reconcile_quiet_skip", "quiet=%s", r.ReconcileQuiet)
+		return nil
+	}
 	return r.resync(ctx, price, seen, nil, true)
 }
 
+func (r *LiveRunner) inBootstrapQuiet() bool {
+	if r.ReconcileQuiet <= 0 {
+		return false
+	}
+	b, ok := r.Strategy.(strategy.Bootstrapper)
+	if !ok {
+		return false
+	}
+	at := b.BootstrappedAt()
+	return !at.IsZero() && time.Since(at) < r.ReconcileQuiet
+}
+
 func (r *LiveRunner) loadPersistedForResync(reconnect bool) ([]core.Order, bool, error) {
 	if reconnect || r.Store == nil {
 		return nil, false, nil
@@ -284,8 +854,10 @@ func (r *LiveRunner) loadPersistedForResync(reconnect bool) ([]core.Order, bool,
 				"open_orders_snapshot_id": ordersID,
 				"action":                  "skip_reconcile_missing",
 			})
-			log.Printf(
-				"level=WARN event=snapshot_mismatch_skip_reconcile_missing state_snapshot_id=%q open_orders_snapshot_id=%q",
+			r.logf(
+				"WARN",
+				"snapshot_mismatch_skip_reconcile_missing",
+				"state_snapshot_id=%q open_orders_snapshot_id=%q",
 				stateID,
 				ordersID,
 			)
@@ -299,7 +871,7 @@ func (r *LiveRunner) loadPersistedForResync(reconnect bool) ([]core.Order, bool,
 }
 
 func (r *LiveRunner) resync(ctx context.Context, price decimal.Decimal, seen *seenTracker, persisted []core.Order, allowPersistedReconcile bool) error {
-	open, err := r.Exchange.OpenOrders(ctx, r.Symbol)
+	open, err := r.openOrders(ctx)
 	if err != nil {
 		r.alertImportant("reconcile_open_orders_failed", map[string]string{
 			"err": err.Error(),
@@ -328,6 +900,8 @@ func (r *LiveRunner) resync(ctx context.Context, price decimal.Decimal, seen *se
 		}
 	}
 
+	r.checkTwinInstance(open)
+
 	if reconciler, ok := r.Strategy.(strategy.Reconciler); ok {
 		if err := reconciler.Reconcile(ctx, price, open); err != nil {
 			if errors.Is(err, strategy.ErrStopped) {
@@ -338,6 +912,7 @@ func (r *LiveRunner) resync(ctx context.Context, price decimal.Decimal, seen *se
 			})
 			return fmt.Errorf("%w: strategy reconcile: %v", ErrFatalLocal, err)
 		}
+		r.Metrics.Inc("gridbot_reconciles_total")
 		return nil
 	}
 	r.alertImportant("reconcile_not_supported", map[string]string{
@@ -364,7 +939,7 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 		}
 		missing = append(missing, ord)
 	}
-	if len(missing) == 0 {
+	if len(missing) == 0 || r.DryRun != nil {
 		return open, nil
 	}
 
@@ -385,14 +960,45 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 			return open, err
 		}
 		switch status.Order.Status {
-		case core.OrderNew, core.OrderPartiallyFilled:
+		case core.OrderNew, core.OrderPartiallyFilled, core.OrderPendingCancel:
 			still := status.Order
 			if status.ExecutedQty.Cmp(decimal.Zero) > 0 && still.Qty.Cmp(status.ExecutedQty) > 0 {
 				still.Qty = still.Qty.Sub(status.ExecutedQty)
 			}
+			if trade, ok := partialTradeFromOrder(status, ord, r.streamedFills[ord.ID]); ok {
+				dup, err := r.shouldSkipTrade(trade, seen, time.Now().UTC())
+				if err != nil {
+					return open, fmt.Errorf("%w: trade dedup check: %v", ErrFatalLocal, err)
+				}
+				if !dup {
+					pnlDelta, err := r.applyReconciledFill(ctx, trade)
+					if err != nil {
+						if errors.Is(err, strategy.ErrStopped) {
+							r.alertImportant("manual_intervention_required", map[string]string{
+								"reason": "strategy_stopped",
+								"stage":  "reconcile_apply_partial_fill",
+							})
+							return open, nil
+						}
+						r.alertImportant("reconcile_apply_partial_fill_failed", map[string]string{
+							"order_id":        trade.OrderID,
+							"client_order_id": status.Order.ClientID,
+							"err":             err.Error(),
+						})
+						return open, fmt.Errorf("%w: strategy reconcile apply partial fill: %v", ErrFatalLocal, err)
+					}
+					if err := r.recordTradeLedger(trade, r.reconcileLedgerEntry(trade, ord, pnlDelta)); err != nil {
+						return open, fmt.Errorf("%w: trade ledger record: %v", ErrFatalLocal, err)
+					}
+					appliedTrade = true
+					r.noteReconciledFill(ord.ID, status.ExecutedQty, false)
+				}
+				r.noteStreamedFill(trade)
+			}
 			stillOpen = append(stillOpen, still)
 		case core.OrderFilled:
-			trade := tradeFromOrder(status, ord)
+			trade := tradeFromOrder(status, ord, r.streamedFills[ord.ID])
+			delete(r.streamedFills, ord.ID)
 			if trade.OrderID != "" {
 				dup, err := r.shouldSkipTrade(trade, seen, time.Now().UTC())
 				if err != nil {
@@ -401,7 +1007,8 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 				if dup {
 					break
 				}
-				if err := r.Strategy.OnFill(ctx, trade); err != nil {
+				pnlDelta, err := r.applyReconciledFill(ctx, trade)
+				if err != nil {
 					if errors.Is(err, strategy.ErrStopped) {
 						r.alertImportant("manual_intervention_required", map[string]string{
 							"reason": "strategy_stopped",
@@ -416,14 +1023,16 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 					})
 					return open, fmt.Errorf("%w: strategy reconcile apply fill: %v", ErrFatalLocal, err)
 				}
-				if err := r.recordTradeLedger(trade); err != nil {
+				if err := r.recordTradeLedger(trade, r.reconcileLedgerEntry(trade, ord, pnlDelta)); err != nil {
 					return open, fmt.Errorf("%w: trade ledger record: %v", ErrFatalLocal, err)
 				}
 				appliedTrade = true
+				r.noteReconciledFill(ord.ID, status.ExecutedQty, true)
 			}
 		case core.OrderCanceled, core.OrderRejected, core.OrderExpired:
 			if status.ExecutedQty.Cmp(decimal.Zero) > 0 {
-				trade := tradeFromOrder(status, ord)
+				trade := tradeFromOrder(status, ord, r.streamedFills[ord.ID])
+				delete(r.streamedFills, ord.ID)
 				if trade.OrderID != "" {
 					dup, err := r.shouldSkipTrade(trade, seen, time.Now().UTC())
 					if err != nil {
@@ -432,7 +1041,8 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 					if dup {
 						break
 					}
-					if err := r.Strategy.OnFill(ctx, trade); err != nil {
+					pnlDelta, err := r.applyReconciledFill(ctx, trade)
+					if err != nil {
 						if errors.Is(err, strategy.ErrStopped) {
 							r.alertImportant("manual_intervention_required", map[string]string{
 								"reason": "strategy_stopped",
@@ -447,10 +1057,11 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 						})
 						return open, fmt.Errorf("%w: strategy reconcile apply partial close: %v", ErrFatalLocal, err)
 					}
-					if err := r.recordTradeLedger(trade); err != nil {
+					if err := r.recordTradeLedger(trade, r.reconcileLedgerEntry(trade, ord, pnlDelta)); err != nil {
 						return open, fmt.Errorf("%w: trade ledger record: %v", ErrFatalLocal, err)
 					}
 					appliedTrade = true
+					r.noteReconciledFill(ord.ID, status.ExecutedQty, true)
 				}
 				r.alertImportant("order_closed_with_partial_fill", map[string]string{
 					"order_id":        status.Order.ID,
@@ -480,7 +1091,7 @@ func (r *LiveRunner) reconcileMissing(ctx context.Context, open []core.Order, pe
 	}
 
 	if appliedTrade {
-		refreshed, err := r.Exchange.OpenOrders(ctx, r.Symbol)
+		refreshed, err := r.openOrders(ctx)
 		if err != nil {
 			r.alertImportant("reconcile_refresh_open_orders_failed", map[string]string{
 				"err": err.Error(),
@@ -501,28 +1112,24 @@ func (r *LiveRunner) alertImportant(event string, fields map[string]string) {
 }
 
 func (r *LiveRunner) persistRuntimeStatus(state string, startedAt time.Time, reconnectAttempts int, disconnectStartedAt time.Time, lastErr error) {
+	r.Metrics.Set("gridbot_reconnect_attempts", float64(reconnectAttempts))
+	r.Metrics.Set("gridbot_runner_running", boolGauge(state == "running"))
 	if r.Store == nil {
 		return
 	}
 	if startedAt.IsZero() {
 		startedAt = time.Now().UTC()
 	}
-	mode := r.Mode
-	if mode == "" {
-		mode = "live"
-	}
-	instanceID := r.InstanceID
-	if instanceID == "" {
-		instanceID = "default"
-	}
+	labels := r.instanceLabels()
 	status := store.RuntimeStatus{
-		Mode:              mode,
-		Symbol:            r.Symbol,
-		InstanceID:        instanceID,
+		Mode:              labels.Mode,
+		Symbol:            labels.Symbol,
+		InstanceID:        labels.InstanceID,
 		PID:               os.Getpid(),
 		State:             state,
 		StartedAt:         startedAt,
 		ReconnectAttempts: reconnectAttempts,
+		PeakEquity:        r.peakEquity,
 	}
 	if !disconnectStartedAt.IsZero() {
 		t := disconnectStartedAt
@@ -531,11 +1138,133 @@ func (r *LiveRunner) persistRuntimeStatus(state string, startedAt time.Time, rec
 	if lastErr != nil {
 		status.LastError = lastErr.Error()
 	}
+	if state == "stopped" && len(r.runSummary) > 0 {
+		status.RunSummary = r.runSummary
+	}
 	if err := r.Store.SaveRuntimeStatus(status); err != nil {
-		log.Printf("level=WARN event=runtime_status_write_failed err=%q", err.Error())
+		r.logf("WARN", "runtime_status_write_failed", "err=%q", err.Error())
+	}
+}
+
+func (r *LiveRunner) instanceLabels() metrics.Labels {
+	mode := r.Mode
+	if mode == "" {
+		mode = "live"
+	}
+	instanceID := r.InstanceID
+	if instanceID == "" {
+		instanceID = "default"
+	}
+	return metrics.Labels{
+		Mode:       mode,
+		Symbol:     r.Symbol,
+		InstanceID: instanceID,
+	}
+}
+
+func (r *LiveRunner) initMetrics() {
+	labels := r.instanceLabels()
+	if r.Metrics == nil {
+		r.Metrics = metrics.NewRegistry(labels)
+	} else {
+		r.Metrics.SetLabels(labels)
+	}
+	r.Metrics.Counter("gridbot_trades_total", "Trades applied to the strategy.")
+	r.Metrics.Counter("gridbot_reconciles_total", "Completed exchange resyncs.")
+	r.Metrics.Counter("gridbot_reconnects_total", "User stream reconnect attempts.")
+	r.Metrics.Gauge("gridbot_reconnect_attempts", "Consecutive reconnect attempts since the last healthy stream.")
+	r.Metrics.Gauge("gridbot_runner_running", "1 when the runner is connected and running.")
+	r.Metrics.Gauge("gridbot_reconcile_interval_seconds", "Current periodic reconcile interval.")
+	r.Metrics.Counter("gridbot_reconcile_quiet_skips_total", "Periodic reconciles skipped in the quiet period after a bootstrap.")
+	r.Metrics.Counter("gridbot_rest_timeout_retries_total", "REST calls retried after a per-call timeout.")
+	r.Metrics.Counter("gridbot_invalid_tick_prices_total", "Zero or negative tick prices dropped before reaching the strategy.")
+	r.Metrics.Gauge("gridbot_equity_drawdown_pct", "Equity drawdown from the run's peak, in percent.")
+	r.Metrics.Gauge("gridbot_last_tick_price", "Last price fed to the strategy.")
+	r.Metrics.Gauge("gridbot_open_orders", "Orders the strategy tracks as open.")
+	r.Metrics.Gauge("gridbot_grid_min_level", "Lowest grid level in the current window.")
+	r.Metrics.Gauge("gridbot_grid_max_level", "Highest grid level in the current window.")
+	for _, name := range breakerCircuits {
+		r.Metrics.Gauge("gridbot_circuit_breaker_"+name+"_state", "Circuit breaker "+name+" circuit: 0 closed, 1 half open, 2 open.")
 	}
 }
 
+var breakerCircuits = []string{"place", "cancel", "reconnect"}
+
+// updateStateMetrics refreshes the gauges read from the strategy and the
+// breaker. It runs on the event loop after resyncs and on each heartbeat.
+func (r *LiveRunner) updateStateMetrics() {
+	if reporter, ok := r.Strategy.(strategy.GridStatusReporter); ok {
+		status := reporter.GridStatus()
+		r.Metrics.Set("gridbot_open_orders", float64(status.OpenOrders))
+		r.Metrics.Set("gridbot_grid_min_level", float64(status.MinLevel))
+		r.Metrics.Set("gridbot_grid_max_level", float64(status.MaxLevel))
+		r.Status.Publish(status)
+	}
+	states := r.Breaker.CircuitStates()
+	for _, name := range breakerCircuits {
+		value := 0.0
+		switch states[name] {
+		case "half_open":
+			value = 1
+		case "open":
+			value = 2
+		}
+		r.Metrics.Set("gridbot_circuit_breaker_"+name+"_state", value)
+	}
+}
+
+func (r *LiveRunner) pushMetrics(ctx context.Context) {
+	if r.Pusher == nil {
+		return
+	}
+	if r.Metrics == nil {
+		r.initMetrics()
+	}
+	// The push runs off the event loop so a slow gateway cannot delay fills
+	// or heartbeats; a heartbeat that finds the previous push still in
+	// flight skips its own.
+	if !r.pushing.CompareAndSwap(false, true) {
+		return
+	}
+	r.pushWG.Add(1)
+	go func() {
+		defer r.pushWG.Done()
+		defer r.pushing.Store(false)
+		if err := r.Pusher.Push(ctx, r.Metrics); err != nil {
+			r.logf("WARN", "metrics_push_failed", "err=%q", err.Error())
+		}
+	}()
+}
+
+func (r *LiveRunner) deletePushedMetrics() {
+	if r.Pusher == nil || r.Metrics == nil {
+		return
+	}
+	r.pushWG.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Pusher.Delete(ctx, r.Metrics); err != nil {
+		r.logf("WARN", "metrics_push_delete_failed", "err=%q", err.Error())
+	}
+}
+
+func (r *LiveRunner) logf(level, event, format string, args ...any) {
+	labels := r.instanceLabels()
+	prefix := fmt.Sprintf("level=%s event=%s mode=%q symbol=%q instance_id=%q", level, event, labels.Mode, labels.Symbol, labels.InstanceID)
+	if format == "" {
+		log.Print(prefix)
+		return
+	}
+	log.Print(prefix + " " + fmt.Sprintf(format, args...))
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
 type seenTracker struct {
 	items map[string]time.Time
 	queue []seenEntry
@@ -628,7 +1357,87 @@ func mergeOrders(open []core.Order, extra []core.Order) []core.Order {
 	return open
 }
 
-func tradeFromOrder(status binance.OrderQuery, fallback core.Order) core.Trade {
+// orderFill is the executed qty and quote of an order already applied to the
+// strategy through stream fills.
+type orderFill struct {
+	qty   decimal.Decimal
+	quote decimal.Decimal
+}
+
+// reconciledFill is how much of an order a reconcile applied from its
+// queried status, and whether the order was closed.
+type reconciledFill struct {
+	executed decimal.Decimal
+	closed   bool
+}
+
+func (r *LiveRunner) noteReconciledFill(orderID string, executed decimal.Decimal, closed bool) {
+	if orderID == "" {
+		return
+	}
+	if r.reconciledFills == nil {
+		r.reconciledFills = make(map[string]reconciledFill)
+	}
+	r.reconciledFills[orderID] = reconciledFill{executed: executed, closed: closed}
+}
+
+// alreadyReconciled reports whether a stream fill is covered by a reconcile
+// that applied the order's queried status, e.g. an execution report queued
+// during a disconnect and delivered after the reconnect resync. The fill is
+// covered when its cumulative qty is within what the reconcile applied, or,
+// without a cumulative qty, when the reconcile closed the order.
+func (r *LiveRunner) alreadyReconciled(trade core.Trade) bool {
+	rec, ok := r.reconciledFills[trade.OrderID]
+	if !ok {
+		return false
+	}
+	if trade.CumQty.Cmp(decimal.Zero) > 0 {
+		return trade.CumQty.Cmp(rec.executed) <= 0
+	}
+	return rec.closed
+}
+
+// forgetReconciledFill drops the reconcile record for trade's order once the
+// stream reports the order closed; no later report for it can follow.
+func (r *LiveRunner) forgetReconciledFill(trade core.Trade) {
+	if trade.Status != core.OrderPartiallyFilled {
+		delete(r.reconciledFills, trade.OrderID)
+	}
+}
+
+// noteStreamedFill tracks partial fills so a later reconcile of the same
+// order applies only the part the stream never delivered.
+func (r *LiveRunner) noteStreamedFill(trade core.Trade) {
+	if trade.OrderID == "" {
+		return
+	}
+	r.forgetReconciledFill(trade)
+	if trade.Status != core.OrderPartiallyFilled {
+		delete(r.streamedFills, trade.OrderID)
+		return
+	}
+	if r.streamedFills == nil {
+		r.streamedFills = make(map[string]orderFill)
+	}
+	prev := r.streamedFills[trade.OrderID]
+	next := orderFill{qty: trade.CumQty, quote: trade.CumQuote}
+	if next.qty.Cmp(decimal.Zero) <= 0 || next.quote.Cmp(decimal.Zero) <= 0 {
+		next = orderFill{
+			qty:   prev.qty.Add(trade.Qty),
+			quote: prev.quote.Add(trade.Qty.Mul(trade.Price)),
+		}
+	}
+	r.streamedFills[trade.OrderID] = next
+}
+
+// tradeFromOrder turns a queried order into the fill still owed to the
+// strategy. Price is the cumulative-quote average; when part of the order was
+// already applied (streamed, or implied by the persisted remaining qty at the
+// limit price) only the residual qty is returned, priced at the residual
+// quote so stream and reconcile fills sum to the exchange's totals. When
+// everything executed was already applied nothing is owed and the returned
+// trade has no OrderID.
+func tradeFromOrder(status binance.OrderQuery, fallback core.Order, applied orderFill) core.Trade {
 	order := status.Order
 	if order.ID == "" {
 		order = fallback
@@ -638,8 +1447,25 @@ func tradeFromOrder(status binance.OrderQuery, fallback core.Order) core.Trade {
 		qty = order.Qty
 	}
 	price := order.Price
-	if status.ExecutedQty.Cmp(decimal.Zero) > 0 && status.CumulativeQuoteQty.Cmp(decimal.Zero) > 0 {
-		price = status.CumulativeQuoteQty.Div(status.ExecutedQty)
+	if status.ExecutedQty.Cmp(decimal.Zero) > 0 {
+		if status.CumulativeQuoteQty.Cmp(decimal.Zero) > 0 {
+			price = status.CumulativeQuoteQty.Div(status.ExecutedQty)
+		}
+		if applied.qty.Cmp(decimal.Zero) <= 0 && fallback.Qty.Cmp(decimal.Zero) > 0 && status.Order.Qty.Cmp(fallback.Qty) > 0 {
+			appliedQty := status.Order.Qty.Sub(fallback.Qty)
+			applied = orderFill{qty: appliedQty, quote: appliedQty.Mul(fallback.Price)}
+		}
+		if applied.qty.Cmp(decimal.Zero) > 0 {
+			residualQty := status.ExecutedQty.Sub(applied.qty)
+			if residualQty.Cmp(decimal.Zero) <= 0 {
+				return core.Trade{}
+			}
+			qty = residualQty
+			residualQuote := status.CumulativeQuoteQty.Sub(applied.quote)
+			if status.CumulativeQuoteQty.Cmp(decimal.Zero) > 0 && residualQuote.Cmp(decimal.Zero) > 0 {
+				price = residualQuote.Div(residualQty)
+			}
+		}
 	}
 	ts := status.UpdateTime
 	if ts.IsZero() {
@@ -658,7 +1484,34 @@ func tradeFromOrder(status binance.OrderQuery, fallback core.Order) core.Trade {
 		Qty:     qty,
 		Status:  order.Status,
 		Time:    ts,
+
+		CumQty:   status.ExecutedQty,
+		CumQuote: status.CumulativeQuoteQty,
+	}
+}
+
+// partialTradeFromOrder returns the executed part of a still-open order that
+// the strategy has not seen yet. The trade id carries the executed qty so a
+// later reconcile of the same order (more fills, or the final FILLED) is not
+// deduped against it.
+func partialTradeFromOrder(status binance.OrderQuery, fallback core.Order, applied orderFill) (core.Trade, bool) {
+	if status.ExecutedQty.Cmp(decimal.Zero) <= 0 || status.Order.ID == "" {
+		return core.Trade{}, false
+	}
+	residual := status.ExecutedQty.Sub(applied.qty)
+	if applied.qty.Cmp(decimal.Zero) <= 0 && fallback.Qty.Cmp(decimal.Zero) > 0 && status.Order.Qty.Cmp(fallback.Qty) > 0 {
+		residual = status.ExecutedQty.Sub(status.Order.Qty.Sub(fallback.Qty))
+	}
+	if residual.Cmp(decimal.Zero) <= 0 {
+		return core.Trade{}, false
 	}
+	trade := tradeFromOrder(status, fallback, applied)
+	if trade.Qty.Cmp(residual) > 0 {
+		trade.Qty = residual
+	}
+	trade.Status = core.OrderPartiallyFilled
+	trade.TradeID = "reconcile-" + status.Order.ID + "-" + status.ExecutedQty.String()
+	return trade, true
 }
 
 func tradeEventKey(trade core.Trade) string {
@@ -707,13 +1560,65 @@ func (r *LiveRunner) shouldSkipTrade(trade core.Trade, seen *seenTracker, now ti
 	return seenBefore, nil
 }
 
-func (r *LiveRunner) recordTradeLedger(trade core.Trade) error {
+// recordTradeLedger marks trade applied; entry carries optional details.
+func (r *LiveRunner) recordTradeLedger(trade core.Trade, entry store.TradeLedgerEntry) error {
 	if r.Store == nil {
 		return nil
 	}
-	ledgerKey := tradeLedgerKey(trade)
-	if ledgerKey == "" {
+	entry.Key = tradeLedgerKey(trade)
+	if entry.Key == "" {
 		return nil
 	}
-	return r.Store.RecordTradeLedgerKey(ledgerKey, trade.Time)
+	entry.SeenAt = trade.Time
+	return r.Store.RecordTradeLedger(entry)
+}
+
+// applyReconciledFill hands a fill found by reconcile to the strategy and
+// counts it in the run stats, as the stream path does. It returns the
+// realized PnL the fill added.
+func (r *LiveRunner) applyReconciledFill(ctx context.Context, trade core.Trade) (decimal.Decimal, error) {
+	pnlBefore := r.stats.RealizedPnL()
+	err := r.Strategy.OnFill(ctx, trade)
+	if err == nil || errors.Is(err, strategy.ErrStopped) {
+		r.stats.Record(trade)
+	}
+	return r.stats.RealizedPnL().Sub(pnlBefore), err
+}
+
+// reconcileLedgerEntry details a fill found by reconcile.
+func (r *LiveRunner) reconcileLedgerEntry(trade core.Trade, ord core.Order, pnlDelta decimal.Decimal) store.TradeLedgerEntry {
+	if !r.LedgerDetails {
+		return store.TradeLedgerEntry{}
+	}
+	return ledgerDetails(trade, ord, true, pnlDelta)
+}
+
+func (r *LiveRunner) trackedOrder(orderID string) (core.Order, bool) {
+	tracker, ok := r.Strategy.(strategy.OrderTracker)
+	if !ok || orderID == "" {
+		return core.Order{}, false
+	}
+	return tracker.TrackedOrder(orderID)
+}
+
+// ledgerDetails describes a fill for the trade ledger: the fill itself and
+// its commission, the level and origin of the order it hit when the grid
+// tracked it, and the realized PnL (as in the run summary) it added.
+func ledgerDetails(trade core.Trade, ord core.Order, tracked bool, pnlDelta decimal.Decimal) store.TradeLedgerEntry {
+	entry := store.TradeLedgerEntry{
+		Side:     string(trade.Side),
+		Price:    trade.Price.String(),
+		Qty:      trade.Qty.String(),
+		PnLDelta: pnlDelta.String(),
+	}
+	if trade.Fee.Sign() > 0 {
+		entry.Fee = trade.Fee.String()
+		entry.FeeAsset = trade.FeeAsset
+	}
+	if tracked {
+		level := ord.GridIndex
+		entry.Level = &level
+		entry.Origin = ord.Origin
+	}
+	return entry
 }
@@ -1,12 +1,15 @@
 package engine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -18,6 +21,7 @@ import (
 
 	"grid-trading/internal/core"
 	"grid-trading/internal/exchange/binance"
+	"grid-trading/internal/metrics"
 	"grid-trading/internal/safety"
 	"grid-trading/internal/store"
 	"grid-trading/internal/strategy"
@@ -313,6 +317,132 @@ func TestLiveRunnerProcessesPartialAndFinalTrades(t *testing.T) {
 	assertNoAsyncErr(t, asyncErrs)
 }
 
+func TestLiveRunnerProcessesBatchedExecutionReportsOnce(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{
+				"symbol": "BTCUSDT",
+				"price":  "100",
+			})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	first := executionReportPayload{
+		OrderID:   52001,
+		TradeID:   62001,
+		Side:      "BUY",
+		Status:    "FILLED",
+		OrderQty:  "1",
+		LastQty:   "1",
+		LastPrice: "99",
+		CumQty:    "1",
+	}
+	second := executionReportPayload{
+		OrderID:   52002,
+		TradeID:   62002,
+		Side:      "SELL",
+		Status:    "FILLED",
+		OrderQty:  "1",
+		LastQty:   "1",
+		LastPrice: "101",
+		CumQty:    "1",
+	}
+	third := executionReportPayload{
+		OrderID:   52003,
+		TradeID:   62003,
+		Side:      "BUY",
+		Status:    "FILLED",
+		OrderQty:  "1",
+		LastQty:   "1",
+		LastPrice: "98",
+		CumQty:    "1",
+	}
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{
+			CheckOrigin: func(*http.Request) bool { return true },
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+
+		if err := writeExecutionReports(conn, first, second); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		// Redelivery of a batched report must be deduplicated.
+		if err := writeExecutionReport(conn, first); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeExecutionReport(conn, third); err != nil {
+			recordAsyncErr(asyncErrs, err)
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	strat := &liveStrategySpy{stopAfterFill: 3}
+	runner := LiveRunner{
+		Exchange: client,
+		Strategy: strat,
+		Symbol:   "BTCUSDT",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	_, _, fills := strat.stats()
+	if len(fills) != 3 {
+		t.Fatalf("fill calls = %d, want 3", len(fills))
+	}
+	wantOrderIDs := []string{"52001", "52002", "52003"}
+	for i, want := range wantOrderIDs {
+		if fills[i].OrderID != want {
+			t.Fatalf("fill[%d] order id = %s, want %s", i, fills[i].OrderID, want)
+		}
+	}
+
+	assertNoAsyncErr(t, asyncErrs)
+}
+
 func TestLiveRunOnceExitsCleanlyWhenInitialResyncStopsStrategy(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
@@ -388,6 +518,126 @@ func TestLiveRunOnceExitsCleanlyWhenInitialResyncStopsStrategy(t *testing.T) {
 	assertNoAsyncErr(t, asyncErrs)
 }
 
+func TestLiveRunOnceReconnectResyncsBeforeDrainingBacklog(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		case "/api/v3/order":
+			_ = writeJSON(w, http.StatusOK, map[string]any{
+				"symbol":              "BTCUSDT",
+				"orderId":             12001,
+				"clientOrderId":       "test-12001",
+				"price":               "100",
+				"origQty":             "1",
+				"executedQty":         "1",
+				"cummulativeQuoteQty": "100",
+				"status":              "FILLED",
+				"side":                "BUY",
+				"type":                "LIMIT",
+				"time":                time.Now().Add(-time.Minute).UnixMilli(),
+				"updateTime":          time.Now().Add(-30 * time.Second).UnixMilli(),
+			})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	queuedAt := time.Now().Add(-30 * time.Second)
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		// The backlog queued during the outage arrives as one burst.
+		if err := writeExecutionReports(conn,
+			executionReportPayload{OrderID: 12001, TradeID: 22001, Side: "BUY", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1", TradeTime: queuedAt},
+			executionReportPayload{OrderID: 12002, TradeID: 22002, Side: "SELL", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "110", CumQty: "1", TradeTime: queuedAt},
+			executionReportPayload{OrderID: 12003, TradeID: 22003, Side: "BUY", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1", TradeTime: queuedAt},
+		); err != nil {
+			recordAsyncErr(asyncErrs, err)
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	if err := st.SaveOpenOrders([]core.Order{{
+		ID: "12001", ClientID: "test-12001", Symbol: "BTCUSDT", Side: core.Buy, Type: core.Limit,
+		Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), GridIndex: -1,
+	}}); err != nil {
+		t.Fatalf("SaveOpenOrders() error = %v", err)
+	}
+	strat := &liveStrategySpy{stopAfterFill: 3}
+	runner := LiveRunner{
+		Exchange:        client,
+		Strategy:        strat,
+		Symbol:          "BTCUSDT",
+		Store:           st,
+		BacklogDrainMax: 1,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	seen := newSeenTracker(128, time.Hour)
+	reconnectAttempts := 1
+	disconnectStartedAt := time.Now().UTC().Add(-time.Minute)
+	backoff := time.Second
+	if err := runner.runOnce(ctx, true, seen, &reconnectAttempts, &disconnectStartedAt, &backoff, time.Now().UTC()); err != nil {
+		t.Fatalf("runOnce() error = %v, want nil", err)
+	}
+
+	_, reconcileCalls, fills := strat.stats()
+	if len(fills) != 3 {
+		t.Fatalf("fills = %d, want 3 (order 12001 once): %+v", len(fills), fills)
+	}
+	// The resync applies 12001 before the stream is read; its queued
+	// execution report is then skipped instead of applied a second time.
+	if fills[0].OrderID != "12001" || fills[0].TradeID != "reconcile-12001" {
+		t.Fatalf("first fill = %s/%s, want the resync's reconcile-12001", fills[0].OrderID, fills[0].TradeID)
+	}
+	if fills[1].OrderID != "12002" || fills[2].OrderID != "12003" {
+		t.Fatalf("backlog fills = %s, %s, want 12002, 12003", fills[1].OrderID, fills[2].OrderID)
+	}
+	// One reconcile from the resync, one yield after the first applied
+	// backlog fill; the third fill stops the strategy.
+	if reconcileCalls != 2 {
+		t.Fatalf("reconcile calls = %d, want 2", reconcileCalls)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
 func TestLiveReconcileMissingAppliesFilledOnlyOnce(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
@@ -434,6 +684,7 @@ func TestLiveReconcileMissingAppliesFilledOnlyOnce(t *testing.T) {
 		Exchange: client,
 		Strategy: strat,
 		Symbol:   "BTCUSDT",
+		stats:    newRunStats(),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -469,10 +720,112 @@ func TestLiveReconcileMissingAppliesFilledOnlyOnce(t *testing.T) {
 	if len(fills) != 1 {
 		t.Fatalf("fill calls = %d, want 1", len(fills))
 	}
+	if got := runner.stats.fields(); got["trades"] != "1" || got["buy_qty"] != "1" {
+		t.Fatalf("run stats = %v, want the reconciled fill counted once", got)
+	}
 
 	assertNoAsyncErr(t, asyncErrs)
 }
 
+func TestLiveReconcileMissingRecordsLedgerPnLDelta(t *testing.T) {
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/order":
+			_ = writeJSON(w, http.StatusOK, map[string]any{
+				"symbol":              "BTCUSDT",
+				"orderId":             50002,
+				"clientOrderId":       "cid-50002",
+				"price":               "110",
+				"origQty":             "1",
+				"executedQty":         "1",
+				"cummulativeQuoteQty": "110",
+				"status":              "FILLED",
+				"side":                "SELL",
+				"type":                "LIMIT",
+				"time":                time.Now().Add(-time.Second).UnixMilli(),
+				"updateTime":          time.Now().UnixMilli(),
+			})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "BTCUSDT",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+
+	runner := LiveRunner{
+		Exchange:      client,
+		Strategy:      &liveStrategySpy{},
+		Symbol:        "BTCUSDT",
+		Store:         st,
+		LedgerDetails: true,
+		stats:         newRunStats(),
+	}
+	// A buy streamed earlier in the run is what the reconciled sell closes.
+	runner.stats.Record(core.Trade{Side: core.Buy, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1)})
+	persisted := []core.Order{{
+		ID: "50002", ClientID: "cid-50002", Symbol: "BTCUSDT", Side: core.Sell, Type: core.Limit,
+		Price: decimal.NewFromInt(110), Qty: decimal.NewFromInt(1), GridIndex: 1,
+	}}
+	if _, err := runner.reconcileMissing(context.Background(), nil, persisted, newSeenTracker(128, time.Hour)); err != nil {
+		t.Fatalf("reconcileMissing() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := st.ExportTradeLedger(&out, store.ExportJSON); err != nil {
+		t.Fatalf("ExportTradeLedger() error = %v", err)
+	}
+	var entries []store.TradeLedgerEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("ledger decode error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Side != "SELL" || entries[0].PnLDelta != "10" {
+		t.Fatalf("ledger = %s, want the reconciled sell with pnl_delta 10", out.String())
+	}
+	if got := runner.stats.RealizedPnL(); !got.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("run realized pnl = %s, want 10", got)
+	}
+}
+
+func TestLiveRunnerForgetsReconciledFillOnceStreamCloses(t *testing.T) {
+	var r LiveRunner
+	r.noteReconciledFill("7", decimal.RequireFromString("0.4"), false)
+	partial := core.Trade{OrderID: "7", Status: core.OrderPartiallyFilled, CumQty: decimal.RequireFromString("0.4")}
+	if !r.alreadyReconciled(partial) {
+		t.Fatalf("alreadyReconciled(partial within executed) = false, want true")
+	}
+	r.forgetReconciledFill(partial)
+	if _, ok := r.reconciledFills["7"]; !ok {
+		t.Fatalf("record dropped on a partial report, want it kept for later reports")
+	}
+	r.noteStreamedFill(core.Trade{OrderID: "7", Status: core.OrderFilled, CumQty: decimal.NewFromInt(1)})
+	if len(r.reconciledFills) != 0 {
+		t.Fatalf("reconciledFills = %v, want empty once the stream closes the order", r.reconciledFills)
+	}
+
+	r.noteReconciledFill("8", decimal.NewFromInt(1), true)
+	closed := core.Trade{OrderID: "8", Status: core.OrderFilled, CumQty: decimal.NewFromInt(1)}
+	if !r.alreadyReconciled(closed) {
+		t.Fatalf("alreadyReconciled(closed) = false, want true")
+	}
+	r.forgetReconciledFill(closed)
+	if len(r.reconciledFills) != 0 {
+		t.Fatalf("reconciledFills = %v, want empty once the skipped report closes the order", r.reconciledFills)
+	}
+}
+
 func TestLiveReconcileMissingAutoHandlesClosedPartialFill(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
@@ -564,15 +917,25 @@ func TestLiveReconcileMissingAutoHandlesClosedPartialFill(t *testing.T) {
 	assertNoAsyncErr(t, asyncErrs)
 }
 
-func TestLiveRunnerReconnectCircuitBreakerDoesNotStopRunner(t *testing.T) {
+func TestLiveReconcileMissingAppliesExecutedPartOfOpenOrder(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
 	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
-		case "/api/v3/ticker/price":
-			_ = writeJSON(w, http.StatusOK, map[string]string{
-				"symbol": "BTCUSDT",
-				"price":  "100",
+		case "/api/v3/order":
+			_ = writeJSON(w, http.StatusOK, map[string]any{
+				"symbol":              "BTCUSDT",
+				"orderId":             50003,
+				"clientOrderId":       "cid-50003",
+				"price":               "100",
+				"origQty":             "1",
+				"executedQty":         "0.4",
+				"cummulativeQuoteQty": "40",
+				"status":              "NEW",
+				"side":                "BUY",
+				"type":                "LIMIT",
+				"time":                time.Now().Add(-time.Second).UnixMilli(),
+				"updateTime":          time.Now().UnixMilli(),
 			})
 		case "/api/v3/openOrders":
 			_ = writeJSON(w, http.StatusOK, []any{})
@@ -587,7 +950,7 @@ func TestLiveRunnerReconnectCircuitBreakerDoesNotStopRunner(t *testing.T) {
 		APIKey:            "k",
 		APISecret:         "s",
 		RestBaseURL:       rest.URL,
-		WSBaseURL:         "ws://127.0.0.1:1/ws-api/v3",
+		WSBaseURL:         "ws://127.0.0.1:1/unused",
 		Symbol:            "BTCUSDT",
 		ClientOrderPrefix: "test",
 		UserStreamAuth:    "signature",
@@ -596,29 +959,178 @@ func TestLiveRunnerReconnectCircuitBreakerDoesNotStopRunner(t *testing.T) {
 	defer client.Close()
 
 	strat := &liveStrategySpy{}
-	breaker := safety.NewBreaker(true, 5, 5, 1)
-	breaker.SetReconnectRecovery(30*time.Second, 1)
 	runner := LiveRunner{
 		Exchange: client,
 		Strategy: strat,
 		Symbol:   "BTCUSDT",
-		Breaker:  breaker,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := runner.Run(ctx)
-	if err == nil {
-		t.Fatalf("Run() error = nil, want context deadline")
-	}
-	if !errors.Is(err, context.DeadlineExceeded) {
-		t.Fatalf("Run() error = %v, want errors.Is(_, context.DeadlineExceeded)", err)
+	persisted := []core.Order{
+		{
+			ID:       "50003",
+			ClientID: "cid-50003",
+			Symbol:   "BTCUSDT",
+			Side:     core.Buy,
+			Type:     core.Limit,
+			Price:    decimal.RequireFromString("100"),
+			Qty:      decimal.RequireFromString("1"),
+		},
 	}
+	seen := newSeenTracker(128, time.Hour)
+
+	open, err := runner.reconcileMissing(ctx, nil, persisted, seen)
+	if err != nil {
+		t.Fatalf("reconcileMissing() first error = %v", err)
+	}
+	if len(open) != 1 || open[0].ID != "50003" || !open[0].Qty.Equal(decimal.RequireFromString("0.6")) {
+		t.Fatalf("reconcileMissing() first open = %+v, want order 50003 with qty 0.6", open)
+	}
+
+	if _, err := runner.reconcileMissing(ctx, nil, persisted, seen); err != nil {
+		t.Fatalf("reconcileMissing() second error = %v", err)
+	}
+
+	_, _, fills := strat.stats()
+	if len(fills) != 1 {
+		t.Fatalf("fill calls = %d, want 1", len(fills))
+	}
+	if fills[0].Status != core.OrderPartiallyFilled {
+		t.Fatalf("fill status = %s, want %s", fills[0].Status, core.OrderPartiallyFilled)
+	}
+	if !fills[0].Qty.Equal(decimal.RequireFromString("0.4")) || !fills[0].Price.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("fill = %s @ %s, want 0.4 @ 100", fills[0].Qty, fills[0].Price)
+	}
+
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestLiveReconcileMissingKeepsTransitionalStatusOrdersTracked(t *testing.T) {
+	for _, status := range []string{"PENDING_NEW", "PENDING_CANCEL"} {
+		t.Run(status, func(t *testing.T) {
+			asyncErrs := make(chan error, 16)
+			rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/order":
+					_ = writeJSON(w, http.StatusOK, map[string]any{
+						"symbol":              "BTCUSDT",
+						"orderId":             50004,
+						"clientOrderId":       "cid-50004",
+						"price":               "100",
+						"origQty":             "1",
+						"executedQty":         "0",
+						"cummulativeQuoteQty": "0",
+						"status":              status,
+						"side":                "BUY",
+						"type":                "LIMIT",
+						"time":                time.Now().Add(-time.Second).UnixMilli(),
+						"updateTime":          time.Now().UnixMilli(),
+					})
+				default:
+					recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+					_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+				}
+			}))
+			defer rest.Close()
+
+			client := binance.NewClientWithOptions(binance.Options{
+				APIKey:            "k",
+				APISecret:         "s",
+				RestBaseURL:       rest.URL,
+				WSBaseURL:         "ws://127.0.0.1:1/unused",
+				Symbol:            "BTCUSDT",
+				ClientOrderPrefix: "test",
+				UserStreamAuth:    "signature",
+				HTTPTimeoutSec:    3,
+			})
+			defer client.Close()
+
+			strat := &liveStrategySpy{}
+			runner := LiveRunner{Exchange: client, Strategy: strat, Symbol: "BTCUSDT"}
+			persisted := []core.Order{{
+				ID:       "50004",
+				ClientID: "cid-50004",
+				Symbol:   "BTCUSDT",
+				Side:     core.Buy,
+				Type:     core.Limit,
+				Price:    decimal.RequireFromString("100"),
+				Qty:      decimal.RequireFromString("1"),
+			}}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			open, err := runner.reconcileMissing(ctx, nil, persisted, newSeenTracker(128, time.Hour))
+			if err != nil {
+				t.Fatalf("reconcileMissing() error = %v", err)
+			}
+			if len(open) != 1 || open[0].ID != "50004" || !open[0].Qty.Equal(decimal.NewFromInt(1)) {
+				t.Fatalf("reconcileMissing() open = %+v, want order 50004 still tracked", open)
+			}
+			if _, _, fills := strat.stats(); len(fills) != 0 {
+				t.Fatalf("fill calls = %d, want 0", len(fills))
+			}
+			assertNoAsyncErr(t, asyncErrs)
+		})
+	}
+}
+
+func TestLiveRunnerReconnectCircuitBreakerDoesNotStopRunner(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{
+				"symbol": "BTCUSDT",
+				"price":  "100",
+			})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         "ws://127.0.0.1:1/ws-api/v3",
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	strat := &liveStrategySpy{}
+	breaker := safety.NewBreaker(true, 5, 5, 1)
+	breaker.SetReconnectRecovery(30*time.Second, 1)
+	runner := LiveRunner{
+		Exchange: client,
+		Strategy: strat,
+		Symbol:   "BTCUSDT",
+		Breaker:  breaker,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := runner.Run(ctx)
+	if err == nil {
+		t.Fatalf("Run() error = nil, want context deadline")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run() error = %v, want errors.Is(_, context.DeadlineExceeded)", err)
+	}
+
+	assertNoAsyncErr(t, asyncErrs)
+}
 
-	assertNoAsyncErr(t, asyncErrs)
-}
-
 func TestLiveRunnerPersistsRuntimeStatus(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
@@ -936,6 +1448,113 @@ func TestLiveRunOncePeriodicReconcile(t *testing.T) {
 	assertNoAsyncErr(t, asyncErrs)
 }
 
+type bootstrapStrategySpy struct {
+	liveStrategySpy
+	bootstrappedAt time.Time
+	reconcileAt    []time.Time
+}
+
+func (s *bootstrapStrategySpy) BootstrappedAt() time.Time {
+	return s.bootstrappedAt
+}
+
+func (s *bootstrapStrategySpy) Reconcile(ctx context.Context, price decimal.Decimal, open []core.Order) error {
+	s.mu.Lock()
+	s.reconcileAt = append(s.reconcileAt, time.Now())
+	s.mu.Unlock()
+	return s.liveStrategySpy.Reconcile(ctx, price, open)
+}
+
+func TestLiveRunOnceSkipsPeriodicReconcileInBootstrapQuietPeriod(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		time.Sleep(400 * time.Millisecond)
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	quiet := 150 * time.Millisecond
+	strat := &bootstrapStrategySpy{bootstrappedAt: time.Now()}
+	runner := LiveRunner{
+		Exchange:       client,
+		Strategy:       strat,
+		Symbol:         "BTCUSDT",
+		Reconcile:      30 * time.Millisecond,
+		ReconcileQuiet: quiet,
+		Metrics:        metrics.NewRegistry(metrics.Labels{}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	seen := newSeenTracker(128, time.Hour)
+	reconnectAttempts := 1
+	disconnectStartedAt := time.Time{}
+	backoff := time.Second
+	err := runner.runOnce(ctx, true, seen, &reconnectAttempts, &disconnectStartedAt, &backoff, time.Now().UTC())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("runOnce() error = %v, want context deadline exceeded", err)
+	}
+
+	strat.mu.Lock()
+	calls := append([]time.Time(nil), strat.reconcileAt...)
+	strat.mu.Unlock()
+	if len(calls) < 2 {
+		t.Fatalf("reconcile calls = %d, want the reconnect resync plus periodic ones after the quiet period", len(calls))
+	}
+	// The first call is the reconnect resync, which ignores the quiet period.
+	quietEnd := strat.bootstrappedAt.Add(quiet)
+	for i, at := range calls[1:] {
+		if at.Before(quietEnd) {
+			t.Fatalf("periodic reconcile %d ran %s after bootstrap, inside the %s quiet period", i+1, at.Sub(strat.bootstrappedAt), quiet)
+		}
+	}
+	if skips, _ := runner.Metrics.Value("gridbot_reconcile_quiet_skips_total"); skips < 2 {
+		t.Fatalf("quiet skips = %v, want >= 2", skips)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
 func TestLiveRunOncePeriodicReconcileStopsCleanlyOnErrStopped(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
@@ -1022,6 +1641,73 @@ func TestLiveRunOncePeriodicReconcileStopsCleanlyOnErrStopped(t *testing.T) {
 	assertNoAsyncErr(t, asyncErrs)
 }
 
+type tickErrStrategy struct {
+	liveStrategySpy
+	tickErr error
+}
+
+func (s *tickErrStrategy) OnTick(_ context.Context, _ decimal.Decimal, _ time.Time) error {
+	return s.tickErr
+}
+
+func TestLivePeriodicReconcileOnlyStopsOnFatalTickErrors(t *testing.T) {
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:      "k",
+		APISecret:   "s",
+		RestBaseURL: rest.URL,
+		Symbol:      "BTCUSDT",
+	})
+	defer client.Close()
+
+	cases := []struct {
+		name      string
+		tickErr   error
+		wantFatal bool
+	}{
+		{name: "exchange_error", tickErr: errors.New("cancel order: timeout")},
+		{name: "persist_failure", tickErr: fmt.Errorf("%w: save grid state: disk full", strategy.ErrFatal), wantFatal: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			strat := &tickErrStrategy{tickErr: tc.tickErr}
+			alerts := &runnerAlertRecorder{}
+			runner := LiveRunner{Exchange: client, Strategy: strat, Symbol: "BTCUSDT", Alerts: alerts}
+
+			err := runner.periodicReconcile(context.Background(), newSeenTracker(16, time.Hour))
+			_, reconcileCalls, _ := strat.stats()
+			if tc.wantFatal {
+				if !errors.Is(err, ErrFatalLocal) {
+					t.Fatalf("periodicReconcile() error = %v, want ErrFatalLocal", err)
+				}
+				if reconcileCalls != 0 {
+					t.Fatalf("reconcile calls = %d after fatal tick, want 0", reconcileCalls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("periodicReconcile() error = %v, want nil", err)
+			}
+			if reconcileCalls != 1 {
+				t.Fatalf("reconcile calls = %d, want the resync to go ahead", reconcileCalls)
+			}
+			if fields, ok := alerts.find("strategy_tick_failed"); !ok || fields["err"] != tc.tickErr.Error() {
+				t.Fatalf("strategy_tick_failed alert = %v (ok %v), want err %q", fields, ok, tc.tickErr)
+			}
+		})
+	}
+}
+
 func TestLiveRunnerStopsOnFatalLocalErrorWithoutReconnectTrip(t *testing.T) {
 	asyncErrs := make(chan error, 16)
 
@@ -1281,7 +1967,7 @@ func TestLiveRunnerRecordTradeLedgerMakesTradeSkippableAcrossRestart(t *testing.
 		Symbol: "BTCUSDT",
 		Store:  st,
 	}
-	if err := runner.recordTradeLedger(trade); err != nil {
+	if err := runner.recordTradeLedger(trade, store.TradeLedgerEntry{}); err != nil {
 		t.Fatalf("recordTradeLedger() error = %v", err)
 	}
 
@@ -1302,74 +1988,217 @@ func TestLiveRunnerRecordTradeLedgerMakesTradeSkippableAcrossRestart(t *testing.
 	}
 }
 
-type executionReportPayload struct {
-	OrderID   int64
-	TradeID   int64
-	Side      string
-	Status    string
-	OrderQty  string
-	LastQty   string
-	LastPrice string
-	CumQty    string
-}
-
-func writeExecutionReport(conn *websocket.Conn, p executionReportPayload) error {
-	ts := time.Now().UTC().UnixMilli()
-	msg := map[string]any{
-		"e": "executionReport",
-		"E": ts,
-		"s": "BTCUSDT",
-		"i": p.OrderID,
-		"S": p.Side,
-		"x": "TRADE",
-		"X": p.Status,
-		"p": p.LastPrice,
-		"q": p.OrderQty,
-		"L": p.LastPrice,
-		"l": p.LastQty,
-		"z": p.CumQty,
-		"T": ts,
-		"t": p.TradeID,
-	}
-	return conn.WriteJSON(msg)
-}
-
-func readWSReqID(conn *websocket.Conn) (string, error) {
-	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
-	defer conn.SetReadDeadline(time.Time{})
-
-	_, data, err := conn.ReadMessage()
-	if err != nil {
-		return "", err
-	}
-	var req struct {
-		ID string `json:"id"`
-	}
-	if err := json.Unmarshal(data, &req); err != nil {
-		return "", err
-	}
-	if req.ID == "" {
-		return "", errors.New("ws request id is empty")
-	}
-	return req.ID, nil
+type trackedOrderSpy struct {
+	liveStrategySpy
+	orders map[string]core.Order
 }
 
-func writeWSResponse(conn *websocket.Conn, reqID string) error {
-	resp := map[string]any{
-		"id":     reqID,
-		"status": 200,
-		"result": map[string]any{},
-	}
-	return conn.WriteJSON(resp)
+func (s *trackedOrderSpy) TrackedOrder(orderID string) (core.Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ord, ok := s.orders[orderID]
+	return ord, ok
 }
 
-func writeJSON(w http.ResponseWriter, status int, v any) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	return json.NewEncoder(w).Encode(v)
-}
+func TestLiveRunnerLedgerDetailsRecordLevelOriginAndPnLDelta(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []map[string]any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
 
-func httpToWS(raw string) string {
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeExecutionReports(conn,
+			executionReportPayload{OrderID: 1, TradeID: 11, Side: "BUY", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1"},
+			executionReportPayload{OrderID: 2, TradeID: 12, Side: "SELL", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "110", CumQty: "1", Commission: "0.11", CommissionAsset: "USDT"},
+			executionReportPayload{OrderID: 3, TradeID: 13, Side: "BUY", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1"},
+		); err != nil {
+			recordAsyncErr(asyncErrs, err)
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	spy := &trackedOrderSpy{
+		liveStrategySpy: liveStrategySpy{stopAfterFill: 3},
+		orders: map[string]core.Order{
+			"1": {ID: "1", Side: core.Buy, GridIndex: -1, Origin: core.OriginBootstrap},
+			"2": {ID: "2", Side: core.Sell, GridIndex: 0, Origin: core.OriginCounter},
+		},
+	}
+	runner := LiveRunner{
+		Exchange:      client,
+		Strategy:      spy,
+		Symbol:        "BTCUSDT",
+		Store:         st,
+		LedgerDetails: true,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	var out bytes.Buffer
+	if err := st.ExportTradeLedger(&out, store.ExportJSON); err != nil {
+		t.Fatalf("ExportTradeLedger() error = %v", err)
+	}
+	var entries []store.TradeLedgerEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("ledger decode error = %v", err)
+	}
+	// The third fill stops the strategy before it is recorded.
+	if len(entries) != 2 {
+		t.Fatalf("ledger entries = %d, want 2: %s", len(entries), out.String())
+	}
+	buy, sell := entries[0], entries[1]
+	if buy.Level == nil || *buy.Level != -1 || buy.Origin != core.OriginBootstrap || buy.PnLDelta != "0" {
+		t.Fatalf("buy entry = %+v, want level -1 bootstrap with no PnL", buy)
+	}
+	if sell.Side != "SELL" || sell.Level == nil || *sell.Level != 0 || sell.Origin != core.OriginCounter {
+		t.Fatalf("sell entry = %+v, want SELL at level 0 from a counter", sell)
+	}
+	if sell.PnLDelta != "10" {
+		t.Fatalf("sell pnl_delta = %q, want 10", sell.PnLDelta)
+	}
+	if buy.Fee != "" || sell.Fee != "0.11" || sell.FeeAsset != "USDT" {
+		t.Fatalf("fees = %q/%q %q, want none on the buy and 0.11 USDT on the sell", buy.Fee, sell.Fee, sell.FeeAsset)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+type executionReportPayload struct {
+	OrderID   int64
+	TradeID   int64
+	Side      string
+	Status    string
+	OrderQty  string
+	LastQty   string
+	LastPrice string
+	CumQty    string
+	// TradeTime is the fill time; zero means now.
+	TradeTime time.Time
+	// Commission and CommissionAsset are left out of the report when empty.
+	Commission      string
+	CommissionAsset string
+}
+
+func writeExecutionReport(conn *websocket.Conn, p executionReportPayload) error {
+	return conn.WriteJSON(executionReportMessage(p))
+}
+
+func writeExecutionReports(conn *websocket.Conn, payloads ...executionReportPayload) error {
+	msgs := make([]map[string]any, 0, len(payloads))
+	for _, p := range payloads {
+		msgs = append(msgs, executionReportMessage(p))
+	}
+	return conn.WriteJSON(msgs)
+}
+
+func executionReportMessage(p executionReportPayload) map[string]any {
+	ts := time.Now().UTC().UnixMilli()
+	tradeTS := ts
+	if !p.TradeTime.IsZero() {
+		tradeTS = p.TradeTime.UnixMilli()
+	}
+	msg := map[string]any{
+		"e": "executionReport",
+		"E": ts,
+		"s": "BTCUSDT",
+		"i": p.OrderID,
+		"S": p.Side,
+		"x": "TRADE",
+		"X": p.Status,
+		"p": p.LastPrice,
+		"q": p.OrderQty,
+		"L": p.LastPrice,
+		"l": p.LastQty,
+		"z": p.CumQty,
+		"T": tradeTS,
+		"t": p.TradeID,
+	}
+	if p.Commission != "" {
+		msg["n"] = p.Commission
+		msg["N"] = p.CommissionAsset
+	}
+	return msg
+}
+
+func readWSReqID(conn *websocket.Conn) (string, error) {
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return "", err
+	}
+	if req.ID == "" {
+		return "", errors.New("ws request id is empty")
+	}
+	return req.ID, nil
+}
+
+func writeWSResponse(conn *websocket.Conn, reqID string) error {
+	resp := map[string]any{
+		"id":     reqID,
+		"status": 200,
+		"result": map[string]any{},
+	}
+	return conn.WriteJSON(resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+func httpToWS(raw string) string {
 	if strings.HasPrefix(raw, "https://") {
 		return "wss://" + strings.TrimPrefix(raw, "https://")
 	}
@@ -1399,3 +2228,774 @@ func assertNoAsyncErr(t *testing.T, ch <-chan error) {
 		}
 	}
 }
+
+func TestLiveRunnerMetricsAndStatusCarryInstanceLabels(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	runner := LiveRunner{
+		Symbol:     "BTCUSDT",
+		Mode:       "testnet",
+		InstanceID: "bot7",
+		Store:      st,
+	}
+	runner.initMetrics()
+	runner.persistRuntimeStatus("running", time.Now().UTC(), 0, time.Time{}, nil)
+	runner.Metrics.Inc("gridbot_trades_total")
+
+	var buf strings.Builder
+	if err := runner.Metrics.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	wantLabels := `{mode="testnet",symbol="BTCUSDT",instance_id="bot7"}`
+	for _, name := range []string{"gridbot_trades_total", "gridbot_runner_running"} {
+		if !strings.Contains(buf.String(), name+wantLabels) {
+			t.Fatalf("metrics missing %s%s, got:\n%s", name, wantLabels, buf.String())
+		}
+	}
+
+	status, ok, err := st.LoadRuntimeStatus()
+	if err != nil || !ok {
+		t.Fatalf("LoadRuntimeStatus() ok=%v err=%v", ok, err)
+	}
+	if status.InstanceID != "bot7" || status.Mode != "testnet" || status.Symbol != "BTCUSDT" {
+		t.Fatalf("runtime status labels = %s/%s/%s, want testnet/BTCUSDT/bot7", status.Mode, status.Symbol, status.InstanceID)
+	}
+}
+
+type gridStatusSpy struct {
+	liveStrategySpy
+	status strategy.GridStatus
+}
+
+func (s *gridStatusSpy) GridStatus() strategy.GridStatus {
+	return s.status
+}
+
+func TestLiveRunnerStateMetricsReportGridAndBreaker(t *testing.T) {
+	breaker := safety.NewBreaker(true, 1, 3, 3)
+	_ = breaker.RecordPlace(errors.New("rejected"))
+	runner := LiveRunner{
+		Strategy:   &gridStatusSpy{status: strategy.GridStatus{OpenOrders: 6, MinLevel: -4, MaxLevel: 2}},
+		Symbol:     "BTCUSDT",
+		Mode:       "live",
+		InstanceID: "bot3",
+		Breaker:    breaker,
+	}
+	runner.initMetrics()
+	runner.updateStateMetrics()
+
+	for name, want := range map[string]float64{
+		"gridbot_open_orders":                     6,
+		"gridbot_grid_min_level":                  -4,
+		"gridbot_grid_max_level":                  2,
+		"gridbot_circuit_breaker_place_state":     2,
+		"gridbot_circuit_breaker_cancel_state":    0,
+		"gridbot_circuit_breaker_reconnect_state": 0,
+	} {
+		if got, ok := runner.Metrics.Value(name); !ok || got != want {
+			t.Fatalf("%s = %v (set %v), want %v", name, got, ok, want)
+		}
+	}
+}
+
+func TestLiveRunOncePushesMetricsOnHeartbeat(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+	type pushReq struct {
+		method string
+		path   string
+		body   string
+	}
+	pushes := make(chan pushReq, 16)
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		pushes <- pushReq{method: r.Method, path: r.URL.Path, body: string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{
+				"symbol": "BTCUSDT",
+				"price":  "100",
+			})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{
+			CheckOrigin: func(*http.Request) bool { return true },
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	runner := LiveRunner{
+		Exchange:   client,
+		Strategy:   &liveStrategySpy{},
+		Symbol:     "BTCUSDT",
+		Mode:       "testnet",
+		InstanceID: "bot3",
+		Heartbeat:  50 * time.Millisecond,
+		Pusher:     metrics.NewPusher(gateway.URL, "gridbot", time.Second),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	seen := newSeenTracker(128, time.Hour)
+	reconnectAttempts := 0
+	disconnectStartedAt := time.Time{}
+	backoff := time.Second
+	_ = runner.runOnce(ctx, false, seen, &reconnectAttempts, &disconnectStartedAt, &backoff, time.Now().UTC())
+	runner.pushWG.Wait()
+
+	select {
+	case got := <-pushes:
+		if got.method != http.MethodPut {
+			t.Fatalf("push method = %s, want PUT", got.method)
+		}
+		wantPath := "/metrics/job/gridbot/mode/testnet/symbol/BTCUSDT/instance_id/bot3"
+		if got.path != wantPath {
+			t.Fatalf("push path = %s, want %s", got.path, wantPath)
+		}
+		if !strings.Contains(got.body, `instance_id="bot3"`) {
+			t.Fatalf("push body missing instance label, got:\n%s", got.body)
+		}
+	default:
+		t.Fatalf("no push received on heartbeat")
+	}
+
+	runner.deletePushedMetrics()
+	for {
+		select {
+		case got := <-pushes:
+			if got.method == http.MethodDelete {
+				assertNoAsyncErr(t, asyncErrs)
+				return
+			}
+		default:
+			t.Fatalf("no DELETE received for push group")
+		}
+	}
+}
+
+func TestLivePushMetricsDoesNotBlockOnSlowGateway(t *testing.T) {
+	release := make(chan struct{})
+	var puts atomic.Int32
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			puts.Add(1)
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	runner := LiveRunner{
+		Symbol:     "BTCUSDT",
+		Mode:       "live",
+		InstanceID: "bot1",
+		Pusher:     metrics.NewPusher(gateway.URL, "gridbot", 5*time.Second),
+	}
+	runner.initMetrics()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		runner.pushMetrics(context.Background())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("pushMetrics blocked for %s on a stalled gateway", elapsed)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for puts.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	runner.pushWG.Wait()
+	if got := puts.Load(); got != 1 {
+		t.Fatalf("gateway PUTs = %d, want 1 while the first push was in flight", got)
+	}
+}
+
+func TestLiveRunnerMidTickPriceUsesBookTickerMidpoint(t *testing.T) {
+	asyncErrs := make(chan error, 4)
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/bookTicker":
+			_ = writeJSON(w, http.StatusOK, map[string]string{
+				"symbol":   "BTCUSDT",
+				"bidPrice": "99.5",
+				"bidQty":   "1",
+				"askPrice": "100.7",
+				"askQty":   "2",
+			})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "BTCUSDT",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+
+	runner := LiveRunner{
+		Exchange:        client,
+		Symbol:          "BTCUSDT",
+		TickPriceSource: TickPriceMid,
+	}
+	price, err := runner.tickPrice(context.Background())
+	if err != nil {
+		t.Fatalf("tickPrice() error = %v", err)
+	}
+	if want := decimal.RequireFromString("100.1"); !price.Equal(want) {
+		t.Fatalf("mid price = %s, want %s", price, want)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestLiveRunnerSuspectTickerPriceBlocksBootstrap(t *testing.T) {
+	asyncErrs := make(chan error, 4)
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "0.0001"})
+		case "/api/v3/ticker/bookTicker":
+			_ = writeJSON(w, http.StatusOK, map[string]string{
+				"symbol":   "BTCUSDT",
+				"bidPrice": "99.9",
+				"bidQty":   "1",
+				"askPrice": "100.1",
+				"askQty":   "1",
+			})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "BTCUSDT",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+
+	spy := &liveStrategySpy{}
+	alerts := &runnerAlertRecorder{}
+	runner := LiveRunner{
+		Exchange:               client,
+		Strategy:               spy,
+		Symbol:                 "BTCUSDT",
+		Alerts:                 alerts,
+		TickerMaxDivergencePct: decimal.NewFromInt(10),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	_ = runner.Run(ctx)
+
+	spy.mu.Lock()
+	initCalls := spy.initCalls
+	spy.mu.Unlock()
+	if initCalls != 0 {
+		t.Fatalf("Init calls = %d, want 0 on a suspect ticker price", initCalls)
+	}
+	fields, ok := alerts.find("ticker_price_suspect")
+	if !ok {
+		t.Fatalf("expected ticker_price_suspect alert")
+	}
+	if fields["ticker"] != "0.0001" || fields["book_mid"] != "100" {
+		t.Fatalf("ticker_price_suspect fields = %v", fields)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+type rulesAwareSpy struct {
+	liveStrategySpy
+	rules core.Rules
+}
+
+func (s *rulesAwareSpy) Rules() core.Rules         { return s.rules }
+func (s *rulesAwareSpy) SetRules(rules core.Rules) { s.rules = rules }
+
+func TestLiveRunnerRefreshRulesAppliesChangedTickSize(t *testing.T) {
+	var calls atomic.Int32
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/exchangeInfo" {
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		tick := "0.01"
+		if calls.Add(1) > 1 {
+			tick = "0.1"
+		}
+		_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+			"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT",
+			"filters": []map[string]string{
+				{"filterType": "PRICE_FILTER", "tickSize": tick},
+				{"filterType": "LOT_SIZE", "minQty": "0.001", "stepSize": "0.001"},
+			},
+		}}})
+	}))
+	defer rest.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "BTCUSDT",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+
+	initial, err := client.GetRules(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetRules() error = %v", err)
+	}
+	strat := &rulesAwareSpy{rules: initial}
+	alerts := &runnerAlertRecorder{}
+	runner := LiveRunner{Exchange: client, Strategy: strat, Symbol: "BTCUSDT", Alerts: alerts}
+
+	runner.refreshRules(context.Background())
+	if !strat.rules.PriceTick.Equal(decimal.RequireFromString("0.1")) {
+		t.Fatalf("strategy price tick = %s, want 0.1", strat.rules.PriceTick)
+	}
+	fields, ok := alerts.find("symbol_rules_changed")
+	if !ok {
+		t.Fatalf("missing symbol_rules_changed alert, got %v", alerts.events)
+	}
+	if fields["old_price_tick"] != "0.01" || fields["new_price_tick"] != "0.1" {
+		t.Fatalf("alert fields = %v, want tick 0.01 -> 0.1", fields)
+	}
+
+	runner.refreshRules(context.Background())
+	if len(alerts.events) != 1 {
+		t.Fatalf("alerts after unchanged refresh = %v, want 1", alerts.events)
+	}
+}
+
+func TestLiveRunnerRefusesStartWithUnknownPrefixedOrders(t *testing.T) {
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/openOrders" {
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		_ = writeJSON(w, http.StatusOK, []map[string]any{
+			{"symbol": "BTCUSDT", "orderId": 7, "clientOrderId": "test-7", "price": "90", "origQty": "1", "executedQty": "0", "status": "NEW", "type": "LIMIT", "side": "BUY"},
+			{"symbol": "BTCUSDT", "orderId": 8, "clientOrderId": "manual-8", "price": "95", "origQty": "1", "executedQty": "0", "status": "NEW", "type": "LIMIT", "side": "BUY"},
+		})
+	}))
+	defer rest.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	alerts := &runnerAlertRecorder{}
+	strat := &liveStrategySpy{}
+	runner := LiveRunner{
+		Exchange:               client,
+		Strategy:               strat,
+		Symbol:                 "BTCUSDT",
+		Store:                  st,
+		Alerts:                 alerts,
+		DuplicateInstanceCheck: true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = runner.Run(ctx)
+	if !errors.Is(err, ErrManualIntervention) || !strings.Contains(err.Error(), "possible_duplicate_instance") {
+		t.Fatalf("Run() error = %v, want possible_duplicate_instance manual intervention", err)
+	}
+	fields, ok := alerts.find("possible_duplicate_instance")
+	if !ok || fields["unknown_orders"] != "1" || fields["first_order_ids"] != "7" {
+		t.Fatalf("possible_duplicate_instance alert = %v (ok %v), want order 7 only", fields, ok)
+	}
+	if initCalls, reconcileCalls, _ := strat.stats(); initCalls != 0 || reconcileCalls != 0 {
+		t.Fatalf("strategy touched before refusal: init=%d reconcile=%d", initCalls, reconcileCalls)
+	}
+
+	if err := st.SaveOpenOrders([]core.Order{{ID: "7", Symbol: "BTCUSDT", Side: core.Buy}}); err != nil {
+		t.Fatalf("SaveOpenOrders() error = %v", err)
+	}
+	if err := runner.checkDuplicateInstance(ctx); err != nil {
+		t.Fatalf("checkDuplicateInstance() with known order error = %v", err)
+	}
+}
+
+func TestLiveRunnerRetriesSlowTickerWithoutReconnect(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+	var tickerCalls atomic.Int32
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			if tickerCalls.Add(1) == 1 {
+				select {
+				case <-time.After(2 * time.Second):
+				case <-r.Context().Done():
+				}
+				return
+			}
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeExecutionReport(conn, executionReportPayload{
+			OrderID: 81001, TradeID: 91001, Side: "BUY", Status: "FILLED",
+			OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1",
+		}); err != nil {
+			recordAsyncErr(asyncErrs, err)
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    1,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	reg := metrics.NewRegistry(metrics.Labels{})
+	runner := LiveRunner{
+		Exchange:           client,
+		Strategy:           &liveStrategySpy{stopAfterFill: 1},
+		Symbol:             "BTCUSDT",
+		Store:              st,
+		Metrics:            reg,
+		RESTTimeoutRetries: 1,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	if got := tickerCalls.Load(); got != 2 {
+		t.Fatalf("ticker calls = %d, want 2 (timeout then success)", got)
+	}
+	if v, _ := reg.Value("gridbot_rest_timeout_retries_total"); v != 1 {
+		t.Fatalf("gridbot_rest_timeout_retries_total = %v, want 1", v)
+	}
+	if v, _ := reg.Value("gridbot_reconnects_total"); v != 0 {
+		t.Fatalf("gridbot_reconnects_total = %v, want 0", v)
+	}
+	status, ok, err := st.LoadRuntimeStatus()
+	if err != nil || !ok {
+		t.Fatalf("LoadRuntimeStatus() = %v, %v", ok, err)
+	}
+	if status.ReconnectAttempts != 0 {
+		t.Fatalf("status.reconnect_attempts = %d, want 0", status.ReconnectAttempts)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestTradeFromOrderUsesCumulativeQuoteAverage(t *testing.T) {
+	placed := core.Order{ID: "7", Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1)}
+	status := binance.OrderQuery{
+		Order:              core.Order{ID: "7", Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Status: core.OrderFilled},
+		ExecutedQty:        decimal.NewFromInt(1),
+		CumulativeQuoteQty: decimal.RequireFromString("100.6"),
+		UpdateTime:         time.Unix(100, 0).UTC(),
+	}
+
+	// 0.4 @ 100 + 0.6 @ 101 = 100.6 quote for 1 base.
+	trade := tradeFromOrder(status, placed, orderFill{})
+	if !trade.Qty.Equal(decimal.NewFromInt(1)) || !trade.Price.Equal(decimal.RequireFromString("100.6")) {
+		t.Fatalf("reconciled trade = %s @ %s, want 1 @ 100.6", trade.Qty, trade.Price)
+	}
+
+	// The 0.4 partial already arrived on the stream: only the 0.6 residual is
+	// owed, at its own average, so stream + reconcile sum to the exchange totals.
+	r := &LiveRunner{}
+	r.noteStreamedFill(core.Trade{
+		OrderID: "7", Side: core.Sell, Status: core.OrderPartiallyFilled,
+		Price: decimal.NewFromInt(100), Qty: decimal.RequireFromString("0.4"),
+		CumQty: decimal.RequireFromString("0.4"), CumQuote: decimal.NewFromInt(40),
+	})
+	trade = tradeFromOrder(status, placed, r.streamedFills["7"])
+	if !trade.Qty.Equal(decimal.RequireFromString("0.6")) || !trade.Price.Equal(decimal.NewFromInt(101)) {
+		t.Fatalf("residual trade = %s @ %s, want 0.6 @ 101", trade.Qty, trade.Price)
+	}
+	streamed := decimal.NewFromInt(40)
+	if total := streamed.Add(trade.Qty.Mul(trade.Price)); !total.Equal(status.CumulativeQuoteQty) {
+		t.Fatalf("stream + reconcile quote = %s, want %s", total, status.CumulativeQuoteQty)
+	}
+
+	// After a restart the stream progress is gone; the persisted remaining qty
+	// still marks 0.4 as applied at the order's limit price.
+	remaining := placed
+	remaining.Qty = decimal.RequireFromString("0.6")
+	trade = tradeFromOrder(status, remaining, orderFill{})
+	if !trade.Qty.Equal(decimal.RequireFromString("0.6")) || !trade.Price.Equal(decimal.NewFromInt(101)) {
+		t.Fatalf("restart residual trade = %s @ %s, want 0.6 @ 101", trade.Qty, trade.Price)
+	}
+
+	// Canceled after the streamed 0.4 with no further execution: nothing is
+	// owed, so no reconcile trade may re-apply the 0.4 under a new id.
+	canceled := binance.OrderQuery{
+		Order:              core.Order{ID: "7", Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Status: core.OrderCanceled},
+		ExecutedQty:        decimal.RequireFromString("0.4"),
+		CumulativeQuoteQty: decimal.NewFromInt(40),
+		UpdateTime:         time.Unix(200, 0).UTC(),
+	}
+	if trade = tradeFromOrder(canceled, placed, r.streamedFills["7"]); trade.OrderID != "" {
+		t.Fatalf("canceled order already applied on stream returned trade %s @ %s, want none", trade.Qty, trade.Price)
+	}
+}
+
+func TestLiveRunnerRetriesSlowWSDialWithoutReconnect(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+	var dials atomic.Int32
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dials.Add(1) == 1 {
+			select {
+			case <-time.After(3 * time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeExecutionReport(conn, executionReportPayload{
+			OrderID: 82001, TradeID: 92001, Side: "BUY", Status: "FILLED",
+			OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1",
+		}); err != nil {
+			recordAsyncErr(asyncErrs, err)
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		WSDialTimeoutSec:  1,
+		WSDialRetries:     1,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	reg := metrics.NewRegistry(metrics.Labels{})
+	runner := LiveRunner{
+		Exchange: client,
+		Strategy: &liveStrategySpy{stopAfterFill: 1},
+		Symbol:   "BTCUSDT",
+		Store:    st,
+		Metrics:  reg,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	if got := dials.Load(); got != 2 {
+		t.Fatalf("ws dials = %d, want 2 (timeout then success)", got)
+	}
+	if v, _ := reg.Value("gridbot_reconnects_total"); v != 0 {
+		t.Fatalf("gridbot_reconnects_total = %v, want 0", v)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestLiveRunnerAlertsPossibleTwinOnSustainedPrefixSurplus(t *testing.T) {
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+	})
+	defer client.Close()
+	alerts := &runnerAlertRecorder{}
+	runner := LiveRunner{
+		Exchange:    client,
+		Strategy:    &gridStatusSpy{status: strategy.GridStatus{OpenOrders: 2}},
+		Symbol:      "BTCUSDT",
+		Alerts:      alerts,
+		TwinSurplus: 3,
+		TwinRounds:  3,
+	}
+	open := func(prefixed int) []core.Order {
+		orders := []core.Order{{ID: "99", ClientID: "manual-99"}}
+		for i := 0; i < prefixed; i++ {
+			orders = append(orders, core.Order{ID: strconv.Itoa(i), ClientID: "test-" + strconv.Itoa(i)})
+		}
+		return orders
+	}
+	twinAlerts := func() int {
+		n := 0
+		for _, event := range alerts.events {
+			if event == "possible_twin_instance" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// A surplus that clears before it has lasted three rounds is ignored.
+	runner.checkTwinInstance(open(6))
+	runner.checkTwinInstance(open(6))
+	runner.checkTwinInstance(open(2))
+	if n := twinAlerts(); n != 0 {
+		t.Fatalf("possible_twin_instance alerts after transient surplus = %d, want 0", n)
+	}
+
+	for i := 0; i < 4; i++ {
+		runner.checkTwinInstance(open(6))
+	}
+	if n := twinAlerts(); n != 1 {
+		t.Fatalf("possible_twin_instance alerts after sustained surplus = %d, want 1", n)
+	}
+	fields, _ := alerts.find("possible_twin_instance")
+	if fields["prefixed_orders"] != "6" || fields["tracked_orders"] != "2" || fields["surplus"] != "4" {
+		t.Fatalf("possible_twin_instance fields = %v", fields)
+	}
+}
+
+func TestLiveRunnerReconnectWaitStaysWithinJitterBounds(t *testing.T) {
+	r := &LiveRunner{}
+	for _, backoff := range []time.Duration{0, time.Second, 8 * time.Second, maxReconnectBackoff} {
+		var lo, hi time.Duration = backoff, 0
+		for i := 0; i < 2000; i++ {
+			wait := r.reconnectWait(backoff)
+			if wait < 0 || wait > backoff || wait > maxReconnectBackoff {
+				t.Fatalf("reconnectWait(%s) = %s, want within [0, %s]", backoff, wait, backoff)
+			}
+			lo, hi = min(lo, wait), max(hi, wait)
+		}
+		// Full jitter should spread over most of the range, not sit at backoff.
+		if backoff > 0 && (lo > backoff/4 || hi < backoff*3/4) {
+			t.Fatalf("reconnectWait(%s) spread = [%s, %s], want jittered across the range", backoff, lo, hi)
+		}
+	}
+}
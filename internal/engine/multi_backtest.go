@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolBacktest is one leg of a multi-symbol backtest. Each leg carries its
+// own SimExchange so balances and resting orders never mix across symbols.
+type SymbolBacktest struct {
+	Symbol string
+	Runner BacktestRunner
+}
+
+type SymbolBacktestResult struct {
+	Symbol string
+	Result BacktestResult
+}
+
+type MultiBacktestResult struct {
+	Symbols []SymbolBacktestResult
+
+	Trades           int
+	StartEquityQuote decimal.Decimal
+	EndEquityQuote   decimal.Decimal
+	ProfitQuote      decimal.Decimal
+	EquityReturnPct  decimal.Decimal
+	FeesPaidQuote    decimal.Decimal
+	WorstDrawdownPct decimal.Decimal
+	Profitable       int
+}
+
+// RunMultiBacktest runs each leg in order and sums equity across legs for the
+// aggregate. The first failing leg aborts the run.
+func RunMultiBacktest(ctx context.Context, legs []SymbolBacktest) (MultiBacktestResult, error) {
+	agg := MultiBacktestResult{
+		StartEquityQuote: decimal.Zero,
+		EndEquityQuote:   decimal.Zero,
+		ProfitQuote:      decimal.Zero,
+		EquityReturnPct:  decimal.Zero,
+		FeesPaidQuote:    decimal.Zero,
+		WorstDrawdownPct: decimal.Zero,
+	}
+	for _, leg := range legs {
+		runner := leg.Runner
+		res, err := runner.Run(ctx)
+		if err != nil {
+			return agg, fmt.Errorf("backtest %s: %w", leg.Symbol, err)
+		}
+		agg.Symbols = append(agg.Symbols, SymbolBacktestResult{Symbol: leg.Symbol, Result: res})
+		agg.Trades += res.Trades
+		agg.StartEquityQuote = agg.StartEquityQuote.Add(res.StartEquityQuote)
+		agg.EndEquityQuote = agg.EndEquityQuote.Add(res.EndEquityQuote)
+		agg.FeesPaidQuote = agg.FeesPaidQuote.Add(res.FeesPaidQuote)
+		if res.MaxDrawdownPct.Cmp(agg.WorstDrawdownPct) > 0 {
+			agg.WorstDrawdownPct = res.MaxDrawdownPct
+		}
+		if res.ProfitQuote.Cmp(decimal.Zero) > 0 {
+			agg.Profitable++
+		}
+	}
+	agg.ProfitQuote = agg.EndEquityQuote.Sub(agg.StartEquityQuote)
+	if agg.StartEquityQuote.Cmp(decimal.Zero) > 0 {
+		agg.EquityReturnPct = agg.ProfitQuote.Div(agg.StartEquityQuote).Mul(decimal.NewFromInt(100))
+	}
+	return agg, nil
+}
+
+func (r MultiBacktestResult) WriteSummary(w io.Writer, instanceID string) error {
+	for _, leg := range r.Symbols {
+		res := leg.Result
+		if _, err := fmt.Fprintf(
+			w,
+			"symbol_summary instance=%s symbol=%s trades=%d equity_return_pct=%s profit_quote=%s max_drawdown_pct=%s start_equity_quote=%s end_equity_quote=%s fees_paid_quote=%s final_base=%s final_quote=%s skipped_ticks=%d\n",
+			instanceID,
+			leg.Symbol,
+			res.Trades,
+			res.EquityReturnPct.StringFixed(4),
+			res.ProfitQuote.String(),
+			res.MaxDrawdownPct.StringFixed(4),
+			res.StartEquityQuote.String(),
+			res.EndEquityQuote.String(),
+			res.FeesPaidQuote.String(),
+			res.FinalBalance.Base.String(),
+			res.FinalBalance.Quote.String(),
+			res.SkippedTicks,
+		); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(
+		w,
+		"aggregate instance=%s symbols=%d profitable_symbols=%d trades=%d equity_return_pct=%s profit_quote=%s worst_max_drawdown_pct=%s start_equity_quote=%s end_equity_quote=%s fees_paid_quote=%s\n",
+		instanceID,
+		len(r.Symbols),
+		r.Profitable,
+		r.Trades,
+		r.EquityReturnPct.StringFixed(4),
+		r.ProfitQuote.String(),
+		r.WorstDrawdownPct.StringFixed(4),
+		r.StartEquityQuote.String(),
+		r.EndEquityQuote.String(),
+		r.FeesPaidQuote.String(),
+	)
+	return err
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/backtest"
+	"grid-trading/internal/core"
+)
+
+func TestRunMultiBacktestReportsPerSymbolAndAggregate(t *testing.T) {
+	t0 := time.Unix(50, 0).UTC()
+	leg := func(symbol string, prices ...int64) SymbolBacktest {
+		feed := &multiTickFeed{}
+		for i, p := range prices {
+			feed.ticks = append(feed.ticks, backtest.Tick{Time: t0.Add(time.Duration(i) * time.Minute), Price: decimal.NewFromInt(p)})
+		}
+		return SymbolBacktest{
+			Symbol: symbol,
+			Runner: BacktestRunner{
+				Exchange: backtest.NewSimExchange(
+					symbol,
+					core.Balance{Base: decimal.NewFromInt(1), Quote: decimal.Zero},
+					core.Rules{},
+				),
+				Feed:     feed,
+				Strategy: noopStrategy{},
+			},
+		}
+	}
+
+	res, err := RunMultiBacktest(context.Background(), []SymbolBacktest{
+		leg("BTCUSDT", 100, 110, 120),
+		leg("ETHUSDT", 100, 90, 80),
+	})
+	if err != nil {
+		t.Fatalf("RunMultiBacktest() error = %v", err)
+	}
+	if len(res.Symbols) != 2 {
+		t.Fatalf("per-symbol results = %d, want 2", len(res.Symbols))
+	}
+	if res.Symbols[0].Symbol != "BTCUSDT" || !res.Symbols[0].Result.ProfitQuote.Equal(decimal.NewFromInt(20)) {
+		t.Fatalf("BTCUSDT result = %s profit %s, want profit 20", res.Symbols[0].Symbol, res.Symbols[0].Result.ProfitQuote)
+	}
+	if res.Symbols[1].Symbol != "ETHUSDT" || !res.Symbols[1].Result.ProfitQuote.Equal(decimal.NewFromInt(-20)) {
+		t.Fatalf("ETHUSDT result = %s profit %s, want profit -20", res.Symbols[1].Symbol, res.Symbols[1].Result.ProfitQuote)
+	}
+	if !res.StartEquityQuote.Equal(decimal.NewFromInt(200)) || !res.EndEquityQuote.Equal(decimal.NewFromInt(200)) {
+		t.Fatalf("aggregate equity = %s -> %s, want 200 -> 200", res.StartEquityQuote, res.EndEquityQuote)
+	}
+	if res.Profitable != 1 || !res.WorstDrawdownPct.Equal(decimal.NewFromInt(20)) {
+		t.Fatalf("profitable = %d worst drawdown = %s, want 1 and 20", res.Profitable, res.WorstDrawdownPct)
+	}
+
+	var buf bytes.Buffer
+	if err := res.WriteSummary(&buf, "bot1"); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("summary lines = %d, want 3:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "symbol_summary instance=bot1 symbol=BTCUSDT ") ||
+		!strings.HasPrefix(lines[1], "symbol_summary instance=bot1 symbol=ETHUSDT ") {
+		t.Fatalf("unexpected per-symbol lines:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[2], "aggregate instance=bot1 symbols=2 profitable_symbols=1 ") ||
+		!strings.Contains(lines[2], "profit_quote=0") {
+		t.Fatalf("unexpected aggregate line: %s", lines[2])
+	}
+}
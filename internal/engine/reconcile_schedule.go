@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// reconcileSchedule drives the periodic reconcile interval. With min/max
+// bounds set it adapts: a reconnect drops straight to min, a reconcile
+// window that saw fills halves the interval, and a quiet window doubles it.
+type reconcileSchedule struct {
+	mu       sync.Mutex
+	min      time.Duration
+	max      time.Duration
+	current  time.Duration
+	activity bool
+}
+
+func newReconcileSchedule(base, min, max time.Duration) *reconcileSchedule {
+	s := &reconcileSchedule{current: base}
+	if base <= 0 || min <= 0 || max < min {
+		return s
+	}
+	s.min = min
+	s.max = max
+	s.current = s.clamp(base)
+	return s
+}
+
+func (s *reconcileSchedule) adaptive() bool {
+	return s.min > 0 && s.max >= s.min
+}
+
+func (s *reconcileSchedule) clamp(d time.Duration) time.Duration {
+	if d < s.min {
+		return s.min
+	}
+	if d > s.max {
+		return s.max
+	}
+	return d
+}
+
+func (s *reconcileSchedule) Interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+func (s *reconcileSchedule) NoteFill() {
+	s.mu.Lock()
+	s.activity = true
+	s.mu.Unlock()
+}
+
+func (s *reconcileSchedule) NoteReconnect() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.adaptive() {
+		s.current = s.min
+		s.activity = false
+	}
+	return s.current
+}
+
+// Advance is called after each periodic reconcile and returns the interval
+// until the next one.
+func (s *reconcileSchedule) Advance() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.adaptive() {
+		return s.current
+	}
+	if s.activity {
+		s.current = s.clamp(s.current / 2)
+	} else {
+		s.current = s.clamp(s.current * 2)
+	}
+	s.activity = false
+	return s.current
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcileScheduleAdaptsToReconnectsFillsAndQuiet(t *testing.T) {
+	runner := LiveRunner{
+		Reconcile:    60 * time.Second,
+		ReconcileMin: 15 * time.Second,
+		ReconcileMax: 240 * time.Second,
+	}
+	sched := runner.schedule()
+	if got := sched.Interval(); got != 60*time.Second {
+		t.Fatalf("initial interval = %s, want 60s", got)
+	}
+
+	if got := sched.NoteReconnect(); got != 15*time.Second {
+		t.Fatalf("interval after reconnect = %s, want 15s", got)
+	}
+
+	for _, want := range []time.Duration{30 * time.Second, 60 * time.Second, 120 * time.Second, 240 * time.Second, 240 * time.Second} {
+		if got := sched.Advance(); got != want {
+			t.Fatalf("quiet interval = %s, want %s", got, want)
+		}
+	}
+
+	sched.NoteFill()
+	if got := sched.Advance(); got != 120*time.Second {
+		t.Fatalf("interval after fills = %s, want 120s", got)
+	}
+	if runner.schedule() != sched {
+		t.Fatalf("schedule should persist across runOnce calls")
+	}
+}
+
+func TestReconcileScheduleFixedWithoutBounds(t *testing.T) {
+	sched := newReconcileSchedule(60*time.Second, 0, 0)
+	sched.NoteFill()
+	if got := sched.Advance(); got != 60*time.Second {
+		t.Fatalf("fixed interval after fill = %s, want 60s", got)
+	}
+	if got := sched.NoteReconnect(); got != 60*time.Second {
+		t.Fatalf("fixed interval after reconnect = %s, want 60s", got)
+	}
+}
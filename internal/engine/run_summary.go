@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+	"grid-trading/internal/store"
+)
+
+// runStats accumulates fills applied during one Run for the exit summary.
+// Realized PnL pairs sells with this run's buys FIFO, the same way the
+// store's report does.
+type runStats struct {
+	mu        sync.Mutex
+	trades    int
+	buys      int
+	sells     int
+	buyQty    decimal.Decimal
+	sellQty   decimal.Decimal
+	realized  decimal.Decimal
+	book      store.FIFOBook
+	lastPrice decimal.Decimal
+}
+
+func newRunStats() *runStats {
+	return &runStats{
+		buyQty:    decimal.Zero,
+		sellQty:   decimal.Zero,
+		realized:  decimal.Zero,
+		lastPrice: decimal.Zero,
+	}
+}
+
+func (s *runStats) Record(trade core.Trade) {
+	if s == nil || trade.Qty.Cmp(decimal.Zero) <= 0 || trade.Price.Cmp(decimal.Zero) <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades++
+	switch trade.Side {
+	case core.Buy:
+		s.buys++
+		s.buyQty = s.buyQty.Add(trade.Qty)
+	case core.Sell:
+		s.sells++
+		s.sellQty = s.sellQty.Add(trade.Qty)
+	}
+	realized, _ := s.book.Apply(trade)
+	s.realized = s.realized.Add(realized)
+	s.lastPrice = trade.Price
+}
+
+func (s *runStats) RealizedPnL() decimal.Decimal {
+	if s == nil {
+		return decimal.Zero
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.realized
+}
+
+func (s *runStats) fields() map[string]string {
+	pnl := s.RealizedPnL()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return map[string]string{
+		"trades":       strconv.Itoa(s.trades),
+		"buys":         strconv.Itoa(s.buys),
+		"sells":        strconv.Itoa(s.sells),
+		"buy_qty":      s.buyQty.String(),
+		"sell_qty":     s.sellQty.String(),
+		"realized_pnl": pnl.String(),
+	}
+}
+
+// usdQuoteAssets are quoted 1:1 to USD when reporting in USD.
+var usdQuoteAssets = map[string]bool{"USDT": true, "USDC": true, "FDUSD": true, "TUSD": true, "BUSD": true, "USD": true}
+
+// addUSDSummary converts realized PnL and, with balances, equity at the
+// current price into USD. A failed rate lookup is reported as usd_rate_err.
+func (r *LiveRunner) addUSDSummary(ctx context.Context, summary map[string]string, bal core.Balance, balOK bool) {
+	rate, err := r.quoteUSDRate(ctx)
+	if err != nil {
+		summary["usd_rate_err"] = err.Error()
+		return
+	}
+	summary["quote_usd_rate"] = rate.String()
+	summary["realized_pnl_usd"] = r.stats.RealizedPnL().Mul(rate).StringFixed(2)
+	if !balOK {
+		return
+	}
+	price, err := r.Exchange.TickerPrice(ctx, r.Symbol)
+	if err != nil {
+		summary["usd_rate_err"] = err.Error()
+		return
+	}
+	equity := bal.Quote.Add(bal.Base.Mul(price))
+	summary["equity_usd"] = equity.Mul(rate).StringFixed(2)
+}
+
+func (r *LiveRunner) quoteUSDRate(ctx context.Context) (decimal.Decimal, error) {
+	symbol := r.USDRateSymbol
+	if symbol == "" {
+		quote, err := r.Exchange.QuoteAsset(ctx, r.Symbol)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		if usdQuoteAssets[quote] {
+			return decimal.NewFromInt(1), nil
+		}
+		symbol = quote + "USDT"
+	}
+	rate, err := r.Exchange.TickerPrice(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s price: %w", symbol, err)
+	}
+	if rate.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero, fmt.Errorf("%s price %s is not positive", symbol, rate)
+	}
+	return rate, nil
+}
+
+// buildRunSummary snapshots the run's fills plus final balances and the
+// orders still resting on the exchange. Exchange lookups that fail are
+// reported in the summary instead of blocking shutdown.
+func (r *LiveRunner) buildRunSummary(startedAt time.Time, runErr error) map[string]string {
+	if r.stats == nil {
+		r.stats = newRunStats()
+	}
+	summary := r.stats.fields()
+	summary["symbol"] = r.Symbol
+	summary["started_at"] = startedAt.Format(time.RFC3339)
+	summary["duration"] = time.Since(startedAt).Round(time.Second).String()
+	reason := "clean_exit"
+	if runErr != nil {
+		reason = runErr.Error()
+	}
+	summary["reason"] = reason
+	if r.Exchange == nil {
+		return summary
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bal, balErr := r.Exchange.Balances(ctx)
+	if balErr != nil {
+		summary["balance_err"] = balErr.Error()
+	} else {
+		summary["final_base"] = bal.Base.String()
+		summary["final_quote"] = bal.Quote.String()
+	}
+	if r.ReportInUSD {
+		r.addUSDSummary(ctx, summary, bal, balErr == nil)
+	}
+	if open, err := r.Exchange.OpenOrders(ctx, r.Symbol); err != nil {
+		summary["open_orders_err"] = err.Error()
+	} else {
+		buys, sells := 0, 0
+		for _, ord := range open {
+			if ord.Side == core.Buy {
+				buys++
+			} else {
+				sells++
+			}
+		}
+		summary["remaining_buy_orders"] = strconv.Itoa(buys)
+		summary["remaining_sell_orders"] = strconv.Itoa(sells)
+	}
+	return summary
+}
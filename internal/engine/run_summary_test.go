@@ -0,0 +1,339 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+	"grid-trading/internal/exchange/binance"
+	"grid-trading/internal/store"
+)
+
+type runnerAlertRecorder struct {
+	mu     sync.Mutex
+	events []string
+	fields []map[string]string
+}
+
+func (a *runnerAlertRecorder) Important(event string, fields map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, event)
+	a.fields = append(a.fields, fields)
+}
+
+func (a *runnerAlertRecorder) find(event string) (map[string]string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, ev := range a.events {
+		if ev == event {
+			return a.fields[i], true
+		}
+	}
+	return nil, false
+}
+
+func TestLiveRunnerRunSummaryAfterStop(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []map[string]any{{
+				"symbol": "BTCUSDT", "orderId": 9, "clientOrderId": "test-9", "price": "120",
+				"origQty": "1", "executedQty": "0", "status": "NEW", "type": "LIMIT", "side": "SELL",
+			}})
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "BTC", "free": "0.5", "locked": "1"},
+				{"asset": "USDT", "free": "1010", "locked": "0"},
+			}})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeExecutionReports(conn,
+			executionReportPayload{OrderID: 1, TradeID: 11, Side: "BUY", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "100", CumQty: "1"},
+			executionReportPayload{OrderID: 2, TradeID: 12, Side: "SELL", Status: "FILLED", OrderQty: "1", LastQty: "1", LastPrice: "110", CumQty: "1"},
+		); err != nil {
+			recordAsyncErr(asyncErrs, err)
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	alerts := &runnerAlertRecorder{}
+	runner := LiveRunner{
+		Exchange:   client,
+		Strategy:   &liveStrategySpy{stopAfterFill: 2},
+		Symbol:     "BTCUSDT",
+		Store:      st,
+		Alerts:     alerts,
+		RunSummary: true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+
+	summary, ok := alerts.find("run_summary")
+	if !ok {
+		t.Fatalf("missing run_summary alert, got %v", alerts.events)
+	}
+	want := map[string]string{
+		"trades":                "2",
+		"buys":                  "1",
+		"sells":                 "1",
+		"realized_pnl":          "10",
+		"final_base":            "1.5",
+		"final_quote":           "1010",
+		"remaining_sell_orders": "1",
+		"reason":                "clean_exit",
+	}
+	for k, v := range want {
+		if summary[k] != v {
+			t.Fatalf("run_summary[%s] = %q, want %q (summary %v)", k, summary[k], v, summary)
+		}
+	}
+
+	status, ok, err := st.LoadRuntimeStatus()
+	if err != nil || !ok {
+		t.Fatalf("LoadRuntimeStatus() = %v, %v", ok, err)
+	}
+	if status.State != "stopped" || status.RunSummary["realized_pnl"] != "10" {
+		t.Fatalf("status = %s summary %v, want stopped with realized_pnl 10", status.State, status.RunSummary)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestLiveRunnerStopsCleanlyAtMaxRunTime(t *testing.T) {
+	asyncErrs := make(chan error, 16)
+
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": "BTCUSDT", "price": "100"})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []any{})
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "BTCUSDT", "baseAsset": "BTC", "quoteAsset": "USDT", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "BTC", "free": "1", "locked": "0"},
+				{"asset": "USDT", "free": "1000", "locked": "0"},
+			}})
+		default:
+			recordAsyncErr(asyncErrs, fmt.Errorf("unexpected REST path: %s", r.URL.Path))
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		defer conn.Close()
+		reqID, err := readWSReqID(conn)
+		if err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		if err := writeWSResponse(conn, reqID); err != nil {
+			recordAsyncErr(asyncErrs, err)
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer ws.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:            "k",
+		APISecret:         "s",
+		RestBaseURL:       rest.URL,
+		WSBaseURL:         httpToWS(ws.URL),
+		Symbol:            "BTCUSDT",
+		ClientOrderPrefix: "test",
+		UserStreamAuth:    "signature",
+		HTTPTimeoutSec:    3,
+	})
+	defer client.Close()
+
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	alerts := &runnerAlertRecorder{}
+	runner := LiveRunner{
+		Exchange:   client,
+		Strategy:   &liveStrategySpy{},
+		Symbol:     "BTCUSDT",
+		Store:      st,
+		Alerts:     alerts,
+		RunSummary: true,
+		MaxRunTime: 300 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	started := time.Now()
+	if err := runner.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+	if elapsed := time.Since(started); elapsed < 300*time.Millisecond || elapsed > 4*time.Second {
+		t.Fatalf("Run() returned after %s, want shortly after max runtime", elapsed)
+	}
+
+	if _, ok := alerts.find("max_runtime_reached"); !ok {
+		t.Fatalf("missing max_runtime_reached alert, got %v", alerts.events)
+	}
+	summary, ok := alerts.find("run_summary")
+	if !ok || summary["reason"] != "max_runtime_reached" {
+		t.Fatalf("run_summary = %v (found %v), want reason max_runtime_reached", summary, ok)
+	}
+	status, ok, err := st.LoadRuntimeStatus()
+	if err != nil || !ok {
+		t.Fatalf("LoadRuntimeStatus() = %v, %v", ok, err)
+	}
+	if status.State != "stopped" || status.LastError != "" {
+		t.Fatalf("status = %s last_error %q, want stopped without error", status.State, status.LastError)
+	}
+	assertNoAsyncErr(t, asyncErrs)
+}
+
+func TestLiveRunnerRunSummaryReportsUSDForNonUSDQuote(t *testing.T) {
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/ticker/price":
+			price := map[string]string{"ETHBTC": "0.06", "BTCUSDT": "60000"}[r.URL.Query().Get("symbol")]
+			_ = writeJSON(w, http.StatusOK, map[string]string{"symbol": r.URL.Query().Get("symbol"), "price": price})
+		case "/api/v3/openOrders":
+			_ = writeJSON(w, http.StatusOK, []map[string]any{})
+		case "/api/v3/exchangeInfo":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"symbols": []map[string]any{{
+				"symbol": "ETHBTC", "baseAsset": "ETH", "quoteAsset": "BTC", "filters": []any{},
+			}}})
+		case "/api/v3/account":
+			_ = writeJSON(w, http.StatusOK, map[string]any{"balances": []map[string]string{
+				{"asset": "ETH", "free": "2", "locked": "0"},
+				{"asset": "BTC", "free": "0.5", "locked": "0"},
+			}})
+		default:
+			_ = writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	}))
+	defer rest.Close()
+
+	client := binance.NewClientWithOptions(binance.Options{
+		APIKey:         "k",
+		APISecret:      "s",
+		RestBaseURL:    rest.URL,
+		Symbol:         "ETHBTC",
+		HTTPTimeoutSec: 3,
+	})
+	defer client.Close()
+	runner := LiveRunner{
+		Exchange:    client,
+		Symbol:      "ETHBTC",
+		ReportInUSD: true,
+		stats:       newRunStats(),
+	}
+	// 1 ETH bought at 0.05 BTC and sold at 0.06 BTC: 0.01 BTC realized.
+	runner.stats.Record(core.Trade{Side: core.Buy, Price: decimal.RequireFromString("0.05"), Qty: decimal.NewFromInt(1)})
+	runner.stats.Record(core.Trade{Side: core.Sell, Price: decimal.RequireFromString("0.06"), Qty: decimal.NewFromInt(1)})
+
+	summary := runner.buildRunSummary(time.Now(), nil)
+	want := map[string]string{
+		"realized_pnl":     "0.01",
+		"quote_usd_rate":   "60000",
+		"realized_pnl_usd": "600.00",
+		// 0.5 BTC + 2 ETH * 0.06 = 0.62 BTC.
+		"equity_usd": "37200.00",
+	}
+	for k, v := range want {
+		if summary[k] != v {
+			t.Fatalf("run summary[%s] = %q, want %q (summary %v)", k, summary[k], v, summary)
+		}
+	}
+}
+
+func TestRunStatsRealizedPnLMatchesStoreReport(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []core.Trade{
+		{Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Time: at},
+		{Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(110), Qty: decimal.NewFromInt(1), Time: at},
+		{Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(120), Qty: decimal.NewFromInt(1), Time: at},
+	}
+	stats := newRunStats()
+	for _, trade := range trades {
+		stats.Record(trade)
+	}
+	report := decimal.Zero
+	for _, day := range store.DailyPnL(trades, "BTCUSDT") {
+		report = report.Add(day.RealizedPnL)
+	}
+	// FIFO sells the 100 lot: 20. Average cost would give 15.
+	if got := stats.RealizedPnL(); !got.Equal(decimal.NewFromInt(20)) || !got.Equal(report) {
+		t.Fatalf("run summary realized = %s, report = %s, want both 20", got, report)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -45,8 +46,16 @@ type Client struct {
 	orderMu           sync.Mutex
 	orderConn         *orderWSConn
 	orderWSKeepalive  time.Duration
+	userStreamSilence time.Duration
 	alerter           alert.Alerter
 
+	wsDialTimeout time.Duration
+	wsDialRetries int
+
+	cancelConcurrency int
+
+	limiter *restLimiter
+
 	recvWindow time.Duration
 	httpClient *http.Client
 
@@ -67,6 +76,18 @@ type Options struct {
 	RecvWindowMs        int64
 	HTTPTimeoutSec      int64
 	OrderWSKeepaliveSec int64
+	// UserStreamSilenceSec probes a user stream silent this long with an
+	// early ping and drops it if the ping goes unanswered; 0 disables.
+	UserStreamSilenceSec int64
+	// WSDialTimeoutSec bounds one websocket dial+handshake; WSDialRetries
+	// re-dials that many times right away before the error is returned.
+	WSDialTimeoutSec int64
+	WSDialRetries    int
+	// CancelConcurrency bounds parallel cancels in CancelOrders; default 5.
+	CancelConcurrency int
+	// MaxRequestsPer10s is the REST request weight budget per 10 seconds;
+	// calls block until it has room. 0 leaves REST calls unthrottled.
+	MaxRequestsPer10s int
 }
 
 func NewClient(cfg config.ExchangeConfig, symbol, instanceID string) (*Client, error) {
@@ -74,17 +95,24 @@ func NewClient(cfg config.ExchangeConfig, symbol, instanceID string) (*Client, e
 		return nil, errors.New("api_key/api_secret required")
 	}
 	opts := Options{
-		APIKey:              cfg.APIKey,
-		APISecret:           cfg.APISecret,
-		RestBaseURL:         cfg.RestBaseURL,
-		WSBaseURL:           cfg.WSBaseURL,
-		Symbol:              symbol,
-		ClientOrderPrefix:   instanceID,
-		UserStreamAuth:      string(cfg.UserStreamAuth),
-		WSEd25519KeyPath:    cfg.WSEd25519KeyPath,
-		RecvWindowMs:        cfg.RecvWindowMs,
-		HTTPTimeoutSec:      cfg.HTTPTimeoutSec,
-		OrderWSKeepaliveSec: cfg.OrderWSKeepaliveSec,
+		APIKey:               cfg.APIKey,
+		APISecret:            cfg.APISecret,
+		RestBaseURL:          cfg.RestBaseURL,
+		WSBaseURL:            cfg.WSBaseURL,
+		Symbol:               symbol,
+		ClientOrderPrefix:    instanceID,
+		UserStreamAuth:       string(cfg.UserStreamAuth),
+		WSEd25519KeyPath:     cfg.WSEd25519KeyPath,
+		RecvWindowMs:         cfg.RecvWindowMs,
+		HTTPTimeoutSec:       cfg.HTTPTimeoutSec,
+		OrderWSKeepaliveSec:  cfg.OrderWSKeepaliveSec,
+		UserStreamSilenceSec: cfg.UserStreamSilenceSec,
+		WSDialTimeoutSec:     cfg.WSDialTimeoutSec,
+		CancelConcurrency:    cfg.CancelConcurrency,
+		MaxRequestsPer10s:    cfg.MaxRequestsPer10s,
+	}
+	if cfg.WSDialRetries != nil {
+		opts.WSDialRetries = *cfg.WSDialRetries
 	}
 	client := NewClientWithOptions(opts)
 	if client.userStreamAuth == "session" {
@@ -108,6 +136,18 @@ func NewClientWithOptions(opts Options) *Client {
 		userStreamAuth = "signature"
 	}
 	orderKeepalive := time.Duration(opts.OrderWSKeepaliveSec) * time.Second
+	dialTimeout := 10 * time.Second
+	if opts.WSDialTimeoutSec > 0 {
+		dialTimeout = time.Duration(opts.WSDialTimeoutSec) * time.Second
+	}
+	dialRetries := opts.WSDialRetries
+	if dialRetries < 0 {
+		dialRetries = 0
+	}
+	cancelConcurrency := opts.CancelConcurrency
+	if cancelConcurrency <= 0 {
+		cancelConcurrency = 5
+	}
 	return &Client{
 		apiKey:            opts.APIKey,
 		apiSecret:         opts.APISecret,
@@ -121,6 +161,11 @@ func NewClientWithOptions(opts Options) *Client {
 		httpClient:        &http.Client{Timeout: timeout},
 		symbolCache:       make(map[string]symbolInfo),
 		orderWSKeepalive:  orderKeepalive,
+		userStreamSilence: time.Duration(opts.UserStreamSilenceSec) * time.Second,
+		wsDialTimeout:     dialTimeout,
+		wsDialRetries:     dialRetries,
+		cancelConcurrency: cancelConcurrency,
+		limiter:           newRestLimiter(opts.MaxRequestsPer10s),
 	}
 }
 
@@ -169,6 +214,10 @@ func (c *Client) getClientOrderPrefix() string {
 	return c.clientOrderPrefix
 }
 
+func (c *Client) ClientOrderPrefix() string {
+	return c.getClientOrderPrefix()
+}
+
 func normalizeClientOrderPrefix(v string) string {
 	v = strings.ToLower(strings.TrimSpace(v))
 	if v == "" {
@@ -207,7 +256,15 @@ func (c *Client) GetRules(ctx context.Context, symbol string) (core.Rules, error
 	return info.rules, nil
 }
 
-func (c *Client) CancelOrder(ctx context.Context, symbol, orderID string) error {
+// RefreshRules drops the cached symbol info and fetches the filters again.
+func (c *Client) RefreshRules(ctx context.Context, symbol string) (core.Rules, error) {
+	c.mu.Lock()
+	delete(c.symbolCache, symbol)
+	c.mu.Unlock()
+	return c.GetRules(ctx, symbol)
+}
+
+func (c *Client) cancelOrderREST(ctx context.Context, symbol, orderID string) error {
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("orderId", orderID)
@@ -215,9 +272,43 @@ func (c *Client) CancelOrder(ctx context.Context, symbol, orderID string) error
 	return err
 }
 
+// CancelOrders cancels ids with at most cancelConcurrency requests in
+// flight (spot has no batch cancel by id). Only failed ids are returned.
+func (c *Client) CancelOrders(ctx context.Context, symbol string, ids []string) map[string]error {
+	failed := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, c.cancelConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := c.CancelOrder(ctx, symbol, id); err != nil {
+				mu.Lock()
+				failed[id] = err
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+	return failed
+}
+
 func (c *Client) OpenOrders(ctx context.Context, symbol string) ([]core.Order, error) {
 	params := url.Values{}
 	params.Set("symbol", symbol)
+	return c.openOrders(ctx, params)
+}
+
+// OpenOrdersAll lists the open orders of every symbol on the account, each
+// with its Symbol set. It costs far more request weight than OpenOrders.
+func (c *Client) OpenOrdersAll(ctx context.Context) ([]core.Order, error) {
+	return c.openOrders(ctx, url.Values{})
+}
+
+func (c *Client) openOrders(ctx context.Context, params url.Values) ([]core.Order, error) {
 	body, err := c.doRequest(ctx, http.MethodGet, "/api/v3/openOrders", params, AuthSigned)
 	if err != nil {
 		return nil, err
@@ -235,14 +326,22 @@ func (c *Client) OpenOrders(ctx context.Context, symbol string) ([]core.Order, e
 		if executedQty.Cmp(decimal.Zero) > 0 && origQty.Cmp(executedQty) > 0 {
 			qty = origQty.Sub(executedQty)
 		}
+		typ, postOnly := parseOrderType(ord.Type)
+		var createdAt time.Time
+		if ord.Time > 0 {
+			createdAt = time.UnixMilli(ord.Time).UTC()
+		}
 		orders = append(orders, core.Order{
-			ID:     strconv.FormatInt(ord.OrderID, 10),
-			Symbol: ord.Symbol,
-			Side:   core.Side(ord.Side),
-			Type:   core.OrderType(ord.Type),
-			Price:  price,
-			Qty:    qty,
-			Status: core.OrderNew,
+			ID:        strconv.FormatInt(ord.OrderID, 10),
+			ClientID:  ord.ClientOrderID,
+			Symbol:    ord.Symbol,
+			Side:      core.Side(ord.Side),
+			Type:      typ,
+			Price:     price,
+			Qty:       qty,
+			Status:    core.OrderNew,
+			PostOnly:  postOnly,
+			CreatedAt: createdAt,
 		})
 	}
 	return orders, nil
@@ -282,15 +381,17 @@ func (c *Client) QueryOrder(ctx context.Context, symbol, orderID, clientID strin
 	executedQty, _ := decimal.NewFromString(resp.ExecutedQty)
 	cumQuote, _ := decimal.NewFromString(resp.CumulativeQuoteQty)
 
+	typ, postOnly := parseOrderType(resp.Type)
 	order := core.Order{
 		ID:       strconv.FormatInt(resp.OrderID, 10),
 		ClientID: resp.ClientOrderID,
 		Symbol:   resp.Symbol,
 		Side:     core.Side(resp.Side),
-		Type:     core.OrderType(resp.Type),
+		Type:     typ,
 		Price:    price,
 		Qty:      qty,
-		Status:   core.OrderStatus(resp.Status),
+		Status:   core.ParseOrderStatus(resp.Status),
+		PostOnly: postOnly,
 	}
 	if resp.Time > 0 {
 		order.CreatedAt = time.UnixMilli(resp.Time)
@@ -307,7 +408,17 @@ func (c *Client) QueryOrder(ctx context.Context, symbol, orderID, clientID strin
 	}, nil
 }
 
+// Balances returns free plus locked base and quote for the client's symbol.
 func (c *Client) Balances(ctx context.Context) (core.Balance, error) {
+	return c.accountBalances(ctx, true)
+}
+
+// FreeBalances returns base and quote not locked in open orders.
+func (c *Client) FreeBalances(ctx context.Context) (core.Balance, error) {
+	return c.accountBalances(ctx, false)
+}
+
+func (c *Client) accountBalances(ctx context.Context, withLocked bool) (core.Balance, error) {
 	if c.symbol == "" {
 		return core.Balance{}, errors.New("symbol is required to resolve balances")
 	}
@@ -325,20 +436,33 @@ func (c *Client) Balances(ctx context.Context) (core.Balance, error) {
 	}
 	bal := core.Balance{Base: decimal.Zero, Quote: decimal.Zero}
 	for _, b := range resp.Balances {
-		if b.Asset == info.baseAsset {
-			free, _ := decimal.NewFromString(b.Free)
+		if b.Asset != info.baseAsset && b.Asset != info.quoteAsset {
+			continue
+		}
+		amount, _ := decimal.NewFromString(b.Free)
+		if withLocked {
 			locked, _ := decimal.NewFromString(b.Locked)
-			bal.Base = free.Add(locked)
+			amount = amount.Add(locked)
+		}
+		if b.Asset == info.baseAsset {
+			bal.Base = amount
 		}
 		if b.Asset == info.quoteAsset {
-			free, _ := decimal.NewFromString(b.Free)
-			locked, _ := decimal.NewFromString(b.Locked)
-			bal.Quote = free.Add(locked)
+			bal.Quote = amount
 		}
 	}
 	return bal, nil
 }
 
+// QuoteAsset returns the quote asset of symbol, e.g. USDT for BTCUSDT.
+func (c *Client) QuoteAsset(ctx context.Context, symbol string) (string, error) {
+	info, err := c.getSymbolInfo(ctx, symbol)
+	if err != nil {
+		return "", err
+	}
+	return info.quoteAsset, nil
+}
+
 func (c *Client) TickerPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
 	params := url.Values{}
 	params.Set("symbol", symbol)
@@ -357,7 +481,33 @@ func (c *Client) TickerPrice(ctx context.Context, symbol string) (decimal.Decima
 	return price, nil
 }
 
+func (c *Client) BookTicker(ctx context.Context, symbol string) (decimal.Decimal, decimal.Decimal, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	body, err := c.doRequest(ctx, http.MethodGet, "/api/v3/ticker/bookTicker", params, AuthNone)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	var resp bookTickerResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	bid, err := decimal.NewFromString(resp.BidPrice)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	ask, err := decimal.NewFromString(resp.AskPrice)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	return bid, ask, nil
+}
+
 func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values, auth AuthType) ([]byte, error) {
+	// Wait before signing so the timestamp is fresh when the call goes out.
+	if err := c.limiter.wait(ctx, requestWeight(method, path, params)); err != nil {
+		return nil, err
+	}
 	if auth == AuthSigned {
 		params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
 		if c.recvWindow > 0 {
@@ -398,6 +548,16 @@ func (c *Client) doRequest(ctx context.Context, method, path string, params url.
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		wait := retryAfter(resp.Header)
+		c.limiter.pause(wait)
+		log.Printf("level=WARN event=rate_limited status=%d path=%s retry_after=%s", resp.StatusCode, path, wait)
+		c.alertImportant("rate_limited", map[string]string{
+			"status":      strconv.Itoa(resp.StatusCode),
+			"path":        path,
+			"retry_after": wait.String(),
+		})
+	}
 	if resp.StatusCode/100 != 2 {
 		return nil, parseAPIError(resp.StatusCode, body)
 	}
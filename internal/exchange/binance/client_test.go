@@ -9,9 +9,12 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/shopspring/decimal"
 
 	"grid-trading/internal/core"
@@ -81,6 +84,12 @@ func TestParseAPIErrorClassifiesKnownFailures(t *testing.T) {
 			wantErr:  core.ErrOrderRejected,
 			wantCode: -2010,
 		},
+		{
+			name:     "would match",
+			payload:  `{"code":-2010,"msg":"Order would immediately match and take."}`,
+			wantErr:  core.ErrWouldMatch,
+			wantCode: -2010,
+		},
 		{
 			name:     "expired",
 			payload:  `{"code":-2010,"msg":"Order was canceled or expired."}`,
@@ -241,6 +250,30 @@ func TestWSOrderParamsSignatureAndSession(t *testing.T) {
 	}
 }
 
+func TestWSOrderParamsPostOnlyUsesLimitMaker(t *testing.T) {
+	c := NewClientWithOptions(Options{UserStreamAuth: "session", RecvWindowMs: 5000})
+	params, err := c.wsOrderParams(core.Order{
+		Symbol:   "BTCUSDT",
+		Side:     core.Sell,
+		Type:     core.Limit,
+		Price:    decimal.RequireFromString("100"),
+		Qty:      decimal.RequireFromString("0.01"),
+		PostOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("wsOrderParams() error = %v", err)
+	}
+	if params["type"] != "LIMIT_MAKER" || params["price"] != "100" {
+		t.Fatalf("type/price = %v/%v, want LIMIT_MAKER/100", params["type"], params["price"])
+	}
+	if _, ok := params["timeInForce"]; ok {
+		t.Fatalf("LIMIT_MAKER params include timeInForce")
+	}
+	if typ, postOnly := parseOrderType("LIMIT_MAKER"); typ != core.Limit || !postOnly {
+		t.Fatalf("parseOrderType(LIMIT_MAKER) = %s, %v; want LIMIT, true", typ, postOnly)
+	}
+}
+
 func TestPlaceOrderRESTDuplicateFallbackByClientID(t *testing.T) {
 	var postCalls int32
 	var getCalls int32
@@ -356,3 +389,236 @@ func TestPlaceOrderFallsBackToRESTWhenWSUnavailable(t *testing.T) {
 		t.Fatalf("post calls = %d, want 1", postCalls)
 	}
 }
+
+func TestOrdersPreferWSAndFallBackToRESTWhenRoundTripFails(t *testing.T) {
+	var wsHealthy atomic.Bool
+	wsHealthy.Store(true)
+	var wsCalls, restCalls sync.Map
+	upgrader := websocket.Upgrader{}
+	ws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if !wsHealthy.Load() {
+				// Drop the connection mid round trip.
+				return
+			}
+			n, _ := wsCalls.LoadOrStore(req.Method, new(atomic.Int32))
+			n.(*atomic.Int32).Add(1)
+			result := map[string]any{}
+			if req.Method == "order.place" {
+				result = map[string]any{"orderId": 501, "clientOrderId": req.Params["newClientOrderId"], "status": "NEW"}
+			}
+			if err := conn.WriteJSON(map[string]any{"id": req.ID, "status": 200, "result": result}); err != nil {
+				return
+			}
+		}
+	}))
+	defer ws.Close()
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/order" {
+			http.NotFound(w, r)
+			return
+		}
+		n, _ := restCalls.LoadOrStore(r.Method, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1)
+		if r.Method == http.MethodDelete {
+			_, _ = io.WriteString(w, `{}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"symbol":"BTCUSDT","orderId":502,"status":"NEW"}`)
+	}))
+	defer rest.Close()
+	calls := func(m *sync.Map, key string) int32 {
+		n, ok := m.Load(key)
+		if !ok {
+			return 0
+		}
+		return n.(*atomic.Int32).Load()
+	}
+
+	c := NewClientWithOptions(Options{
+		APIKey:      "k",
+		APISecret:   "s",
+		RestBaseURL: rest.URL,
+		WSBaseURL:   "ws://" + strings.TrimPrefix(ws.URL, "http://"),
+	})
+	defer c.Close()
+	alerts := &clientAlertRecorder{}
+	c.SetAlerter(alerts)
+	order := core.Order{
+		Symbol: "BTCUSDT",
+		Side:   core.Buy,
+		Type:   core.Limit,
+		Price:  decimal.RequireFromString("100"),
+		Qty:    decimal.RequireFromString("0.01"),
+	}
+	ctx := context.Background()
+
+	placed, err := c.PlaceOrder(ctx, order)
+	if err != nil || placed.ID != "501" {
+		t.Fatalf("PlaceOrder() over ws = %q, %v; want 501", placed.ID, err)
+	}
+	if err := c.CancelOrder(ctx, "BTCUSDT", "501"); err != nil {
+		t.Fatalf("CancelOrder() over ws error = %v", err)
+	}
+	if calls(&wsCalls, "order.place") != 1 || calls(&wsCalls, "order.cancel") != 1 || calls(&restCalls, http.MethodPost)+calls(&restCalls, http.MethodDelete) != 0 {
+		t.Fatalf("healthy ws: ws place/cancel=%d/%d rest post/delete=%d/%d, want 1/1 0/0",
+			calls(&wsCalls, "order.place"), calls(&wsCalls, "order.cancel"), calls(&restCalls, http.MethodPost), calls(&restCalls, http.MethodDelete))
+	}
+	if c.WSDegraded() {
+		t.Fatalf("WSDegraded() = true after ws round trips succeeded")
+	}
+
+	wsHealthy.Store(false)
+	placed, err = c.PlaceOrder(ctx, order)
+	if err != nil || placed.ID != "502" {
+		t.Fatalf("PlaceOrder() fallback = %q, %v; want 502 from rest", placed.ID, err)
+	}
+	if err := c.CancelOrder(ctx, "BTCUSDT", "502"); err != nil {
+		t.Fatalf("CancelOrder() fallback error = %v", err)
+	}
+	if calls(&restCalls, http.MethodPost) != 1 || calls(&restCalls, http.MethodDelete) != 1 {
+		t.Fatalf("rest post/delete = %d/%d, want 1/1", calls(&restCalls, http.MethodPost), calls(&restCalls, http.MethodDelete))
+	}
+	if !c.WSDegraded() {
+		t.Fatalf("WSDegraded() = false after falling back to rest")
+	}
+
+	wsHealthy.Store(true)
+	if _, err := c.PlaceOrder(ctx, order); err != nil {
+		t.Fatalf("PlaceOrder() after recovery error = %v", err)
+	}
+	if c.WSDegraded() {
+		t.Fatalf("WSDegraded() = true after ws recovered")
+	}
+	alerts.mu.Lock()
+	defer alerts.mu.Unlock()
+	// One alert per degraded stretch, not one per fallback.
+	if strings.Join(alerts.events, ",") != "ws_order_fallback_to_rest,ws_order_recovered" {
+		t.Fatalf("alerts = %v, want one fallback then recovered", alerts.events)
+	}
+	if alerts.fields[0]["method"] != "order.place" {
+		t.Fatalf("fallback alert method = %q, want order.place", alerts.fields[0]["method"])
+	}
+}
+
+type clientAlertRecorder struct {
+	mu     sync.Mutex
+	events []string
+	fields []map[string]string
+}
+
+func (r *clientAlertRecorder) Important(event string, fields map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	r.fields = append(r.fields, fields)
+}
+
+func TestRESTLimiterBlocksOnceWeightBudgetIsSpent(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = io.WriteString(w, `{"symbol":"BTCUSDT","price":"100"}`)
+	}))
+	defer srv.Close()
+	// ticker/price weighs 2, so a budget of 4 covers two calls.
+	client := NewClientWithOptions(Options{RestBaseURL: srv.URL, MaxRequestsPer10s: 4})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.TickerPrice(context.Background(), "BTCUSDT"); err != nil {
+			t.Fatalf("TickerPrice() #%d error = %v", i+1, err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.TickerPrice(ctx, "BTCUSDT"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("TickerPrice() over budget error = %v, want context deadline", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("server hits = %d, want 2 (third call must wait for the bucket)", got)
+	}
+}
+
+func TestRESTPausesForRetryAfterOn429AndAlerts(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"code":-1003,"msg":"Too many requests"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"symbol":"BTCUSDT","price":"100"}`)
+	}))
+	defer srv.Close()
+	client := NewClientWithOptions(Options{RestBaseURL: srv.URL})
+	alerts := &clientAlertRecorder{}
+	client.SetAlerter(alerts)
+
+	if _, err := client.TickerPrice(context.Background(), "BTCUSDT"); err == nil {
+		t.Fatalf("TickerPrice() error = nil, want the 429")
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "rate_limited" || alerts.fields[0]["retry_after"] != "1s" {
+		t.Fatalf("alerts = %v %v, want one rate_limited with retry_after 1s", alerts.events, alerts.fields)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := client.TickerPrice(ctx, "BTCUSDT"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("TickerPrice() during pause error = %v, want context deadline", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("server hits during pause = %d, want 1", got)
+	}
+
+	start := time.Now()
+	price, err := client.TickerPrice(context.Background(), "BTCUSDT")
+	if err != nil || !price.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("TickerPrice() after pause = %s, %v", price, err)
+	}
+	if waited := time.Since(start); waited < 500*time.Millisecond {
+		t.Fatalf("call after 429 went out after %s, want it held until Retry-After", waited)
+	}
+}
+
+func TestOpenOrdersAllQueriesEverySymbol(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/api/v3/openOrders" || q.Has("symbol") || q.Get("signature") == "" || r.Header.Get("X-MBX-APIKEY") != "k" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, `[
+			{"symbol":"BTCUSDT","orderId":1,"clientOrderId":"bot1-a","price":"90.5","origQty":"0.010","executedQty":"0.004","status":"PARTIALLY_FILLED","type":"LIMIT","side":"BUY","time":1700000000000},
+			{"symbol":"ETHUSDT","orderId":2,"clientOrderId":"bot2-b","price":"2100","origQty":"0.5","executedQty":"0","status":"NEW","type":"LIMIT","side":"SELL"}
+		]`)
+	}))
+	defer srv.Close()
+	client := NewClientWithOptions(Options{APIKey: "k", APISecret: "s", RestBaseURL: srv.URL, Symbol: "BTCUSDT"})
+
+	orders, err := client.OpenOrdersAll(context.Background())
+	if err != nil {
+		t.Fatalf("OpenOrdersAll() error = %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("OpenOrdersAll() = %d orders, want 2", len(orders))
+	}
+	if got := orders[0]; got.Symbol != "BTCUSDT" || got.ID != "1" || !got.Price.Equal(decimal.RequireFromString("90.5")) || !got.Qty.Equal(decimal.RequireFromString("0.006")) {
+		t.Fatalf("first order = %+v, want BTCUSDT id 1 price 90.5 remaining qty 0.006", got)
+	}
+	if got := orders[0].CreatedAt; !got.Equal(time.UnixMilli(1700000000000)) {
+		t.Fatalf("first order CreatedAt = %s, want the exchange time", got)
+	}
+	if got := orders[1]; got.Symbol != "ETHUSDT" || got.Side != core.Sell || got.ClientID != "bot2-b" || !got.CreatedAt.IsZero() {
+		t.Fatalf("second order = %+v, want ETHUSDT sell bot2-b without a creation time", got)
+	}
+}
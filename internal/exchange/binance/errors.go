@@ -20,6 +20,7 @@ var apiErrorMessageKinds = map[string]error{
 	"unknown order sent.":                                    core.ErrOrderNotFound,
 	"order does not exist.":                                  core.ErrOrderNotFound,
 	"order was canceled or expired.":                         core.ErrOrderExpired,
+	"order would immediately match and take.":                core.ErrWouldMatch,
 }
 
 func wrapAPIError(code int, msg string) error {
@@ -45,7 +46,8 @@ func classifyAPIErrorKinds(apiErr APIError) []error {
 	case apiCodeOrderNotFound, apiCodeCancelRejected:
 		kinds = appendErrorKind(kinds, core.ErrOrderNotFound)
 	case apiCodeNewOrderRejected:
-		if kind, ok := apiErrorMessageKinds[normalizedMsg]; ok {
+		// A would-match reject is still a reject; ErrWouldMatch is added below.
+		if kind, ok := apiErrorMessageKinds[normalizedMsg]; ok && kind != core.ErrWouldMatch {
 			kinds = appendErrorKind(kinds, kind)
 		} else {
 			kinds = appendErrorKind(kinds, core.ErrOrderRejected)
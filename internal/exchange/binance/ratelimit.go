@@ -0,0 +1,112 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is how long REST calls pause after a 429/418 that does
+// not say how long to wait.
+const defaultRetryAfter = 60 * time.Second
+
+// restWeights are Binance request weights for the endpoints this client
+// calls; anything not listed costs 1.
+var restWeights = map[string]int{
+	"GET /api/v3/account":           20,
+	"GET /api/v3/exchangeInfo":      20,
+	"GET /api/v3/openOrders":        6,
+	"GET /api/v3/order":             4,
+	"GET /api/v3/ticker/bookTicker": 2,
+	"GET /api/v3/ticker/price":      2,
+}
+
+func requestWeight(method, path string, params url.Values) int {
+	if method == http.MethodGet && path == "/api/v3/openOrders" && params.Get("symbol") == "" {
+		return 80
+	}
+	if w, ok := restWeights[method+" "+path]; ok {
+		return w
+	}
+	return 1
+}
+
+// restLimiter is a token bucket holding up to perWindow request weight,
+// refilled evenly over every 10 seconds. Each REST call takes its weight
+// before it is sent. A 429/418 pauses all calls until its Retry-After
+// elapses, whether or not the bucket is enabled.
+type restLimiter struct {
+	mu          sync.Mutex
+	capacity    float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newRestLimiter(perWindow int) *restLimiter {
+	capacity := float64(max(perWindow, 0))
+	return &restLimiter{capacity: capacity, tokens: capacity, last: time.Now()}
+}
+
+// wait blocks until weight is available and no pause is in effect, or ctx
+// is done.
+func (l *restLimiter) wait(ctx context.Context, weight int) error {
+	for {
+		delay := l.reserve(weight)
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes weight and returns 0, or returns how long to wait before
+// trying again.
+func (l *restLimiter) reserve(weight int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.capacity <= 0 {
+		return 0
+	}
+	rate := l.capacity / 10 // weight per second
+	l.tokens = min(l.capacity, l.tokens+now.Sub(l.last).Seconds()*rate)
+	l.last = now
+	// A call heavier than the whole bucket waits for a full bucket.
+	need := min(float64(weight), l.capacity)
+	if l.tokens >= need {
+		l.tokens -= need
+		return 0
+	}
+	return time.Duration((need - l.tokens) / rate * float64(time.Second))
+}
+
+func (l *restLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// retryAfter reads the Retry-After header as seconds.
+func retryAfter(h http.Header) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(h.Get("Retry-After")))
+	if err != nil || secs <= 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(secs) * time.Second
+}
@@ -23,10 +23,12 @@ func (e APIError) Error() string {
 }
 
 type orderResponse struct {
-	Symbol  string `json:"symbol"`
-	OrderID int64  `json:"orderId"`
-	Price   string `json:"price"`
-	OrigQty string `json:"origQty"`
+	Symbol       string `json:"symbol"`
+	OrderID      int64  `json:"orderId"`
+	Price        string `json:"price"`
+	OrigQty      string `json:"origQty"`
+	Status       string `json:"status"`
+	TransactTime int64  `json:"transactTime"`
 }
 
 type orderQueryResponse struct {
@@ -45,13 +47,15 @@ type orderQueryResponse struct {
 }
 
 type openOrderResponse struct {
-	Symbol      string `json:"symbol"`
-	OrderID     int64  `json:"orderId"`
-	Price       string `json:"price"`
-	OrigQty     string `json:"origQty"`
-	ExecutedQty string `json:"executedQty"`
-	Side        string `json:"side"`
-	Type        string `json:"type"`
+	Symbol        string `json:"symbol"`
+	OrderID       int64  `json:"orderId"`
+	ClientOrderID string `json:"clientOrderId"`
+	Price         string `json:"price"`
+	OrigQty       string `json:"origQty"`
+	ExecutedQty   string `json:"executedQty"`
+	Side          string `json:"side"`
+	Type          string `json:"type"`
+	Time          int64  `json:"time"`
 }
 
 type tickerPriceResponse struct {
@@ -59,6 +63,14 @@ type tickerPriceResponse struct {
 	Price  string `json:"price"`
 }
 
+type bookTickerResponse struct {
+	Symbol   string `json:"symbol"`
+	BidPrice string `json:"bidPrice"`
+	BidQty   string `json:"bidQty"`
+	AskPrice string `json:"askPrice"`
+	AskQty   string `json:"askQty"`
+}
+
 type accountResponse struct {
 	Balances []struct {
 		Asset  string `json:"asset"`
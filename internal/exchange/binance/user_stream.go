@@ -1,12 +1,15 @@
 package binance
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,10 +18,17 @@ import (
 	"grid-trading/internal/core"
 )
 
+// ErrUserStreamSilent is reported when the stream stays silent through a
+// probe ping, so the caller reconnects before the server drops it.
+var ErrUserStreamSilent = errors.New("user stream silent")
+
 type UserStream struct {
 	client    *Client
 	conn      *websocket.Conn
 	keepalive time.Duration
+	// silence is how long the stream may go without any frame, pongs
+	// included, before it is probed with an early ping; 0 disables.
+	silence time.Duration
 }
 
 type executionReport struct {
@@ -34,15 +44,18 @@ type executionReport struct {
 	LastExecPrice   string `json:"L"`
 	LastExecQty     string `json:"l"`
 	CumulativeQty   string `json:"z"`
+	CumulativeQuote string `json:"Z"`
 	TransactionTime int64  `json:"T"`
 	TradeID         int64  `json:"t"`
+	Commission      string `json:"n"`
+	CommissionAsset string `json:"N"`
 }
 
 func (c *Client) NewUserStream(ctx context.Context, keepalive time.Duration) (*UserStream, error) {
 	if c.wsBaseURL == "" {
 		return nil, errors.New("ws base url required")
 	}
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsBaseURL, nil)
+	conn, err := c.dialWS(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +79,42 @@ func (c *Client) NewUserStream(ctx context.Context, keepalive time.Duration) (*U
 			return nil, err
 		}
 	}
-	return &UserStream{client: c, conn: conn, keepalive: keepalive}, nil
+	return &UserStream{client: c, conn: conn, keepalive: keepalive, silence: c.userStreamSilence}, nil
+}
+
+// dialWS dials the ws-api endpoint with a per-attempt timeout, retrying a
+// failed dial a few times with a short pause so a DNS or handshake blip does
+// not surface as a reconnect.
+func (c *Client) dialWS(ctx context.Context) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		Proxy:            websocket.DefaultDialer.Proxy,
+		HandshakeTimeout: c.wsDialTimeout,
+	}
+	var lastErr error
+	for attempt := 0; attempt <= c.wsDialRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * 200 * time.Millisecond):
+			}
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, c.wsDialTimeout)
+		conn, _, err := dialer.DialContext(dialCtx, c.wsBaseURL, nil)
+		cancel()
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lastErr = err
+		if attempt == c.wsDialRetries {
+			break
+		}
+		log.Printf("level=WARN event=ws_dial_retry attempt=%d max_retries=%d err=%q", attempt+1, c.wsDialRetries, err.Error())
+	}
+	return nil, lastErr
 }
 
 func (c *Client) userStreamParams() (map[string]interface{}, error) {
@@ -152,7 +200,10 @@ func (u *UserStream) Trades(ctx context.Context, symbol string) (<-chan core.Tra
 			readTimeout = 30 * time.Second
 		}
 	}
+	var lastFrame atomic.Int64
+	lastFrame.Store(time.Now().UnixNano())
 	u.conn.SetPongHandler(func(string) error {
+		lastFrame.Store(time.Now().UnixNano())
 		return u.conn.SetReadDeadline(time.Now().Add(readTimeout))
 	})
 
@@ -168,68 +219,31 @@ func (u *UserStream) Trades(ctx context.Context, symbol string) (<-chan core.Tra
 				reportErr(err)
 				return
 			}
+			lastFrame.Store(time.Now().UnixNano())
 			if len(data) == 0 {
 				continue
 			}
 			if isWSResponse(data) {
 				continue
 			}
-			var msg executionReport
-			if err := json.Unmarshal(data, &msg); err != nil {
-				continue
-			}
-			if msg.EventType != "executionReport" {
-				continue
-			}
-			if symbol != "" && msg.Symbol != symbol {
-				continue
-			}
-			if msg.ExecutionType != "TRADE" {
-				continue
-			}
-			qty, err := decimal.NewFromString(msg.LastExecQty)
+			reports, err := decodeExecutionReports(data)
 			if err != nil {
 				continue
 			}
-			if qty.Cmp(decimal.Zero) <= 0 {
-				continue
-			}
-			price, err := decimal.NewFromString(msg.LastExecPrice)
-			if err != nil {
-				price, err = decimal.NewFromString(msg.OrderPrice)
+			for _, msg := range reports {
+				trade, ok, err := tradeFromExecutionReport(msg, symbol)
 				if err != nil {
+					reportErr(err)
 					continue
 				}
-			}
-			if price.Cmp(decimal.Zero) <= 0 {
-				continue
-			}
-			ts := msg.TransactionTime
-			if ts == 0 {
-				ts = msg.EventTime
-			}
-			if ts == 0 {
-				reportErr(errors.New("missing trade timestamp"))
-				continue
-			}
-			tradeID := ""
-			if msg.TradeID > 0 {
-				tradeID = strconv.FormatInt(msg.TradeID, 10)
-			}
-			trade := core.Trade{
-				OrderID: strconv.FormatInt(msg.OrderID, 10),
-				TradeID: tradeID,
-				Symbol:  msg.Symbol,
-				Side:    core.Side(msg.Side),
-				Price:   price,
-				Qty:     qty,
-				Status:  core.OrderStatus(msg.OrderStatus),
-				Time:    time.UnixMilli(ts),
-			}
-			select {
-			case trades <- trade:
-			case <-ctx.Done():
-				return
+				if !ok {
+					continue
+				}
+				select {
+				case trades <- trade:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
@@ -264,5 +278,135 @@ func (u *UserStream) Trades(ctx context.Context, symbol string) (<-chan core.Tra
 		}()
 	}
 
+	if u.silence > 0 {
+		go u.watchSilence(ctx, done, &lastFrame, reportErr)
+	}
+
 	return trades, errCh
 }
+
+// watchSilence pings early once the stream has been silent for u.silence and
+// closes it with ErrUserStreamSilent if nothing arrives within half that
+// again, instead of waiting out the much longer read deadline.
+func (u *UserStream) watchSilence(ctx context.Context, done <-chan struct{}, lastFrame *atomic.Int64, reportErr func(error)) {
+	probeWait := u.silence / 2
+	interval := u.silence / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var probedAt time.Time
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			last := time.Unix(0, lastFrame.Load())
+			if !probedAt.IsZero() && last.After(probedAt) {
+				probedAt = time.Time{}
+			}
+			idle := now.Sub(last)
+			if probedAt.IsZero() {
+				if idle < u.silence {
+					continue
+				}
+				log.Printf("level=WARN event=user_stream_silence_probe idle_ms=%d", idle.Milliseconds())
+				if err := u.conn.WriteControl(websocket.PingMessage, nil, now.Add(5*time.Second)); err != nil {
+					reportErr(err)
+					_ = u.conn.Close()
+					return
+				}
+				probedAt = now
+				continue
+			}
+			if now.Sub(probedAt) < probeWait {
+				continue
+			}
+			reportErr(fmt.Errorf("%w for %s after probe ping", ErrUserStreamSilent, idle.Round(time.Millisecond)))
+			_ = u.conn.Close()
+			return
+		}
+	}
+}
+
+// decodeExecutionReports accepts a single event object or a batched array of
+// events and returns them in stream order.
+func decodeExecutionReports(data []byte) ([]executionReport, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var reports []executionReport
+		if err := json.Unmarshal(trimmed, &reports); err != nil {
+			return nil, err
+		}
+		return reports, nil
+	}
+	var msg executionReport
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	return []executionReport{msg}, nil
+}
+
+func tradeFromExecutionReport(msg executionReport, symbol string) (core.Trade, bool, error) {
+	if msg.EventType != "executionReport" {
+		return core.Trade{}, false, nil
+	}
+	if symbol != "" && msg.Symbol != symbol {
+		return core.Trade{}, false, nil
+	}
+	if msg.ExecutionType != "TRADE" {
+		return core.Trade{}, false, nil
+	}
+	qty, err := decimal.NewFromString(msg.LastExecQty)
+	if err != nil {
+		return core.Trade{}, false, nil
+	}
+	if qty.Cmp(decimal.Zero) <= 0 {
+		return core.Trade{}, false, nil
+	}
+	price, err := decimal.NewFromString(msg.LastExecPrice)
+	if err != nil {
+		price, err = decimal.NewFromString(msg.OrderPrice)
+		if err != nil {
+			return core.Trade{}, false, nil
+		}
+	}
+	if price.Cmp(decimal.Zero) <= 0 {
+		return core.Trade{}, false, nil
+	}
+	ts := msg.TransactionTime
+	if ts == 0 {
+		ts = msg.EventTime
+	}
+	if ts == 0 {
+		return core.Trade{}, false, errors.New("missing trade timestamp")
+	}
+	tradeID := ""
+	if msg.TradeID > 0 {
+		tradeID = strconv.FormatInt(msg.TradeID, 10)
+	}
+	trade := core.Trade{
+		OrderID: strconv.FormatInt(msg.OrderID, 10),
+		TradeID: tradeID,
+		Symbol:  msg.Symbol,
+		Side:    core.Side(msg.Side),
+		Price:   price,
+		Qty:     qty,
+		Status:  core.ParseOrderStatus(msg.OrderStatus),
+		Time:    time.UnixMilli(ts),
+	}
+	if cumQty, err := decimal.NewFromString(msg.CumulativeQty); err == nil {
+		if cumQuote, err := decimal.NewFromString(msg.CumulativeQuote); err == nil {
+			trade.CumQty = cumQty
+			trade.CumQuote = cumQuote
+		}
+	}
+	if fee, err := decimal.NewFromString(msg.Commission); err == nil && fee.Sign() > 0 && msg.CommissionAsset != "" {
+		trade.Fee = fee
+		trade.FeeAsset = msg.CommissionAsset
+	}
+	return trade, true, nil
+}
@@ -0,0 +1,71 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestUserStreamReconnectsWhenSilentThroughProbe(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+		if err := conn.WriteJSON(map[string]any{"id": req.ID, "status": 200, "result": map[string]any{}}); err != nil {
+			return
+		}
+		// Stay connected but stop reading, so pings are never answered.
+		<-release
+	}))
+	defer srv.Close()
+
+	client := NewClientWithOptions(Options{
+		APIKey:    "key",
+		APISecret: "secret",
+		WSBaseURL: "ws://" + strings.TrimPrefix(srv.URL, "http://"),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.NewUserStream(ctx, 0)
+	if err != nil {
+		t.Fatalf("NewUserStream() error = %v", err)
+	}
+	stream.silence = 200 * time.Millisecond
+
+	start := time.Now()
+	_, errCh := stream.Trades(ctx, "BTCUSDT")
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrUserStreamSilent) {
+			t.Fatalf("stream error = %v, want %v", err, ErrUserStreamSilent)
+		}
+		// 200ms silence plus a 100ms probe, far inside the 30s read deadline.
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("silent stream dropped after %s, want within the adaptive window", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("silent stream was not dropped")
+	}
+}
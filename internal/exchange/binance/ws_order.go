@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -20,6 +22,7 @@ type wsOrderResult struct {
 	OrderID       int64  `json:"orderId"`
 	ClientOrderID string `json:"clientOrderId"`
 	Status        string `json:"status"`
+	TransactTime  int64  `json:"transactTime"`
 }
 
 type orderWSConn struct {
@@ -27,28 +30,35 @@ type orderWSConn struct {
 	stop chan struct{}
 }
 
+// wsOrderTimeout bounds one WS-API order round trip before the call falls
+// back to REST.
+const wsOrderTimeout = 5 * time.Second
+
+// PlaceOrder sends order over the WS-API connection and falls back to REST
+// when the round trip fails or times out. An error answered by the exchange
+// is returned as is: REST would get the same answer.
 func (c *Client) PlaceOrder(ctx context.Context, order core.Order) (core.Order, error) {
 	if order.ClientID == "" {
 		order.ClientID = newClientOrderID(c.getClientOrderPrefix())
 	}
 	placed, err := c.placeOrderWS(ctx, order)
 	if err == nil {
-		if c.clearWSDegraded() {
-			c.alertImportant("ws_order_recovered", map[string]string{
-				"symbol": order.Symbol,
-			})
-		}
+		c.wsOrderOK(order.Symbol)
 		return placed, nil
 	}
-	c.markWSDegraded()
-	c.alertImportant("ws_order_fallback_to_rest", map[string]string{
-		"symbol":    order.Symbol,
+	if _, ok := AsAPIError(err); ok {
+		c.wsOrderOK(order.Symbol)
+		return c.placeOrderFailed(ctx, order, err)
+	}
+	if ctx.Err() != nil {
+		return core.Order{}, err
+	}
+	c.wsOrderFallback("order.place", order.Symbol, err, map[string]string{
 		"side":      string(order.Side),
 		"type":      string(order.Type),
 		"price":     order.Price.String(),
 		"qty":       order.Qty.String(),
 		"client_id": order.ClientID,
-		"ws_error":  err.Error(),
 	})
 	placed, restErr := c.placeOrderREST(ctx, order)
 	if restErr != nil {
@@ -65,6 +75,55 @@ func (c *Client) PlaceOrder(ctx context.Context, order core.Order) (core.Order,
 	return placed, restErr
 }
 
+// CancelOrder cancels over the WS-API connection with the same REST
+// fallback as PlaceOrder.
+func (c *Client) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	err := c.cancelOrderWS(ctx, symbol, orderID)
+	if err == nil {
+		c.wsOrderOK(symbol)
+		return nil
+	}
+	if _, ok := AsAPIError(err); ok {
+		c.wsOrderOK(symbol)
+		return err
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+	c.wsOrderFallback("order.cancel", symbol, err, map[string]string{
+		"order_id": orderID,
+	})
+	return c.cancelOrderREST(ctx, symbol, orderID)
+}
+
+// wsOrderFallback marks the WS-API path degraded; only the first fallback of
+// a degraded stretch alerts.
+func (c *Client) wsOrderFallback(method, symbol string, wsErr error, fields map[string]string) {
+	if !c.markWSDegraded() {
+		log.Printf("level=WARN event=ws_order_fallback method=%s symbol=%q err=%q", method, symbol, wsErr.Error())
+		return
+	}
+	fields["symbol"] = symbol
+	fields["method"] = method
+	fields["ws_error"] = wsErr.Error()
+	c.alertImportant("ws_order_fallback_to_rest", fields)
+}
+
+func (c *Client) wsOrderOK(symbol string) {
+	if c.clearWSDegraded() {
+		c.alertImportant("ws_order_recovered", map[string]string{
+			"symbol": symbol,
+		})
+	}
+}
+
+// WSDegraded reports whether order calls are currently falling back to REST.
+func (c *Client) WSDegraded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wsDegraded
+}
+
 func (c *Client) placeOrderWS(ctx context.Context, order core.Order) (core.Order, error) {
 	if c.wsBaseURL == "" {
 		return core.Order{}, errors.New("ws base url required")
@@ -81,9 +140,13 @@ func (c *Client) placeOrderWS(ctx context.Context, order core.Order) (core.Order
 	if err != nil {
 		return core.Order{}, err
 	}
+	ctx, cancel := context.WithTimeout(ctx, wsOrderTimeout)
+	defer cancel()
 	resp, err := sendWSRequest(ctx, conn, "order.place", params)
 	if err != nil {
-		c.resetOrderConn()
+		if _, ok := AsAPIError(err); !ok {
+			c.resetOrderConn()
+		}
 		return core.Order{}, err
 	}
 	var result wsOrderResult
@@ -92,13 +155,16 @@ func (c *Client) placeOrderWS(ctx context.Context, order core.Order) (core.Order
 	}
 	order.ID = strconv.FormatInt(result.OrderID, 10)
 	if result.Status != "" {
-		order.Status = core.OrderStatus(result.Status)
+		order.Status = core.ParseOrderStatus(result.Status)
 	} else {
 		order.Status = core.OrderNew
 	}
 	if order.ClientID == "" && result.ClientOrderID != "" {
 		order.ClientID = result.ClientOrderID
 	}
+	if result.TransactTime > 0 {
+		order.CreatedAt = time.UnixMilli(result.TransactTime).UTC()
+	}
 	return order, nil
 }
 
@@ -116,12 +182,14 @@ func (c *Client) wsOrderParams(order core.Order) (map[string]interface{}, error)
 	params := map[string]interface{}{
 		"symbol":    order.Symbol,
 		"side":      string(order.Side),
-		"type":      string(order.Type),
+		"type":      orderTypeParam(order),
 		"quantity":  order.Qty.String(),
 		"timestamp": ts,
 	}
 	if order.Type == core.Limit {
-		params["timeInForce"] = "GTC"
+		if !order.PostOnly {
+			params["timeInForce"] = "GTC"
+		}
 		params["price"] = order.Price.String()
 	}
 	if order.ClientID != "" {
@@ -131,43 +199,77 @@ func (c *Client) wsOrderParams(order core.Order) (map[string]interface{}, error)
 		params["recvWindow"] = c.recvWindow.Milliseconds()
 	}
 
-	if c.userStreamAuth != "session" {
-		if c.apiKey == "" || c.apiSecret == "" {
-			return nil, errors.New("api_key/api_secret required")
-		}
-		values := url.Values{}
-		values.Set("apiKey", c.apiKey)
-		values.Set("symbol", order.Symbol)
-		values.Set("side", string(order.Side))
-		values.Set("type", string(order.Type))
-		values.Set("quantity", order.Qty.String())
-		if order.Type == core.Limit {
-			values.Set("timeInForce", "GTC")
-			values.Set("price", order.Price.String())
-		}
-		values.Set("timestamp", strconv.FormatInt(ts, 10))
-		if order.ClientID != "" {
-			values.Set("newClientOrderId", order.ClientID)
-		}
-		if c.recvWindow > 0 {
-			values.Set("recvWindow", strconv.FormatInt(c.recvWindow.Milliseconds(), 10))
+	if err := c.signWSParams(params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func (c *Client) cancelOrderWS(ctx context.Context, symbol, orderID string) error {
+	if c.wsBaseURL == "" {
+		return errors.New("ws base url required")
+	}
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order id %q: %w", orderID, err)
+	}
+	c.orderMu.Lock()
+	defer c.orderMu.Unlock()
+
+	conn, err := c.ensureOrderConn(ctx)
+	if err != nil {
+		return err
+	}
+	params := map[string]interface{}{
+		"symbol":    symbol,
+		"orderId":   id,
+		"timestamp": time.Now().UnixMilli(),
+	}
+	if c.recvWindow > 0 {
+		params["recvWindow"] = c.recvWindow.Milliseconds()
+	}
+	if err := c.signWSParams(params); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, wsOrderTimeout)
+	defer cancel()
+	if _, err := sendWSRequest(ctx, conn, "order.cancel", params); err != nil {
+		if _, ok := AsAPIError(err); !ok {
+			c.resetOrderConn()
 		}
-		signature := sign(c.apiSecret, values.Encode())
-		params["apiKey"] = c.apiKey
-		params["signature"] = signature
+		return err
 	}
+	return nil
+}
 
-	return params, nil
+// signWSParams adds apiKey and signature to params unless the connection is
+// a logged-on session. The payload is params sorted by key.
+func (c *Client) signWSParams(params map[string]interface{}) error {
+	if c.userStreamAuth == "session" {
+		return nil
+	}
+	if c.apiKey == "" || c.apiSecret == "" {
+		return errors.New("api_key/api_secret required")
+	}
+	params["apiKey"] = c.apiKey
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, fmt.Sprint(v))
+	}
+	params["signature"] = sign(c.apiSecret, values.Encode())
+	return nil
 }
 
 func (c *Client) placeOrderREST(ctx context.Context, order core.Order) (core.Order, error) {
 	params := url.Values{}
 	params.Set("symbol", order.Symbol)
 	params.Set("side", string(order.Side))
-	params.Set("type", string(order.Type))
+	params.Set("type", orderTypeParam(order))
 	params.Set("quantity", order.Qty.String())
 	if order.Type == core.Limit {
-		params.Set("timeInForce", "GTC")
+		if !order.PostOnly {
+			params.Set("timeInForce", "GTC")
+		}
 		params.Set("price", order.Price.String())
 	}
 	if order.ClientID != "" {
@@ -176,30 +278,7 @@ func (c *Client) placeOrderREST(ctx context.Context, order core.Order) (core.Ord
 
 	body, err := c.doRequest(ctx, http.MethodPost, "/api/v3/order", params, AuthSigned)
 	if err != nil {
-		if errors.Is(err, core.ErrOrderRejected) || errors.Is(err, core.ErrOrderExpired) {
-			errorCode := "unknown"
-			errorMsg := err.Error()
-			if apiErr, ok := AsAPIError(err); ok {
-				errorCode = strconv.Itoa(apiErr.Code)
-				errorMsg = apiErr.Msg
-			}
-			c.alertImportant("order_rejected_or_expired", map[string]string{
-				"symbol":     order.Symbol,
-				"side":       string(order.Side),
-				"type":       string(order.Type),
-				"client_id":  order.ClientID,
-				"error_code": errorCode,
-				"error_msg":  errorMsg,
-			})
-		}
-		if errors.Is(err, core.ErrDuplicateOrder) {
-			if order.ClientID != "" {
-				if existing, err := c.getOrderByClientID(ctx, order.Symbol, order.ClientID); err == nil {
-					return existing, nil
-				}
-			}
-		}
-		return core.Order{}, err
+		return c.placeOrderFailed(ctx, order, err)
 	}
 	var resp orderResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
@@ -207,14 +286,69 @@ func (c *Client) placeOrderREST(ctx context.Context, order core.Order) (core.Ord
 	}
 	order.ID = strconv.FormatInt(resp.OrderID, 10)
 	order.Status = core.OrderNew
+	if resp.Status != "" {
+		order.Status = core.ParseOrderStatus(resp.Status)
+	}
+	if resp.TransactTime > 0 {
+		order.CreatedAt = time.UnixMilli(resp.TransactTime).UTC()
+	}
 	return order, nil
 }
 
+const orderTypeLimitMaker = "LIMIT_MAKER"
+
+// orderTypeParam is the Binance type for order: post-only limits are sent
+// as LIMIT_MAKER, which takes no timeInForce.
+func orderTypeParam(order core.Order) string {
+	if order.Type == core.Limit && order.PostOnly {
+		return orderTypeLimitMaker
+	}
+	return string(order.Type)
+}
+
+// parseOrderType maps a Binance order type back, reading LIMIT_MAKER as a
+// post-only limit.
+func parseOrderType(t string) (typ core.OrderType, postOnly bool) {
+	if t == orderTypeLimitMaker {
+		return core.Limit, true
+	}
+	return core.OrderType(t), false
+}
+
+// placeOrderFailed alerts on rejected or expired orders and resolves a
+// duplicate client id to the order already on the book.
+func (c *Client) placeOrderFailed(ctx context.Context, order core.Order, err error) (core.Order, error) {
+	if errors.Is(err, core.ErrOrderRejected) || errors.Is(err, core.ErrOrderExpired) {
+		errorCode := "unknown"
+		errorMsg := err.Error()
+		if apiErr, ok := AsAPIError(err); ok {
+			errorCode = strconv.Itoa(apiErr.Code)
+			errorMsg = apiErr.Msg
+		}
+		c.alertImportant("order_rejected_or_expired", map[string]string{
+			"symbol":     order.Symbol,
+			"side":       string(order.Side),
+			"type":       string(order.Type),
+			"client_id":  order.ClientID,
+			"error_code": errorCode,
+			"error_msg":  errorMsg,
+		})
+	}
+	if errors.Is(err, core.ErrDuplicateOrder) {
+		if order.ClientID != "" {
+			if existing, err := c.getOrderByClientID(ctx, order.Symbol, order.ClientID); err == nil {
+				return existing, nil
+			}
+		}
+	}
+	return core.Order{}, err
+}
+
 func (c *Client) ensureOrderConn(ctx context.Context) (*websocket.Conn, error) {
 	if c.orderConn != nil {
 		return c.orderConn.conn, nil
 	}
-	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsBaseURL, nil)
+	conn, err := c.dialWS(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -261,15 +395,17 @@ func (c *Client) getOrderByClientID(ctx context.Context, symbol, clientID string
 	if err != nil {
 		return core.Order{}, err
 	}
+	typ, postOnly := parseOrderType(resp.Type)
 	return core.Order{
 		ID:       strconv.FormatInt(resp.OrderID, 10),
 		ClientID: resp.ClientOrderID,
 		Symbol:   resp.Symbol,
 		Side:     core.Side(resp.Side),
-		Type:     core.OrderType(resp.Type),
+		Type:     typ,
 		Price:    price,
 		Qty:      qty,
-		Status:   core.OrderStatus(resp.Status),
+		Status:   core.ParseOrderStatus(resp.Status),
+		PostOnly: postOnly,
 	}, nil
 }
 
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Labels struct {
+	Mode       string
+	Symbol     string
+	InstanceID string
+}
+
+type kind string
+
+const (
+	kindCounter kind = "counter"
+	kindGauge   kind = "gauge"
+)
+
+type series struct {
+	kind  kind
+	help  string
+	value float64
+}
+
+type Registry struct {
+	mu     sync.Mutex
+	labels Labels
+	series map[string]*series
+}
+
+func NewRegistry(labels Labels) *Registry {
+	return &Registry{
+		labels: labels,
+		series: make(map[string]*series),
+	}
+}
+
+func (r *Registry) SetLabels(labels Labels) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.labels = labels
+	r.mu.Unlock()
+}
+
+func (r *Registry) Labels() Labels {
+	if r == nil {
+		return Labels{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.labels
+}
+
+func (r *Registry) Counter(name, help string) {
+	r.declare(name, help, kindCounter)
+}
+
+func (r *Registry) Gauge(name, help string) {
+	r.declare(name, help, kindGauge)
+}
+
+func (r *Registry) declare(name, help string, k kind) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name)
+	s.kind = k
+	s.help = help
+}
+
+func (r *Registry) Inc(name string) {
+	r.Add(name, 1)
+}
+
+func (r *Registry) Add(name string, delta float64) {
+	if r == nil || delta < 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name)
+	s.kind = kindCounter
+	s.value += delta
+}
+
+func (r *Registry) Set(name string, value float64) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name)
+	s.kind = kindGauge
+	s.value = value
+}
+
+func (r *Registry) Value(name string) (float64, bool) {
+	if r == nil {
+		return 0, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.series[name]
+	if !ok {
+		return 0, false
+	}
+	return s.value, true
+}
+
+func (r *Registry) get(name string) *series {
+	s, ok := r.series[name]
+	if !ok {
+		s = &series{}
+		r.series[name] = s
+	}
+	return s
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	labels := formatLabels(r.labels)
+	names := make([]string, 0, len(r.series))
+	for name := range r.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		s := r.series[name]
+		if s.help != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, s.help)
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, s.kind)
+		fmt.Fprintf(&b, "%s%s %s\n", name, labels, strconv.FormatFloat(s.value, 'g', -1, 64))
+	}
+	r.mu.Unlock()
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatLabels(labels Labels) string {
+	return fmt.Sprintf(
+		"{mode=%s,symbol=%s,instance_id=%s}",
+		quoteLabel(labels.Mode),
+		quoteLabel(labels.Symbol),
+		quoteLabel(labels.InstanceID),
+	)
+}
+
+func quoteLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
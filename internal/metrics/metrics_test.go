@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteTextIncludesInstanceLabels(t *testing.T) {
+	r := NewRegistry(Labels{Mode: "live", Symbol: "BTCUSDT", InstanceID: "bot1"})
+	r.Counter("gridbot_trades_total", "Trades applied to the strategy.")
+	r.Counter("gridbot_reconnects_total", "User stream reconnect attempts.")
+	r.Gauge("gridbot_open_orders", "Orders the strategy tracks as open.")
+	r.Inc("gridbot_trades_total")
+	r.Inc("gridbot_trades_total")
+	r.Set("gridbot_runner_running", 1)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# HELP gridbot_trades_total Trades applied to the strategy.\n",
+		"# TYPE gridbot_trades_total counter\n",
+		`gridbot_trades_total{mode="live",symbol="BTCUSDT",instance_id="bot1"} 2` + "\n",
+		"# TYPE gridbot_runner_running gauge\n",
+		"# TYPE gridbot_reconnects_total counter\n",
+		`gridbot_reconnects_total{mode="live",symbol="BTCUSDT",instance_id="bot1"} 0` + "\n",
+		"# TYPE gridbot_open_orders gauge\n",
+		`gridbot_runner_running{mode="live",symbol="BTCUSDT",instance_id="bot1"} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("metrics output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRegistryNilIsNoop(t *testing.T) {
+	var r *Registry
+	r.Inc("x")
+	r.Set("y", 1)
+	if _, ok := r.Value("x"); ok {
+		t.Fatalf("nil registry should not report values")
+	}
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("nil registry output = %q, want empty", buf.String())
+	}
+}
+
+func TestQuoteLabelEscapesSpecialCharacters(t *testing.T) {
+	got := quoteLabel("a\"b\\c\nd")
+	want := `"a\"b\\c\nd"`
+	if got != want {
+		t.Fatalf("quoteLabel() = %s, want %s", got, want)
+	}
+}
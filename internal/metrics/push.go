@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultPushJob = "gridbot"
+
+type Pusher struct {
+	baseURL string
+	job     string
+	client  *http.Client
+}
+
+func NewPusher(baseURL, job string, timeout time.Duration) *Pusher {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return nil
+	}
+	job = strings.TrimSpace(job)
+	if job == "" {
+		job = defaultPushJob
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Pusher{
+		baseURL: baseURL,
+		job:     job,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *Pusher) Push(ctx context.Context, r *Registry) error {
+	if p == nil || r == nil {
+		return nil
+	}
+	var body bytes.Buffer
+	if err := r.WriteText(&body); err != nil {
+		return err
+	}
+	return p.do(ctx, http.MethodPut, p.groupURL(r.Labels()), &body)
+}
+
+func (p *Pusher) Delete(ctx context.Context, r *Registry) error {
+	if p == nil || r == nil {
+		return nil
+	}
+	return p.do(ctx, http.MethodDelete, p.groupURL(r.Labels()), nil)
+}
+
+func (p *Pusher) groupURL(labels Labels) string {
+	parts := []string{
+		p.baseURL,
+		"metrics",
+		"job", url.PathEscape(p.job),
+		"mode", url.PathEscape(labels.Mode),
+		"symbol", url.PathEscape(labels.Symbol),
+		"instance_id", url.PathEscape(labels.InstanceID),
+	}
+	return strings.Join(parts, "/")
+}
+
+func (p *Pusher) do(ctx context.Context, method, endpoint string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
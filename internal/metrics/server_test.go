@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeExposesRegistryForScraping(t *testing.T) {
+	r := NewRegistry(Labels{Mode: "testnet", Symbol: "ETHUSDT", InstanceID: "b2"})
+	r.Set("gridbot_open_orders", 6)
+
+	srv, err := Serve("127.0.0.1:0", r)
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Close(ctx)
+	}()
+
+	resp, err := http.Get("http://" + srv.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	want := `gridbot_open_orders{mode="testnet",symbol="ETHUSDT",instance_id="b2"} 6`
+	if !strings.Contains(string(body), want) {
+		t.Fatalf("body missing %q, got:\n%s", want, body)
+	}
+
+	// Values are read at scrape time.
+	r.Set("gridbot_open_orders", 4)
+	resp, err = http.Get("http://" + srv.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("second GET /metrics error = %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `instance_id="b2"} 4`) {
+		t.Fatalf("second scrape not updated, got:\n%s", body)
+	}
+}
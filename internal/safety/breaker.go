@@ -9,6 +9,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/shopspring/decimal"
+
 	"grid-trading/internal/alert"
 	"grid-trading/internal/core"
 )
@@ -169,6 +171,21 @@ func (b *Breaker) ResetReconnect() {
 	_ = b.RecordReconnect(nil)
 }
 
+// CircuitStates reports each circuit's state ("closed", "half_open" or
+// "open") keyed by "place", "cancel" and "reconnect"; nil when disabled.
+func (b *Breaker) CircuitStates() map[string]string {
+	if b == nil || !b.enabled {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return map[string]string{
+		"place":     string(b.place.state),
+		"cancel":    string(b.cancel.state),
+		"reconnect": string(b.reconnect.state),
+	}
+}
+
 func (b *Breaker) SetAlerter(alerter alert.Alerter) {
 	if b == nil {
 		return
@@ -368,6 +385,54 @@ func (e *GuardedExecutor) CancelOrder(ctx context.Context, symbol, orderID strin
 	return err
 }
 
+// CancelOrders batches through the inner executor when it supports it and
+// records every result with the breaker; a trip replaces that id's error.
+func (e *GuardedExecutor) CancelOrders(ctx context.Context, symbol string, ids []string) map[string]error {
+	var failed map[string]error
+	if batcher, ok := e.inner.(interface {
+		CancelOrders(ctx context.Context, symbol string, ids []string) map[string]error
+	}); ok {
+		failed = batcher.CancelOrders(ctx, symbol, ids)
+	} else {
+		failed = make(map[string]error)
+		for _, id := range ids {
+			if err := e.inner.CancelOrder(ctx, symbol, id); err != nil {
+				failed[id] = err
+			}
+		}
+	}
+	if failed == nil {
+		failed = make(map[string]error)
+	}
+	for _, id := range ids {
+		err := failed[id]
+		if trip := e.breaker.RecordCancel(err); trip != nil {
+			failed[id] = trip
+		}
+	}
+	return failed
+}
+
 func (e *GuardedExecutor) Balances(ctx context.Context) (core.Balance, error) {
 	return e.inner.Balances(ctx)
 }
+
+func (e *GuardedExecutor) OpenOrders(ctx context.Context, symbol string) ([]core.Order, error) {
+	lister, ok := e.inner.(interface {
+		OpenOrders(ctx context.Context, symbol string) ([]core.Order, error)
+	})
+	if !ok {
+		return nil, errors.New("executor does not support open orders")
+	}
+	return lister.OpenOrders(ctx, symbol)
+}
+
+func (e *GuardedExecutor) TickerPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	reader, ok := e.inner.(interface {
+		TickerPrice(ctx context.Context, symbol string) (decimal.Decimal, error)
+	})
+	if !ok {
+		return decimal.Zero, errors.New("executor does not support ticker price")
+	}
+	return reader.TickerPrice(ctx, symbol)
+}
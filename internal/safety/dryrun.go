@@ -0,0 +1,119 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+)
+
+// DryRunExecutor logs order placements and cancels instead of sending them.
+// Placed orders get synthetic "dry-N" ids and stay in an in-memory book that
+// OpenOrders serves, so reconcile and persistence run as usual. Balances and
+// prices still come from the inner executor. Nothing in the book ever fills.
+type DryRunExecutor struct {
+	inner Executor
+
+	mu     sync.Mutex
+	nextID int64
+	book   map[string]core.Order
+}
+
+func NewDryRunExecutor(inner Executor) *DryRunExecutor {
+	return &DryRunExecutor{
+		inner: inner,
+		book:  make(map[string]core.Order),
+	}
+}
+
+// Seed restores previously placed dry-run orders, e.g. the persisted open
+// orders of an earlier dry run, so a restart resumes the same book.
+func (e *DryRunExecutor) Seed(orders []core.Order) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, ord := range orders {
+		if ord.ID == "" {
+			continue
+		}
+		e.book[ord.ID] = ord
+		var n int64
+		if _, err := fmt.Sscanf(ord.ID, "dry-%d", &n); err == nil && n > e.nextID {
+			e.nextID = n
+		}
+	}
+}
+
+func (e *DryRunExecutor) PlaceOrder(_ context.Context, order core.Order) (core.Order, error) {
+	e.mu.Lock()
+	e.nextID++
+	order.ID = fmt.Sprintf("dry-%d", e.nextID)
+	order.Status = core.OrderNew
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = time.Now().UTC()
+	}
+	if order.Type == core.Market {
+		order.Status = core.OrderFilled
+	} else {
+		e.book[order.ID] = order
+	}
+	e.mu.Unlock()
+	log.Printf(
+		"level=INFO event=dry_run_place symbol=%q side=%s type=%s price=%s qty=%s grid_index=%d order_id=%s client_order_id=%q",
+		order.Symbol, order.Side, order.Type, order.Price, order.Qty, order.GridIndex, order.ID, order.ClientID,
+	)
+	return order, nil
+}
+
+func (e *DryRunExecutor) CancelOrder(_ context.Context, symbol, orderID string) error {
+	e.mu.Lock()
+	ord, ok := e.book[orderID]
+	delete(e.book, orderID)
+	e.mu.Unlock()
+	if !ok {
+		return core.ErrOrderNotFound
+	}
+	log.Printf(
+		"level=INFO event=dry_run_cancel symbol=%q side=%s price=%s qty=%s grid_index=%d order_id=%s",
+		symbol, ord.Side, ord.Price, ord.Qty, ord.GridIndex, orderID,
+	)
+	return nil
+}
+
+func (e *DryRunExecutor) Balances(ctx context.Context) (core.Balance, error) {
+	return e.inner.Balances(ctx)
+}
+
+// OpenOrders returns the dry-run book for symbol, oldest id first.
+func (e *DryRunExecutor) OpenOrders(_ context.Context, symbol string) ([]core.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	open := make([]core.Order, 0, len(e.book))
+	for _, ord := range e.book {
+		if ord.Symbol == "" || ord.Symbol == symbol {
+			open = append(open, ord)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		if len(open[i].ID) != len(open[j].ID) {
+			return len(open[i].ID) < len(open[j].ID)
+		}
+		return open[i].ID < open[j].ID
+	})
+	return open, nil
+}
+
+func (e *DryRunExecutor) TickerPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	reader, ok := e.inner.(interface {
+		TickerPrice(ctx context.Context, symbol string) (decimal.Decimal, error)
+	})
+	if !ok {
+		return decimal.Zero, fmt.Errorf("executor does not support ticker price")
+	}
+	return reader.TickerPrice(ctx, symbol)
+}
@@ -0,0 +1,275 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"grid-trading/internal/store"
+	"grid-trading/internal/strategy"
+)
+
+// Board holds the last grid snapshot the live runner published, so /status
+// can read it without touching the strategy off the event loop. A nil Board
+// ignores publishes.
+type Board struct {
+	mu          sync.RWMutex
+	grid        strategy.GridStatus
+	publishedAt time.Time
+
+	loopAt          time.Time
+	tickAt          time.Time
+	streamConnected bool
+	loopStale       time.Duration
+	tickMaxAge      time.Duration
+}
+
+func NewBoard() *Board {
+	return &Board{}
+}
+
+func (b *Board) Publish(grid strategy.GridStatus) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.grid = grid
+	b.publishedAt = time.Now().UTC()
+}
+
+// SetProbeLimits sets how long the runner loop may go without waking before
+// /healthz fails, and how old the last tick may be before /readyz fails.
+// Zero disables the respective age check.
+func (b *Board) SetProbeLimits(loopStale, tickMaxAge time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.loopStale = max(loopStale, 0)
+	b.tickMaxAge = max(tickMaxAge, 0)
+}
+
+// MarkLoop records that the runner loop was alive at at. A loop about to
+// sleep may pass the time it will wake.
+func (b *Board) MarkLoop(at time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if at.After(b.loopAt) {
+		b.loopAt = at
+	}
+}
+
+func (b *Board) MarkTick(at time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tickAt = at
+}
+
+func (b *Board) SetStreamConnected(connected bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.streamConnected = connected
+}
+
+// Grid returns the last published snapshot and when it was published; ok is
+// false until the first publish.
+func (b *Board) Grid() (grid strategy.GridStatus, publishedAt time.Time, ok bool) {
+	if b == nil {
+		return strategy.GridStatus{}, time.Time{}, false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.grid, b.publishedAt, !b.publishedAt.IsZero()
+}
+
+// Response is the /status body.
+type Response struct {
+	Runtime *store.RuntimeStatus `json:"runtime,omitempty"`
+	Grid    *GridView            `json:"grid,omitempty"`
+}
+
+// GridView is the JSON form of strategy.GridStatus.
+type GridView struct {
+	PublishedAt time.Time `json:"published_at"`
+	OpenOrders  int       `json:"open_orders"`
+	MinLevel    int       `json:"min_level"`
+	MaxLevel    int       `json:"max_level"`
+	Anchor      string    `json:"anchor"`
+	Ratio       string    `json:"ratio"`
+	SellRatio   string    `json:"sell_ratio"`
+	LastPrice   string    `json:"last_price"`
+	Paused      bool      `json:"paused"`
+	Stopped     bool      `json:"stopped"`
+}
+
+// ProbeResponse is the /healthz and /readyz body.
+type ProbeResponse struct {
+	Status          string     `json:"status"`
+	Reason          string     `json:"reason,omitempty"`
+	LastLoopAt      *time.Time `json:"last_loop_at,omitempty"`
+	LastTickAt      *time.Time `json:"last_tick_at,omitempty"`
+	StreamConnected bool       `json:"stream_connected"`
+}
+
+// probe answers liveness (ready false) or readiness (ready true) at now.
+// Liveness only fails when the loop has stopped waking; before the first
+// mark the process is still starting and counts as alive but not ready.
+func (b *Board) probe(now time.Time, ready bool) (int, ProbeResponse) {
+	var loopAt, tickAt time.Time
+	var connected bool
+	var loopStale, tickMaxAge time.Duration
+	if b != nil {
+		b.mu.RLock()
+		loopAt, tickAt, connected = b.loopAt, b.tickAt, b.streamConnected
+		loopStale, tickMaxAge = b.loopStale, b.tickMaxAge
+		b.mu.RUnlock()
+	}
+	resp := ProbeResponse{Status: "ok", StreamConnected: connected}
+	if !loopAt.IsZero() {
+		resp.LastLoopAt = &loopAt
+	}
+	if !tickAt.IsZero() {
+		resp.LastTickAt = &tickAt
+	}
+	fail := func(reason string) (int, ProbeResponse) {
+		resp.Status = "unavailable"
+		resp.Reason = reason
+		return http.StatusServiceUnavailable, resp
+	}
+	if loopStale > 0 && !loopAt.IsZero() && now.Sub(loopAt) > loopStale {
+		return fail("runner loop stalled")
+	}
+	if !ready {
+		return http.StatusOK, resp
+	}
+	switch {
+	case !connected:
+		return fail("user stream not connected")
+	case tickAt.IsZero():
+		return fail("no tick yet")
+	case tickMaxAge > 0 && now.Sub(tickAt) > tickMaxAge:
+		return fail("last tick too old")
+	}
+	return http.StatusOK, resp
+}
+
+// ProbeHandler serves /healthz (ready false) or /readyz (ready true) from
+// board.
+func ProbeHandler(board *Board, ready bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		code, resp := board.probe(time.Now().UTC(), ready)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// Handler serves the runtime status from st and the grid from board as
+// JSON. It answers 503 until the runner has written a state other than
+// "starting".
+func Handler(st *store.Store, board *Board) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var resp Response
+		code := http.StatusServiceUnavailable
+		if st != nil {
+			runtime, ok, err := st.LoadRuntimeStatus()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if ok {
+				resp.Runtime = &runtime
+				if runtime.State != "starting" {
+					code = http.StatusOK
+				}
+			}
+		}
+		if grid, at, ok := board.Grid(); ok {
+			resp.Grid = &GridView{
+				PublishedAt: at,
+				OpenOrders:  grid.OpenOrders,
+				MinLevel:    grid.MinLevel,
+				MaxLevel:    grid.MaxLevel,
+				Anchor:      grid.Anchor.String(),
+				Ratio:       grid.Ratio.String(),
+				SellRatio:   grid.SellRatio.String(),
+				LastPrice:   grid.LastPrice.String(),
+				Paused:      grid.Paused,
+				Stopped:     grid.Stopped,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// Server exposes /status, /healthz and /readyz for a running instance.
+type Server struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// Serve starts serving /status, /healthz and /readyz on addr in the
+// background.
+func Serve(addr string, st *store.Store, board *Board) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/status", Handler(st, board))
+	mux.Handle("/healthz", ProbeHandler(board, false))
+	mux.Handle("/readyz", ProbeHandler(board, true))
+	s := &Server{
+		srv: &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		ln:  ln,
+	}
+	go func() {
+		_ = s.srv.Serve(ln)
+	}()
+	return s, nil
+}
+
+// Addr is the address the server listens on, with the port resolved.
+func (s *Server) Addr() string {
+	if s == nil {
+		return ""
+	}
+	return s.ln.Addr().String()
+}
+
+func (s *Server) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.srv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
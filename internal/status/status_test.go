@@ -0,0 +1,131 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/store"
+	"grid-trading/internal/strategy"
+)
+
+func TestServeReportsRuntimeAndGridOnceRunning(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	board := NewBoard()
+	srv, err := Serve("127.0.0.1:0", st, board)
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Close(ctx)
+	}()
+	get := func() (int, Response) {
+		t.Helper()
+		resp, err := http.Get("http://" + srv.Addr() + "/status")
+		if err != nil {
+			t.Fatalf("GET /status error = %v", err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("Content-Type = %q, want application/json", ct)
+		}
+		var body Response
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body error = %v", err)
+		}
+		return resp.StatusCode, body
+	}
+
+	if code, _ := get(); code != http.StatusServiceUnavailable {
+		t.Fatalf("status before any runtime status = %d, want 503", code)
+	}
+	if err := st.SaveRuntimeStatus(store.RuntimeStatus{Symbol: "BTCUSDT", State: "starting"}); err != nil {
+		t.Fatalf("SaveRuntimeStatus() error = %v", err)
+	}
+	if code, body := get(); code != http.StatusServiceUnavailable || body.Runtime == nil || body.Runtime.State != "starting" {
+		t.Fatalf("starting status = %d %+v, want 503 with the runtime status", code, body.Runtime)
+	}
+
+	if err := st.SaveRuntimeStatus(store.RuntimeStatus{Symbol: "BTCUSDT", State: "running"}); err != nil {
+		t.Fatalf("SaveRuntimeStatus() error = %v", err)
+	}
+	board.Publish(strategy.GridStatus{
+		OpenOrders: 6,
+		MinLevel:   -4,
+		MaxLevel:   2,
+		Anchor:     decimal.NewFromInt(100),
+		Ratio:      decimal.RequireFromString("1.01"),
+		SellRatio:  decimal.RequireFromString("1.02"),
+		LastPrice:  decimal.RequireFromString("99.5"),
+	})
+	code, body := get()
+	if code != http.StatusOK || body.Runtime == nil || body.Runtime.State != "running" {
+		t.Fatalf("running status = %d %+v, want 200", code, body.Runtime)
+	}
+	g := body.Grid
+	if g == nil || g.OpenOrders != 6 || g.MinLevel != -4 || g.MaxLevel != 2 || g.Anchor != "100" ||
+		g.Ratio != "1.01" || g.SellRatio != "1.02" || g.LastPrice != "99.5" || g.PublishedAt.IsZero() {
+		t.Fatalf("grid = %+v, want the published snapshot", g)
+	}
+}
+
+func TestProbesSeparateLivenessFromReadiness(t *testing.T) {
+	board := NewBoard()
+	board.SetProbeLimits(time.Minute, 30*time.Second)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	check := func(name string, ready bool, at time.Time, wantCode int, wantReason string) {
+		t.Helper()
+		code, resp := board.probe(at, ready)
+		if code != wantCode || resp.Reason != wantReason {
+			t.Fatalf("%s = %d %q, want %d %q", name, code, resp.Reason, wantCode, wantReason)
+		}
+	}
+
+	check("healthz while starting", false, now, http.StatusOK, "")
+	check("readyz while starting", true, now, http.StatusServiceUnavailable, "user stream not connected")
+
+	board.MarkLoop(now)
+	board.MarkTick(now)
+	board.SetStreamConnected(true)
+	check("readyz when connected", true, now.Add(10*time.Second), http.StatusOK, "")
+	check("readyz with an old tick", true, now.Add(31*time.Second), http.StatusServiceUnavailable, "last tick too old")
+	check("healthz with an old tick", false, now.Add(31*time.Second), http.StatusOK, "")
+
+	// Reconnecting: the stream is down and the loop sleeps through its backoff.
+	board.SetStreamConnected(false)
+	board.MarkLoop(now.Add(2 * time.Minute))
+	check("readyz while reconnecting", true, now.Add(20*time.Second), http.StatusServiceUnavailable, "user stream not connected")
+	check("healthz during backoff", false, now.Add(2*time.Minute+30*time.Second), http.StatusOK, "")
+	check("healthz once wedged", false, now.Add(3*time.Minute+time.Second), http.StatusServiceUnavailable, "runner loop stalled")
+
+	srv, err := Serve("127.0.0.1:0", nil, board)
+	if err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Close(ctx)
+	}()
+	resp, err := http.Get("http://" + srv.Addr() + "/readyz")
+	if err != nil {
+		t.Fatalf("GET /readyz error = %v", err)
+	}
+	defer resp.Body.Close()
+	var body ProbeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body error = %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || body.Status != "unavailable" || body.StreamConnected {
+		t.Fatalf("GET /readyz = %d %+v, want 503 unavailable", resp.StatusCode, body)
+	}
+}
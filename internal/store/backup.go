@@ -0,0 +1,133 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupVersion is the Backup schema version Export writes and Import
+// accepts.
+const BackupVersion = 1
+
+var ErrBackupVersion = errors.New("unsupported state backup version")
+
+// Backup is a whole state directory in one JSON document: grid state, the
+// open-order snapshot, runtime status and the trade ledger. Files missing
+// from the store are nil. Daily trade and order audit logs are not included.
+type Backup struct {
+	Version       int                 `json:"version"`
+	ExportedAt    time.Time           `json:"exported_at"`
+	GridState     *GridState          `json:"grid_state,omitempty"`
+	OpenOrders    *OpenOrdersSnapshot `json:"open_orders,omitempty"`
+	RuntimeStatus *RuntimeStatus      `json:"runtime_status,omitempty"`
+	TradeLedger   []TradeLedgerEntry  `json:"trade_ledger"`
+}
+
+// Export writes the store's state to w as a versioned Backup.
+func (s *Store) Export(w io.Writer) error {
+	b := Backup{Version: BackupVersion, ExportedAt: time.Now().UTC()}
+	state, ok, err := s.LoadGridState()
+	if err != nil {
+		return fmt.Errorf("read grid state: %w", err)
+	}
+	if ok {
+		b.GridState = &state
+	}
+	orders, ok, err := s.LoadOpenOrdersSnapshot()
+	if err != nil {
+		return fmt.Errorf("read open orders: %w", err)
+	}
+	if ok {
+		b.OpenOrders = &orders
+	}
+	runtime, ok, err := s.LoadRuntimeStatus()
+	if err != nil {
+		return fmt.Errorf("read runtime status: %w", err)
+	}
+	if ok {
+		b.RuntimeStatus = &runtime
+	}
+	s.mu.Lock()
+	if err := s.loadTradeLedgerLocked(); err != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("read trade ledger: %w", err)
+	}
+	b.TradeLedger = append([]TradeLedgerEntry{}, s.tradeLedgerEntries...)
+	s.mu.Unlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Import replaces the store's state with a Backup read from r. Every file is
+// staged in a temp dir under the store first, so a bad backup or failed
+// write leaves the store untouched; files the backup lacks are removed. The
+// caller must hold the instance lock.
+func (s *Store) Import(r io.Reader) error {
+	var b Backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return fmt.Errorf("decode state backup: %w", err)
+	}
+	if b.Version != BackupVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrBackupVersion, b.Version, BackupVersion)
+	}
+	tmp, err := os.MkdirTemp(s.root, ".import-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	staged := make(map[string]bool)
+	stage := func(name string, write func(path string) error) error {
+		if err := write(filepath.Join(tmp, name)); err != nil {
+			return fmt.Errorf("stage %s: %w", name, err)
+		}
+		staged[name] = true
+		return nil
+	}
+	if b.GridState != nil {
+		if err := stage("state.json", func(path string) error { return writeJSONAtomic(path, b.GridState) }); err != nil {
+			return err
+		}
+	}
+	if b.OpenOrders != nil {
+		if err := stage("open_orders.json", func(path string) error { return writeJSONAtomic(path, b.OpenOrders) }); err != nil {
+			return err
+		}
+	}
+	if b.RuntimeStatus != nil {
+		if err := stage("runtime_status.json", func(path string) error { return writeJSONAtomic(path, b.RuntimeStatus) }); err != nil {
+			return err
+		}
+	}
+	if len(b.TradeLedger) > 0 {
+		if err := stage("trade_ledger.jsonl", func(path string) error { return writeJSONLinesAtomic(path, b.TradeLedger) }); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// state.json goes last so a crash midway never pairs new state with old
+	// orders.
+	for _, name := range []string{"open_orders.json", "runtime_status.json", "trade_ledger.jsonl", "state.json"} {
+		dst := filepath.Join(s.root, name)
+		if !staged[name] {
+			if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(filepath.Join(tmp, name), dst); err != nil {
+			return err
+		}
+	}
+	s.pendingSnapshotID = ""
+	s.tradeLedgerLoaded = false
+	return fsyncDirBestEffort(s.root, s.root)
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"grid-trading/internal/core"
+)
+
+const (
+	ExportCSV  = "csv"
+	ExportJSON = "json"
+)
+
+type exportedOrder struct {
+	Level    int    `json:"level"`
+	Side     string `json:"side"`
+	Price    string `json:"price"`
+	Qty      string `json:"qty"`
+	ID       string `json:"id"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// ExportOpenOrders writes the persisted open-order snapshot to w as csv or
+// json, sorted by grid level. A missing snapshot exports no rows.
+func (s *Store) ExportOpenOrders(w io.Writer, format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != ExportCSV && format != ExportJSON {
+		return fmt.Errorf("unknown export format %q, want csv or json", format)
+	}
+	orders, _, err := s.LoadOpenOrders()
+	if err != nil {
+		return err
+	}
+	sorted := append([]core.Order(nil), orders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].GridIndex != sorted[j].GridIndex {
+			return sorted[i].GridIndex < sorted[j].GridIndex
+		}
+		if sorted[i].Side != sorted[j].Side {
+			return sorted[i].Side < sorted[j].Side
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	rows := make([]exportedOrder, 0, len(sorted))
+	for _, ord := range sorted {
+		rows = append(rows, exportedOrder{
+			Level:    ord.GridIndex,
+			Side:     string(ord.Side),
+			Price:    ord.Price.String(),
+			Qty:      ord.Qty.String(),
+			ID:       ord.ID,
+			ClientID: ord.ClientID,
+		})
+	}
+	if format == ExportJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"level", "side", "price", "qty", "id", "client_id"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write([]string{strconv.Itoa(row.Level), row.Side, row.Price, row.Qty, row.ID, row.ClientID}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportTradeLedger writes the recorded trade ledger to w as csv or json in
+// recording order. Entries written without ledger details export only their
+// key and time.
+func (s *Store) ExportTradeLedger(w io.Writer, format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != ExportCSV && format != ExportJSON {
+		return fmt.Errorf("unknown export format %q, want csv or json", format)
+	}
+	s.mu.Lock()
+	if err := s.loadTradeLedgerLocked(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	entries := make([]TradeLedgerEntry, len(s.tradeLedgerEntries))
+	copy(entries, s.tradeLedgerEntries)
+	s.mu.Unlock()
+	if format == ExportJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"seen_at", "key", "side", "price", "qty", "level", "origin", "pnl_delta", "fee", "fee_asset"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		level := ""
+		if e.Level != nil {
+			level = strconv.Itoa(*e.Level)
+		}
+		if err := cw.Write([]string{e.SeenAt.Format(time.RFC3339Nano), e.Key, e.Side, e.Price, e.Qty, level, e.Origin, e.PnLDelta, e.Fee, e.FeeAsset}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+)
+
+// DayPnL is one UTC day of fills. RealizedPnL is credited to the day of the
+// sell and is before fees. UnmatchedSellQty is sold base with no earlier
+// recorded buy, e.g. inventory held before the bot started; it adds no PnL.
+// FeeQuote is commission paid in the quote asset, with base commissions
+// valued at the fill price; OtherFees keeps commissions in any other asset
+// (e.g. BNB) unvalued, by asset.
+type DayPnL struct {
+	Day              string
+	Buys             int
+	Sells            int
+	BuyQty           decimal.Decimal
+	SellQty          decimal.Decimal
+	RealizedPnL      decimal.Decimal
+	UnmatchedSellQty decimal.Decimal
+	FeeQuote         decimal.Decimal
+	OtherFees        map[string]decimal.Decimal
+}
+
+// LoadTrades returns every recorded trade, oldest first.
+func (s *Store) LoadTrades() ([]core.Trade, error) {
+	return s.QueryTrades(time.Time{}, time.Time{})
+}
+
+// RealizedPnL pairs symbol's recorded buy and sell fills FIFO and returns the
+// realized quote PnL before fees; Fees returns what those fills paid. An
+// empty symbol includes every trade.
+func (s *Store) RealizedPnL(symbol string) (decimal.Decimal, error) {
+	trades, err := s.LoadTrades()
+	if err != nil {
+		return decimal.Zero, err
+	}
+	total := decimal.Zero
+	for _, day := range DailyPnL(trades, symbol) {
+		total = total.Add(day.RealizedPnL)
+	}
+	return total, nil
+}
+
+// Fees sums the commissions symbol's recorded fills paid, split as in
+// DayPnL: quote (base valued at the fill price) and other assets. An empty
+// symbol includes every trade.
+func (s *Store) Fees(symbol string) (decimal.Decimal, map[string]decimal.Decimal, error) {
+	trades, err := s.LoadTrades()
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+	quote := decimal.Zero
+	other := make(map[string]decimal.Decimal)
+	for _, day := range DailyPnL(trades, symbol) {
+		quote = quote.Add(day.FeeQuote)
+		for asset, fee := range day.OtherFees {
+			other[asset] = other[asset].Add(fee)
+		}
+	}
+	return quote, other, nil
+}
+
+// feeInQuote values trade's commission in the symbol's quote asset. It
+// reports false for a commission in any other asset.
+func feeInQuote(trade core.Trade) (decimal.Decimal, bool) {
+	switch {
+	case strings.HasSuffix(trade.Symbol, trade.FeeAsset):
+		return trade.Fee, true
+	case strings.HasPrefix(trade.Symbol, trade.FeeAsset):
+		return trade.Fee.Mul(trade.Price), true
+	}
+	return decimal.Zero, false
+}
+
+// FIFOBook pairs sells with the oldest open buys. Both the report and the
+// live run summary value realized PnL through it so the two agree.
+type FIFOBook struct {
+	lots []fifoLot
+}
+
+type fifoLot struct {
+	price decimal.Decimal
+	qty   decimal.Decimal
+}
+
+// Apply adds a buy as an open lot or matches a sell against the open lots.
+// It returns the realized quote PnL and the sold qty no lot covered.
+func (b *FIFOBook) Apply(trade core.Trade) (realized, unmatched decimal.Decimal) {
+	realized, unmatched = decimal.Zero, decimal.Zero
+	if trade.Qty.Cmp(decimal.Zero) <= 0 {
+		return realized, unmatched
+	}
+	switch trade.Side {
+	case core.Buy:
+		b.lots = append(b.lots, fifoLot{price: trade.Price, qty: trade.Qty})
+	case core.Sell:
+		remaining := trade.Qty
+		for remaining.Cmp(decimal.Zero) > 0 && len(b.lots) > 0 {
+			matched := decimal.Min(remaining, b.lots[0].qty)
+			realized = realized.Add(trade.Price.Sub(b.lots[0].price).Mul(matched))
+			remaining = remaining.Sub(matched)
+			b.lots[0].qty = b.lots[0].qty.Sub(matched)
+			if b.lots[0].qty.Cmp(decimal.Zero) <= 0 {
+				b.lots = b.lots[1:]
+			}
+		}
+		unmatched = remaining
+	}
+	return realized, unmatched
+}
+
+// DailyPnL pairs trades FIFO, in time order, and sums them per UTC day. An
+// empty symbol includes every trade.
+func DailyPnL(trades []core.Trade, symbol string) []DayPnL {
+	var book FIFOBook
+	var days []DayPnL
+	for _, trade := range trades {
+		if symbol != "" && trade.Symbol != symbol {
+			continue
+		}
+		if trade.Qty.Cmp(decimal.Zero) <= 0 {
+			continue
+		}
+		day := trade.Time.UTC().Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1].Day != day {
+			days = append(days, DayPnL{
+				Day:              day,
+				BuyQty:           decimal.Zero,
+				SellQty:          decimal.Zero,
+				RealizedPnL:      decimal.Zero,
+				UnmatchedSellQty: decimal.Zero,
+				FeeQuote:         decimal.Zero,
+			})
+		}
+		d := &days[len(days)-1]
+		if trade.Fee.Sign() > 0 && trade.FeeAsset != "" {
+			if fee, ok := feeInQuote(trade); ok {
+				d.FeeQuote = d.FeeQuote.Add(fee)
+			} else {
+				if d.OtherFees == nil {
+					d.OtherFees = make(map[string]decimal.Decimal)
+				}
+				d.OtherFees[trade.FeeAsset] = d.OtherFees[trade.FeeAsset].Add(trade.Fee)
+			}
+		}
+		realized, unmatched := book.Apply(trade)
+		switch trade.Side {
+		case core.Buy:
+			d.Buys++
+			d.BuyQty = d.BuyQty.Add(trade.Qty)
+		case core.Sell:
+			d.Sells++
+			d.SellQty = d.SellQty.Add(trade.Qty)
+			d.RealizedPnL = d.RealizedPnL.Add(realized)
+			d.UnmatchedSellQty = d.UnmatchedSellQty.Add(unmatched)
+		}
+	}
+	return days
+}
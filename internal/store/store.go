@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,9 +26,15 @@ type GridState struct {
 	Anchor             decimal.Decimal `json:"anchor"`
 	Low                decimal.Decimal `json:"low"`
 	StopPrice          decimal.Decimal `json:"stop_price"`
+	FloorPrice         decimal.Decimal `json:"floor_price,omitempty"`
+	TakeProfitPrice    decimal.Decimal `json:"take_profit_price,omitempty"`
+	BuysHalted         bool            `json:"buys_halted,omitempty"`
 	Ratio              decimal.Decimal `json:"ratio"`
 	BaseRatio          decimal.Decimal `json:"base_ratio,omitempty"`
 	SellRatio          decimal.Decimal `json:"sell_ratio,omitempty"`
+	SellSpacingFactor  decimal.Decimal `json:"sell_spacing_factor,omitempty"`
+	BalancedLevels     int             `json:"balanced_levels,omitempty"`
+	Bias               string          `json:"bias,omitempty"`
 	Levels             int             `json:"levels"`
 	MinLevel           int             `json:"min_level"`
 	MaxLevel           int             `json:"max_level"`
@@ -38,31 +45,84 @@ type GridState struct {
 	Stopped            bool            `json:"stopped"`
 	LastDownShiftPrice decimal.Decimal `json:"last_down_shift_price,omitempty"`
 	LastDownShiftAt    time.Time       `json:"last_down_shift_at,omitempty"`
+	LastRebuildAt      time.Time       `json:"last_rebuild_at,omitempty"`
+	LastShiftUpAt      time.Time       `json:"last_shift_up_at,omitempty"`
+	ShiftUpDeferred    bool            `json:"shift_up_deferred,omitempty"`
+	PendingWindow      *WindowChange   `json:"pending_window_change,omitempty"`
+	HeldCounters       []HeldCounter   `json:"held_counters,omitempty"`
 	UpdatedAt          time.Time       `json:"updated_at"`
 }
 
+// HeldCounter is a counter order waiting out the minimum hold time before it
+// is placed at Level.
+type HeldCounter struct {
+	Level int       `json:"level"`
+	Side  core.Side `json:"side"`
+	Until time.Time `json:"until"`
+}
+
+// WindowChange is the write-ahead intent for a grid window move. It is saved
+// before the window is mutated and cleared once the move's orders are placed
+// and the resulting state is persisted.
+type WindowChange struct {
+	Kind      string    `json:"kind"`
+	FromMin   int       `json:"from_min"`
+	FromMax   int       `json:"from_max"`
+	ToMin     int       `json:"to_min"`
+	ToMax     int       `json:"to_max"`
+	StartedAt time.Time `json:"started_at"`
+}
+
 type OpenOrdersSnapshot struct {
 	SnapshotID string       `json:"snapshot_id,omitempty"`
 	Orders     []core.Order `json:"orders"`
 	UpdatedAt  time.Time    `json:"updated_at,omitempty"`
 }
 
+// TradeLedgerEntry marks a trade as applied. With ledger details on, it
+// also carries the fill, the grid level and origin of its order, and the
+// realized PnL it added.
 type TradeLedgerEntry struct {
-	Key    string    `json:"key"`
-	SeenAt time.Time `json:"seen_at"`
+	Key      string    `json:"key"`
+	SeenAt   time.Time `json:"seen_at"`
+	Side     string    `json:"side,omitempty"`
+	Price    string    `json:"price,omitempty"`
+	Qty      string    `json:"qty,omitempty"`
+	Level    *int      `json:"level,omitempty"`
+	Origin   string    `json:"origin,omitempty"`
+	PnLDelta string    `json:"pnl_delta,omitempty"`
+	Fee      string    `json:"fee,omitempty"`
+	FeeAsset string    `json:"fee_asset,omitempty"`
 }
 
 type RuntimeStatus struct {
-	Mode              string     `json:"mode"`
-	Symbol            string     `json:"symbol"`
-	InstanceID        string     `json:"instance_id"`
-	PID               int        `json:"pid"`
-	State             string     `json:"state"`
-	StartedAt         time.Time  `json:"started_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
-	LastError         string     `json:"last_error,omitempty"`
-	ReconnectAttempts int        `json:"reconnect_attempts,omitempty"`
-	DisconnectedAt    *time.Time `json:"disconnected_at,omitempty"`
+	Mode              string            `json:"mode"`
+	Symbol            string            `json:"symbol"`
+	InstanceID        string            `json:"instance_id"`
+	PID               int               `json:"pid"`
+	State             string            `json:"state"`
+	StartedAt         time.Time         `json:"started_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	LastError         string            `json:"last_error,omitempty"`
+	ReconnectAttempts int               `json:"reconnect_attempts,omitempty"`
+	DisconnectedAt    *time.Time        `json:"disconnected_at,omitempty"`
+	RunSummary        map[string]string `json:"run_summary,omitempty"`
+	PeakEquity        decimal.Decimal   `json:"peak_equity,omitempty"`
+}
+
+type OrderAuditEntry struct {
+	RecordedAt   time.Time       `json:"recorded_at"`
+	Symbol       string          `json:"symbol"`
+	Side         string          `json:"side"`
+	Type         string          `json:"type"`
+	Price        decimal.Decimal `json:"price"`
+	Qty          decimal.Decimal `json:"qty"`
+	ClientID     string          `json:"client_id,omitempty"`
+	GridIndex    int             `json:"grid_index"`
+	OrderID      string          `json:"order_id,omitempty"`
+	Status       string          `json:"status,omitempty"`
+	ExchangeTime time.Time       `json:"exchange_time,omitempty"`
+	Error        string          `json:"error,omitempty"`
 }
 
 type Persister interface {
@@ -71,6 +131,12 @@ type Persister interface {
 	AppendTrade(trade core.Trade) error
 }
 
+// OrderAuditor is implemented by persisters that keep an order placement audit
+// trail, separate from the trade ledger.
+type OrderAuditor interface {
+	AppendOrderAudit(entry OrderAuditEntry) error
+}
+
 type Store struct {
 	root               string
 	mu                 sync.Mutex
@@ -200,14 +266,109 @@ func (s *Store) AppendTrade(trade core.Trade) error {
 	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.appendDailyJSONLine("trades", trade.Time, trade)
+}
 
+// QueryTrades returns trades with from <= Time < to, oldest first. A zero
+// from or to leaves that side open.
+func (s *Store) QueryTrades(from, to time.Time) ([]core.Trade, error) {
+	trades := make([]core.Trade, 0)
+	err := s.EachTrade(from, to, func(trade core.Trade) error {
+		trades = append(trades, trade)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time.Before(trades[j].Time) })
+	return trades, nil
+}
+
+// EachTrade streams trades in [from, to) to fn, one daily file and one line
+// at a time, skipping files whose date falls outside the range. Files are
+// visited by date and lines in append order; fn's error stops the scan.
+func (s *Store) EachTrade(from, to time.Time, fn func(core.Trade) error) error {
 	dir := filepath.Join(s.root, "trades")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	fromDay, toDay := "", ""
+	if !from.IsZero() {
+		fromDay = from.UTC().Format("2006-01-02")
+	}
+	if !to.IsZero() {
+		toDay = to.UTC().Format("2006-01-02")
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".jsonl") {
+			continue
+		}
+		day := strings.TrimSuffix(name, ".jsonl")
+		if (fromDay != "" && day < fromDay) || (toDay != "" && day > toDay) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := eachTradeInFile(filepath.Join(dir, name), from, to, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eachTradeInFile(path string, from, to time.Time, fn func(core.Trade) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 2*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var trade core.Trade
+		if err := json.Unmarshal(line, &trade); err != nil {
+			continue
+		}
+		if (!from.IsZero() && trade.Time.Before(from)) || (!to.IsZero() && !trade.Time.Before(to)) {
+			continue
+		}
+		if err := fn(trade); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Store) AppendOrderAudit(entry OrderAuditEntry) error {
+	if entry.RecordedAt.IsZero() {
+		entry.RecordedAt = time.Now().UTC()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.appendDailyJSONLine("order_audit", entry.RecordedAt, entry)
+}
+
+func (s *Store) appendDailyJSONLine(subdir string, at time.Time, v any) error {
+	dir := filepath.Join(s.root, subdir)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
-	date := trade.Time.UTC().Format("2006-01-02")
+	date := at.UTC().Format("2006-01-02")
 	path := filepath.Join(dir, date+".jsonl")
-	data, err := json.Marshal(trade)
+	data, err := json.Marshal(v)
 	if err != nil {
 		return err
 	}
@@ -237,13 +398,20 @@ func (s *Store) HasTradeLedgerKey(key string) (bool, error) {
 }
 
 func (s *Store) RecordTradeLedgerKey(key string, seenAt time.Time) error {
-	key = strings.TrimSpace(key)
+	return s.RecordTradeLedger(TradeLedgerEntry{Key: key, SeenAt: seenAt})
+}
+
+// RecordTradeLedger appends entry unless its key is already recorded.
+func (s *Store) RecordTradeLedger(entry TradeLedgerEntry) error {
+	key := strings.TrimSpace(entry.Key)
 	if key == "" {
 		return nil
 	}
-	if seenAt.IsZero() {
-		seenAt = time.Now().UTC()
+	entry.Key = key
+	if entry.SeenAt.IsZero() {
+		entry.SeenAt = time.Now().UTC()
 	}
+	entry.SeenAt = entry.SeenAt.UTC()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if err := s.loadTradeLedgerLocked(); err != nil {
@@ -253,10 +421,6 @@ func (s *Store) RecordTradeLedgerKey(key string, seenAt time.Time) error {
 		return nil
 	}
 
-	entry := TradeLedgerEntry{
-		Key:    key,
-		SeenAt: seenAt.UTC(),
-	}
 	line, err := json.Marshal(entry)
 	if err != nil {
 		return err
@@ -1,10 +1,13 @@
 package store
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -265,3 +268,240 @@ func TestStoreTradeLedgerTrimByCount(t *testing.T) {
 		t.Fatalf("tradeLedgerEntries len after restart = %d, want %d", len(s2.tradeLedgerEntries), wantEntries)
 	}
 }
+
+func TestStoreQueryTradesReturnsRangeInOrder(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	// Six trades 12h apart span three daily files.
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		if err := s.AppendTrade(core.Trade{
+			OrderID: fmt.Sprintf("o-%d", i),
+			Side:    core.Buy,
+			Price:   decimal.NewFromInt(100),
+			Qty:     decimal.NewFromInt(1),
+			Time:    t0.Add(time.Duration(i) * 12 * time.Hour),
+		}); err != nil {
+			t.Fatalf("AppendTrade(%d) error = %v", i, err)
+		}
+	}
+
+	trades, err := s.QueryTrades(t0.Add(12*time.Hour), t0.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("QueryTrades() error = %v", err)
+	}
+	var got []string
+	for _, trade := range trades {
+		got = append(got, trade.OrderID)
+	}
+	if want := "o-1,o-2,o-3"; strings.Join(got, ",") != want {
+		t.Fatalf("QueryTrades() = %v, want %s", got, want)
+	}
+
+	all, err := s.QueryTrades(time.Time{}, time.Time{})
+	if err != nil || len(all) != 6 {
+		t.Fatalf("open-range QueryTrades() = %d trades, err %v, want 6", len(all), err)
+	}
+}
+
+func TestStoreExportOpenOrdersSortedByLevel(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	orders := []core.Order{
+		{ID: "o-3", ClientID: "c-3", Side: core.Sell, Price: decimal.RequireFromString("121"), Qty: decimal.NewFromInt(1), GridIndex: 2},
+		{ID: "o-1", ClientID: "c-1", Side: core.Buy, Price: decimal.RequireFromString("90.9"), Qty: decimal.NewFromInt(1), GridIndex: -1},
+		{ID: "o-2", Side: core.Sell, Price: decimal.RequireFromString("110"), Qty: decimal.RequireFromString("0.5"), GridIndex: 1},
+	}
+	if err := s.SaveOpenOrders(orders); err != nil {
+		t.Fatalf("SaveOpenOrders() error = %v", err)
+	}
+
+	var csvOut bytes.Buffer
+	if err := s.ExportOpenOrders(&csvOut, "csv"); err != nil {
+		t.Fatalf("ExportOpenOrders(csv) error = %v", err)
+	}
+	wantCSV := "level,side,price,qty,id,client_id\n" +
+		"-1,BUY,90.9,1,o-1,c-1\n" +
+		"1,SELL,110,0.5,o-2,\n" +
+		"2,SELL,121,1,o-3,c-3\n"
+	if csvOut.String() != wantCSV {
+		t.Fatalf("csv export = %q, want %q", csvOut.String(), wantCSV)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := s.ExportOpenOrders(&jsonOut, "JSON"); err != nil {
+		t.Fatalf("ExportOpenOrders(json) error = %v", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(jsonOut.Bytes(), &rows); err != nil {
+		t.Fatalf("json export decode error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("json rows = %d, want 3", len(rows))
+	}
+	wantIDs := []string{"o-1", "o-2", "o-3"}
+	for i, row := range rows {
+		if row["id"] != wantIDs[i] {
+			t.Fatalf("json row %d id = %v, want %s", i, row["id"], wantIDs[i])
+		}
+	}
+	if rows[0]["level"] != float64(-1) || rows[0]["side"] != "BUY" || rows[0]["price"] != "90.9" || rows[0]["qty"] != "1" {
+		t.Fatalf("json row 0 = %v", rows[0])
+	}
+
+	if err := s.ExportOpenOrders(&bytes.Buffer{}, "xml"); err == nil {
+		t.Fatalf("ExportOpenOrders(xml) error = nil, want error")
+	}
+}
+
+func TestStoreExportImportRoundTrip(t *testing.T) {
+	src, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := src.SaveGridState(GridState{Symbol: "BTCUSDT", Anchor: decimal.NewFromInt(100), MinLevel: -3, MaxLevel: 2}); err != nil {
+		t.Fatalf("SaveGridState() error = %v", err)
+	}
+	if err := src.SaveOpenOrders([]core.Order{{ID: "1", Side: core.Buy, Price: decimal.NewFromInt(90), Qty: decimal.NewFromInt(1), GridIndex: -1}}); err != nil {
+		t.Fatalf("SaveOpenOrders() error = %v", err)
+	}
+	if err := src.RecordTradeLedgerKey("trade-1", time.Now()); err != nil {
+		t.Fatalf("RecordTradeLedgerKey() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	dstDir := t.TempDir()
+	dst, err := New(dstDir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	// A stale runtime status absent from the backup is removed.
+	if err := dst.SaveRuntimeStatus(RuntimeStatus{State: "running"}); err != nil {
+		t.Fatalf("SaveRuntimeStatus() error = %v", err)
+	}
+	if err := dst.Import(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	state, ok, err := dst.LoadGridState()
+	if err != nil || !ok || !state.Anchor.Equal(decimal.NewFromInt(100)) || state.MinLevel != -3 || state.MaxLevel != 2 {
+		t.Fatalf("LoadGridState() = %+v, %v, %v; want the exported state", state, ok, err)
+	}
+	orders, ok, err := dst.LoadOpenOrders()
+	if err != nil || !ok || len(orders) != 1 || orders[0].ID != "1" {
+		t.Fatalf("LoadOpenOrders() = %+v, %v, %v; want order 1", orders, ok, err)
+	}
+	if _, ok, _ := dst.LoadRuntimeStatus(); ok {
+		t.Fatalf("runtime status kept, want it removed")
+	}
+	if seen, err := dst.HasTradeLedgerKey("trade-1"); err != nil || !seen {
+		t.Fatalf("HasTradeLedgerKey(trade-1) = %v, %v; want true", seen, err)
+	}
+	entries, err := os.ReadDir(dstDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".import-") {
+			t.Fatalf("staging dir %s left behind", e.Name())
+		}
+	}
+}
+
+func TestStoreImportRejectsUnknownVersion(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := s.SaveGridState(GridState{Symbol: "BTCUSDT", Anchor: decimal.NewFromInt(100)}); err != nil {
+		t.Fatalf("SaveGridState() error = %v", err)
+	}
+	err = s.Import(strings.NewReader(`{"version": 99, "grid_state": {"symbol": "ETHUSDT"}}`))
+	if !errors.Is(err, ErrBackupVersion) || !strings.Contains(err.Error(), "got 99, want 1") {
+		t.Fatalf("Import() error = %v, want ErrBackupVersion naming both versions", err)
+	}
+	if state, _, _ := s.LoadGridState(); state.Symbol != "BTCUSDT" {
+		t.Fatalf("state symbol = %q after rejected import, want BTCUSDT", state.Symbol)
+	}
+}
+
+func TestStoreRealizedPnLPairsFillsFIFO(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	day1 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	fills := []core.Trade{
+		{Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Time: day1},
+		{Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(90), Qty: decimal.NewFromInt(1), Time: day1.Add(time.Hour)},
+		{Symbol: "ETHUSDT", Side: core.Sell, Price: decimal.NewFromInt(5), Qty: decimal.NewFromInt(1), Time: day1.Add(2 * time.Hour)},
+		// Closes the 100 lot and half the 90 lot: 10 + 0.5*20.
+		{Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(110), Qty: decimal.RequireFromString("1.5"), Time: day2},
+		// 0.5 left at 90, the other 0.5 has no recorded buy.
+		{Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Time: day2.Add(time.Hour)},
+	}
+	for _, f := range fills {
+		if err := s.AppendTrade(f); err != nil {
+			t.Fatalf("AppendTrade() error = %v", err)
+		}
+	}
+	pnl, err := s.RealizedPnL("BTCUSDT")
+	if err != nil {
+		t.Fatalf("RealizedPnL() error = %v", err)
+	}
+	if !pnl.Equal(decimal.NewFromInt(25)) {
+		t.Fatalf("RealizedPnL() = %s, want 25", pnl)
+	}
+	trades, err := s.LoadTrades()
+	if err != nil || len(trades) != len(fills) {
+		t.Fatalf("LoadTrades() = %d trades, %v; want %d", len(trades), err, len(fills))
+	}
+	days := DailyPnL(trades, "BTCUSDT")
+	if len(days) != 2 {
+		t.Fatalf("days = %+v, want 2", days)
+	}
+	if d := days[0]; d.Day != "2024-03-01" || d.Buys != 2 || d.Sells != 0 || !d.RealizedPnL.IsZero() {
+		t.Fatalf("day 1 = %+v, want 2 buys and no PnL", d)
+	}
+	if d := days[1]; d.Sells != 2 || !d.RealizedPnL.Equal(decimal.NewFromInt(25)) || !d.UnmatchedSellQty.Equal(decimal.RequireFromString("0.5")) {
+		t.Fatalf("day 2 = %+v, want 2 sells, PnL 25 and 0.5 unmatched", d)
+	}
+}
+
+func TestStoreFeesValuesCommissionsInQuote(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	fills := []core.Trade{
+		// A base commission is valued at the fill price: 0.001 * 100.
+		{Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Time: at, Fee: decimal.RequireFromString("0.001"), FeeAsset: "BTC"},
+		{Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(110), Qty: decimal.NewFromInt(1), Time: at.Add(time.Hour), Fee: decimal.RequireFromString("0.11"), FeeAsset: "USDT"},
+		{Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1), Time: at.Add(2 * time.Hour), Fee: decimal.RequireFromString("0.0002"), FeeAsset: "BNB"},
+		{Symbol: "ETHUSDT", Side: core.Buy, Price: decimal.NewFromInt(5), Qty: decimal.NewFromInt(1), Time: at, Fee: decimal.NewFromInt(1), FeeAsset: "USDT"},
+	}
+	for _, f := range fills {
+		if err := s.AppendTrade(f); err != nil {
+			t.Fatalf("AppendTrade() error = %v", err)
+		}
+	}
+	quote, other, err := s.Fees("BTCUSDT")
+	if err != nil {
+		t.Fatalf("Fees() error = %v", err)
+	}
+	if !quote.Equal(decimal.RequireFromString("0.21")) {
+		t.Fatalf("Fees() quote = %s, want 0.21", quote)
+	}
+	if len(other) != 1 || !other["BNB"].Equal(decimal.RequireFromString("0.0002")) {
+		t.Fatalf("Fees() other = %v, want 0.0002 BNB", other)
+	}
+}
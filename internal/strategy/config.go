@@ -0,0 +1,93 @@
+package strategy
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+)
+
+// ApplyGridConfig applies the optional grid settings on top of the values
+// passed to NewSpotDual. Unset pointer fields keep the strategy defaults.
+func (s *SpotDual) ApplyGridConfig(grid config.GridConfig) {
+	s.SetBias(string(grid.Bias))
+	s.SetExternalAmendmentAction(string(grid.ExternalAmendment))
+	s.SetLevelMapping(string(grid.LevelMapping))
+	s.SetFloorPrice(grid.FloorPrice.Decimal)
+	s.SetTakeProfitPrice(grid.TakeProfitPrice.Decimal)
+	s.SetRebuildMinInterval(time.Duration(grid.RebuildMinIntervalSec) * time.Second)
+	s.SetMinHold(time.Duration(grid.MinHoldSec) * time.Second)
+	s.SetMaxResting(grid.MaxRestingBuys, grid.MaxRestingSells)
+	s.SetMaxOrderQty(grid.MaxOrderQty.Decimal)
+	var buyQty, sellQty decimal.Decimal
+	if grid.BuyQty != nil {
+		buyQty = grid.BuyQty.Decimal
+	}
+	if grid.SellQty != nil {
+		sellQty = grid.SellQty.Decimal
+	}
+	s.SetSideQty(buyQty, sellQty)
+	s.SetBuyQtyGrowth(grid.BuyQtyGrowth.Decimal)
+	s.SetSnapAnchor(grid.SnapAnchorToTick)
+	s.SetAnchorPrice(grid.AnchorPrice.Decimal)
+	if grid.SellInventoryGuard != nil {
+		s.SetSellInventoryGuard(*grid.SellInventoryGuard)
+	}
+	s.SetMakerRetry(grid.MakerRetry)
+	s.SetPostOnly(grid.PostOnly)
+	s.SetSellRatio(grid.SellRatio.Decimal)
+	s.SetSweepDustOnStop(grid.SweepDustOnStop)
+	if grid.BootstrapRetrySec != nil {
+		s.SetBootstrapRetry(time.Duration(*grid.BootstrapRetrySec) * time.Second)
+	}
+	s.SetBootstrapReanchor(time.Duration(grid.BootstrapSettleMs)*time.Millisecond, grid.BootstrapReanchorPct.Decimal)
+	s.SetShiftUpCooldown(time.Duration(grid.ShiftUpCooldownSec) * time.Second)
+	if grid.OnStop == config.StopMarketSell {
+		slippage := decimal.Zero
+		if grid.OnStopMaxSlippagePct != nil {
+			slippage = grid.OnStopMaxSlippagePct.Decimal
+		}
+		s.SetSellOnStop(true, slippage)
+	}
+	if grid.BootstrapMarketBuy != nil {
+		s.SetBootstrapMarketBuy(*grid.BootstrapMarketBuy)
+	}
+	if grid.RatioStep != nil {
+		s.SetRatioStep(grid.RatioStep.Decimal)
+	}
+	s.SetRatioQtyMultiple(grid.RatioQtyMultiple.Decimal)
+	if grid.OversizedFillTolerancePct != nil {
+		s.SetOversizedFillTolerance(grid.OversizedFillTolerancePct.Decimal)
+	}
+	if grid.SuspiciousSnapshotPct != nil {
+		s.SetSuspiciousSnapshotPct(grid.SuspiciousSnapshotPct.Decimal)
+	}
+	if ias := grid.InventoryAdaptiveSell; ias.Enabled {
+		s.SetInventoryAdaptiveSell(InventoryAdaptiveSell{
+			Enabled:     true,
+			Sensitivity: ias.Sensitivity.Decimal,
+			MinFactor:   ias.MinFactor.Decimal,
+			MaxFactor:   ias.MaxFactor.Decimal,
+		})
+	}
+	if ab := grid.AutoBalance; ab.Enabled {
+		s.SetAutoBalance(true, ab.TolerancePct.Decimal)
+	}
+	if ttl := grid.OrderTTL; ttl.Enabled {
+		s.SetOrderTTL(time.Duration(ttl.TTLSec)*time.Second, time.Duration(ttl.StaggerSec)*time.Second)
+	}
+	if rc := grid.Recenter; rc.Enabled {
+		s.SetRecenter(rc.DriftMultiple.Decimal, time.Duration(rc.SustainSec)*time.Second)
+	}
+	if as := grid.AdaptiveShift; as.Enabled {
+		s.SetAdaptiveShift(time.Duration(as.WindowSec)*time.Second, as.MaxShifts)
+	}
+	if vp := grid.VolatilityPause; vp.Enabled {
+		s.SetVolatilityPause(
+			time.Duration(vp.WindowSec)*time.Second,
+			vp.ThresholdPct.Decimal,
+			time.Duration(vp.CooldownSec)*time.Second,
+		)
+	}
+}
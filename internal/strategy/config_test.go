@@ -0,0 +1,151 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+	"grid-trading/internal/core"
+)
+
+func ratioStepPtr(v string) *config.Decimal {
+	return &config.Decimal{Decimal: decimal.RequireFromString(v)}
+}
+
+func TestApplyGridConfigKeepsDefaultRatioStepWhenUnset(t *testing.T) {
+	strat := NewSpotDual(
+		"BTCUSDT",
+		decimal.Zero,
+		decimal.RequireFromString("1.01"),
+		20,
+		10,
+		decimal.RequireFromString("0.001"),
+		1,
+		core.Rules{},
+		nil,
+		nil,
+	)
+	want := strat.RatioStep
+	wantQtyMultiple := strat.RatioQtyMultiple
+	cfg := config.Config{
+		Grid: config.GridConfig{
+			SellRatio: config.Decimal{Decimal: decimal.RequireFromString("1.01")},
+		},
+	}
+
+	strat.ApplyGridConfig(cfg.Grid)
+
+	if !strat.RatioStep.Equal(want) {
+		t.Fatalf("ratio_step changed unexpectedly: got=%s want=%s", strat.RatioStep.String(), want.String())
+	}
+	if !strat.RatioQtyMultiple.Equal(wantQtyMultiple) {
+		t.Fatalf("ratio_qty_multiple changed unexpectedly: got=%s want=%s", strat.RatioQtyMultiple.String(), wantQtyMultiple.String())
+	}
+}
+
+func TestApplyGridConfigAllowsZeroRatioStep(t *testing.T) {
+	strat := NewSpotDual(
+		"BTCUSDT",
+		decimal.Zero,
+		decimal.RequireFromString("1.01"),
+		20,
+		10,
+		decimal.RequireFromString("0.001"),
+		1,
+		core.Rules{},
+		nil,
+		nil,
+	)
+	cfg := config.Config{
+		Grid: config.GridConfig{
+			SellRatio: config.Decimal{Decimal: decimal.RequireFromString("1.01")},
+			RatioStep: ratioStepPtr("0"),
+		},
+	}
+
+	strat.ApplyGridConfig(cfg.Grid)
+
+	if !strat.RatioStep.Equal(decimal.Zero) {
+		t.Fatalf("ratio_step = %s, want 0", strat.RatioStep.String())
+	}
+}
+
+func TestApplyGridConfigSetsRatioQtyMultiple(t *testing.T) {
+	strat := NewSpotDual(
+		"BTCUSDT",
+		decimal.Zero,
+		decimal.RequireFromString("1.01"),
+		20,
+		10,
+		decimal.RequireFromString("0.001"),
+		1,
+		core.Rules{},
+		nil,
+		nil,
+	)
+	cfg := config.Config{
+		Grid: config.GridConfig{
+			SellRatio:        config.Decimal{Decimal: decimal.RequireFromString("1.01")},
+			RatioQtyMultiple: config.Decimal{Decimal: decimal.RequireFromString("1.2")},
+		},
+	}
+
+	strat.ApplyGridConfig(cfg.Grid)
+
+	if !strat.RatioQtyMultiple.Equal(decimal.RequireFromString("1.2")) {
+		t.Fatalf("ratio_qty_multiple = %s, want 1.2", strat.RatioQtyMultiple.String())
+	}
+}
+
+func TestApplyGridConfigWiresSettingsMovedFromGridbot(t *testing.T) {
+	strat := NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.01"), 20, 10, decimal.RequireFromString("0.001"), 1, core.Rules{}, nil, nil)
+	off := false
+	retry := int64(30)
+	grid := config.GridConfig{
+		Bias:                  config.GridBiasSellRally,
+		ExternalAmendment:     config.AmendmentReplace,
+		FloorPrice:            config.Decimal{Decimal: decimal.NewFromInt(80)},
+		RebuildMinIntervalSec: 60,
+		MinHoldSec:            120,
+		SnapAnchorToTick:      true,
+		SellInventoryGuard:    &off,
+		SellRatio:             config.Decimal{Decimal: decimal.RequireFromString("1.02")},
+		SweepDustOnStop:       true,
+		BootstrapRetrySec:     &retry,
+		OnStop:                config.StopMarketSell,
+		OnStopMaxSlippagePct:  ratioStepPtr("2"),
+		BootstrapMarketBuy:    &off,
+		AutoBalance:           config.AutoBalanceConfig{Enabled: true, TolerancePct: config.Decimal{Decimal: decimal.NewFromInt(15)}},
+		OrderTTL:              config.OrderTTLConfig{Enabled: true, TTLSec: 3600, StaggerSec: 60},
+		VolatilityPause:       config.VolatilityPauseConfig{Enabled: true, WindowSec: 300, ThresholdPct: config.Decimal{Decimal: decimal.NewFromInt(5)}, CooldownSec: 600},
+	}
+
+	strat.ApplyGridConfig(grid)
+
+	switch {
+	case strat.Bias != BiasSellRally:
+		t.Fatalf("bias = %q", strat.Bias)
+	case strat.amendmentAction != AmendmentReplace:
+		t.Fatalf("amendment action = %q", strat.amendmentAction)
+	case !strat.FloorPrice.Equal(decimal.NewFromInt(80)):
+		t.Fatalf("floor price = %s", strat.FloorPrice)
+	case strat.rebuildMinInterval != time.Minute || strat.minHold != 2*time.Minute:
+		t.Fatalf("rebuild interval = %s min hold = %s", strat.rebuildMinInterval, strat.minHold)
+	case !strat.snapAnchor || strat.sellInventoryGuard:
+		t.Fatalf("snap anchor = %v sell inventory guard = %v", strat.snapAnchor, strat.sellInventoryGuard)
+	case !strat.SellRatio.Equal(decimal.RequireFromString("1.02")):
+		t.Fatalf("sell ratio = %s", strat.SellRatio)
+	case !strat.sweepDustOnStop || strat.bootstrapRetry != 30*time.Second:
+		t.Fatalf("sweep dust = %v bootstrap retry = %s", strat.sweepDustOnStop, strat.bootstrapRetry)
+	case !strat.sellOnStop || !strat.sellOnStopSlippage.Equal(decimal.NewFromInt(2)) || !strat.noBootstrapBuy:
+		t.Fatalf("sell on stop = %v slippage = %s no bootstrap buy = %v", strat.sellOnStop, strat.sellOnStopSlippage, strat.noBootstrapBuy)
+	case !strat.autoBalance || !strat.autoBalanceTolerance.Equal(decimal.NewFromInt(15)):
+		t.Fatalf("auto balance = %v tolerance = %s", strat.autoBalance, strat.autoBalanceTolerance)
+	case strat.orderTTL != time.Hour || strat.orderTTLStagger != time.Minute:
+		t.Fatalf("order ttl = %s stagger = %s", strat.orderTTL, strat.orderTTLStagger)
+	case strat.volatilityWindow != 5*time.Minute || !strat.volatilityThreshold.Equal(decimal.NewFromInt(5)) || strat.volatilityCooldown != 10*time.Minute:
+		t.Fatalf("volatility pause = %s %s %s", strat.volatilityWindow, strat.volatilityThreshold, strat.volatilityCooldown)
+	}
+}
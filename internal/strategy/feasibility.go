@@ -0,0 +1,340 @@
+package strategy
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+)
+
+type GridPlanLevel struct {
+	Index int
+	Side  core.Side
+	Price decimal.Decimal
+	Qty   decimal.Decimal
+	// RawQty is the configured qty before exchange-rule normalization;
+	// BumpedTo names the rule (min_qty or min_notional) that raised it.
+	RawQty   decimal.Decimal
+	BumpedTo string
+}
+
+func (l GridPlanLevel) Notional() decimal.Decimal {
+	return l.Price.Mul(l.Qty)
+}
+
+// QtyChangePct is how far normalization moved the qty, relative to RawQty.
+func (l GridPlanLevel) QtyChangePct() decimal.Decimal {
+	if l.RawQty.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero
+	}
+	return l.Qty.Sub(l.RawQty).Abs().Div(l.RawQty).Mul(decimal.NewFromInt(100))
+}
+
+// DefaultQtyChangeWarnPct is the normalization change WriteGrid flags when
+// the report does not set QtyChangeWarnPct.
+var DefaultQtyChangeWarnPct = decimal.NewFromInt(10)
+
+// FeasibilityReport compares what a fresh bootstrap at Anchor would lock on
+// each side against the available balance. Surplus values are negative when
+// the side is short.
+type FeasibilityReport struct {
+	Symbol          string
+	Anchor          decimal.Decimal
+	Levels          []GridPlanLevel
+	Skipped         int
+	Available       core.Balance
+	QuoteNeeded     decimal.Decimal
+	BaseNeeded      decimal.Decimal
+	BootstrapBuyQty decimal.Decimal
+	BootstrapQuote  decimal.Decimal
+	QuoteSurplus    decimal.Decimal
+	BaseSurplus     decimal.Decimal
+
+	BuyLevels    int
+	SellLevels   int
+	BuyNotional  decimal.Decimal
+	SellNotional decimal.Decimal
+	ShiftLevels  int
+
+	QtyChangeWarnPct decimal.Decimal
+}
+
+// QtyFlag explains why a level's normalized qty deserves a look, or returns
+// "" when it is within QtyChangeWarnPct of the configured qty.
+func (r FeasibilityReport) QtyFlag(l GridPlanLevel) string {
+	if l.BumpedTo != "" {
+		return "bumped_to_" + l.BumpedTo
+	}
+	warn := r.QtyChangeWarnPct
+	if warn.Cmp(decimal.Zero) <= 0 {
+		warn = DefaultQtyChangeWarnPct
+	}
+	if l.QtyChangePct().Cmp(warn) >= 0 {
+		return "qty_changed"
+	}
+	return ""
+}
+
+func (r FeasibilityReport) Feasible() bool {
+	return r.QuoteSurplus.Cmp(decimal.Zero) >= 0
+}
+
+// ImbalancePct is |buy - sell| notional as a percentage of the larger side.
+func (r FeasibilityReport) ImbalancePct() decimal.Decimal {
+	larger := r.BuyNotional
+	if r.SellNotional.Cmp(larger) > 0 {
+		larger = r.SellNotional
+	}
+	if larger.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero
+	}
+	return r.BuyNotional.Sub(r.SellNotional).Abs().Div(larger).Mul(decimal.NewFromInt(100))
+}
+
+// Feasibility plans the initial grid around anchor without touching the
+// exchange or the strategy's own state.
+func (s *SpotDual) Feasibility(anchor decimal.Decimal, balance core.Balance) FeasibilityReport {
+	plan := *s
+	if plan.autoBalance && anchor.Cmp(decimal.Zero) > 0 {
+		plan.balancedLevels = plan.balancedShift(anchor)
+	}
+	return plan.planFeasibility(anchor, balance)
+}
+
+// balancedShift picks the shift window size (the sell side under buy_dip)
+// whose notional is closest to the opposite side at anchor.
+func (s *SpotDual) balancedShift(anchor decimal.Decimal) int {
+	best := s.sellLevels()
+	var bestDiff decimal.Decimal
+	for n := 1; n <= s.Levels; n++ {
+		plan := *s
+		plan.balancedLevels = n
+		report := plan.planFeasibility(anchor, core.Balance{})
+		diff := report.BuyNotional.Sub(report.SellNotional).Abs()
+		if n == 1 || diff.Cmp(bestDiff) < 0 {
+			best = n
+			bestDiff = diff
+		}
+	}
+	return best
+}
+
+func (s *SpotDual) planFeasibility(anchor decimal.Decimal, balance core.Balance) FeasibilityReport {
+	plan := *s
+	plan.anchor = anchor
+	plan.minLevel = 0
+	plan.maxLevel = 0
+	plan.ensureWindow()
+
+	report := FeasibilityReport{
+		Symbol:       s.Symbol,
+		Anchor:       anchor,
+		Available:    balance,
+		QuoteNeeded:  decimal.Zero,
+		BaseNeeded:   decimal.Zero,
+		BuyNotional:  decimal.Zero,
+		SellNotional: decimal.Zero,
+		ShiftLevels:  plan.sellLevels(),
+	}
+	if anchor.Cmp(decimal.Zero) <= 0 {
+		return report
+	}
+	for i := plan.maxLevel; i >= plan.minLevel; i-- {
+		if i == 0 {
+			continue
+		}
+		side := core.Sell
+		if i < 0 {
+			side = core.Buy
+		}
+		qty := plan.levelQty(side, i)
+		norm, err := core.NormalizeOrder(core.Order{
+			Symbol: s.Symbol,
+			Side:   side,
+			Type:   core.Limit,
+			Price:  plan.priceForLevel(i),
+			Qty:    qty,
+		}, s.rules)
+		if err != nil {
+			report.Skipped++
+			continue
+		}
+		report.Levels = append(report.Levels, GridPlanLevel{
+			Index:    i,
+			Side:     side,
+			Price:    norm.Price,
+			Qty:      norm.Qty,
+			RawQty:   qty,
+			BumpedTo: bumpedTo(norm.Price, qty, s.rules),
+		})
+		notional := norm.Price.Mul(norm.Qty)
+		if side == core.Buy {
+			report.BuyLevels++
+			report.BuyNotional = report.BuyNotional.Add(notional)
+			report.QuoteNeeded = report.QuoteNeeded.Add(notional)
+		} else {
+			report.SellLevels++
+			report.SellNotional = report.SellNotional.Add(notional)
+			report.BaseNeeded = report.BaseNeeded.Add(norm.Qty)
+		}
+	}
+	report.BaseSurplus = balance.Base.Sub(report.BaseNeeded)
+	report.BootstrapBuyQty = decimal.Zero
+	if report.BaseSurplus.Cmp(decimal.Zero) < 0 && !s.noBootstrapBuy {
+		report.BootstrapBuyQty = report.BaseSurplus.Neg()
+	}
+	report.BootstrapQuote = report.BootstrapBuyQty.Mul(anchor)
+	report.QuoteSurplus = balance.Quote.Sub(report.BootstrapQuote).Sub(report.QuoteNeeded)
+	return report
+}
+
+// bumpedTo mirrors core.NormalizeOrder's floors to name the one that lifted
+// qty, if any.
+func bumpedTo(price, qty decimal.Decimal, rules core.Rules) string {
+	rounded := qty
+	if rules.QtyStep.Cmp(decimal.Zero) > 0 {
+		rounded = core.RoundDown(qty, rules.QtyStep)
+	}
+	if rules.MinNotional.Cmp(decimal.Zero) > 0 && price.Mul(rounded).Cmp(rules.MinNotional) < 0 {
+		return "min_notional"
+	}
+	if rules.MinQty.Cmp(decimal.Zero) > 0 && rounded.Cmp(rules.MinQty) < 0 {
+		return "min_qty"
+	}
+	return ""
+}
+
+func (r FeasibilityReport) WriteGrid(w io.Writer) error {
+	for _, lvl := range r.Levels {
+		flag := r.QtyFlag(lvl)
+		if flag == "" {
+			flag = "-"
+		}
+		if _, err := fmt.Fprintf(
+			w,
+			"level=%d side=%s price=%s qty=%s raw_qty=%s qty_change_pct=%s notional=%s flag=%s\n",
+			lvl.Index,
+			lvl.Side,
+			lvl.Price.String(),
+			lvl.Qty.String(),
+			lvl.RawQty.String(),
+			lvl.QtyChangePct().StringFixed(2),
+			lvl.Notional().String(),
+			flag,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LadderWidth is the bar length WriteLadder gives the level farthest from
+// the anchor.
+const LadderWidth = 40
+
+// WriteLadder draws the planned grid as a price ladder, highest price first:
+// sells, an anchor row, then buys. Each bar's length is the level's distance
+// from the anchor on a log scale, normalized to LadderWidth, so a geometric
+// grid spans the same width however wide its price range is.
+func (r FeasibilityReport) WriteLadder(w io.Writer) error {
+	levels := append([]GridPlanLevel(nil), r.Levels...)
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].Price.Cmp(levels[j].Price) > 0
+	})
+	anchor := r.Anchor.InexactFloat64()
+	maxDist := 0.0
+	priceWidth, qtyWidth := len(r.Anchor.String()), 0
+	for _, lvl := range levels {
+		if d := ladderDistance(lvl.Price.InexactFloat64(), anchor); d > maxDist {
+			maxDist = d
+		}
+		priceWidth = max(priceWidth, len(lvl.Price.String()))
+		qtyWidth = max(qtyWidth, len(lvl.Qty.String()))
+	}
+	row := func(lvl GridPlanLevel) error {
+		bar := 0
+		if maxDist > 0 {
+			bar = int(math.Round(ladderDistance(lvl.Price.InexactFloat64(), anchor) / maxDist * LadderWidth))
+		}
+		_, err := fmt.Fprintf(
+			w,
+			"%-4s %5d  %*s  qty=%-*s |%s\n",
+			lvl.Side,
+			lvl.Index,
+			priceWidth,
+			lvl.Price.String(),
+			qtyWidth,
+			lvl.Qty.String(),
+			strings.Repeat("#", bar),
+		)
+		return err
+	}
+	anchorDone := false
+	for _, lvl := range levels {
+		if !anchorDone && lvl.Price.Cmp(r.Anchor) < 0 {
+			if err := writeLadderAnchor(w, r.Anchor, priceWidth); err != nil {
+				return err
+			}
+			anchorDone = true
+		}
+		if err := row(lvl); err != nil {
+			return err
+		}
+	}
+	if !anchorDone {
+		return writeLadderAnchor(w, r.Anchor, priceWidth)
+	}
+	return nil
+}
+
+func writeLadderAnchor(w io.Writer, anchor decimal.Decimal, priceWidth int) error {
+	_, err := fmt.Fprintf(w, "%-10s  %*s  %s\n", "-- anchor", priceWidth, anchor.String(), strings.Repeat("-", LadderWidth))
+	return err
+}
+
+func ladderDistance(price, anchor float64) float64 {
+	if price <= 0 || anchor <= 0 {
+		return 0
+	}
+	return math.Abs(math.Log(price / anchor))
+}
+
+func (r FeasibilityReport) WriteSummary(w io.Writer) error {
+	_, err := fmt.Fprintf(
+		w,
+		"feasibility symbol=%s anchor=%s levels=%d skipped_levels=%d quote_needed=%s base_needed=%s bootstrap_buy_qty=%s bootstrap_quote=%s available_quote=%s available_base=%s quote_surplus=%s base_surplus=%s feasible=%t\n",
+		r.Symbol,
+		r.Anchor.String(),
+		len(r.Levels),
+		r.Skipped,
+		r.QuoteNeeded.String(),
+		r.BaseNeeded.String(),
+		r.BootstrapBuyQty.String(),
+		r.BootstrapQuote.String(),
+		r.Available.Quote.String(),
+		r.Available.Base.String(),
+		r.QuoteSurplus.String(),
+		r.BaseSurplus.String(),
+		r.Feasible(),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(
+		w,
+		"symmetry symbol=%s buy_levels=%d sell_levels=%d shift_levels=%d buy_notional=%s sell_notional=%s imbalance_pct=%s\n",
+		r.Symbol,
+		r.BuyLevels,
+		r.SellLevels,
+		r.ShiftLevels,
+		r.BuyNotional.String(),
+		r.SellNotional.String(),
+		r.ImbalancePct().StringFixed(2),
+	)
+	return err
+}
@@ -0,0 +1,169 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+)
+
+func TestSpotDualFeasibilityComputesQuoteRequirement(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "0")
+	s.rules = core.Rules{PriceTick: decimal.RequireFromString("0.01")}
+
+	report := s.Feasibility(decimal.NewFromInt(121), core.Balance{
+		Base:  decimal.Zero,
+		Quote: decimal.NewFromInt(1000),
+	})
+
+	// Buys at 110, 100, 90.90 (121/1.331 rounded down to the tick), qty 1 each.
+	if want := decimal.RequireFromString("300.9"); !report.QuoteNeeded.Equal(want) {
+		t.Fatalf("quote needed = %s, want %s", report.QuoteNeeded, want)
+	}
+	if want := decimal.NewFromInt(1); !report.BaseNeeded.Equal(want) {
+		t.Fatalf("base needed = %s, want %s", report.BaseNeeded, want)
+	}
+	if want := decimal.NewFromInt(1); !report.BootstrapBuyQty.Equal(want) {
+		t.Fatalf("bootstrap buy qty = %s, want %s", report.BootstrapBuyQty, want)
+	}
+	if want := decimal.RequireFromString("578.1"); !report.QuoteSurplus.Equal(want) {
+		t.Fatalf("quote surplus = %s, want %s", report.QuoteSurplus, want)
+	}
+	if !report.Feasible() {
+		t.Fatalf("report should be feasible")
+	}
+	if len(report.Levels) != 4 {
+		t.Fatalf("planned levels = %d, want 4", len(report.Levels))
+	}
+	if !s.anchor.IsZero() || s.minLevel != 0 || s.maxLevel != 0 {
+		t.Fatalf("Feasibility() mutated strategy state: anchor=%s min=%d max=%d", s.anchor, s.minLevel, s.maxLevel)
+	}
+}
+
+func TestSpotDualAutoBalancePicksSymmetricSellLevels(t *testing.T) {
+	s, _ := newSpotDualForTest(10, 1, "0")
+	tolerance := decimal.NewFromInt(10)
+	s.SetAutoBalance(true, tolerance)
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+
+	before := s.Feasibility(decimal.NewFromInt(100), core.Balance{})
+	if before.ShiftLevels != 5 {
+		t.Fatalf("planned shift levels = %d, want 5", before.ShiftLevels)
+	}
+	if before.ImbalancePct().Cmp(tolerance) > 0 {
+		t.Fatalf("imbalance = %s%%, want <= %s%%", before.ImbalancePct(), tolerance)
+	}
+
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if s.Shift != 1 || s.balancedLevels != 5 {
+		t.Fatalf("shift step = %d balanced levels = %d, want step 1 kept and 5 balanced levels", s.Shift, s.balancedLevels)
+	}
+	sells := 0
+	for _, ord := range s.openOrders {
+		if ord.Side == core.Sell {
+			sells++
+		}
+	}
+	if sells != 5 {
+		t.Fatalf("sell orders = %d, want 5", sells)
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "grid_auto_balanced" {
+		t.Fatalf("alerts = %v, want [grid_auto_balanced]", alerts.events)
+	}
+
+	restored, _ := newSpotDualForTest(10, 1, "0")
+	restored.LoadState(s.snapshotState())
+	if restored.sellLevels() != 5 || restored.shiftLevels() != 1 {
+		t.Fatalf("restored sell levels = %d shift step = %d, want 5 and 1", restored.sellLevels(), restored.shiftLevels())
+	}
+}
+
+func TestSpotDualFeasibilityFlagsQtyBumpedToMinNotional(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "0")
+	s.Qty = decimal.RequireFromString("0.1")
+	// 0.1 * 100 = 10 clears min notional; 0.1 * 90.9 at the lowest buy does not.
+	s.rules = core.Rules{
+		PriceTick:   decimal.RequireFromString("0.01"),
+		QtyStep:     decimal.RequireFromString("0.001"),
+		MinNotional: decimal.NewFromInt(10),
+	}
+
+	report := s.Feasibility(decimal.NewFromInt(121), core.Balance{})
+	var bumped, clean *GridPlanLevel
+	for i := range report.Levels {
+		switch report.Levels[i].Index {
+		case -3:
+			bumped = &report.Levels[i]
+		case -2:
+			clean = &report.Levels[i]
+		}
+	}
+	if bumped == nil || clean == nil {
+		t.Fatalf("missing planned levels: %+v", report.Levels)
+	}
+	if got := report.QtyFlag(*bumped); got != "bumped_to_min_notional" {
+		t.Fatalf("level -3 flag = %q, want bumped_to_min_notional (qty %s)", got, bumped.Qty)
+	}
+	if !bumped.RawQty.Equal(decimal.RequireFromString("0.1")) || bumped.Qty.Cmp(bumped.RawQty) <= 0 {
+		t.Fatalf("level -3 raw/qty = %s/%s, want raw 0.1 bumped up", bumped.RawQty, bumped.Qty)
+	}
+	if bumped.Notional().Cmp(decimal.NewFromInt(10)) < 0 {
+		t.Fatalf("level -3 notional = %s, want >= 10", bumped.Notional())
+	}
+	if got := report.QtyFlag(*clean); got != "" {
+		t.Fatalf("level -2 flag = %q, want none", got)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteGrid(&buf); err != nil {
+		t.Fatalf("WriteGrid() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "level=-3 side=BUY price=90.9 qty=0.111 raw_qty=0.1 qty_change_pct=11.00 notional=10.0899 flag=bumped_to_min_notional") {
+		t.Fatalf("grid preview missing flagged level:\n%s", buf.String())
+	}
+}
+
+func TestFeasibilityWriteLadderMarksAnchorBetweenSidesAndScalesBars(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 2, "0")
+	report := s.Feasibility(decimal.NewFromInt(100), core.Balance{})
+
+	var buf bytes.Buffer
+	if err := report.WriteLadder(&buf); err != nil {
+		t.Fatalf("WriteLadder() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	anchorAt, sells, buys := -1, 0, 0
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "-- anchor") && strings.Fields(line)[2] == "100":
+			anchorAt = i
+		case strings.HasPrefix(line, "SELL"):
+			sells++
+			if anchorAt >= 0 {
+				t.Fatalf("sell row below the anchor:\n%s", buf.String())
+			}
+		case strings.HasPrefix(line, "BUY"):
+			buys++
+			if anchorAt < 0 {
+				t.Fatalf("buy row above the anchor:\n%s", buf.String())
+			}
+		}
+	}
+	if anchorAt < 0 {
+		t.Fatalf("missing anchor marker:\n%s", buf.String())
+	}
+	if sells != 2 || buys != 3 {
+		t.Fatalf("ladder rows sells=%d buys=%d, want 2/3:\n%s", sells, buys, buf.String())
+	}
+	// The farthest level (the deepest buy) gets the full bar.
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "|"+strings.Repeat("#", LadderWidth)) {
+		t.Fatalf("deepest buy bar not full width: %q", last)
+	}
+}
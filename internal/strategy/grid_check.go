@@ -0,0 +1,136 @@
+package strategy
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"grid-trading/internal/core"
+)
+
+// GridCheck compares exchange open orders with the canonical grid that
+// reconcile would converge to: a sell on every level 1..max and a buy on
+// every level -1..min. Level 0 and levels outside the window may hold
+// either side and are only checked for duplicates.
+type GridCheck struct {
+	Expected   []GridPlanLevel
+	Missing    []GridPlanLevel
+	WrongSide  []ExchangeOrder
+	Duplicates []ExchangeOrder
+	OffGrid    []ExchangeOrder
+	Present    int
+}
+
+func (c GridCheck) Clean() bool {
+	return len(c.Missing) == 0 && len(c.WrongSide) == 0 && len(c.Duplicates) == 0 && len(c.OffGrid) == 0
+}
+
+// CanonicalGrid lists the orders a complete grid holds for the loaded anchor
+// and window, sells from the top down then buys.
+func (s *SpotDual) CanonicalGrid() []GridPlanLevel {
+	plan := *s
+	plan.ensureWindow()
+	if plan.anchor.IsZero() {
+		return nil
+	}
+	sellQty := plan.orderQtyForSide(core.Sell)
+	levels := make([]GridPlanLevel, 0, plan.maxLevel-plan.minLevel)
+	for i := plan.maxLevel; i >= 1; i-- {
+		levels = append(levels, GridPlanLevel{Index: i, Side: core.Sell, Price: plan.priceForLevel(i), Qty: sellQty})
+	}
+	for i := -1; i >= plan.minLevel; i-- {
+		levels = append(levels, GridPlanLevel{Index: i, Side: core.Buy, Price: plan.priceForLevel(i), Qty: plan.levelQty(core.Buy, i)})
+	}
+	return levels
+}
+
+// CheckGrid classifies open orders against CanonicalGrid without touching
+// tracked state or the exchange.
+func (s *SpotDual) CheckGrid(open []core.Order) GridCheck {
+	plan := *s
+	plan.ensureWindow()
+	check := GridCheck{Expected: plan.CanonicalGrid()}
+	byLevel := make(map[int][]ExchangeOrder)
+	for _, ord := range open {
+		ex := ExchangeOrder{Order: ord}
+		ex.GridIndex, ex.OnGrid = plan.levelForOrder(ord)
+		if !ex.OnGrid {
+			check.OffGrid = append(check.OffGrid, ex)
+			continue
+		}
+		byLevel[ex.GridIndex] = append(byLevel[ex.GridIndex], ex)
+	}
+	expectedSide := make(map[int]core.Side, len(check.Expected))
+	for _, level := range check.Expected {
+		expectedSide[level.Index] = level.Side
+		found := false
+		for _, ex := range byLevel[level.Index] {
+			if ex.Side == level.Side {
+				found = true
+				break
+			}
+		}
+		if found {
+			check.Present++
+		} else {
+			check.Missing = append(check.Missing, level)
+		}
+	}
+	levels := make([]int, 0, len(byLevel))
+	for idx := range byLevel {
+		levels = append(levels, idx)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(levels)))
+	for _, idx := range levels {
+		orders := byLevel[idx]
+		side, expected := expectedSide[idx]
+		kept := false
+		for _, ex := range orders {
+			if expected && ex.Side != side {
+				check.WrongSide = append(check.WrongSide, ex)
+				continue
+			}
+			if kept {
+				check.Duplicates = append(check.Duplicates, ex)
+				continue
+			}
+			kept = true
+		}
+	}
+	return check
+}
+
+func (c GridCheck) WriteReport(w io.Writer) error {
+	for _, level := range c.Missing {
+		if _, err := fmt.Fprintf(w, "missing side=%s level=%d price=%s\n", level.Side, level.Index, level.Price.String()); err != nil {
+			return err
+		}
+	}
+	for _, group := range []struct {
+		kind   string
+		orders []ExchangeOrder
+	}{
+		{"wrong_side", c.WrongSide},
+		{"duplicate", c.Duplicates},
+		{"off_grid", c.OffGrid},
+	} {
+		for _, ex := range group.orders {
+			if _, err := fmt.Fprintf(w, "%s %s\n", group.kind, diffOrderFields(ex.Order, ex.level())); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(
+		w,
+		"grid_check clean=%s expected=%d present=%d missing=%d wrong_side=%d duplicates=%d off_grid=%d\n",
+		strconv.FormatBool(c.Clean()),
+		len(c.Expected),
+		c.Present,
+		len(c.Missing),
+		len(c.WrongSide),
+		len(c.Duplicates),
+		len(c.OffGrid),
+	)
+	return err
+}
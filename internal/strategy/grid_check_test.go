@@ -0,0 +1,55 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+)
+
+func TestSpotDualCheckGridReportsMissingLevel(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 2, "10")
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	open := make([]core.Order, 0, len(s.openOrders))
+	for _, ord := range s.openOrders {
+		open = append(open, ord)
+	}
+
+	check := s.CheckGrid(open)
+	if !check.Clean() || len(check.Expected) != 5 || check.Present != 5 {
+		t.Fatalf("complete grid check = %+v, want clean with 5/5 levels", check)
+	}
+
+	gone, ok := findOpenOrder(s, core.Buy, -2)
+	if !ok {
+		t.Fatalf("missing buy order at level -2")
+	}
+	partial := make([]core.Order, 0, len(open))
+	for _, ord := range open {
+		if ord.ID != gone.ID {
+			partial = append(partial, ord)
+		}
+	}
+	check = s.CheckGrid(partial)
+	if check.Clean() {
+		t.Fatalf("grid missing level -2 should not be clean")
+	}
+	if len(check.Missing) != 1 || check.Missing[0].Index != -2 || check.Missing[0].Side != core.Buy {
+		t.Fatalf("missing = %+v, want buy at level -2", check.Missing)
+	}
+
+	var buf bytes.Buffer
+	if err := check.WriteReport(&buf); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "missing side=BUY level=-2 ") ||
+		!strings.Contains(buf.String(), "grid_check clean=false expected=5 present=4 missing=1") {
+		t.Fatalf("unexpected grid check report:\n%s", buf.String())
+	}
+}
@@ -3,8 +3,11 @@ package strategy
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -16,6 +19,24 @@ import (
 
 const defaultRatioStep = "0.002"
 const defaultRatioQtyMultiple = "1"
+const defaultOversizedFillTolerancePct = "5"
+const defaultSuspiciousSnapshotPct = "50"
+const inventoryRefreshInterval = 30 * time.Second
+
+const (
+	BiasBuyDip    = "buy_dip"
+	BiasSellRally = "sell_rally"
+)
+
+const (
+	AmendmentRealign = "realign"
+	AmendmentReplace = "replace"
+)
+
+const (
+	LevelMappingExact   = "exact"
+	LevelMappingNearest = "nearest"
+)
 
 type OrderExecutor interface {
 	PlaceOrder(ctx context.Context, order core.Order) (core.Order, error)
@@ -23,9 +44,35 @@ type OrderExecutor interface {
 	Balances(ctx context.Context) (core.Balance, error)
 }
 
+// BatchCanceler executors cancel several orders in one call. The result
+// holds only the ids that failed.
+type BatchCanceler interface {
+	CancelOrders(ctx context.Context, symbol string, ids []string) map[string]error
+}
+
+type InventoryAdaptiveSell struct {
+	Enabled     bool
+	Sensitivity decimal.Decimal
+	MinFactor   decimal.Decimal
+	MaxFactor   decimal.Decimal
+}
+
+type OpenOrdersLister interface {
+	OpenOrders(ctx context.Context, symbol string) ([]core.Order, error)
+}
+
+// PriceReader is implemented by executors that can quote the current price.
+type PriceReader interface {
+	TickerPrice(ctx context.Context, symbol string) (decimal.Decimal, error)
+}
+
 type SpotDual struct {
-	Symbol           string
-	StopPrice        decimal.Decimal
+	Symbol     string
+	StopPrice  decimal.Decimal
+	FloorPrice decimal.Decimal
+	// TakeProfitPrice halts new buys once price trades above it; sells keep
+	// running. 0 disables.
+	TakeProfitPrice  decimal.Decimal
 	Ratio            decimal.Decimal
 	SellRatio        decimal.Decimal
 	RatioStep        decimal.Decimal
@@ -33,6 +80,7 @@ type SpotDual struct {
 	Levels           int
 	Shift            int
 	Qty              decimal.Decimal
+	Bias             string
 
 	minQtyMultiple int64
 	rules          core.Rules
@@ -51,6 +99,128 @@ type SpotDual struct {
 	baseBuyRatio       decimal.Decimal
 	lastDownShiftPrice decimal.Decimal
 	lastDownShiftAt    time.Time
+
+	volatilityWindow      time.Duration
+	volatilityThreshold   decimal.Decimal
+	volatilityCooldown    time.Duration
+	volatilitySamples     []priceSample
+	volatilityPaused      bool
+	volatilityPausedUntil time.Time
+	deferredPlacements    map[int]deferredPlacement
+
+	balanceFloorQuote decimal.Decimal
+	balanceFloorBase  decimal.Decimal
+	balancePaused     bool
+
+	stopCancelUntracked bool
+	clientIDPrefix      string
+
+	// refusedPriceTick is the tick change last refused, so it alerts once.
+	refusedPriceTick decimal.Decimal
+
+	inventorySell     InventoryAdaptiveSell
+	sellSpacingFactor decimal.Decimal
+
+	rebuildMinInterval time.Duration
+	lastRebuildAt      time.Time
+
+	recenterMultiple     decimal.Decimal
+	recenterSustain      time.Duration
+	recenterOutsideSince time.Time
+
+	orderAudit bool
+
+	autoBalance          bool
+	autoBalanceTolerance decimal.Decimal
+	// balancedLevels is the window size auto_balance picked; Shift stays
+	// the shift step.
+	balancedLevels int
+
+	amendmentAction string
+	levelMapping    string
+	makerRetry      bool
+	postOnly        bool
+
+	oversizedFillTolerance decimal.Decimal
+	oversizedFillGuard     bool
+
+	adaptiveShiftWindow time.Duration
+	adaptiveShiftMax    int
+	shiftTimes          []time.Time
+
+	shiftUpCooldown time.Duration
+	lastShiftUpAt   time.Time
+	shiftUpDeferred bool
+
+	windowIntent        bool
+	pendingWindowChange *store.WindowChange
+
+	noBootstrapBuy bool
+
+	orderTTL         time.Duration
+	orderTTLStagger  time.Duration
+	lastTTLRefreshAt time.Time
+
+	sweepDustOnStop bool
+	lastPrice       decimal.Decimal
+
+	sellOnStop         bool
+	sellOnStopSlippage decimal.Decimal
+
+	bootstrapIncomplete  bool
+	bootstrapWantSells   int
+	bootstrapWantBuys    int
+	bootstrapRetry       time.Duration
+	lastBootstrapRetryAt time.Time
+
+	bootstrapSettle      time.Duration
+	bootstrapReanchorPct decimal.Decimal
+	bootstrappedAt       time.Time
+
+	maxRestingBuys  int
+	maxRestingSells int
+	maxOrderQty     decimal.Decimal
+	buyQty          decimal.Decimal
+	sellQty         decimal.Decimal
+	buyQtyGrowth    decimal.Decimal
+	quoteBudget     decimal.Decimal
+	budgetAlerted   bool
+	// placeOrigin tags orders placed while it is set; see placingAs.
+	placeOrigin string
+
+	minHold      time.Duration
+	heldCounters map[int]heldCounter
+
+	sellInventoryGuard bool
+	inventoryBase      decimal.Decimal
+	inventoryAt        time.Time
+
+	// eventAt is the time of the fill or tick being handled, so time-based
+	// checks follow the data clock in backtests; see now.
+	eventAt time.Time
+
+	snapAnchor  bool
+	fixedAnchor decimal.Decimal
+	buysHalted  bool
+
+	suspiciousSnapshotPct  decimal.Decimal
+	suspiciousSnapshotSeen bool
+}
+
+type priceSample struct {
+	price decimal.Decimal
+	at    time.Time
+}
+
+type deferredPlacement struct {
+	side        core.Side
+	qtyMultiple decimal.Decimal
+	origin      string
+}
+
+type heldCounter struct {
+	side  core.Side
+	until time.Time
 }
 
 func NewSpotDual(symbol string, stopPrice, ratio decimal.Decimal, levels, shift int, qty decimal.Decimal, minQtyMultiple int64, rules core.Rules, store store.Persister, executor OrderExecutor) *SpotDual {
@@ -64,6 +234,7 @@ func NewSpotDual(symbol string, stopPrice, ratio decimal.Decimal, levels, shift
 		Levels:           levels,
 		Shift:            shift,
 		Qty:              qty,
+		Bias:             BiasBuyDip,
 		minQtyMultiple:   minQtyMultiple,
 		rules:            rules,
 		executor:         executor,
@@ -71,6 +242,13 @@ func NewSpotDual(symbol string, stopPrice, ratio decimal.Decimal, levels, shift
 		store:            store,
 		ignoreFills:      make(map[string]struct{}),
 		baseBuyRatio:     ratio,
+
+		deferredPlacements: make(map[int]deferredPlacement),
+		heldCounters:       make(map[int]heldCounter),
+
+		oversizedFillTolerance: decimal.RequireFromString(defaultOversizedFillTolerancePct),
+		oversizedFillGuard:     true,
+		suspiciousSnapshotPct:  decimal.RequireFromString(defaultSuspiciousSnapshotPct),
 	}
 }
 
@@ -78,8 +256,13 @@ func (s *SpotDual) LoadState(state store.GridState) {
 	if state.Symbol != "" && state.Symbol != s.Symbol {
 		return
 	}
-	if state.StopPrice.Cmp(decimal.Zero) > 0 {
-		s.StopPrice = state.StopPrice
+	s.StopPrice = s.restoreStopBound("stop_price", s.StopPrice, state.StopPrice)
+	s.FloorPrice = s.restoreStopBound("floor_price", s.FloorPrice, state.FloorPrice)
+	if state.TakeProfitPrice.Cmp(decimal.Zero) > 0 {
+		s.TakeProfitPrice = state.TakeProfitPrice
+	}
+	if state.BuysHalted {
+		s.buysHalted = true
 	}
 	if state.Ratio.Cmp(decimal.NewFromInt(1)) > 0 {
 		s.Ratio = state.Ratio
@@ -90,6 +273,23 @@ func (s *SpotDual) LoadState(state store.GridState) {
 	if state.SellRatio.Cmp(decimal.NewFromInt(1)) > 0 {
 		s.SellRatio = state.SellRatio
 	}
+	if state.SellSpacingFactor.Cmp(decimal.Zero) > 0 {
+		s.sellSpacingFactor = state.SellSpacingFactor
+	}
+	if state.BalancedLevels > 0 {
+		s.balancedLevels = state.BalancedLevels
+	}
+	if state.Bias != "" && state.Bias != s.Bias {
+		// The resting ladder was laid out for the persisted bias; flipping it
+		// under live orders would mirror the window around them.
+		s.alertImportant("bias_changed_on_restart", map[string]string{
+			"symbol":      s.Symbol,
+			"persisted":   state.Bias,
+			"configured":  s.Bias,
+			"next_action": "keep_persisted",
+		})
+		s.SetBias(state.Bias)
+	}
 	if state.Anchor.Cmp(decimal.Zero) > 0 {
 		s.anchor = state.Anchor
 	}
@@ -111,12 +311,107 @@ func (s *SpotDual) LoadState(state store.GridState) {
 	if !state.LastDownShiftAt.IsZero() {
 		s.lastDownShiftAt = state.LastDownShiftAt
 	}
+	if !state.LastRebuildAt.IsZero() {
+		s.lastRebuildAt = state.LastRebuildAt
+	}
+	if !state.LastShiftUpAt.IsZero() {
+		s.lastShiftUpAt = state.LastShiftUpAt
+	}
+	if state.ShiftUpDeferred {
+		s.shiftUpDeferred = true
+	}
+	for _, held := range state.HeldCounters {
+		s.heldCounters[held.Level] = heldCounter{side: held.Side, until: held.Until}
+	}
+	if pending := state.PendingWindow; pending != nil {
+		s.pendingWindowChange = pending
+		s.minLevel = pending.ToMin
+		s.maxLevel = pending.ToMax
+		s.alertImportant("pending_window_change_detected", map[string]string{
+			"symbol":      s.Symbol,
+			"kind":        pending.Kind,
+			"from_window": fmt.Sprintf("%d..%d", pending.FromMin, pending.FromMax),
+			"to_window":   fmt.Sprintf("%d..%d", pending.ToMin, pending.ToMax),
+			"started_at":  pending.StartedAt.Format(time.RFC3339),
+			"next_action": "roll_forward_on_reconcile",
+		})
+	}
+}
+
+// restoreStopBound picks the stop bound to run with after a restart. The
+// config wins when it sets the bound; a persisted bound the config no longer
+// sets is still restored so it keeps being honored. Either way a difference is
+// alerted rather than applied silently.
+func (s *SpotDual) restoreStopBound(bound string, configured, persisted decimal.Decimal) decimal.Decimal {
+	if persisted.Cmp(decimal.Zero) <= 0 || persisted.Equal(configured) {
+		return configured
+	}
+	if configured.Cmp(decimal.Zero) <= 0 {
+		s.alertImportant("stop_bound_missing_on_restart", map[string]string{
+			"symbol":    s.Symbol,
+			"bound":     bound,
+			"persisted": persisted.String(),
+		})
+		return persisted
+	}
+	s.alertImportant("stop_bound_changed_on_restart", map[string]string{
+		"symbol":      s.Symbol,
+		"bound":       bound,
+		"persisted":   persisted.String(),
+		"configured":  configured.String(),
+		"next_action": "use_configured",
+	})
+	return configured
 }
 
 func (s *SpotDual) SetAlerter(alerter alert.Alerter) {
 	s.alerter = alerter
 }
 
+func (s *SpotDual) SetFloorPrice(price decimal.Decimal) {
+	if price.Cmp(decimal.Zero) >= 0 {
+		s.FloorPrice = price
+	}
+}
+
+func (s *SpotDual) SetTakeProfitPrice(price decimal.Decimal) {
+	if price.Cmp(decimal.Zero) >= 0 {
+		s.TakeProfitPrice = price
+	}
+}
+
+func (s *SpotDual) SetRebuildMinInterval(interval time.Duration) {
+	if interval < 0 {
+		interval = 0
+	}
+	s.rebuildMinInterval = interval
+}
+
+// SetRecenter rebuilds the grid at the current price once price has stayed
+// above the top level price * multiple, or below the bottom level price /
+// multiple, for sustain. A multiple <= 1 or non-positive sustain disables it.
+func (s *SpotDual) SetRecenter(multiple decimal.Decimal, sustain time.Duration) {
+	if multiple.Cmp(decimal.NewFromInt(1)) <= 0 || sustain <= 0 {
+		s.recenterMultiple = decimal.Zero
+		s.recenterSustain = 0
+		return
+	}
+	s.recenterMultiple = multiple
+	s.recenterSustain = sustain
+}
+
+// SetAutoBalance lets a fresh Init resize the shift window so both sides
+// lock roughly the same notional at the anchor. tolerancePct only controls
+// when the best fit is still reported as out of tolerance.
+func (s *SpotDual) SetAutoBalance(enabled bool, tolerancePct decimal.Decimal) {
+	s.autoBalance = enabled
+	s.autoBalanceTolerance = tolerancePct
+}
+
+func (s *SpotDual) SetOrderAudit(enabled bool) {
+	s.orderAudit = enabled
+}
+
 func (s *SpotDual) SetSellRatio(ratio decimal.Decimal) {
 	if ratio.Cmp(decimal.NewFromInt(1)) > 0 {
 		s.SellRatio = ratio
@@ -135,15 +430,368 @@ func (s *SpotDual) SetRatioQtyMultiple(v decimal.Decimal) {
 	}
 }
 
+func (s *SpotDual) SetVolatilityPause(window time.Duration, thresholdPct decimal.Decimal, cooldown time.Duration) {
+	if window <= 0 || thresholdPct.Cmp(decimal.Zero) <= 0 {
+		s.volatilityWindow = 0
+		s.volatilityThreshold = decimal.Zero
+		s.volatilityCooldown = 0
+		return
+	}
+	if cooldown < 0 {
+		cooldown = 0
+	}
+	s.volatilityWindow = window
+	s.volatilityThreshold = thresholdPct
+	s.volatilityCooldown = cooldown
+}
+
+func (s *SpotDual) SetInventoryAdaptiveSell(cfg InventoryAdaptiveSell) {
+	if !cfg.Enabled {
+		s.inventorySell = InventoryAdaptiveSell{}
+		s.sellSpacingFactor = decimal.Zero
+		return
+	}
+	if cfg.Sensitivity.Cmp(decimal.Zero) <= 0 {
+		cfg.Sensitivity = decimal.RequireFromString("0.5")
+	}
+	if cfg.MinFactor.Cmp(decimal.Zero) <= 0 || cfg.MinFactor.Cmp(decimal.NewFromInt(1)) > 0 {
+		cfg.MinFactor = decimal.RequireFromString("0.5")
+	}
+	if cfg.MaxFactor.Cmp(decimal.NewFromInt(1)) < 0 {
+		cfg.MaxFactor = decimal.RequireFromString("1.5")
+	}
+	s.inventorySell = cfg
+}
+
+func (s *SpotDual) SetBias(bias string) {
+	switch bias {
+	case BiasBuyDip, BiasSellRally:
+		s.Bias = bias
+	}
+}
+
+// SetAdaptiveShift halves the shift/extend size for every shift beyond
+// maxShifts within window, never below one level. Sizes recover as old
+// shifts age out of the window.
+func (s *SpotDual) SetAdaptiveShift(window time.Duration, maxShifts int) {
+	if window <= 0 || maxShifts < 1 {
+		s.adaptiveShiftWindow = 0
+		s.adaptiveShiftMax = 0
+		s.shiftTimes = nil
+		return
+	}
+	s.adaptiveShiftWindow = window
+	s.adaptiveShiftMax = maxShifts
+}
+
+// SetShiftUpCooldown spaces shift-ups at least cooldown apart. A top sell
+// that fills sooner still gets its counter buy, but the window advance and
+// refill market buy wait for the first tick after the cooldown.
+func (s *SpotDual) SetShiftUpCooldown(cooldown time.Duration) {
+	if cooldown >= 0 {
+		s.shiftUpCooldown = cooldown
+	}
+}
+
+// SetOversizedFillTolerance sets how far (in percent) a single fill may exceed
+// the tracked order qty before it is rejected. A negative value (-1 in
+// config) disables the guard.
+func (s *SpotDual) SetOversizedFillTolerance(pct decimal.Decimal) {
+	if pct.Cmp(decimal.Zero) < 0 {
+		s.oversizedFillGuard = false
+		return
+	}
+	s.oversizedFillGuard = true
+	s.oversizedFillTolerance = pct
+}
+
+func (s *SpotDual) SetExternalAmendmentAction(action string) {
+	switch action {
+	case AmendmentRealign, AmendmentReplace:
+		s.amendmentAction = action
+	}
+}
+
+// SetLevelMapping picks how an order whose price is not exactly on a level
+// is mapped: exact leaves it unmapped, nearest maps it to the closer of the
+// two levels around it.
+func (s *SpotDual) SetLevelMapping(mapping string) {
+	switch mapping {
+	case LevelMappingExact, LevelMappingNearest:
+		s.levelMapping = mapping
+	}
+}
+
+// SetMakerRetry makes a limit order rejected because it would have matched
+// immediately retry once one price tick further from the market. Orders one
+// tick more passive than a level still map to that level.
+func (s *SpotDual) SetMakerRetry(enabled bool) {
+	s.makerRetry = enabled
+}
+
+// SetPostOnly places grid limit orders as post-only. One the exchange
+// rejects for crossing the book is skipped like an unaffordable one.
+func (s *SpotDual) SetPostOnly(enabled bool) {
+	s.postOnly = enabled
+}
+
+// SetWindowChangeIntent persists a pending_window_change record before every
+// shift/extend so a crash mid-move is rolled forward on the next reconcile.
+func (s *SpotDual) SetWindowChangeIntent(enabled bool) {
+	s.windowIntent = enabled
+}
+
+// SetBootstrapMarketBuy(false) never market-buys base to fund sell levels;
+// only as many sells as the free base balance covers are placed, and the rest
+// are filled in by reconcile as inventory arrives.
+func (s *SpotDual) SetBootstrapMarketBuy(enabled bool) {
+	s.noBootstrapBuy = !enabled
+}
+
+// SetOrderTTL cancels and re-places resting orders older than ttl at the same
+// level, price and qty, at most one per stagger interval.
+func (s *SpotDual) SetOrderTTL(ttl, stagger time.Duration) {
+	if ttl <= 0 {
+		s.orderTTL = 0
+		return
+	}
+	s.orderTTL = ttl
+	s.orderTTLStagger = stagger
+}
+
+// SetBootstrapRetry re-runs gap fill on ticks at most once per interval while
+// bootstrap left levels unplaced; 0 only alerts.
+func (s *SpotDual) SetBootstrapRetry(interval time.Duration) {
+	if interval < 0 {
+		interval = 0
+	}
+	s.bootstrapRetry = interval
+}
+
+// SetBootstrapReanchor makes Init wait settle after the bootstrap market buy,
+// re-read the ticker price and re-anchor the grid before placing sells when
+// the price moved more than thresholdPct from the anchor. thresholdPct <= 0
+// disables the re-read. It needs an executor implementing PriceReader.
+func (s *SpotDual) SetBootstrapReanchor(settle time.Duration, thresholdPct decimal.Decimal) {
+	if settle < 0 {
+		settle = 0
+	}
+	if thresholdPct.Cmp(decimal.Zero) < 0 {
+		thresholdPct = decimal.Zero
+	}
+	s.bootstrapSettle = settle
+	s.bootstrapReanchorPct = thresholdPct
+}
+
+// SetMaxResting caps how many buys and sells may rest at once when the
+// window moves; 0 leaves a side uncapped.
+func (s *SpotDual) SetMaxResting(buys, sells int) {
+	s.maxRestingBuys = max(buys, 0)
+	s.maxRestingSells = max(sells, 0)
+}
+
+// SetSideQty gives buy and sell orders their own base qty, e.g. buying more
+// than each sell releases to accumulate; 0 keeps Qty for that side.
+func (s *SpotDual) SetSideQty(buy, sell decimal.Decimal) {
+	s.buyQty = decimal.Max(buy, decimal.Zero)
+	s.sellQty = decimal.Max(sell, decimal.Zero)
+}
+
+// SetBuyQtyGrowth makes the buy at level -n use factor^(n-1) times the buy
+// qty, so deeper buys lower the average entry. Depth counts from the anchor,
+// so a level keeps its qty across window moves. Factors below 1 are ignored.
+func (s *SpotDual) SetBuyQtyGrowth(factor decimal.Decimal) {
+	if factor.Cmp(decimal.NewFromInt(1)) >= 0 {
+		s.buyQtyGrowth = factor
+	}
+}
+
+// SetMaxOrderQty makes a level fail with core.ErrMinNotionalUnfundable when
+// min notional at its price needs more than qty; 0 disables the check.
+func (s *SpotDual) SetMaxOrderQty(qty decimal.Decimal) {
+	if qty.Cmp(decimal.Zero) < 0 {
+		qty = decimal.Zero
+	}
+	s.maxOrderQty = qty
+}
+
+// SetCapitalBudget stops placing buys once the resting buy notional would
+// exceed quote; 0 disables the budget.
+func (s *SpotDual) SetCapitalBudget(quote decimal.Decimal) {
+	if quote.Cmp(decimal.Zero) < 0 {
+		quote = decimal.Zero
+	}
+	s.quoteBudget = quote
+}
+
+// SetBalanceFloor pauses new placements while the free quote or base balance
+// passed to ObserveBalance is below these floors; 0 disables a floor.
+func (s *SpotDual) SetBalanceFloor(quote, base decimal.Decimal) {
+	s.balanceFloorQuote = decimal.Max(quote, decimal.Zero)
+	s.balanceFloorBase = decimal.Max(base, decimal.Zero)
+}
+
+// ObserveBalance pauses placements when bal drops below a balance floor and
+// replays the placements deferred meanwhile once it recovers.
+func (s *SpotDual) ObserveBalance(ctx context.Context, bal core.Balance) error {
+	quoteLow := s.balanceFloorQuote.Cmp(decimal.Zero) > 0 && bal.Quote.Cmp(s.balanceFloorQuote) < 0
+	baseLow := s.balanceFloorBase.Cmp(decimal.Zero) > 0 && bal.Base.Cmp(s.balanceFloorBase) < 0
+	fields := map[string]string{
+		"free_quote":  bal.Quote.String(),
+		"free_base":   bal.Base.String(),
+		"floor_quote": s.balanceFloorQuote.String(),
+		"floor_base":  s.balanceFloorBase.String(),
+	}
+	if quoteLow || baseLow {
+		if !s.balancePaused {
+			s.balancePaused = true
+			s.alertImportant("balance_floor_reached", fields)
+		}
+		return nil
+	}
+	if !s.balancePaused {
+		return nil
+	}
+	s.balancePaused = false
+	fields["deferred"] = strconv.Itoa(len(s.deferredPlacements))
+	s.alertImportant("balance_floor_recovered", fields)
+	return s.replayDeferredPlacements(ctx)
+}
+
+// placementsPaused reports whether new orders are deferred instead of placed.
+func (s *SpotDual) placementsPaused() bool {
+	return s.volatilityPaused || s.balancePaused
+}
+
+func (s *SpotDual) GridStatus() GridStatus {
+	buyRatio, sellRatio := s.effectiveRatios()
+	return GridStatus{
+		OpenOrders: len(s.openOrders),
+		MinLevel:   s.minLevel,
+		MaxLevel:   s.maxLevel,
+		Anchor:     s.anchor,
+		Ratio:      buyRatio,
+		SellRatio:  sellRatio,
+		LastPrice:  s.lastPrice,
+		Paused:     s.placementsPaused(),
+		Stopped:    s.stopped,
+	}
+}
+
+// BootstrappedAt is when Init last placed the grid in this process (startup
+// or rebuild); zero when the grid was resumed from state.
+func (s *SpotDual) BootstrappedAt() time.Time {
+	return s.bootstrappedAt
+}
+
+// Stopped reports whether the stop or floor price has been reached.
+func (s *SpotDual) Stopped() bool {
+	return s.stopped
+}
+
+// SetMinHold delays the counter order for a filled level by hold, so a
+// choppy market cannot round-trip the same pair of levels for fees. Zero
+// places counters immediately.
+func (s *SpotDual) SetMinHold(hold time.Duration) {
+	if hold < 0 {
+		hold = 0
+	}
+	s.minHold = hold
+}
+
+// SetSellInventoryGuard skips a limit sell whose qty plus the qty already
+// resting in sells exceeds the base balance, instead of sending an order
+// the exchange would reject.
+func (s *SpotDual) SetSellInventoryGuard(enabled bool) {
+	s.sellInventoryGuard = enabled
+}
+
+// SetSuspiciousSnapshotPct makes Reconcile skip a pass whose snapshot lacks
+// more than pct percent of the orders the grid tracks, which usually means
+// the exchange returned a bad snapshot. The same result on the next pass is
+// trusted. 0 disables.
+func (s *SpotDual) SetSuspiciousSnapshotPct(pct decimal.Decimal) {
+	s.suspiciousSnapshotPct = pct
+}
+
+// SetSnapAnchor rounds a new anchor to the nearest price tick so level
+// prices derive from a tick-aligned base.
+func (s *SpotDual) SetSnapAnchor(enabled bool) {
+	s.snapAnchor = enabled
+}
+
+// SetAnchorPrice anchors a fresh grid at price instead of the first price
+// Init sees; 0 keeps the first price. A persisted anchor still wins on
+// restart.
+func (s *SpotDual) SetAnchorPrice(price decimal.Decimal) {
+	s.fixedAnchor = decimal.Max(price, decimal.Zero)
+}
+
+func (s *SpotDual) Rules() core.Rules {
+	return s.rules
+}
+
+// SetRules swaps in refreshed exchange filters. New placements use them
+// immediately; resting orders keep the price/qty they were placed with. A
+// price tick change is held back while orders rest, because reconcile maps
+// orders to levels by their tick-rounded price; it goes through on a later
+// refresh once the book is empty.
+func (s *SpotDual) SetRules(rules core.Rules) {
+	if !rules.PriceTick.Equal(s.rules.PriceTick) && len(s.openOrders) > 0 {
+		if !rules.PriceTick.Equal(s.refusedPriceTick) {
+			s.refusedPriceTick = rules.PriceTick
+			s.alertImportant("price_tick_change_refused", map[string]string{
+				"symbol":         s.Symbol,
+				"old_price_tick": s.rules.PriceTick.String(),
+				"new_price_tick": rules.PriceTick.String(),
+				"open_orders":    strconv.Itoa(len(s.openOrders)),
+				"next_action":    "cancel_orders_and_restart",
+			})
+		}
+		rules.PriceTick = s.rules.PriceTick
+	} else {
+		s.refusedPriceTick = decimal.Zero
+	}
+	s.rules = rules
+	_ = s.persistSnapshot()
+}
+
+// SetSweepDustOnStop market-sells the free base balance (not locked in
+// resting sells) when the strategy stops, if it clears min qty/notional.
+func (s *SpotDual) SetSweepDustOnStop(enabled bool) {
+	s.sweepDustOnStop = enabled
+}
+
+// SetSellOnStop liquidates the grid's base inventory when the stop fires:
+// resting sells are cancelled and their base is sold with a marketable limit
+// at most maxSlippagePct below the last price (a plain market order at 0).
+func (s *SpotDual) SetSellOnStop(enabled bool, maxSlippagePct decimal.Decimal) {
+	s.sellOnStop = enabled
+	if maxSlippagePct.Cmp(decimal.Zero) < 0 {
+		maxSlippagePct = decimal.Zero
+	}
+	s.sellOnStopSlippage = maxSlippagePct
+}
+
+func (s *SpotDual) SetStopCancelUntracked(enabled bool, clientIDPrefix string) {
+	s.stopCancelUntracked = enabled
+	s.clientIDPrefix = strings.TrimSpace(clientIDPrefix)
+}
+
 func (s *SpotDual) Init(ctx context.Context, price decimal.Decimal) error {
+	defer s.placingAs(core.OriginBootstrap)()
 	if s.stopped {
 		return ErrStopped
 	}
 	if s.initialized {
 		return nil
 	}
+	s.observePrice(price)
 	if s.shouldStop(price) {
-		return s.stopNow(ctx)
+		return s.stopNow(ctx, price)
+	}
+	if err := s.checkTakeProfit(ctx, price); err != nil {
+		return err
 	}
 	if s.Qty.Cmp(decimal.Zero) <= 0 {
 		return errors.New("qty must be > 0")
@@ -161,21 +809,60 @@ func (s *SpotDual) Init(ctx context.Context, price decimal.Decimal) error {
 		return errors.New("sell_ratio must be > 1")
 	}
 	if s.anchor.Cmp(decimal.Zero) <= 0 {
-		s.anchor = price
+		s.anchor = s.initialAnchor(price)
+	}
+	if s.anchor.Cmp(decimal.Zero) <= 0 {
+		return errors.New("anchor must be > 0")
 	}
-	if s.maxLevel == 0 {
-		s.maxLevel = s.sellLevels()
+	if s.fixedAnchor.Cmp(decimal.Zero) > 0 {
+		if s.StopPrice.Cmp(decimal.Zero) > 0 && s.anchor.Cmp(s.StopPrice) >= 0 {
+			return fmt.Errorf("anchor %s must be below stop_price %s", s.anchor, s.StopPrice)
+		}
+		if s.FloorPrice.Cmp(decimal.Zero) > 0 && s.anchor.Cmp(s.FloorPrice) <= 0 {
+			return fmt.Errorf("anchor %s must be above floor_price %s", s.anchor, s.FloorPrice)
+		}
 	}
-	if s.minLevel == 0 && s.maxLevel <= s.Levels {
-		s.minLevel = -s.Levels
+	if s.autoBalance && s.minLevel == 0 && s.maxLevel == 0 {
+		s.applyAutoBalance()
 	}
-	if s.maxLevel < 1 {
+	s.ensureWindow()
+	if s.sellRally() {
+		if s.minLevel > -1 {
+			return errors.New("shift_levels must be >= 1")
+		}
+	} else if s.maxLevel < 1 {
 		return errors.New("shift_levels must be >= 1")
 	}
 
-	orderQty := s.orderQty()
-	totalBase := orderQty.Mul(decimal.NewFromInt(int64(s.maxLevel)))
-	if totalBase.Cmp(decimal.Zero) > 0 {
+	sellLevels := s.maxLevel
+	if s.maxRestingSells > 0 {
+		sellLevels = min(sellLevels, s.maxRestingSells)
+	}
+	wantBuys := -s.minLevel
+	if s.maxRestingBuys > 0 {
+		wantBuys = min(wantBuys, s.maxRestingBuys)
+	}
+	orderQty := s.orderQtyForSide(core.Sell)
+	totalBase := orderQty.Mul(decimal.NewFromInt(int64(sellLevels)))
+	if s.noBootstrapBuy {
+		n, err := s.inventorySellLevels(ctx, sellLevels)
+		if err != nil {
+			s.alertImportant("bootstrap_failed", map[string]string{
+				"stage": "query_balance",
+				"err":   err.Error(),
+			})
+			_ = s.persistSnapshot()
+			return err
+		}
+		if n < sellLevels {
+			s.alertImportant("bootstrap_sells_limited_by_inventory", map[string]string{
+				"symbol":             s.Symbol,
+				"placed_sell_levels": strconv.Itoa(n),
+				"target_sell_levels": strconv.Itoa(sellLevels),
+			})
+		}
+		sellLevels = n
+	} else if totalBase.Cmp(decimal.Zero) > 0 {
 		need, err := s.baseBuyNeed(ctx, totalBase)
 		if err != nil {
 			s.alertImportant("bootstrap_failed", map[string]string{
@@ -195,10 +882,14 @@ func (s *SpotDual) Init(ctx context.Context, price decimal.Decimal) error {
 				_ = s.persistSnapshot()
 				return err
 			}
+			if err := s.reanchorAfterBootstrapBuy(ctx); err != nil {
+				return err
+			}
 		}
 	}
 
-	for i := 1; i <= s.maxLevel; i++ {
+	s.freezeSellSpacingFactor(ctx)
+	for i := 1; i <= sellLevels; i++ {
 		if err := s.placeLimit(ctx, core.Sell, i); err != nil {
 			s.alertImportant("bootstrap_failed", map[string]string{
 				"stage": "place_initial_sell",
@@ -209,7 +900,7 @@ func (s *SpotDual) Init(ctx context.Context, price decimal.Decimal) error {
 			return err
 		}
 	}
-	for i := -1; i >= s.minLevel; i-- {
+	for i := -1; i >= s.minLevel && i >= -wantBuys; i-- {
 		if err := s.placeLimit(ctx, core.Buy, i); err != nil {
 			s.alertImportant("bootstrap_failed", map[string]string{
 				"stage": "place_initial_buy",
@@ -222,6 +913,8 @@ func (s *SpotDual) Init(ctx context.Context, price decimal.Decimal) error {
 	}
 
 	s.initialized = true
+	s.bootstrappedAt = time.Now().UTC()
+	s.verifyBootstrap(sellLevels, wantBuys)
 	if err := s.persistSnapshot(); err != nil {
 		s.alertImportant("bootstrap_failed", map[string]string{
 			"stage": "persist_bootstrap_state",
@@ -232,49 +925,228 @@ func (s *SpotDual) Init(ctx context.Context, price decimal.Decimal) error {
 	return nil
 }
 
-func (s *SpotDual) baseBuyNeed(ctx context.Context, target decimal.Decimal) (decimal.Decimal, error) {
-	if target.Cmp(decimal.Zero) <= 0 {
-		return decimal.Zero, nil
+// placedLevels counts window levels holding an order on their expected side;
+// placements deferred by a volatility pause or a counter hold count as placed.
+func (s *SpotDual) placedLevels() (sells, buys int) {
+	for i := 1; i <= s.maxLevel; i++ {
+		if s.placementPending(i) || s.hasOrderLevelWithSide(core.Sell, i) {
+			sells++
+		}
 	}
-	bal, err := s.executor.Balances(ctx)
-	if err != nil {
-		return decimal.Zero, err
+	for i := -1; i >= s.minLevel; i-- {
+		if s.buysHalted || s.placementPending(i) || s.hasOrderLevelWithSide(core.Buy, i) {
+			buys++
+		}
 	}
-	need := target.Sub(bal.Base)
-	if need.Cmp(decimal.Zero) <= 0 {
-		return decimal.Zero, nil
+	return sells, buys
+}
+
+func (s *SpotDual) placementPending(idx int) bool {
+	if _, deferred := s.deferredPlacements[idx]; deferred {
+		return true
 	}
-	return need, nil
+	_, held := s.heldCounters[idx]
+	return held
 }
 
-func (s *SpotDual) OnFill(ctx context.Context, trade core.Trade) error {
-	if s.stopped {
-		return ErrStopped
+// verifyBootstrap compares what Init placed against what it meant to place,
+// so levels skipped for balance do not leave a silently lopsided grid.
+func (s *SpotDual) verifyBootstrap(wantSells, wantBuys int) {
+	sells, buys := s.placedLevels()
+	if sells >= wantSells && buys >= wantBuys {
+		s.bootstrapIncomplete = false
+		return
 	}
-	if trade.Status == "" {
-		trade.Status = core.OrderFilled
+	s.bootstrapIncomplete = true
+	s.bootstrapWantSells = wantSells
+	s.bootstrapWantBuys = wantBuys
+	nextAction := "alert_only"
+	if s.bootstrapRetry > 0 {
+		nextAction = "retry_gap_fill"
+	}
+	s.alertImportant("bootstrap_incomplete", map[string]string{
+		"symbol":               s.Symbol,
+		"expected_sell_levels": strconv.Itoa(wantSells),
+		"placed_sell_levels":   strconv.Itoa(sells),
+		"missing_sell_levels":  strconv.Itoa(max(wantSells-sells, 0)),
+		"expected_buy_levels":  strconv.Itoa(wantBuys),
+		"placed_buy_levels":    strconv.Itoa(buys),
+		"missing_buy_levels":   strconv.Itoa(max(wantBuys-buys, 0)),
+		"next_action":          nextAction,
+	})
+}
+
+// retryBootstrap re-runs Init's gap fill over the tracked orders for the
+// levels bootstrap skipped. It only reports completion once placedLevels
+// reaches what Init meant to place; anything still missing waits for the
+// next interval.
+func (s *SpotDual) retryBootstrap(ctx context.Context, at time.Time) error {
+	if !s.bootstrapIncomplete || s.bootstrapRetry <= 0 || s.placementsPaused() {
+		return nil
 	}
-	if _, ok := s.ignoreFills[trade.OrderID]; ok {
-		if s.store != nil {
-			if err := s.store.AppendTrade(trade); err != nil {
-				s.alertImportant("state_persist_failed", map[string]string{
-					"stage": "append_trade",
-					"err":   err.Error(),
-				})
-				_ = s.persistSnapshot()
-				return err
-			}
-		}
-		if trade.Status == core.OrderFilled || trade.Status == core.OrderCanceled || trade.Status == core.OrderExpired || trade.Status == core.OrderRejected {
-			delete(s.ignoreFills, trade.OrderID)
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	if !s.lastBootstrapRetryAt.IsZero() && at.Sub(s.lastBootstrapRetryAt) < s.bootstrapRetry {
+		return nil
+	}
+	s.lastBootstrapRetryAt = at
+	defer s.placingAs(core.OriginBootstrap)()
+	fill := func(side core.Side, idx int) error {
+		if s.placementPending(idx) || s.hasOrderLevelWithSide(side, idx) {
+			return nil
 		}
-		if s.shouldStop(trade.Price) {
-			return s.stopNow(ctx)
+		err := s.placeLimit(ctx, side, idx)
+		if err != nil && !errors.Is(err, ErrStopped) {
+			s.alertImportant("bootstrap_retry_failed", map[string]string{
+				"symbol": s.Symbol,
+				"side":   string(side),
+				"level":  strconv.Itoa(idx),
+				"err":    err.Error(),
+			})
+		}
+		return err
+	}
+	for i := 1; i <= s.maxLevel && i <= s.bootstrapWantSells; i++ {
+		if err := fill(core.Sell, i); err != nil {
+			return s.bootstrapRetryResult(err)
+		}
+	}
+	for i := -1; i >= s.minLevel && i >= -s.bootstrapWantBuys && !s.buysHalted; i-- {
+		if err := fill(core.Buy, i); err != nil {
+			return s.bootstrapRetryResult(err)
+		}
+	}
+	if sells, buys := s.placedLevels(); sells < s.bootstrapWantSells || buys < s.bootstrapWantBuys {
+		return s.bootstrapRetryResult(nil)
+	}
+	s.bootstrapIncomplete = false
+	s.alertImportant("bootstrap_completed", map[string]string{
+		"symbol": s.Symbol,
+	})
+	return s.bootstrapRetryResult(nil)
+}
+
+// bootstrapRetryResult persists what a retry placed and keeps the grid
+// running unless the strategy stopped.
+func (s *SpotDual) bootstrapRetryResult(err error) error {
+	if errors.Is(err, ErrStopped) {
+		return err
+	}
+	if err := s.persistSnapshot(); err != nil {
+		s.alertImportant("reconcile_persist_failed", map[string]string{
+			"err": err.Error(),
+		})
+	}
+	return nil
+}
+
+func (s *SpotDual) applyAutoBalance() {
+	prev := s.sellLevels()
+	s.balancedLevels = s.balancedShift(s.anchor)
+	report := s.planFeasibility(s.anchor, core.Balance{})
+	fields := map[string]string{
+		"symbol":              s.Symbol,
+		"anchor":              s.anchor.String(),
+		"shift_levels_before": strconv.Itoa(prev),
+		"shift_levels":        strconv.Itoa(s.balancedLevels),
+		"buy_notional":        report.BuyNotional.String(),
+		"sell_notional":       report.SellNotional.String(),
+		"imbalance_pct":       report.ImbalancePct().StringFixed(2),
+	}
+	if s.autoBalanceTolerance.Cmp(decimal.Zero) > 0 && report.ImbalancePct().Cmp(s.autoBalanceTolerance) > 0 {
+		fields["tolerance_pct"] = s.autoBalanceTolerance.String()
+		s.alertImportant("grid_auto_balance_out_of_tolerance", fields)
+		return
+	}
+	if s.balancedLevels != prev {
+		s.alertImportant("grid_auto_balanced", fields)
+	}
+}
+
+func (s *SpotDual) baseBuyNeed(ctx context.Context, target decimal.Decimal) (decimal.Decimal, error) {
+	if target.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero, nil
+	}
+	bal, err := s.executor.Balances(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	need := target.Sub(bal.Base)
+	if need.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero, nil
+	}
+	return need, nil
+}
+
+// inventorySellLevels returns how many of want sell levels the free base
+// balance (total minus base locked in tracked sells) can fund.
+func (s *SpotDual) inventorySellLevels(ctx context.Context, want int) (int, error) {
+	qty := s.orderQtyForSide(core.Sell)
+	if want < 1 || qty.Cmp(decimal.Zero) <= 0 {
+		return 0, nil
+	}
+	bal, err := s.executor.Balances(ctx)
+	if err != nil {
+		return 0, err
+	}
+	free := bal.Base.Sub(s.lockedSellBase())
+	if free.Cmp(decimal.Zero) <= 0 {
+		return 0, nil
+	}
+	n := free.Div(qty).IntPart()
+	if n < int64(want) {
+		return int(n), nil
+	}
+	return want, nil
+}
+
+func (s *SpotDual) OnFill(ctx context.Context, trade core.Trade) error {
+	if s.stopped {
+		return ErrStopped
+	}
+	if !trade.Time.IsZero() {
+		s.eventAt = trade.Time
+	}
+	if trade.Status == "" {
+		trade.Status = core.OrderFilled
+	}
+	if _, ok := s.ignoreFills[trade.OrderID]; ok {
+		if s.store != nil {
+			if err := s.store.AppendTrade(trade); err != nil {
+				s.alertImportant("state_persist_failed", map[string]string{
+					"stage": "append_trade",
+					"err":   err.Error(),
+				})
+				_ = s.persistSnapshot()
+				return err
+			}
+		}
+		if trade.Status == core.OrderFilled || trade.Status == core.OrderCanceled || trade.Status == core.OrderExpired || trade.Status == core.OrderRejected {
+			delete(s.ignoreFills, trade.OrderID)
+		}
+		s.observePrice(trade.Price)
+		if s.shouldStop(trade.Price) {
+			return s.stopNow(ctx, trade.Price)
 		}
 		return s.persistSnapshot()
 	}
 
 	ord, ok := s.openOrders[trade.OrderID]
+	if ok && s.fillOversized(ord, trade) {
+		s.alertImportant("oversized_fill_rejected", map[string]string{
+			"order_id":    trade.OrderID,
+			"trade_id":    trade.TradeID,
+			"side":        string(trade.Side),
+			"level":       strconv.Itoa(ord.GridIndex),
+			"order_qty":   ord.Qty.String(),
+			"fill_qty":    trade.Qty.String(),
+			"price":       trade.Price.String(),
+			"tolerance":   s.oversizedFillTolerance.String() + "%",
+			"next_action": "reconcile_or_manual_review",
+		})
+		return nil
+	}
 	if ok {
 		if trade.Qty.Cmp(decimal.Zero) > 0 && trade.Qty.Cmp(ord.Qty) < 0 && trade.Status == core.OrderPartiallyFilled {
 			ord.Qty = ord.Qty.Sub(trade.Qty)
@@ -289,8 +1161,9 @@ func (s *SpotDual) OnFill(ctx context.Context, trade core.Trade) error {
 					return err
 				}
 			}
+			s.observePrice(trade.Price)
 			if s.shouldStop(trade.Price) {
-				return s.stopNow(ctx)
+				return s.stopNow(ctx, trade.Price)
 			}
 			return s.persistSnapshot()
 		}
@@ -307,8 +1180,20 @@ func (s *SpotDual) OnFill(ctx context.Context, trade core.Trade) error {
 			return err
 		}
 	}
+	s.observePrice(trade.Price)
 	if s.shouldStop(trade.Price) {
-		return s.stopNow(ctx)
+		return s.stopNow(ctx, trade.Price)
+	}
+	if err := s.checkTakeProfit(ctx, trade.Price); err != nil {
+		return err
+	}
+	// Live only ticks the strategy on reconciles, so fills feed the
+	// volatility guard and release due counters too.
+	if err := s.observeVolatility(ctx, trade.Price, trade.Time); err != nil {
+		return err
+	}
+	if err := s.releaseHeldCounters(ctx, trade.Time); err != nil {
+		return err
 	}
 
 	side := trade.Side
@@ -321,7 +1206,7 @@ func (s *SpotDual) OnFill(ctx context.Context, trade core.Trade) error {
 			return s.persistSnapshot()
 		}
 		var idxOk bool
-		idx, idxOk = s.indexForPrice(trade.Price)
+		idx, idxOk = s.levelForOrder(core.Order{Side: trade.Side, Price: trade.Price})
 		if !idxOk {
 			return s.persistSnapshot()
 		}
@@ -329,87 +1214,218 @@ func (s *SpotDual) OnFill(ctx context.Context, trade core.Trade) error {
 
 	switch side {
 	case core.Sell:
-		if err := s.placeLimit(ctx, core.Buy, idx-1); err != nil {
+		if err := s.placeCounter(ctx, core.Buy, idx-1, trade.Time); err != nil {
 			_ = s.persistSnapshot()
 			return err
 		}
 		if idx == s.maxLevel {
-			if err := s.shiftUp(ctx, idx, trade.Price, trade.Time); err != nil {
+			var err error
+			if s.sellRally() {
+				err = s.extendUp(ctx, trade.Time)
+			} else {
+				err = s.shiftUp(ctx, idx, trade.Price, trade.Time)
+			}
+			if err != nil {
 				_ = s.persistSnapshot()
 				return err
 			}
 		}
 	case core.Buy:
-		if err := s.placeLimit(ctx, core.Sell, idx+1); err != nil {
+		if err := s.placeCounter(ctx, core.Sell, idx+1, trade.Time); err != nil {
 			_ = s.persistSnapshot()
 			return err
 		}
 		if idx == s.minLevel {
-			s.onDownShiftTriggered(trade.Price, trade.Time)
-			if err := s.extendDown(ctx); err != nil {
+			var err error
+			if s.sellRally() {
+				err = s.shiftDown(ctx, idx, trade.Time)
+			} else {
+				s.onDownShiftTriggered(trade.Price, trade.Time)
+				err = s.extendDown(ctx, trade.Time)
+			}
+			if err != nil {
 				_ = s.persistSnapshot()
 				return err
 			}
 		}
 	}
+	s.pendingWindowChange = nil
 	return s.persistSnapshot()
 }
 
-func (s *SpotDual) OnTick(ctx context.Context, price decimal.Decimal, _ time.Time) error {
+func (s *SpotDual) OnTick(ctx context.Context, price decimal.Decimal, at time.Time) error {
 	if s.stopped {
 		return ErrStopped
 	}
+	if price.Cmp(decimal.Zero) <= 0 {
+		// A corrupt tick would poison the anchor and level math; runners
+		// count and drop these before they get here.
+		return nil
+	}
+	if !at.IsZero() {
+		s.eventAt = at
+	}
+	s.observePrice(price)
 	if s.shouldStop(price) {
-		return s.stopNow(ctx)
+		return s.stopNow(ctx, price)
+	}
+	if err := s.checkTakeProfit(ctx, price); err != nil {
+		return err
+	}
+	if err := s.observeVolatility(ctx, price, at); err != nil {
+		return err
 	}
 	if !s.initialized {
 		return nil
 	}
 	if s.anchor.Cmp(decimal.Zero) <= 0 {
-		s.anchor = price
+		s.anchor = s.initialAnchor(price)
 	}
 	if s.SellRatio.Cmp(decimal.NewFromInt(1)) <= 0 {
 		s.SellRatio = s.Ratio
 	}
-	if s.maxLevel == 0 {
-		s.maxLevel = s.sellLevels()
+	s.ensureWindow()
+	if err := s.retryBootstrap(ctx, at); err != nil {
+		return err
+	}
+	if err := s.releaseHeldCounters(ctx, at); err != nil {
+		return err
 	}
-	if s.minLevel == 0 && s.maxLevel <= s.Levels {
-		s.minLevel = -s.Levels
+	if err := s.resumeDeferredShiftUp(ctx, price, at); err != nil {
+		return err
 	}
-	return nil
+	if recentered, err := s.checkRecenter(ctx, price, at); err != nil || recentered {
+		return err
+	}
+	return s.refreshExpiredOrder(ctx, at)
+}
+
+// refreshExpiredOrder replaces the oldest order past the TTL, keeping its
+// level, price and remaining qty. The replacement goes through the same
+// placement checks as a new grid order.
+func (s *SpotDual) refreshExpiredOrder(ctx context.Context, at time.Time) error {
+	if s.orderTTL <= 0 || s.placementsPaused() {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	if !s.lastTTLRefreshAt.IsZero() && at.Sub(s.lastTTLRefreshAt) < s.orderTTLStagger {
+		return nil
+	}
+	var oldest core.Order
+	found := false
+	for id, ord := range s.openOrders {
+		if id == "" || ord.CreatedAt.IsZero() || at.Sub(ord.CreatedAt) < s.orderTTL {
+			continue
+		}
+		if !found || ord.CreatedAt.Before(oldest.CreatedAt) ||
+			(ord.CreatedAt.Equal(oldest.CreatedAt) && ord.GridIndex < oldest.GridIndex) {
+			oldest = ord
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	s.lastTTLRefreshAt = at
+	if err := s.executor.CancelOrder(ctx, s.Symbol, oldest.ID); err != nil {
+		if errors.Is(err, core.ErrOrderNotFound) {
+			// Lost the race with a fill; keep it tracked so the fill or the next reconcile settles it.
+			return nil
+		}
+		s.alertImportant("cancel_order_failed", map[string]string{
+			"order_id": oldest.ID,
+			"side":     string(oldest.Side),
+			"price":    oldest.Price.String(),
+			"qty":      oldest.Qty.String(),
+			"err":      err.Error(),
+		})
+		return err
+	}
+	delete(s.openOrders, oldest.ID)
+	order := core.Order{
+		Symbol:    s.Symbol,
+		Side:      oldest.Side,
+		Type:      core.Limit,
+		Price:     oldest.Price,
+		Qty:       oldest.Qty,
+		GridIndex: oldest.GridIndex,
+		CreatedAt: at,
+		PostOnly:  s.postOnly,
+	}
+	restore := s.placingAs(oldest.Origin)
+	err := s.submitLimit(ctx, order)
+	restore()
+	if err != nil {
+		s.alertImportant("order_ttl_replace_failed", map[string]string{
+			"order_id":    oldest.ID,
+			"side":        string(oldest.Side),
+			"level":       strconv.Itoa(oldest.GridIndex),
+			"price":       oldest.Price.String(),
+			"qty":         oldest.Qty.String(),
+			"err":         err.Error(),
+			"next_action": "reconcile_gap_fill",
+		})
+		_ = s.persistSnapshot()
+		return err
+	}
+	return s.persistSnapshot()
+}
+
+func (s *SpotDual) fillOversized(ord core.Order, trade core.Trade) bool {
+	if !s.oversizedFillGuard || ord.Qty.Cmp(decimal.Zero) <= 0 {
+		return false
+	}
+	limit := ord.Qty.Mul(decimal.NewFromInt(100).Add(s.oversizedFillTolerance)).Div(decimal.NewFromInt(100))
+	return trade.Qty.Cmp(limit) > 0
 }
 
 func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrders []core.Order) error {
 	if s.stopped {
 		return s.reconcileStopped(ctx, openOrders)
 	}
+	s.observePrice(price)
 	if s.shouldStop(price) {
 		s.replaceOpenOrdersFromExchange(openOrders)
-		return s.stopNow(ctx)
+		return s.stopNow(ctx, price)
 	}
 	if s.anchor.Cmp(decimal.Zero) <= 0 {
-		s.anchor = price
+		s.anchor = s.initialAnchor(price)
 	}
 	if s.SellRatio.Cmp(decimal.NewFromInt(1)) <= 0 {
 		s.SellRatio = s.Ratio
 	}
-	if s.maxLevel == 0 {
-		s.maxLevel = s.sellLevels()
-	}
-	if s.minLevel == 0 && s.maxLevel <= s.Levels {
-		s.minLevel = -s.Levels
+	s.ensureWindow()
+	if s.suspiciousSnapshot(openOrders) {
+		return nil
 	}
 	s.initialized = false
 
+	openOrders, err := s.reconcileExternalAmendments(ctx, openOrders)
+	if err != nil {
+		_ = s.persistSnapshot()
+		return err
+	}
+	openOrders, err = s.rollForwardWindowChange(ctx, openOrders)
+	if err != nil {
+		_ = s.persistSnapshot()
+		return err
+	}
+
+	tracked := s.openOrders
 	s.openOrders = make(map[string]core.Order)
 	levelBuckets := make(map[int][]core.Order)
 	for _, ord := range openOrders {
-		idx, ok := s.indexForPrice(ord.Price)
+		idx, ok := s.levelForOrder(ord)
 		if !ok {
 			continue
 		}
 		ord.GridIndex = idx
+		if ord.CreatedAt.IsZero() {
+			// Snapshots without a creation time would otherwise exempt the order from the TTL refresh.
+			ord.CreatedAt = tracked[ord.ID].CreatedAt
+		}
 		levelBuckets[idx] = append(levelBuckets[idx], ord)
 	}
 
@@ -419,6 +1435,8 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 	}
 	sort.Ints(levels)
 
+	var duplicates []core.Order
+	keptIDs := make(map[int]string)
 	for _, idx := range levels {
 		ordersAtLevel := levelBuckets[idx]
 		keepIdx := primaryOrderIndex(ordersAtLevel)
@@ -426,33 +1444,19 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 		if keep.ID != "" {
 			s.openOrders[keep.ID] = keep
 		}
+		keptIDs[idx] = keep.ID
 
 		for i, ord := range ordersAtLevel {
 			if i == keepIdx || ord.ID == "" {
 				continue
 			}
-			if err := s.executor.CancelOrder(ctx, s.Symbol, ord.ID); err != nil {
-				s.alertImportant("reconcile_duplicate_order_cancel_failed", map[string]string{
-					"order_id": idOrPlaceholder(ord.ID),
-					"side":     string(ord.Side),
-					"price":    ord.Price.String(),
-					"qty":      ord.Qty.String(),
-					"level":    strconv.Itoa(idx),
-					"err":      err.Error(),
-				})
-				_ = s.persistSnapshot()
-				return err
-			}
-			s.alertImportant("reconcile_duplicate_order_canceled", map[string]string{
-				"order_id": idOrPlaceholder(ord.ID),
-				"side":     string(ord.Side),
-				"price":    ord.Price.String(),
-				"qty":      ord.Qty.String(),
-				"level":    strconv.Itoa(idx),
-				"kept_id":  idOrPlaceholder(keep.ID),
-			})
+			duplicates = append(duplicates, ord)
 		}
 	}
+	if err := s.cancelDuplicateOrders(ctx, duplicates, keptIDs); err != nil {
+		_ = s.persistSnapshot()
+		return err
+	}
 
 	lowestBuy := 0
 	for _, ord := range s.openOrders {
@@ -490,13 +1494,44 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 		}
 	}
 
+	if err := s.checkTakeProfit(ctx, price); err != nil {
+		return err
+	}
+	if s.buysHalted {
+		// Buys still on the exchange after a restart or a failed cancel.
+		s.cancelAllOpenBuyOrders(ctx)
+	}
+
+	if s.placementsPaused() {
+		s.initialized = true
+		if err := s.persistSnapshot(); err != nil {
+			s.alertImportant("reconcile_persist_failed", map[string]string{
+				"err": err.Error(),
+			})
+			return err
+		}
+		return nil
+	}
+
+	s.freezeSellSpacingFactor(ctx)
 	missingSellLevels := make([]int, 0)
 	for i := 1; i <= s.maxLevel; i++ {
 		if !s.hasOrderLevelWithSide(core.Sell, i) {
 			missingSellLevels = append(missingSellLevels, i)
 		}
 	}
-	if len(missingSellLevels) > 0 {
+	if room, capped := s.restingRoom(core.Sell); capped && len(missingSellLevels) > room {
+		missingSellLevels = missingSellLevels[:room]
+	}
+	sellBudget := len(missingSellLevels)
+	if s.noBootstrapBuy && len(missingSellLevels) > 0 {
+		n, err := s.inventorySellLevels(ctx, len(missingSellLevels))
+		if err != nil {
+			_ = s.persistSnapshot()
+			return err
+		}
+		sellBudget = n
+	} else if len(missingSellLevels) > 0 {
 		buyQty, err := s.shiftBuyNeed(ctx, len(missingSellLevels))
 		if err != nil {
 			s.alertImportant("reconcile_base_buy_need_failed", map[string]string{
@@ -519,10 +1554,11 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 		}
 	}
 
-	for i := 1; i <= s.maxLevel; i++ {
+	for i := 1; i <= s.maxLevel && sellBudget > 0; i++ {
 		if s.hasOrderLevelWithSide(core.Sell, i) {
 			continue
 		}
+		sellBudget--
 		if err := s.placeLimit(ctx, core.Sell, i); err != nil {
 			s.alertImportant("reconcile_gap_order_failed", map[string]string{
 				"side":  string(core.Sell),
@@ -537,6 +1573,9 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 		if s.hasOrderLevelWithSide(core.Buy, i) {
 			continue
 		}
+		if room, capped := s.restingRoom(core.Buy); capped && room == 0 {
+			break
+		}
 		if err := s.placeLimit(ctx, core.Buy, i); err != nil {
 			s.alertImportant("reconcile_gap_order_failed", map[string]string{
 				"side":  string(core.Buy),
@@ -555,11 +1594,24 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 		}
 	}
 	missingBuy := 0
-	for i := -1; i >= s.minLevel; i-- {
+	for i := -1; i >= s.minLevel && !s.buysHalted; i-- {
 		if !s.hasOrderLevelWithSide(core.Buy, i) {
 			missingBuy++
 		}
 	}
+	if room, capped := s.restingRoom(core.Sell); capped {
+		missingSell = min(missingSell, room)
+	}
+	if room, capped := s.restingRoom(core.Buy); capped {
+		missingBuy = min(missingBuy, room)
+	}
+	if s.noBootstrapBuy && missingSell > 0 {
+		s.alertImportant("sell_levels_awaiting_inventory", map[string]string{
+			"symbol":              s.Symbol,
+			"missing_sell_levels": strconv.Itoa(missingSell),
+		})
+		missingSell = 0
+	}
 	if missingSell > 0 || missingBuy > 0 {
 		s.alertImportant("reconcile_grid_incomplete", map[string]string{
 			"missing_sell_levels": strconv.Itoa(missingSell),
@@ -575,6 +1627,8 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 	}
 
 	s.initialized = true
+	s.pendingWindowChange = nil
+	s.bootstrapIncomplete = false
 	if err := s.persistSnapshot(); err != nil {
 		s.alertImportant("reconcile_persist_failed", map[string]string{
 			"err": err.Error(),
@@ -584,58 +1638,404 @@ func (s *SpotDual) Reconcile(ctx context.Context, price decimal.Decimal, openOrd
 	return nil
 }
 
-func (s *SpotDual) reconcileStopped(ctx context.Context, openOrders []core.Order) error {
-	s.replaceOpenOrdersFromExchange(openOrders)
-	return s.stopNow(ctx)
+// rollForwardWindowChange finishes a window move interrupted by a crash: the
+// window already points at the intended bounds (see LoadState), so only the
+// orders the move would have canceled need to go before gap fill runs.
+func (s *SpotDual) rollForwardWindowChange(ctx context.Context, openOrders []core.Order) ([]core.Order, error) {
+	pending := s.pendingWindowChange
+	if pending == nil {
+		return openOrders, nil
+	}
+	// Map against both windows so orders the move vacated are still found.
+	s.minLevel = min(pending.FromMin, pending.ToMin)
+	s.maxLevel = max(pending.FromMax, pending.ToMax)
+	stale := make(map[string]core.Order)
+	for _, ord := range openOrders {
+		idx, ok := s.levelForOrder(ord)
+		if ok && ord.ID != "" &&
+			((ord.Side == core.Buy && idx < pending.ToMin) || (ord.Side == core.Sell && idx > pending.ToMax)) {
+			stale[ord.ID] = ord
+		}
+	}
+	s.minLevel = pending.ToMin
+	s.maxLevel = pending.ToMax
+
+	kept := make([]core.Order, 0, len(openOrders))
+	canceled := 0
+	for _, ord := range openOrders {
+		if _, ok := stale[ord.ID]; !ok || ord.ID == "" {
+			kept = append(kept, ord)
+			continue
+		}
+		if err := s.executor.CancelOrder(ctx, s.Symbol, ord.ID); err != nil {
+			s.alertImportant("cancel_order_failed", map[string]string{
+				"order_id": ord.ID,
+				"side":     string(ord.Side),
+				"price":    ord.Price.String(),
+				"qty":      ord.Qty.String(),
+				"err":      err.Error(),
+			})
+			return nil, err
+		}
+		canceled++
+	}
+	s.alertImportant("pending_window_change_rolled_forward", map[string]string{
+		"symbol":          s.Symbol,
+		"kind":            pending.Kind,
+		"to_window":       fmt.Sprintf("%d..%d", pending.ToMin, pending.ToMax),
+		"canceled_orders": strconv.Itoa(canceled),
+	})
+	return kept, nil
 }
 
-func (s *SpotDual) Reset() {
-	s.openOrders = make(map[string]core.Order)
+// beginWindowChange persists the intent for a move to [toMin, toMax] before
+// any order or window mutation; a failed write aborts the move.
+func (s *SpotDual) beginWindowChange(kind string, toMin, toMax int, at time.Time) error {
+	if !s.windowIntent {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	s.pendingWindowChange = &store.WindowChange{
+		Kind:      kind,
+		FromMin:   s.minLevel,
+		FromMax:   s.maxLevel,
+		ToMin:     toMin,
+		ToMax:     toMax,
+		StartedAt: at,
+	}
+	if err := s.persistSnapshot(); err != nil {
+		s.pendingWindowChange = nil
+		return err
+	}
+	return nil
+}
+
+// Rebuild cancels every grid order and re-initialises the grid anchored at
+// price. Every full-rebuild trigger goes through here so the minimum rebuild
+// interval holds no matter which trigger fired; a suppressed rebuild returns
+// false with a nil error.
+func (s *SpotDual) Rebuild(ctx context.Context, price decimal.Decimal, at time.Time, trigger string) (bool, error) {
+	defer s.placingAs(core.OriginBootstrap)()
+	if s.stopped {
+		return false, ErrStopped
+	}
+	if price.Cmp(decimal.Zero) <= 0 {
+		return false, errors.New("rebuild price must be > 0")
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	if s.rebuildMinInterval > 0 && !s.lastRebuildAt.IsZero() {
+		nextAllowed := s.lastRebuildAt.Add(s.rebuildMinInterval)
+		if at.Before(nextAllowed) {
+			s.alertImportant("rebuild_suppressed_too_frequent", map[string]string{
+				"symbol":          s.Symbol,
+				"trigger":         trigger,
+				"last_rebuild_at": s.lastRebuildAt.Format(time.RFC3339),
+				"next_allowed_at": nextAllowed.Format(time.RFC3339),
+			})
+			return false, nil
+		}
+	}
+	if err := s.cancelAllGridOrders(ctx); err != nil {
+		_ = s.persistSnapshot()
+		return false, err
+	}
+	s.lastRebuildAt = at
+	s.anchor = s.anchorFor(price)
+	s.sellSpacingFactor = decimal.Zero
+	s.minLevel = 0
+	s.maxLevel = 0
 	s.initialized = false
-	s.stopped = false
 	if s.baseBuyRatio.Cmp(decimal.NewFromInt(1)) > 0 {
 		s.Ratio = s.baseBuyRatio
 	}
 	s.lastDownShiftPrice = decimal.Zero
 	s.lastDownShiftAt = time.Time{}
-	_ = s.persistSnapshot()
+	s.deferredPlacements = make(map[int]deferredPlacement)
+	s.heldCounters = make(map[int]heldCounter)
+	s.pendingWindowChange = nil
+	s.alertImportant("grid_rebuilt", map[string]string{
+		"symbol":  s.Symbol,
+		"trigger": trigger,
+		"anchor":  s.anchor.String(),
+	})
+	return true, s.Init(ctx, price)
 }
 
-func (s *SpotDual) orderQty() decimal.Decimal {
-	qty := s.Qty
-	if s.minQtyMultiple > 0 && s.rules.MinQty.Cmp(decimal.Zero) > 0 {
-		minQty := s.rules.MinQty.Mul(decimal.NewFromInt(s.minQtyMultiple))
-		if qty.Cmp(minQty) < 0 {
-			qty = minQty
-		}
+// checkRecenter tracks how long price has been outside the window by the
+// recenter multiple and rebuilds through Rebuild once it has been for the
+// sustain period, so the shared rebuild interval applies.
+func (s *SpotDual) checkRecenter(ctx context.Context, price decimal.Decimal, at time.Time) (bool, error) {
+	if s.recenterSustain <= 0 || (s.minLevel == 0 && s.maxLevel == 0) {
+		return false, nil
 	}
-	return qty
-}
-
-func (s *SpotDual) effectiveRatios() (decimal.Decimal, decimal.Decimal) {
-	one := decimal.NewFromInt(1)
-	buy := s.Ratio
-	if buy.Cmp(one) <= 0 {
-		buy = decimal.RequireFromString("1.000001")
+	high := s.priceForLevel(s.maxLevel).Mul(s.recenterMultiple)
+	low := s.priceForLevel(s.minLevel).Div(s.recenterMultiple)
+	if price.Cmp(high) <= 0 && price.Cmp(low) >= 0 {
+		s.recenterOutsideSince = time.Time{}
+		return false, nil
 	}
-	sell := s.SellRatio
-	if sell.Cmp(one) <= 0 {
-		sell = buy
+	if at.IsZero() {
+		at = time.Now().UTC()
 	}
-	if buy.Cmp(one) <= 0 {
-		buy = s.Ratio
+	if s.recenterOutsideSince.IsZero() {
+		s.recenterOutsideSince = at
+		return false, nil
 	}
-	if sell.Cmp(one) <= 0 {
-		sell = s.SellRatio
+	if at.Sub(s.recenterOutsideSince) < s.recenterSustain || s.placementsPaused() || s.pendingWindowChange != nil {
+		return false, nil
 	}
-	return buy, sell
+	since := s.recenterOutsideSince
+	oldAnchor := s.anchor
+	rebuilt, err := s.Rebuild(ctx, price, at, "recenter")
+	if err != nil {
+		return false, err
+	}
+	if !rebuilt {
+		// Suppressed by the rebuild interval: wait out another sustain
+		// period rather than retrying on every tick.
+		s.recenterOutsideSince = at
+		return false, nil
+	}
+	s.recenterOutsideSince = time.Time{}
+	s.alertImportant("grid_recentered", map[string]string{
+		"symbol":        s.Symbol,
+		"price":         price.String(),
+		"old_anchor":    oldAnchor.String(),
+		"new_anchor":    s.anchor.String(),
+		"outside_since": since.Format(time.RFC3339),
+	})
+	return true, s.persistSnapshot()
 }
 
-func (s *SpotDual) priceForLevel(idx int) decimal.Decimal {
-	if s.anchor.Cmp(decimal.Zero) <= 0 {
-		return decimal.Zero
+func (s *SpotDual) cancelAllGridOrders(ctx context.Context) error {
+	if err := s.cancelSideRange(ctx, core.Buy, math.MinInt, math.MaxInt); err != nil {
+		return err
 	}
-	buyRatio, sellRatio := s.effectiveRatios()
+	return s.cancelSideRange(ctx, core.Sell, math.MinInt, math.MaxInt)
+}
+
+// CancelAllOrders cancels every tracked order in one batch, e.g. to leave the
+// book empty on shutdown. The grid stays initialized, so the next reconcile
+// re-places the canceled levels. Orders whose cancel failed stay tracked; it
+// returns how many were canceled and the first failure.
+func (s *SpotDual) CancelAllOrders(ctx context.Context) (int, error) {
+	ids := make([]string, 0, len(s.openOrders))
+	for id := range s.openOrders {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	failed := s.cancelOrders(ctx, ids)
+	var firstErr error
+	canceled := 0
+	for _, id := range ids {
+		if err, ok := failed[id]; ok {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		delete(s.openOrders, id)
+		canceled++
+	}
+	if err := s.persistSnapshot(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return canceled, firstErr
+}
+
+// reconcileExternalAmendments compares exchange orders against the tracked
+// copies of the same order id. A moved price or a larger remaining qty can
+// only come from an edit outside the bot; a smaller remaining qty is treated
+// as unobserved partial fills. Replaced orders are canceled and dropped so
+// gap fill re-places the level with the expected price and qty.
+func (s *SpotDual) reconcileExternalAmendments(ctx context.Context, openOrders []core.Order) ([]core.Order, error) {
+	kept := make([]core.Order, 0, len(openOrders))
+	for _, ord := range openOrders {
+		tracked, ok := s.openOrders[ord.ID]
+		if !ok || ord.ID == "" {
+			kept = append(kept, ord)
+			continue
+		}
+		priceChanged := ord.Price.Cmp(tracked.Price) != 0
+		qtyGrown := ord.Qty.Cmp(tracked.Qty) > 0
+		if !priceChanged && !qtyGrown {
+			kept = append(kept, ord)
+			continue
+		}
+		action := AmendmentReplace
+		if s.amendmentAction != AmendmentReplace {
+			if _, onGrid := s.indexForPrice(ord.Price); onGrid {
+				action = AmendmentRealign
+			}
+		}
+		s.alertImportant("order_amended_externally", map[string]string{
+			"order_id":       ord.ID,
+			"side":           string(ord.Side),
+			"level":          strconv.Itoa(tracked.GridIndex),
+			"expected_price": tracked.Price.String(),
+			"expected_qty":   tracked.Qty.String(),
+			"exchange_price": ord.Price.String(),
+			"exchange_qty":   ord.Qty.String(),
+			"action":         action,
+		})
+		if action == AmendmentRealign {
+			kept = append(kept, ord)
+			continue
+		}
+		if err := s.executor.CancelOrder(ctx, s.Symbol, ord.ID); err != nil {
+			s.alertImportant("cancel_order_failed", map[string]string{
+				"order_id": ord.ID,
+				"side":     string(ord.Side),
+				"price":    ord.Price.String(),
+				"qty":      ord.Qty.String(),
+				"err":      err.Error(),
+			})
+			return nil, err
+		}
+	}
+	return kept, nil
+}
+
+func (s *SpotDual) reconcileStopped(ctx context.Context, openOrders []core.Order) error {
+	s.replaceOpenOrdersFromExchange(openOrders)
+	return s.stopNow(ctx, decimal.Zero)
+}
+
+func (s *SpotDual) Reset() {
+	s.openOrders = make(map[string]core.Order)
+	s.initialized = false
+	s.stopped = false
+	s.sellSpacingFactor = decimal.Zero
+	if s.baseBuyRatio.Cmp(decimal.NewFromInt(1)) > 0 {
+		s.Ratio = s.baseBuyRatio
+	}
+	s.lastDownShiftPrice = decimal.Zero
+	s.lastDownShiftAt = time.Time{}
+	s.volatilitySamples = nil
+	s.volatilityPaused = false
+	s.volatilityPausedUntil = time.Time{}
+	s.deferredPlacements = make(map[int]deferredPlacement)
+	s.heldCounters = make(map[int]heldCounter)
+	_ = s.persistSnapshot()
+}
+
+// orderQtyForSide is the base qty of a side's orders: buyQty/sellQty when
+// set, else Qty, raised to the min-qty multiple.
+func (s *SpotDual) orderQtyForSide(side core.Side) decimal.Decimal {
+	qty := s.Qty
+	if side == core.Buy && s.buyQty.Cmp(decimal.Zero) > 0 {
+		qty = s.buyQty
+	}
+	if side == core.Sell && s.sellQty.Cmp(decimal.Zero) > 0 {
+		qty = s.sellQty
+	}
+	if s.minQtyMultiple > 0 && s.rules.MinQty.Cmp(decimal.Zero) > 0 {
+		minQty := s.rules.MinQty.Mul(decimal.NewFromInt(s.minQtyMultiple))
+		if qty.Cmp(minQty) < 0 {
+			qty = minQty
+		}
+	}
+	return qty
+}
+
+// levelQty is the base qty of the order at idx before normalization:
+// orderQtyForSide, grown by buyQtyGrowth for each buy level below -1.
+func (s *SpotDual) levelQty(side core.Side, idx int) decimal.Decimal {
+	qty := s.orderQtyForSide(side)
+	if side == core.Buy && idx < -1 && s.buyQtyGrowth.Cmp(decimal.NewFromInt(1)) > 0 {
+		qty = qty.Mul(powDecimal(s.buyQtyGrowth, -idx-1))
+	}
+	return qty
+}
+
+func (s *SpotDual) effectiveRatios() (decimal.Decimal, decimal.Decimal) {
+	one := decimal.NewFromInt(1)
+	buy := s.Ratio
+	if buy.Cmp(one) <= 0 {
+		buy = decimal.RequireFromString("1.000001")
+	}
+	sell := s.SellRatio
+	if sell.Cmp(one) <= 0 {
+		sell = buy
+	}
+	if buy.Cmp(one) <= 0 {
+		buy = s.Ratio
+	}
+	if sell.Cmp(one) <= 0 {
+		sell = s.SellRatio
+	}
+	if s.inventorySell.Enabled && s.sellSpacingFactor.Cmp(decimal.Zero) > 0 && sell.Cmp(one) > 0 {
+		sell = one.Add(sell.Sub(one).Mul(s.sellSpacingFactor))
+	}
+	return buy, sell
+}
+
+// freezeSellSpacingFactor fixes the sell spacing factor when the sell side is
+// laid out fresh: tighter when the base balance exceeds what the sell window
+// needs, wider when it falls short. Level prices must not move while orders
+// rest on them, so the factor then holds until the next rebuild; with sells
+// already resting under an unset factor it freezes at 1.
+func (s *SpotDual) freezeSellSpacingFactor(ctx context.Context) {
+	if !s.inventorySell.Enabled || s.sellSpacingFactor.Cmp(decimal.Zero) > 0 {
+		return
+	}
+	one := decimal.NewFromInt(1)
+	if s.lockedSellBase().Cmp(decimal.Zero) > 0 {
+		s.sellSpacingFactor = one
+		return
+	}
+	target := s.orderQtyForSide(core.Sell).Mul(decimal.NewFromInt(int64(s.sellLevels())))
+	if target.Cmp(decimal.Zero) <= 0 {
+		return
+	}
+	bal, err := s.executor.Balances(ctx)
+	if err != nil {
+		s.sellSpacingFactor = one
+		return
+	}
+	deviation := bal.Base.Sub(target).Div(target)
+	factor := one.Sub(s.inventorySell.Sensitivity.Mul(deviation))
+	if factor.Cmp(s.inventorySell.MinFactor) < 0 {
+		factor = s.inventorySell.MinFactor
+	}
+	if factor.Cmp(s.inventorySell.MaxFactor) > 0 {
+		factor = s.inventorySell.MaxFactor
+	}
+	s.sellSpacingFactor = factor
+}
+
+// initialAnchor is the anchor of a fresh grid: the configured anchor price
+// if any, else price.
+func (s *SpotDual) initialAnchor(price decimal.Decimal) decimal.Decimal {
+	if s.fixedAnchor.Cmp(decimal.Zero) > 0 {
+		return s.anchorFor(s.fixedAnchor)
+	}
+	return s.anchorFor(price)
+}
+
+func (s *SpotDual) anchorFor(price decimal.Decimal) decimal.Decimal {
+	tick := s.rules.PriceTick
+	if !s.snapAnchor || tick.Cmp(decimal.Zero) <= 0 {
+		return price
+	}
+	snapped := price.Div(tick).Round(0).Mul(tick)
+	if snapped.Cmp(decimal.Zero) <= 0 {
+		return price
+	}
+	return snapped
+}
+
+func (s *SpotDual) priceForLevel(idx int) decimal.Decimal {
+	if s.anchor.Cmp(decimal.Zero) <= 0 {
+		return decimal.Zero
+	}
+	buyRatio, sellRatio := s.effectiveRatios()
 	price := s.anchor
 	switch {
 	case idx > 0:
@@ -658,14 +2058,7 @@ func (s *SpotDual) indexForPrice(price decimal.Decimal) (int, bool) {
 		target = core.RoundDown(price, s.rules.PriceTick)
 	}
 
-	minIdx := s.minLevel
-	maxIdx := s.maxLevel
-	if maxIdx == 0 {
-		maxIdx = s.sellLevels()
-	}
-	if minIdx == 0 && maxIdx <= s.Levels {
-		minIdx = -s.Levels
-	}
+	minIdx, maxIdx := s.windowBounds()
 	for idx := minIdx; idx <= maxIdx; idx++ {
 		if s.priceForLevel(idx).Cmp(target) == 0 {
 			return idx, true
@@ -674,6 +2067,51 @@ func (s *SpotDual) indexForPrice(price decimal.Decimal) (int, bool) {
 	return 0, false
 }
 
+// levelForOrder maps ord to a grid level, falling back to the nearest level
+// when level_mapping is nearest.
+func (s *SpotDual) levelForOrder(ord core.Order) (int, bool) {
+	if idx, ok := s.indexForPrice(ord.Price); ok {
+		return idx, true
+	}
+	if s.makerRetry {
+		if idx, ok := s.indexForPrice(s.makerRetryPrice(ord.Side, ord.Price, -1)); ok {
+			return idx, true
+		}
+	}
+	if s.levelMapping != LevelMappingNearest {
+		return 0, false
+	}
+	return s.nearestLevel(ord.Price, ord.Side)
+}
+
+// nearestLevel maps a price between two levels of the window to the closer
+// one. A price exactly halfway goes to the lower level for buys and the upper
+// one for sells, so the result never depends on map order. Prices outside
+// the window stay unmapped.
+func (s *SpotDual) nearestLevel(price decimal.Decimal, side core.Side) (int, bool) {
+	if s.anchor.Cmp(decimal.Zero) <= 0 {
+		return 0, false
+	}
+	minIdx, maxIdx := s.windowBounds()
+	for idx := minIdx; idx < maxIdx; idx++ {
+		lo, hi := s.priceForLevel(idx), s.priceForLevel(idx+1)
+		if price.Cmp(lo) < 0 || price.Cmp(hi) > 0 {
+			continue
+		}
+		switch cmp := price.Sub(lo).Cmp(hi.Sub(price)); {
+		case cmp < 0:
+			return idx, true
+		case cmp > 0:
+			return idx + 1, true
+		case side == core.Buy:
+			return idx, true
+		default:
+			return idx + 1, true
+		}
+	}
+	return 0, false
+}
+
 func (s *SpotDual) placeLimit(ctx context.Context, side core.Side, idx int) error {
 	return s.placeLimitWithQtyMultiple(ctx, side, idx, decimal.NewFromInt(1))
 }
@@ -682,20 +2120,33 @@ func (s *SpotDual) placeLimitWithQtyMultiple(ctx context.Context, side core.Side
 	if idx > s.maxLevel {
 		return nil
 	}
+	if side == core.Buy && s.buysHalted {
+		return nil
+	}
 	if s.hasOrderLevel(idx) {
 		return nil
 	}
+	if _, held := s.heldCounters[idx]; held {
+		return nil
+	}
+	if s.placementsPaused() {
+		s.deferredPlacements[idx] = deferredPlacement{side: side, qtyMultiple: qtyMultiple, origin: s.placeOrigin}
+		return nil
+	}
 	price := s.priceForLevel(idx)
 	if price.Cmp(decimal.Zero) <= 0 {
 		return nil
 	}
-	qty := s.orderQty()
+	qty := s.levelQty(side, idx)
 	if qtyMultiple.Cmp(decimal.Zero) > 0 {
 		qty = qty.Mul(qtyMultiple)
 	}
 	if qty.Cmp(decimal.Zero) <= 0 {
 		return nil
 	}
+	if err := core.CheckMinNotionalFundable(price, s.maxOrderQty, s.rules); err != nil {
+		return fmt.Errorf("level %d: %w", idx, err)
+	}
 	order := core.Order{
 		Symbol:    s.Symbol,
 		Side:      side,
@@ -703,14 +2154,48 @@ func (s *SpotDual) placeLimitWithQtyMultiple(ctx context.Context, side core.Side
 		Price:     price,
 		Qty:       qty,
 		GridIndex: idx,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: s.now().UTC(),
+		PostOnly:  s.postOnly,
 	}
+	return s.submitLimit(ctx, order)
+}
+
+// submitLimit normalizes a grid limit order and places it through the quote
+// budget, sell inventory guard and maker retry, tracking it on success.
+// Skipped levels return nil and are left to the reconcile gap fill.
+func (s *SpotDual) submitLimit(ctx context.Context, order core.Order) error {
+	side, idx := order.Side, order.GridIndex
 	norm, err := core.NormalizeOrder(order, s.rules)
 	if err != nil {
 		return err
 	}
 	order = norm
-	placed, err := s.executor.PlaceOrder(ctx, order)
+	if side == core.Buy && !s.withinQuoteBudget(order) {
+		return nil
+	}
+	if side == core.Sell && s.sellInventoryGuard {
+		if exceeds, base := s.sellExceedsInventory(ctx, order.Qty); exceeds {
+			s.alertImportant("sell_exceeds_inventory", map[string]string{
+				"level":       strconv.Itoa(idx),
+				"price":       order.Price.String(),
+				"qty":         order.Qty.String(),
+				"locked_sell": s.lockedSellBase().String(),
+				"base":        base.String(),
+				"next_action": "skip_level_until_reconcile",
+			})
+			return nil
+		}
+	}
+	placed, err := s.placeOrder(ctx, order)
+	if err != nil && s.makerRetry && errors.Is(err, core.ErrWouldMatch) && s.rules.PriceTick.Cmp(decimal.Zero) > 0 {
+		retry := order
+		retry.Price = s.makerRetryPrice(side, order.Price, 1)
+		if retry.Price.Cmp(decimal.Zero) > 0 {
+			if placed, err = s.placeOrder(ctx, retry); err == nil {
+				order = retry
+			}
+		}
+	}
 	if err != nil {
 		if isInsufficientBalanceError(err) {
 			s.alertImportant("place_order_skipped_insufficient_balance", map[string]string{
@@ -722,18 +2207,181 @@ func (s *SpotDual) placeLimitWithQtyMultiple(ctx context.Context, side core.Side
 			})
 			return nil
 		}
+		if order.PostOnly && errors.Is(err, core.ErrWouldMatch) {
+			s.alertImportant("place_order_skipped_would_match", map[string]string{
+				"side":  string(side),
+				"level": strconv.Itoa(idx),
+				"price": order.Price.String(),
+				"qty":   order.Qty.String(),
+				"err":   err.Error(),
+			})
+			return nil
+		}
 		return err
 	}
 	if placed.CreatedAt.IsZero() {
 		placed.CreatedAt = order.CreatedAt
 	}
 	placed.GridIndex = idx
+	placed.Origin = s.placeOrigin
 	s.openOrders[placed.ID] = placed
 	return nil
 }
 
+// makerRetryPrice moves price steps ticks away from the market for side:
+// down for buys, up for sells. Negative steps move back toward it.
+func (s *SpotDual) makerRetryPrice(side core.Side, price decimal.Decimal, steps int64) decimal.Decimal {
+	move := s.rules.PriceTick.Mul(decimal.NewFromInt(steps))
+	if side == core.Buy {
+		return price.Sub(move)
+	}
+	return price.Add(move)
+}
+
+// placingAs tags orders placed until the returned func runs with origin.
+func (s *SpotDual) placingAs(origin string) func() {
+	prev := s.placeOrigin
+	s.placeOrigin = origin
+	return func() { s.placeOrigin = prev }
+}
+
+// TrackedOrder returns the open order the grid tracks under orderID.
+func (s *SpotDual) TrackedOrder(orderID string) (core.Order, bool) {
+	ord, ok := s.openOrders[orderID]
+	return ord, ok
+}
+
+// withinQuoteBudget reports whether buy fits under the quote budget next to
+// the buys already resting. The first skip after a fit alerts
+// capital_budget_reached; later skips stay quiet until a buy fits again.
+func (s *SpotDual) withinQuoteBudget(buy core.Order) bool {
+	if s.quoteBudget.Cmp(decimal.Zero) <= 0 {
+		return true
+	}
+	deployed := decimal.Zero
+	for _, ord := range s.openOrders {
+		if ord.Side == core.Buy {
+			deployed = deployed.Add(ord.Price.Mul(ord.Qty))
+		}
+	}
+	notional := buy.Price.Mul(buy.Qty)
+	if deployed.Add(notional).Cmp(s.quoteBudget) <= 0 {
+		s.budgetAlerted = false
+		return true
+	}
+	if !s.budgetAlerted {
+		s.budgetAlerted = true
+		s.alertImportant("capital_budget_reached", map[string]string{
+			"level":       strconv.Itoa(buy.GridIndex),
+			"price":       buy.Price.String(),
+			"notional":    notional.String(),
+			"deployed":    deployed.String(),
+			"budget":      s.quoteBudget.String(),
+			"next_action": "skip_buy_level",
+		})
+	}
+	return false
+}
+
+func (s *SpotDual) placeOrder(ctx context.Context, order core.Order) (core.Order, error) {
+	placed, err := s.executor.PlaceOrder(ctx, order)
+	s.recordOrderAudit(order, placed, err)
+	return placed, err
+}
+
+func (s *SpotDual) recordOrderAudit(order, placed core.Order, placeErr error) {
+	if !s.orderAudit || s.store == nil {
+		return
+	}
+	auditor, ok := s.store.(store.OrderAuditor)
+	if !ok {
+		return
+	}
+	entry := store.OrderAuditEntry{
+		RecordedAt: time.Now().UTC(),
+		Symbol:     order.Symbol,
+		Side:       string(order.Side),
+		Type:       string(order.Type),
+		Price:      order.Price,
+		Qty:        order.Qty,
+		ClientID:   order.ClientID,
+		GridIndex:  order.GridIndex,
+	}
+	if placeErr != nil {
+		entry.Error = placeErr.Error()
+	} else {
+		entry.OrderID = placed.ID
+		entry.Status = string(placed.Status)
+		entry.ExchangeTime = placed.CreatedAt
+		if placed.ClientID != "" {
+			entry.ClientID = placed.ClientID
+		}
+	}
+	if err := auditor.AppendOrderAudit(entry); err != nil {
+		s.alertImportant("order_audit_failed", map[string]string{
+			"side":     entry.Side,
+			"order_id": entry.OrderID,
+			"err":      err.Error(),
+		})
+	}
+}
+
+// reanchorAfterBootstrapBuy moves the anchor to the post-buy price when the
+// bootstrap market buy (or the time it took) pushed the price beyond
+// bootstrapReanchorPct, so the first sells are not laid out around a stale
+// price. A failed price read keeps the current anchor.
+func (s *SpotDual) reanchorAfterBootstrapBuy(ctx context.Context) error {
+	if s.bootstrapReanchorPct.Cmp(decimal.Zero) <= 0 || s.anchor.Cmp(decimal.Zero) <= 0 {
+		return nil
+	}
+	reader, ok := s.executor.(PriceReader)
+	if !ok {
+		return nil
+	}
+	if s.bootstrapSettle > 0 {
+		timer := time.NewTimer(s.bootstrapSettle)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	price, err := reader.TickerPrice(ctx, s.Symbol)
+	if err != nil || price.Cmp(decimal.Zero) <= 0 {
+		fields := map[string]string{
+			"symbol": s.Symbol,
+			"anchor": s.anchor.String(),
+		}
+		if err != nil {
+			fields["err"] = err.Error()
+		}
+		s.alertImportant("bootstrap_reanchor_skipped", fields)
+		return nil
+	}
+	s.observePrice(price)
+	if s.shouldStop(price) {
+		return s.stopNow(ctx, price)
+	}
+	movePct := price.Sub(s.anchor).Abs().Div(s.anchor).Mul(decimal.NewFromInt(100))
+	if movePct.Cmp(s.bootstrapReanchorPct) <= 0 {
+		return nil
+	}
+	prev := s.anchor
+	s.anchor = s.anchorFor(price)
+	s.alertImportant("bootstrap_reanchored", map[string]string{
+		"symbol":        s.Symbol,
+		"prev_anchor":   prev.String(),
+		"anchor":        s.anchor.String(),
+		"price":         price.String(),
+		"move_pct":      movePct.StringFixed(4),
+		"threshold_pct": s.bootstrapReanchorPct.String(),
+	})
+	return nil
+}
+
 func (s *SpotDual) placeMarketBuy(ctx context.Context, qty decimal.Decimal) error {
-	if qty.Cmp(decimal.Zero) <= 0 {
+	if qty.Cmp(decimal.Zero) <= 0 || s.buysHalted {
 		return nil
 	}
 	order := core.Order{
@@ -742,96 +2390,552 @@ func (s *SpotDual) placeMarketBuy(ctx context.Context, qty decimal.Decimal) erro
 		Type:      core.Market,
 		Qty:       qty,
 		Price:     s.anchor,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: s.now().UTC(),
 	}
 	norm, err := core.NormalizeOrder(order, s.rules)
 	if err != nil {
 		return err
 	}
 	order = norm
-	placed, err := s.executor.PlaceOrder(ctx, order)
+	placed, err := s.placeOrder(ctx, order)
 	if err != nil {
 		return err
 	}
-	if placed.ID != "" {
-		s.ignoreFills[placed.ID] = struct{}{}
+	if placed.ID != "" {
+		s.ignoreFills[placed.ID] = struct{}{}
+	}
+	return nil
+}
+
+func (s *SpotDual) extendDown(ctx context.Context, at time.Time) error {
+	defer s.placingAs(core.OriginShift)()
+	if s.Levels <= 0 {
+		return nil
+	}
+	oldMin := s.minLevel
+	newMin := s.minLevel - s.nextShiftSize(s.Levels, at, "extend_down")
+	if err := s.beginWindowChange("extend_down", newMin, s.maxLevel, at); err != nil {
+		return err
+	}
+	s.minLevel = newMin
+	qtyMultiple := s.downShiftQtyMultiple()
+	for i := oldMin - 1; i >= s.minLevel; i-- {
+		if err := s.placeWithinRestingCap(ctx, core.Buy, i, qtyMultiple); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SpotDual) shiftUp(ctx context.Context, filledLevel int, triggerPrice decimal.Decimal, at time.Time) error {
+	defer s.placingAs(core.OriginShift)()
+	shift := s.shiftLevels()
+	if shift < 1 {
+		return nil
+	}
+	oldMin := s.minLevel
+	oldMax := s.maxLevel
+	if filledLevel != oldMax {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	if s.shiftUpCooling(at) {
+		if !s.shiftUpDeferred {
+			s.alertImportant("shift_up_deferred", map[string]string{
+				"symbol":        s.Symbol,
+				"level":         strconv.Itoa(filledLevel),
+				"price":         triggerPrice.String(),
+				"last_shift_at": s.lastShiftUpAt.Format(time.RFC3339),
+				"cooldown":      s.shiftUpCooldown.String(),
+			})
+		}
+		s.shiftUpDeferred = true
+		return nil
+	}
+	s.lastShiftUpAt = at
+	s.shiftUpDeferred = false
+	shift = s.nextShiftSize(shift, at, "shift_up")
+	s.restoreBuyRatioOnShiftUp(triggerPrice, at)
+	newMin := oldMin + shift
+	newMax := oldMax + shift
+	if err := s.beginWindowChange("shift_up", newMin, newMax, at); err != nil {
+		return err
+	}
+	if err := s.cancelBuyRange(ctx, oldMin, oldMin+shift-1); err != nil {
+		return err
+	}
+	if err := s.placeWithinRestingCap(ctx, core.Buy, oldMax, decimal.Zero); err != nil {
+		return err
+	}
+	sellLevels := shift
+	if s.noBootstrapBuy {
+		n, err := s.inventorySellLevels(ctx, shift)
+		if err != nil {
+			return err
+		}
+		sellLevels = n
+	} else {
+		buyQty, err := s.shiftBuyNeed(ctx, shift)
+		if err != nil {
+			return err
+		}
+		if buyQty.Cmp(decimal.Zero) > 0 {
+			if err := s.placeMarketBuy(ctx, buyQty); err != nil {
+				return err
+			}
+		}
+	}
+	s.minLevel = newMin
+	s.maxLevel = newMax
+	for i := oldMax + 1; i <= oldMax+sellLevels; i++ {
+		if err := s.placeWithinRestingCap(ctx, core.Sell, i, decimal.Zero); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SpotDual) shiftUpCooling(at time.Time) bool {
+	return s.shiftUpCooldown > 0 && !s.lastShiftUpAt.IsZero() && at.Sub(s.lastShiftUpAt) < s.shiftUpCooldown
+}
+
+// resumeDeferredShiftUp runs a shift-up held back by the cooldown once it has
+// elapsed. It is dropped if the top level has a sell again, e.g. after price
+// came back and the counter buy refilled it.
+func (s *SpotDual) resumeDeferredShiftUp(ctx context.Context, price decimal.Decimal, at time.Time) error {
+	if !s.shiftUpDeferred || s.placementsPaused() {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	if s.shiftUpCooling(at) {
+		return nil
+	}
+	if s.hasOrderLevelWithSide(core.Sell, s.maxLevel) {
+		s.shiftUpDeferred = false
+		return s.persistSnapshot()
+	}
+	if err := s.shiftUp(ctx, s.maxLevel, price, at); err != nil {
+		_ = s.persistSnapshot()
+		return err
+	}
+	s.pendingWindowChange = nil
+	return s.persistSnapshot()
+}
+
+func (s *SpotDual) observeVolatility(ctx context.Context, price decimal.Decimal, at time.Time) error {
+	if s.volatilityWindow <= 0 || price.Cmp(decimal.Zero) <= 0 {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	cutoff := at.Add(-s.volatilityWindow)
+	kept := s.volatilitySamples[:0]
+	for _, sample := range s.volatilitySamples {
+		if sample.at.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, sample)
+	}
+	s.volatilitySamples = append(kept, priceSample{price: price, at: at})
+
+	move := s.volatilityMovePct(price)
+	if move.Cmp(s.volatilityThreshold) >= 0 {
+		if !s.volatilityPaused {
+			s.alertImportant("volatility_pause", map[string]string{
+				"price":         price.String(),
+				"move_pct":      move.StringFixed(4),
+				"threshold_pct": s.volatilityThreshold.String(),
+				"window":        s.volatilityWindow.String(),
+				"cooldown":      s.volatilityCooldown.String(),
+			})
+		}
+		s.volatilityPaused = true
+		s.volatilityPausedUntil = at.Add(s.volatilityCooldown)
+		return nil
+	}
+	if !s.volatilityPaused || at.Before(s.volatilityPausedUntil) {
+		return nil
+	}
+	s.volatilityPaused = false
+	s.volatilityPausedUntil = time.Time{}
+	s.alertImportant("volatility_pause_resumed", map[string]string{
+		"price":    price.String(),
+		"deferred": strconv.Itoa(len(s.deferredPlacements)),
+	})
+	return s.replayDeferredPlacements(ctx)
+}
+
+func (s *SpotDual) volatilityMovePct(price decimal.Decimal) decimal.Decimal {
+	maxMove := decimal.Zero
+	for _, sample := range s.volatilitySamples {
+		if sample.price.Cmp(decimal.Zero) <= 0 {
+			continue
+		}
+		move := price.Sub(sample.price).Abs().Div(sample.price).Mul(decimal.NewFromInt(100))
+		if move.Cmp(maxMove) > 0 {
+			maxMove = move
+		}
+	}
+	return maxMove
+}
+
+// placeCounter places the counter order for a fill at time at, or holds it
+// until the minimum hold time has passed.
+func (s *SpotDual) placeCounter(ctx context.Context, side core.Side, idx int, at time.Time) error {
+	defer s.placingAs(core.OriginCounter)()
+	if s.minHold <= 0 || idx > s.maxLevel || s.hasOrderLevel(idx) {
+		return s.placeWithinRestingCap(ctx, side, idx, decimal.Zero)
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	s.heldCounters[idx] = heldCounter{side: side, until: at.Add(s.minHold)}
+	return nil
+}
+
+func (s *SpotDual) releaseHeldCounters(ctx context.Context, at time.Time) error {
+	defer s.placingAs(core.OriginCounter)()
+	if len(s.heldCounters) == 0 {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	levels := make([]int, 0, len(s.heldCounters))
+	for idx, held := range s.heldCounters {
+		if !at.Before(held.until) {
+			levels = append(levels, idx)
+		}
+	}
+	if len(levels) == 0 {
+		return nil
+	}
+	sort.Ints(levels)
+	for _, idx := range levels {
+		held := s.heldCounters[idx]
+		delete(s.heldCounters, idx)
+		if held.side == core.Buy && idx < s.minLevel {
+			continue
+		}
+		if err := s.placeWithinRestingCap(ctx, held.side, idx, decimal.Zero); err != nil {
+			_ = s.persistSnapshot()
+			return err
+		}
+	}
+	return s.persistSnapshot()
+}
+
+func (s *SpotDual) replayDeferredPlacements(ctx context.Context) error {
+	if len(s.deferredPlacements) == 0 || s.placementsPaused() {
+		return nil
+	}
+	levels := make([]int, 0, len(s.deferredPlacements))
+	for idx := range s.deferredPlacements {
+		levels = append(levels, idx)
+	}
+	sort.Ints(levels)
+	pending := s.deferredPlacements
+	s.deferredPlacements = make(map[int]deferredPlacement)
+	for _, idx := range levels {
+		p := pending[idx]
+		if p.side == core.Buy && idx < s.minLevel {
+			continue
+		}
+		restore := s.placingAs(p.origin)
+		err := s.placeLimitWithQtyMultiple(ctx, p.side, idx, p.qtyMultiple)
+		restore()
+		if err != nil {
+			_ = s.persistSnapshot()
+			return err
+		}
+	}
+	return s.persistSnapshot()
+}
+
+func (s *SpotDual) extendUp(ctx context.Context, at time.Time) error {
+	defer s.placingAs(core.OriginShift)()
+	if s.Levels <= 0 {
+		return nil
+	}
+	oldMax := s.maxLevel
+	newMax := s.maxLevel + s.nextShiftSize(s.Levels, at, "extend_up")
+	if err := s.beginWindowChange("extend_up", s.minLevel, newMax, at); err != nil {
+		return err
+	}
+	s.maxLevel = newMax
+	for i := oldMax + 1; i <= s.maxLevel; i++ {
+		if err := s.placeWithinRestingCap(ctx, core.Sell, i, decimal.Zero); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SpotDual) shiftDown(ctx context.Context, filledLevel int, at time.Time) error {
+	defer s.placingAs(core.OriginShift)()
+	shift := s.shiftLevels()
+	if shift < 1 {
+		return nil
+	}
+	oldMin := s.minLevel
+	oldMax := s.maxLevel
+	if filledLevel != oldMin {
+		return nil
+	}
+	shift = s.nextShiftSize(shift, at, "shift_down")
+	if err := s.beginWindowChange("shift_down", oldMin-shift, oldMax-shift, at); err != nil {
+		return err
+	}
+	if err := s.cancelSideRange(ctx, core.Sell, oldMax-shift+1, oldMax); err != nil {
+		return err
+	}
+	if err := s.placeWithinRestingCap(ctx, core.Sell, oldMin, decimal.Zero); err != nil {
+		return err
+	}
+	s.minLevel = oldMin - shift
+	s.maxLevel = oldMax - shift
+	for i := oldMin - 1; i >= s.minLevel; i-- {
+		if err := s.placeWithinRestingCap(ctx, core.Buy, i, decimal.Zero); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeWithinRestingCap places a window-move or counter order while keeping the side at
+// or under its max resting count: at the cap the farthest resting order on
+// that side (deepest buy, highest sell) is canceled to make room, unless the
+// new level would itself be the farthest, in which case it is skipped.
+func (s *SpotDual) placeWithinRestingCap(ctx context.Context, side core.Side, idx int, qtyMultiple decimal.Decimal) error {
+	limit := s.maxRestingBuys
+	if side == core.Sell {
+		limit = s.maxRestingSells
+	}
+	if limit <= 0 || s.hasOrderLevel(idx) {
+		return s.placeLimitWithQtyMultiple(ctx, side, idx, qtyMultiple)
+	}
+	count := 0
+	var farthestID string
+	var farthest core.Order
+	for id, ord := range s.openOrders {
+		if ord.Side != side {
+			continue
+		}
+		count++
+		if farthestID == "" || fartherFromGrid(side, ord.GridIndex, farthest.GridIndex) ||
+			(ord.GridIndex == farthest.GridIndex && id > farthestID) {
+			farthestID, farthest = id, ord
+		}
+	}
+	if count < limit {
+		return s.placeLimitWithQtyMultiple(ctx, side, idx, qtyMultiple)
+	}
+	fields := map[string]string{
+		"symbol":  s.Symbol,
+		"side":    string(side),
+		"level":   strconv.Itoa(idx),
+		"resting": strconv.Itoa(count),
+		"cap":     strconv.Itoa(limit),
+	}
+	if farthestID == "" || !fartherFromGrid(side, farthest.GridIndex, idx) {
+		fields["action"] = "skip_level"
+		s.alertImportant("resting_cap_reached", fields)
+		return nil
+	}
+	if err := s.executor.CancelOrder(ctx, s.Symbol, farthestID); err != nil && !errors.Is(err, core.ErrOrderNotFound) {
+		s.alertImportant("cancel_order_failed", map[string]string{
+			"order_id": farthestID,
+			"side":     string(farthest.Side),
+			"price":    farthest.Price.String(),
+			"qty":      farthest.Qty.String(),
+			"err":      err.Error(),
+		})
+		return err
+	}
+	delete(s.openOrders, farthestID)
+	fields["action"] = "cancel_farthest"
+	fields["canceled_level"] = strconv.Itoa(farthest.GridIndex)
+	fields["canceled_order_id"] = farthestID
+	s.alertImportant("resting_cap_reached", fields)
+	return s.placeLimitWithQtyMultiple(ctx, side, idx, qtyMultiple)
+}
+
+// restingRoom reports how many more orders side may rest under its cap;
+// capped is false when the side has no cap.
+func (s *SpotDual) restingRoom(side core.Side) (room int, capped bool) {
+	limit := s.maxRestingBuys
+	if side == core.Sell {
+		limit = s.maxRestingSells
+	}
+	if limit <= 0 {
+		return 0, false
+	}
+	count := 0
+	for _, ord := range s.openOrders {
+		if ord.Side == side {
+			count++
+		}
+	}
+	return max(limit-count, 0), true
+}
+
+// fartherFromGrid reports whether level a is farther out than level b on
+// side: lower for buys, higher for sells.
+func fartherFromGrid(side core.Side, a, b int) bool {
+	if side == core.Buy {
+		return a < b
+	}
+	return a > b
+}
+
+// nextShiftSize records a window move at `at` and returns how many levels it
+// may move given the recent shift rate.
+func (s *SpotDual) nextShiftSize(base int, at time.Time, kind string) int {
+	if s.adaptiveShiftWindow <= 0 || base <= 1 {
+		return base
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	cutoff := at.Add(-s.adaptiveShiftWindow)
+	kept := s.shiftTimes[:0]
+	for _, ts := range s.shiftTimes {
+		if ts.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	s.shiftTimes = append(kept, at)
+	excess := len(kept) - s.adaptiveShiftMax + 1
+	if excess <= 0 {
+		return base
+	}
+	size := base
+	for i := 0; i < excess && size > 1; i++ {
+		size /= 2
+	}
+	if size < 1 {
+		size = 1
+	}
+	s.alertImportant("shift_size_reduced", map[string]string{
+		"symbol":        s.Symbol,
+		"kind":          kind,
+		"base_levels":   strconv.Itoa(base),
+		"levels":        strconv.Itoa(size),
+		"recent_shifts": strconv.Itoa(len(kept)),
+		"window":        s.adaptiveShiftWindow.String(),
+	})
+	return size
+}
+
+// observePrice records the latest price seen; the dust sweep values the
+// leftover base at the price that triggered the stop.
+func (s *SpotDual) observePrice(price decimal.Decimal) {
+	if price.Cmp(decimal.Zero) > 0 {
+		s.lastPrice = price
 	}
-	return nil
 }
 
-func (s *SpotDual) extendDown(ctx context.Context) error {
-	if s.Levels <= 0 {
-		return nil
+func (s *SpotDual) shouldStop(price decimal.Decimal) bool {
+	if price.Cmp(decimal.Zero) <= 0 {
+		return false
 	}
-	oldMin := s.minLevel
-	s.minLevel = s.minLevel - s.Levels
-	qtyMultiple := s.downShiftQtyMultiple()
-	for i := oldMin - 1; i >= s.minLevel; i-- {
-		if err := s.placeLimitWithQtyMultiple(ctx, core.Buy, i, qtyMultiple); err != nil {
-			return err
-		}
+	if s.StopPrice.Cmp(decimal.Zero) > 0 && price.Cmp(s.StopPrice) > 0 {
+		return true
 	}
-	return nil
+	return s.FloorPrice.Cmp(decimal.Zero) > 0 && price.Cmp(s.FloorPrice) < 0
 }
 
-func (s *SpotDual) shiftUp(ctx context.Context, filledLevel int, triggerPrice decimal.Decimal, at time.Time) error {
-	shift := s.shiftLevels()
-	if shift < 1 {
-		return nil
-	}
-	oldMin := s.minLevel
-	oldMax := s.maxLevel
-	if filledLevel != oldMax {
+// checkTakeProfit halts buying for good once price trades above
+// TakeProfitPrice: resting and pending buys are dropped, while sells, their
+// buy-side counters (now no-ops) and shift-up keep running so the position
+// winds down.
+func (s *SpotDual) checkTakeProfit(ctx context.Context, price decimal.Decimal) error {
+	if s.buysHalted || s.TakeProfitPrice.Cmp(decimal.Zero) <= 0 || price.Cmp(s.TakeProfitPrice) <= 0 {
 		return nil
 	}
-	s.restoreBuyRatioOnShiftUp(triggerPrice, at)
-	newMin := oldMin + shift
-	newMax := oldMax + shift
-	if err := s.cancelBuyRange(ctx, oldMin, oldMin+shift-1); err != nil {
-		return err
-	}
-	if err := s.placeLimit(ctx, core.Buy, oldMax); err != nil {
-		return err
+	s.buysHalted = true
+	buys := 0
+	for _, ord := range s.openOrders {
+		if ord.Side == core.Buy {
+			buys++
+		}
 	}
-	buyQty, err := s.shiftBuyNeed(ctx, shift)
-	if err != nil {
-		return err
+	s.cancelAllOpenBuyOrders(ctx)
+	for idx, pending := range s.deferredPlacements {
+		if pending.side == core.Buy {
+			delete(s.deferredPlacements, idx)
+		}
 	}
-	if buyQty.Cmp(decimal.Zero) > 0 {
-		if err := s.placeMarketBuy(ctx, buyQty); err != nil {
-			return err
+	for idx, held := range s.heldCounters {
+		if held.side == core.Buy {
+			delete(s.heldCounters, idx)
 		}
 	}
-	s.minLevel = newMin
-	s.maxLevel = newMax
-	for i := oldMax + 1; i <= newMax; i++ {
-		if err := s.placeLimit(ctx, core.Sell, i); err != nil {
-			return err
+	remaining := 0
+	for _, ord := range s.openOrders {
+		if ord.Side == core.Buy {
+			remaining++
 		}
 	}
-	return nil
+	s.alertImportant("take_profit_triggered", map[string]string{
+		"symbol":            s.Symbol,
+		"price":             price.String(),
+		"take_profit_price": s.TakeProfitPrice.String(),
+		"canceled_buys":     strconv.Itoa(buys - remaining),
+		"remaining_buys":    strconv.Itoa(remaining),
+		"next_action":       "sell_only",
+	})
+	return s.persistSnapshot()
 }
 
-func (s *SpotDual) shouldStop(price decimal.Decimal) bool {
-	if s.StopPrice.Cmp(decimal.Zero) <= 0 {
-		return false
-	}
+func (s *SpotDual) stopNow(ctx context.Context, price decimal.Decimal) error {
 	if price.Cmp(decimal.Zero) <= 0 {
-		return false
+		return s.halt(ctx, "strategy_stop_price_triggered", nil)
 	}
-	return price.Cmp(s.StopPrice) > 0
+	event := "strategy_stop_price_triggered"
+	if s.FloorPrice.Cmp(decimal.Zero) > 0 && price.Cmp(s.FloorPrice) < 0 {
+		event = "strategy_floor_price_triggered"
+	}
+	return s.halt(ctx, event, map[string]string{"price": price.String()})
+}
+
+// Stop halts the grid on the runner's request, e.g. at max drawdown, the
+// same way a stop price does.
+func (s *SpotDual) Stop(ctx context.Context, reason string) error {
+	return s.halt(ctx, "strategy_stopped", map[string]string{"reason": reason})
 }
 
-func (s *SpotDual) stopNow(ctx context.Context) error {
+func (s *SpotDual) halt(ctx context.Context, event string, extra map[string]string) error {
 	justStopped := !s.stopped
 	s.cancelAllOpenBuyOrders(ctx)
+	s.cancelUntrackedInstanceOrders(ctx)
 	s.stopped = true
 	s.initialized = false
 	if justStopped {
-		s.alertImportant("strategy_stop_price_triggered", map[string]string{
-			"symbol":     s.Symbol,
-			"stop_price": s.StopPrice.String(),
-		})
+		fields := map[string]string{
+			"symbol":      s.Symbol,
+			"stop_price":  s.StopPrice.String(),
+			"floor_price": s.FloorPrice.String(),
+			"on_stop":     "hold",
+		}
+		for k, v := range extra {
+			fields[k] = v
+		}
+		if s.sellOnStop {
+			fields["on_stop"] = "market_sell"
+			for k, v := range s.liquidateInventory(ctx) {
+				fields[k] = v
+			}
+		}
+		s.alertImportant(event, fields)
+	}
+	if justStopped && s.sweepDustOnStop && !s.sellOnStop {
+		s.sweepDust(ctx)
 	}
 	if err := s.persistSnapshot(); err != nil {
 		return err
@@ -842,13 +2946,188 @@ func (s *SpotDual) stopNow(ctx context.Context) error {
 	return ErrStopped
 }
 
+// sweepDust sells the free base at market so the account ends flat apart
+// from resting sells. A balance that rounds below min qty or min notional is
+// left in place and reported.
+func (s *SpotDual) sweepDust(ctx context.Context) {
+	free, err := s.freeBase(ctx)
+	if err != nil {
+		s.alertImportant("dust_sweep_failed", map[string]string{
+			"symbol": s.Symbol,
+			"stage":  "query_balance",
+			"err":    err.Error(),
+		})
+		return
+	}
+	if free.Cmp(decimal.Zero) <= 0 {
+		return
+	}
+	qty, price, reason := s.marketSellQty(free)
+	if reason != "" {
+		s.alertImportant("dust_left", map[string]string{
+			"symbol": s.Symbol,
+			"qty":    free.String(),
+			"price":  price.String(),
+			"reason": reason,
+		})
+		return
+	}
+	placed, err := s.placeOrder(ctx, core.Order{
+		Symbol:    s.Symbol,
+		Side:      core.Sell,
+		Type:      core.Market,
+		Qty:       qty,
+		Price:     price,
+		CreatedAt: s.now().UTC(),
+	})
+	if err != nil {
+		s.alertImportant("dust_sweep_failed", map[string]string{
+			"symbol": s.Symbol,
+			"stage":  "market_sell",
+			"qty":    qty.String(),
+			"err":    err.Error(),
+		})
+		return
+	}
+	if placed.ID != "" {
+		s.ignoreFills[placed.ID] = struct{}{}
+	}
+	s.alertImportant("dust_swept", map[string]string{
+		"symbol":    s.Symbol,
+		"qty":       qty.String(),
+		"price":     price.String(),
+		"est_quote": qty.Mul(price).String(),
+		"left":      free.Sub(qty).String(),
+	})
+}
+
+// freeBase is the base balance not locked in the grid's resting sells.
+func (s *SpotDual) freeBase(ctx context.Context) (decimal.Decimal, error) {
+	bal, err := s.executor.Balances(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return bal.Base.Sub(s.lockedSellBase()), nil
+}
+
+// marketSellQty rounds amount to a market sell at the last price. reason is
+// set when the rounded qty falls below the symbol's minimums.
+func (s *SpotDual) marketSellQty(amount decimal.Decimal) (qty, price decimal.Decimal, reason string) {
+	price = s.lastPrice
+	if price.Cmp(decimal.Zero) <= 0 {
+		price = s.anchor
+	}
+	qty = core.RoundDown(amount, s.rules.QtyStep)
+	switch {
+	case qty.Cmp(decimal.Zero) <= 0:
+		reason = "below_qty_step"
+	case s.rules.MinQty.Cmp(decimal.Zero) > 0 && qty.Cmp(s.rules.MinQty) < 0:
+		reason = "below_min_qty"
+	case s.rules.MinNotional.Cmp(decimal.Zero) > 0 && qty.Mul(price).Cmp(s.rules.MinNotional) < 0:
+		reason = "below_min_notional"
+	}
+	return qty, price, reason
+}
+
+// gridSellBase is the base the grid means to sell: its resting sells plus
+// sell counters held back or deferred. Base the account held besides that is
+// not the grid's to liquidate.
+func (s *SpotDual) gridSellBase() decimal.Decimal {
+	total := s.lockedSellBase()
+	for idx, held := range s.heldCounters {
+		if held.side == core.Sell {
+			total = total.Add(s.levelQty(core.Sell, idx))
+		}
+	}
+	for idx, p := range s.deferredPlacements {
+		if p.side != core.Sell {
+			continue
+		}
+		qty := s.levelQty(core.Sell, idx)
+		if p.qtyMultiple.Cmp(decimal.Zero) > 0 {
+			qty = qty.Mul(p.qtyMultiple)
+		}
+		total = total.Add(qty)
+	}
+	return total
+}
+
+// liquidateInventory cancels resting sells and sells the grid's inventory,
+// never more than the free base. The returned fields describe the outcome
+// for the stop alert.
+func (s *SpotDual) liquidateInventory(ctx context.Context) map[string]string {
+	inventory := s.gridSellBase()
+	for id, ord := range s.openOrders {
+		if ord.Side != core.Sell {
+			continue
+		}
+		if err := s.executor.CancelOrder(ctx, s.Symbol, id); err != nil && !errors.Is(err, core.ErrOrderNotFound) {
+			s.alertImportant("cancel_order_failed", map[string]string{
+				"order_id": id,
+				"side":     string(ord.Side),
+				"price":    ord.Price.String(),
+				"qty":      ord.Qty.String(),
+				"stage":    "stop_liquidation",
+				"err":      err.Error(),
+			})
+			continue
+		}
+		delete(s.openOrders, id)
+	}
+	free, err := s.freeBase(ctx)
+	if err != nil {
+		return map[string]string{"liquidation": "failed", "liquidation_err": err.Error()}
+	}
+	amount := decimal.Min(free, inventory)
+	if amount.Cmp(decimal.Zero) <= 0 {
+		return map[string]string{"liquidation": "none", "liquidated_qty": "0"}
+	}
+	qty, price, reason := s.marketSellQty(amount)
+	if reason != "" {
+		return map[string]string{"liquidation": "below_minimum", "liquidated_qty": "0", "left_base": amount.String()}
+	}
+	order := core.Order{
+		Symbol:    s.Symbol,
+		Side:      core.Sell,
+		Type:      core.Market,
+		Qty:       qty,
+		Price:     price,
+		CreatedAt: s.now().UTC(),
+	}
+	if s.sellOnStopSlippage.Cmp(decimal.Zero) > 0 && price.Cmp(decimal.Zero) > 0 {
+		limit := price.Mul(decimal.NewFromInt(1).Sub(s.sellOnStopSlippage.Div(decimal.NewFromInt(100))))
+		if s.rules.PriceTick.Cmp(decimal.Zero) > 0 {
+			limit = core.RoundDown(limit, s.rules.PriceTick)
+		}
+		if limit.Cmp(decimal.Zero) > 0 {
+			order.Type = core.Limit
+			order.Price = limit
+		}
+	}
+	placed, err := s.placeOrder(ctx, order)
+	if err != nil {
+		return map[string]string{"liquidation": "failed", "liquidated_qty": "0", "left_base": qty.String(), "liquidation_err": err.Error()}
+	}
+	if placed.ID != "" {
+		s.ignoreFills[placed.ID] = struct{}{}
+	}
+	return map[string]string{
+		"liquidation":       "submitted",
+		"liquidation_type":  string(order.Type),
+		"liquidated_qty":    qty.String(),
+		"liquidation_price": order.Price.String(),
+		"last_price":        price.String(),
+		"est_quote":         qty.Mul(order.Price).String(),
+	}
+}
+
 func (s *SpotDual) replaceOpenOrdersFromExchange(openOrders []core.Order) {
 	next := make(map[string]core.Order, len(openOrders))
 	for _, ord := range openOrders {
 		if ord.ID == "" {
 			continue
 		}
-		if idx, ok := s.indexForPrice(ord.Price); ok {
+		if idx, ok := s.levelForOrder(ord); ok {
 			ord.GridIndex = idx
 		}
 		next[ord.ID] = ord
@@ -879,6 +3158,51 @@ func (s *SpotDual) cancelAllOpenBuyOrders(ctx context.Context) {
 	}
 }
 
+func (s *SpotDual) cancelUntrackedInstanceOrders(ctx context.Context) {
+	if !s.stopCancelUntracked || s.clientIDPrefix == "" {
+		return
+	}
+	lister, ok := s.executor.(OpenOrdersLister)
+	if !ok {
+		return
+	}
+	open, err := lister.OpenOrders(ctx, s.Symbol)
+	if err != nil {
+		s.alertImportant("stop_untracked_orders_query_failed", map[string]string{
+			"err": err.Error(),
+		})
+		return
+	}
+	for _, ord := range open {
+		if ord.ID == "" {
+			continue
+		}
+		if _, tracked := s.openOrders[ord.ID]; tracked {
+			continue
+		}
+		if !strings.HasPrefix(ord.ClientID, s.clientIDPrefix+"-") {
+			continue
+		}
+		if err := s.executor.CancelOrder(ctx, s.Symbol, ord.ID); err != nil {
+			s.alertImportant("cancel_order_failed", map[string]string{
+				"order_id": ord.ID,
+				"side":     string(ord.Side),
+				"price":    ord.Price.String(),
+				"qty":      ord.Qty.String(),
+				"err":      err.Error(),
+			})
+			continue
+		}
+		s.alertImportant("stop_untracked_order_canceled", map[string]string{
+			"order_id":  ord.ID,
+			"client_id": ord.ClientID,
+			"side":      string(ord.Side),
+			"price":     ord.Price.String(),
+			"qty":       ord.Qty.String(),
+		})
+	}
+}
+
 func (s *SpotDual) hasOpenBuyOrders() bool {
 	for _, ord := range s.openOrders {
 		if ord.Side == core.Buy {
@@ -889,9 +3213,13 @@ func (s *SpotDual) hasOpenBuyOrders() bool {
 }
 
 func (s *SpotDual) cancelBuyRange(ctx context.Context, from, to int) error {
+	return s.cancelSideRange(ctx, core.Buy, from, to)
+}
+
+func (s *SpotDual) cancelSideRange(ctx context.Context, side core.Side, from, to int) error {
 	var firstErr error
 	for id, ord := range s.openOrders {
-		if ord.Side != core.Buy {
+		if ord.Side != side {
 			continue
 		}
 		if ord.GridIndex < from || ord.GridIndex > to {
@@ -915,6 +3243,60 @@ func (s *SpotDual) cancelBuyRange(ctx context.Context, from, to int) error {
 	return firstErr
 }
 
+// cancelDuplicateOrders cancels reconcile duplicates in one batch. Orders
+// whose cancel failed stay tracked so the next reconcile retries them; the
+// first failure is returned.
+func (s *SpotDual) cancelDuplicateOrders(ctx context.Context, duplicates []core.Order, keptIDs map[int]string) error {
+	if len(duplicates) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(duplicates))
+	for _, ord := range duplicates {
+		ids = append(ids, ord.ID)
+	}
+	failed := s.cancelOrders(ctx, ids)
+	var firstErr error
+	for _, ord := range duplicates {
+		if err, ok := failed[ord.ID]; ok {
+			s.openOrders[ord.ID] = ord
+			s.alertImportant("reconcile_duplicate_order_cancel_failed", map[string]string{
+				"order_id": idOrPlaceholder(ord.ID),
+				"side":     string(ord.Side),
+				"price":    ord.Price.String(),
+				"qty":      ord.Qty.String(),
+				"level":    strconv.Itoa(ord.GridIndex),
+				"err":      err.Error(),
+			})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		s.alertImportant("reconcile_duplicate_order_canceled", map[string]string{
+			"order_id": idOrPlaceholder(ord.ID),
+			"side":     string(ord.Side),
+			"price":    ord.Price.String(),
+			"qty":      ord.Qty.String(),
+			"level":    strconv.Itoa(ord.GridIndex),
+			"kept_id":  idOrPlaceholder(keptIDs[ord.GridIndex]),
+		})
+	}
+	return firstErr
+}
+
+func (s *SpotDual) cancelOrders(ctx context.Context, ids []string) map[string]error {
+	if batcher, ok := s.executor.(BatchCanceler); ok {
+		return batcher.CancelOrders(ctx, s.Symbol, ids)
+	}
+	failed := make(map[string]error)
+	for _, id := range ids {
+		if err := s.executor.CancelOrder(ctx, s.Symbol, id); err != nil {
+			failed[id] = err
+		}
+	}
+	return failed
+}
+
 func (s *SpotDual) cancelConflictingOrderAtLevel(ctx context.Context, idx int, expectedSide core.Side) error {
 	for id, ord := range s.openOrders {
 		if ord.GridIndex != idx {
@@ -1021,8 +3403,37 @@ func (s *SpotDual) restoreBuyRatioOnShiftUp(price decimal.Decimal, at time.Time)
 	})
 }
 
+func (s *SpotDual) sellRally() bool {
+	return s.Bias == BiasSellRally
+}
+
+func (s *SpotDual) windowBounds() (int, int) {
+	minIdx := s.minLevel
+	maxIdx := s.maxLevel
+	if s.sellRally() {
+		if minIdx == 0 && maxIdx == 0 {
+			return -s.sellLevels(), s.Levels
+		}
+		return minIdx, maxIdx
+	}
+	if maxIdx == 0 {
+		maxIdx = s.sellLevels()
+	}
+	if minIdx == 0 && maxIdx <= s.Levels {
+		minIdx = -s.Levels
+	}
+	return minIdx, maxIdx
+}
+
+func (s *SpotDual) ensureWindow() {
+	s.minLevel, s.maxLevel = s.windowBounds()
+}
+
 func (s *SpotDual) sellLevels() int {
 	n := s.shiftLevels()
+	if s.balancedLevels > 0 {
+		n = s.balancedLevels
+	}
 	if n < 1 {
 		return 1
 	}
@@ -1033,7 +3444,7 @@ func (s *SpotDual) shiftBuyNeed(ctx context.Context, shift int) (decimal.Decimal
 	if shift < 1 {
 		return decimal.Zero, nil
 	}
-	required := s.orderQty().Mul(decimal.NewFromInt(int64(shift)))
+	required := s.orderQtyForSide(core.Sell).Mul(decimal.NewFromInt(int64(shift)))
 	if required.Cmp(decimal.Zero) <= 0 {
 		return decimal.Zero, nil
 	}
@@ -1052,6 +3463,48 @@ func (s *SpotDual) shiftBuyNeed(ctx context.Context, shift int) (decimal.Decimal
 	return s.roundQtyUp(need), nil
 }
 
+// sellExceedsInventory checks qty plus resting sells against the base
+// balance. The balance is cached for inventoryRefreshInterval and re-read
+// before a sell is refused, so fills since the last read are not held
+// against it. A failed balance read lets the order through.
+func (s *SpotDual) sellExceedsInventory(ctx context.Context, qty decimal.Decimal) (bool, decimal.Decimal) {
+	now := s.now()
+	refreshed := false
+	if s.inventoryAt.IsZero() || now.Sub(s.inventoryAt) >= inventoryRefreshInterval {
+		if !s.refreshInventory(ctx, now) {
+			return false, decimal.Zero
+		}
+		refreshed = true
+	}
+	need := qty.Add(s.lockedSellBase())
+	if need.Cmp(s.inventoryBase) <= 0 {
+		return false, s.inventoryBase
+	}
+	if !refreshed && !s.refreshInventory(ctx, now) {
+		return false, decimal.Zero
+	}
+	return need.Cmp(s.inventoryBase) > 0, s.inventoryBase
+}
+
+// now is the time of the fill or tick being handled, or the wall clock before
+// the first one.
+func (s *SpotDual) now() time.Time {
+	if s.eventAt.IsZero() {
+		return time.Now()
+	}
+	return s.eventAt
+}
+
+func (s *SpotDual) refreshInventory(ctx context.Context, now time.Time) bool {
+	bal, err := s.executor.Balances(ctx)
+	if err != nil {
+		return false
+	}
+	s.inventoryBase = bal.Base
+	s.inventoryAt = now
+	return true
+}
+
 func (s *SpotDual) lockedSellBase() decimal.Decimal {
 	total := decimal.Zero
 	for _, ord := range s.openOrders {
@@ -1159,14 +3612,14 @@ func (s *SpotDual) persistSnapshot() error {
 			"stage": "save_grid_state",
 			"err":   err.Error(),
 		})
-		return err
+		return fmt.Errorf("%w: save grid state: %w", ErrFatal, err)
 	}
 	if err := s.store.SaveOpenOrders(s.snapshotOrders()); err != nil {
 		s.alertImportant("state_persist_failed", map[string]string{
 			"stage": "save_open_orders",
 			"err":   err.Error(),
 		})
-		return err
+		return fmt.Errorf("%w: save open orders: %w", ErrFatal, err)
 	}
 	return nil
 }
@@ -1184,9 +3637,15 @@ func (s *SpotDual) snapshotState() store.GridState {
 		Symbol:             s.Symbol,
 		Anchor:             s.anchor,
 		StopPrice:          s.StopPrice,
+		FloorPrice:         s.FloorPrice,
+		TakeProfitPrice:    s.TakeProfitPrice,
+		BuysHalted:         s.buysHalted,
 		Ratio:              s.Ratio,
 		BaseRatio:          s.baseBuyRatio,
 		SellRatio:          s.SellRatio,
+		SellSpacingFactor:  s.sellSpacingFactor,
+		BalancedLevels:     s.balancedLevels,
+		Bias:               s.Bias,
 		Levels:             s.Levels,
 		MinLevel:           s.minLevel,
 		MaxLevel:           s.maxLevel,
@@ -1197,10 +3656,20 @@ func (s *SpotDual) snapshotState() store.GridState {
 		Stopped:            s.stopped,
 		LastDownShiftPrice: s.lastDownShiftPrice,
 		LastDownShiftAt:    s.lastDownShiftAt,
+		LastRebuildAt:      s.lastRebuildAt,
+		LastShiftUpAt:      s.lastShiftUpAt,
+		ShiftUpDeferred:    s.shiftUpDeferred,
+		PendingWindow:      s.pendingWindowChange,
 	}
 	if s.minLevel != 0 {
 		state.Low = s.priceForLevel(s.minLevel)
 	}
+	for idx, held := range s.heldCounters {
+		state.HeldCounters = append(state.HeldCounters, store.HeldCounter{Level: idx, Side: held.side, Until: held.until})
+	}
+	sort.Slice(state.HeldCounters, func(i, j int) bool {
+		return state.HeldCounters[i].Level < state.HeldCounters[j].Level
+	})
 	return state
 }
 
@@ -1237,3 +3706,54 @@ func powDecimal(base decimal.Decimal, exp int) decimal.Decimal {
 	}
 	return result
 }
+
+// suspiciousSnapshot reports whether this reconcile should be skipped because
+// the exchange snapshot lacks most of the orders the grid tracks. Levels left
+// empty on purpose (resting caps, held counters, missing inventory) are not
+// tracked and so never count. Only the first such snapshot in a row is
+// skipped, so a real mass cancel is repaired on the next pass.
+func (s *SpotDual) suspiciousSnapshot(openOrders []core.Order) bool {
+	if s.suspiciousSnapshotPct.Cmp(decimal.Zero) <= 0 || len(s.openOrders) == 0 {
+		s.suspiciousSnapshotSeen = false
+		return false
+	}
+	onExchange := make(map[string]struct{}, len(openOrders))
+	for _, ord := range openOrders {
+		if ord.ID != "" {
+			onExchange[ord.ID] = struct{}{}
+		}
+	}
+	tracked, missing := 0, 0
+	for id := range s.openOrders {
+		if id == "" {
+			continue
+		}
+		tracked++
+		if _, ok := onExchange[id]; !ok {
+			missing++
+		}
+	}
+	limit := decimal.NewFromInt(int64(tracked)).Mul(s.suspiciousSnapshotPct).Div(decimal.NewFromInt(100))
+	if tracked == 0 || decimal.NewFromInt(int64(missing)).Cmp(limit) <= 0 {
+		s.suspiciousSnapshotSeen = false
+		return false
+	}
+	action := "skip_reconcile"
+	if s.suspiciousSnapshotSeen {
+		action = "reconcile_confirmed"
+	}
+	s.alertImportant("suspicious_empty_snapshot", map[string]string{
+		"symbol":          s.Symbol,
+		"tracked_orders":  strconv.Itoa(tracked),
+		"exchange_orders": strconv.Itoa(len(openOrders)),
+		"missing_orders":  strconv.Itoa(missing),
+		"threshold_pct":   s.suspiciousSnapshotPct.String(),
+		"action":          action,
+	})
+	if s.suspiciousSnapshotSeen {
+		s.suspiciousSnapshotSeen = false
+		return false
+	}
+	s.suspiciousSnapshotSeen = true
+	return true
+}
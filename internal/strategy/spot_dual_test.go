@@ -2,8 +2,14 @@ package strategy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -55,6 +61,15 @@ func (f *fakeExecutor) Balances(_ context.Context) (core.Balance, error) {
 	return f.balance, nil
 }
 
+type listingExecutor struct {
+	fakeExecutor
+	open []core.Order
+}
+
+func (f *listingExecutor) OpenOrders(_ context.Context, _ string) ([]core.Order, error) {
+	return f.open, nil
+}
+
 type insufficientDuringRebuildExecutor struct {
 	fakeExecutor
 	failMarketBuy bool
@@ -248,6 +263,73 @@ func TestSpotDualInitBuysMissingBaseAtStartup(t *testing.T) {
 	}
 }
 
+type tickerExecutor struct {
+	fakeExecutor
+	price decimal.Decimal
+	reads int
+}
+
+func (f *tickerExecutor) TickerPrice(_ context.Context, _ string) (decimal.Decimal, error) {
+	f.reads++
+	return f.price, nil
+}
+
+func TestSpotDualInitReanchorsAfterBootstrapBuyMove(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "0")
+	exec := &tickerExecutor{
+		fakeExecutor: fakeExecutor{balance: core.Balance{Quote: decimal.NewFromInt(1_000_000)}},
+		price:        decimal.NewFromInt(103),
+	}
+	s.executor = exec
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetBootstrapReanchor(time.Millisecond, decimal.NewFromInt(2))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if exec.reads != 1 {
+		t.Fatalf("ticker reads = %d, want 1", exec.reads)
+	}
+	if !s.anchor.Equal(decimal.NewFromInt(103)) {
+		t.Fatalf("anchor = %s, want 103", s.anchor)
+	}
+	sell, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok {
+		t.Fatalf("missing sell at level 1")
+	}
+	if want := decimal.RequireFromString("113.3"); !sell.Price.Equal(want) {
+		t.Fatalf("sell price = %s, want %s", sell.Price, want)
+	}
+	found := false
+	for i, event := range alerts.events {
+		if event == "bootstrap_reanchored" {
+			found = true
+			if alerts.fields[i]["prev_anchor"] != "100" || alerts.fields[i]["anchor"] != "103" {
+				t.Fatalf("bootstrap_reanchored fields = %v", alerts.fields[i])
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("alerts = %v, want bootstrap_reanchored", alerts.events)
+	}
+}
+
+func TestSpotDualInitKeepsAnchorWhenBootstrapMoveWithinThreshold(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "0")
+	exec := &tickerExecutor{
+		fakeExecutor: fakeExecutor{balance: core.Balance{Quote: decimal.NewFromInt(1_000_000)}},
+		price:        decimal.NewFromInt(101),
+	}
+	s.executor = exec
+	s.SetBootstrapReanchor(0, decimal.NewFromInt(2))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if !s.anchor.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("anchor = %s, want 100", s.anchor)
+	}
+}
+
 func TestSpotDualOnFillSellAtTopShiftsUp(t *testing.T) {
 	s, exec := newSpotDualForTest(3, 1, "10")
 	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
@@ -330,6 +412,174 @@ func TestSpotDualOnFillBuyAtBottomExtendsDown(t *testing.T) {
 	}
 }
 
+func restingLevels(s *SpotDual, side core.Side) []int {
+	var levels []int
+	for _, ord := range s.openOrders {
+		if ord.Side == side {
+			levels = append(levels, ord.GridIndex)
+		}
+	}
+	sort.Ints(levels)
+	return levels
+}
+
+func TestSpotDualExtendDownKeepsRestingBuysAtCap(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetMaxResting(3, 0)
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	// The bottom buy fills while -1 and -2 still rest, as when one tick
+	// crosses them all and the deepest fill is reported first.
+	bottomBuy, ok := findOpenOrder(s, core.Buy, s.minLevel)
+	if !ok {
+		t.Fatalf("missing bottom buy order")
+	}
+	trade := core.Trade{
+		OrderID: bottomBuy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   bottomBuy.Price,
+		Qty:     bottomBuy.Qty,
+		Time:    time.Now().UTC(),
+	}
+	if err := s.OnFill(context.Background(), trade); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+
+	if s.minLevel != -6 {
+		t.Fatalf("minLevel = %d, want -6", s.minLevel)
+	}
+	if got := restingLevels(s, core.Buy); fmt.Sprint(got) != "[-4 -2 -1]" {
+		t.Fatalf("resting buys = %v, want [-4 -2 -1]", got)
+	}
+	found := false
+	for i, event := range alerts.events {
+		if event == "resting_cap_reached" && alerts.fields[i]["level"] == "-5" {
+			found = alerts.fields[i]["action"] == "skip_level"
+		}
+	}
+	if !found {
+		t.Fatalf("want resting_cap_reached skip_level for -5, got %v %v", alerts.events, alerts.fields)
+	}
+}
+
+func TestSpotDualInitRefusesUnfundableMinNotionalQty(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	// 5 USDT at 0.00001 needs 500000 units, far above the 1000 cap.
+	s.rules = core.Rules{QtyStep: decimal.NewFromInt(1), MinNotional: decimal.NewFromInt(5)}
+	s.SetMaxOrderQty(decimal.NewFromInt(1000))
+
+	err := s.Init(context.Background(), decimal.RequireFromString("0.00001"))
+	if !errors.Is(err, core.ErrMinNotionalUnfundable) {
+		t.Fatalf("Init() error = %v, want %v", err, core.ErrMinNotionalUnfundable)
+	}
+	for _, ord := range exec.placed {
+		if ord.Type == core.Limit && ord.Qty.Cmp(decimal.NewFromInt(1000)) > 0 {
+			t.Fatalf("placed oversized order %+v", ord)
+		}
+	}
+}
+
+func TestSpotDualQuoteBudgetStopsBuysAndExtendDown(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	// Buys at anchor 100 cost about 90.9, 82.6 and 75.1; 180 fits two.
+	s.SetCapitalBudget(decimal.NewFromInt(180))
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if got := restingLevels(s, core.Buy); fmt.Sprint(got) != "[-2 -1]" {
+		t.Fatalf("resting buys after Init = %v, want [-2 -1]", got)
+	}
+	if err := s.extendDown(context.Background(), time.Now().UTC()); err != nil {
+		t.Fatalf("extendDown() error = %v", err)
+	}
+	if got := restingLevels(s, core.Buy); fmt.Sprint(got) != "[-2 -1]" {
+		t.Fatalf("resting buys after extendDown = %v, want [-2 -1]", got)
+	}
+	reached := 0
+	for _, event := range alerts.events {
+		if event == "capital_budget_reached" {
+			reached++
+		}
+	}
+	if reached != 1 {
+		t.Fatalf("capital_budget_reached alerts = %d, want 1 (%v)", reached, alerts.events)
+	}
+}
+
+func TestSpotDualSideQtyUsesBuyAndSellQty(t *testing.T) {
+	s, _ := newSpotDualForTest(2, 2, "10")
+	s.SetSideQty(decimal.RequireFromString("1.5"), decimal.RequireFromString("0.5"))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	buys, sells := 0, 0
+	for _, ord := range s.openOrders {
+		want := decimal.RequireFromString("0.5")
+		if ord.Side == core.Buy {
+			want = decimal.RequireFromString("1.5")
+			buys++
+		} else {
+			sells++
+		}
+		if !ord.Qty.Equal(want) {
+			t.Fatalf("%s level %d qty = %s, want %s", ord.Side, ord.GridIndex, ord.Qty, want)
+		}
+	}
+	if buys != 2 || sells != 2 {
+		t.Fatalf("resting buys/sells = %d/%d, want 2/2", buys, sells)
+	}
+}
+
+func TestSpotDualShiftUpAtBuyCapCancelsDeepestBuy(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetMaxResting(2, 0)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	deepest, ok := findOpenOrder(s, core.Buy, -2)
+	if !ok {
+		t.Fatalf("missing buy at -2")
+	}
+	next, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy at -1")
+	}
+	topSell, ok := findOpenOrder(s, core.Sell, s.maxLevel)
+	if !ok {
+		t.Fatalf("missing top sell order")
+	}
+	trade := core.Trade{
+		OrderID: topSell.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Sell,
+		Price:   topSell.Price,
+		Qty:     topSell.Qty,
+		Time:    time.Now().UTC(),
+	}
+	if err := s.OnFill(context.Background(), trade); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+
+	// The counter buy at 0 displaces -2, then the shift's new buy at 1
+	// displaces -1: the resting count never exceeds the cap.
+	if got := restingLevels(s, core.Buy); fmt.Sprint(got) != "[0 1]" {
+		t.Fatalf("resting buys = %v, want [0 1]", got)
+	}
+	canceled := map[string]bool{}
+	for _, id := range exec.canceled {
+		canceled[id] = true
+	}
+	if !canceled[deepest.ID] || !canceled[next.ID] {
+		t.Fatalf("canceled = %v, want %s and %s", exec.canceled, deepest.ID, next.ID)
+	}
+}
+
 func TestSpotDualOnFillBuyAtBottomExtendsDownWithRatioQtyMultiple(t *testing.T) {
 	s, _ := newSpotDualForTest(3, 1, "10")
 	s.SetRatioQtyMultiple(decimal.RequireFromString("1.2"))
@@ -565,6 +815,62 @@ func TestSpotDualDownShiftDefenseAfterRestoreRaisesOnNextShift(t *testing.T) {
 	}
 }
 
+func TestSpotDualShiftUpCooldownDefersShiftUntilElapsed(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetShiftUpCooldown(time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	fillTop := func(at time.Time) {
+		t.Helper()
+		top, ok := findOpenOrder(s, core.Sell, s.maxLevel)
+		if !ok {
+			t.Fatalf("missing top sell at %d", s.maxLevel)
+		}
+		trade := core.Trade{OrderID: top.ID, Symbol: s.Symbol, Side: core.Sell, Price: top.Price, Qty: top.Qty, Time: at}
+		if err := s.OnFill(context.Background(), trade); err != nil {
+			t.Fatalf("OnFill() error = %v", err)
+		}
+	}
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fillTop(t0)
+	if s.maxLevel != 2 {
+		t.Fatalf("maxLevel = %d after first top fill, want 2", s.maxLevel)
+	}
+	fillTop(t0.Add(10 * time.Second))
+	if s.maxLevel != 2 || !s.shiftUpDeferred {
+		t.Fatalf("maxLevel = %d deferred = %v within cooldown, want 2 and true", s.maxLevel, s.shiftUpDeferred)
+	}
+	if _, ok := findOpenOrder(s, core.Buy, 1); !ok {
+		t.Fatalf("missing counter buy at 1 during cooldown")
+	}
+	if alerts.events[len(alerts.events)-1] != "shift_up_deferred" {
+		t.Fatalf("alerts = %v, want shift_up_deferred last", alerts.events)
+	}
+	if state := s.snapshotState(); !state.LastShiftUpAt.Equal(t0) || !state.ShiftUpDeferred {
+		t.Fatalf("state last_shift_up_at = %s deferred = %v, want %s and true", state.LastShiftUpAt, state.ShiftUpDeferred, t0)
+	}
+
+	price := decimal.RequireFromString("125")
+	if err := s.OnTick(context.Background(), price, t0.Add(30*time.Second)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if s.maxLevel != 2 {
+		t.Fatalf("maxLevel = %d before cooldown elapsed, want 2", s.maxLevel)
+	}
+	if err := s.OnTick(context.Background(), price, t0.Add(61*time.Second)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if s.maxLevel != 3 || s.shiftUpDeferred {
+		t.Fatalf("maxLevel = %d deferred = %v after cooldown, want 3 and false", s.maxLevel, s.shiftUpDeferred)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 3); !ok {
+		t.Fatalf("missing sell at 3 after the deferred shift")
+	}
+}
+
 func TestSpotDualShiftUpCancelFailureKeepsFailedOrderTracked(t *testing.T) {
 	s, exec := newSpotDualForTest(3, 1, "10")
 	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
@@ -929,6 +1235,63 @@ func TestSpotDualReconcileFillsOrderGaps(t *testing.T) {
 	}
 }
 
+func TestSpotDualReconcileMapsOffLevelOrdersToNearestLevel(t *testing.T) {
+	open := []core.Order{
+		// Exactly on the anchor.
+		{ID: "anchor-buy", Symbol: "BTCUSDT", Side: core.Buy, Type: core.Limit, Price: decimal.NewFromInt(100), Qty: decimal.NewFromInt(1)},
+		// Between levels 1 (110) and 2 (121): nearer to 1.
+		{ID: "near-sell", Symbol: "BTCUSDT", Side: core.Sell, Type: core.Limit, Price: decimal.RequireFromString("110.5"), Qty: decimal.NewFromInt(1)},
+		// Exactly halfway: a sell goes to the upper level.
+		{ID: "halfway-sell", Symbol: "BTCUSDT", Side: core.Sell, Type: core.Limit, Price: decimal.RequireFromString("115.5"), Qty: decimal.NewFromInt(1)},
+	}
+	load := func(mapping string) (*SpotDual, *fakeExecutor) {
+		s, exec := newSpotDualForTest(2, 1, "10")
+		s.SetLevelMapping(mapping)
+		s.LoadState(store.GridState{
+			Symbol:      "BTCUSDT",
+			Anchor:      decimal.NewFromInt(100),
+			Ratio:       decimal.RequireFromString("1.1"),
+			MinLevel:    -2,
+			MaxLevel:    2,
+			Initialized: true,
+		})
+		return s, exec
+	}
+
+	exact, _ := load(LevelMappingExact)
+	if err := exact.Reconcile(context.Background(), decimal.NewFromInt(100), open); err != nil {
+		t.Fatalf("Reconcile(exact) error = %v", err)
+	}
+	if _, ok := exact.openOrders["near-sell"]; ok {
+		t.Fatalf("exact mapping tracked an off-level order")
+	}
+	if ord, ok := exact.openOrders["anchor-buy"]; !ok || ord.GridIndex != 0 {
+		t.Fatalf("exact mapping anchor order = %+v, %v; want level 0", ord, ok)
+	}
+
+	s, exec := load(LevelMappingNearest)
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), open); err != nil {
+		t.Fatalf("Reconcile(nearest) error = %v", err)
+	}
+	for id, want := range map[string]int{"anchor-buy": 0, "near-sell": 1, "halfway-sell": 2} {
+		ord, ok := s.openOrders[id]
+		if !ok || ord.GridIndex != want {
+			t.Fatalf("order %s = %+v, %v; want tracked at level %d", id, ord, ok, want)
+		}
+	}
+	for _, ord := range exec.placed {
+		if ord.Side == core.Sell {
+			t.Fatalf("placed sell at level %d, want the mapped sells to cover levels 1 and 2", ord.GridIndex)
+		}
+	}
+	if idx, ok := s.nearestLevel(decimal.RequireFromString("115.5"), core.Buy); !ok || idx != 1 {
+		t.Fatalf("nearestLevel(halfway, buy) = %d, %v; want 1", idx, ok)
+	}
+	if _, ok := s.nearestLevel(decimal.NewFromInt(200), core.Sell); ok {
+		t.Fatalf("nearestLevel(outside window) mapped, want unmapped")
+	}
+}
+
 func TestSpotDualReconcileKeepsZeroMinLevelAfterShiftedState(t *testing.T) {
 	s, _ := newSpotDualForTest(3, 1, "10")
 	s.LoadState(store.GridState{
@@ -1329,3 +1692,1922 @@ func TestSpotDualReconcileReturnsErrorWhenBaseBuyForMissingSellsFails(t *testing
 		t.Fatalf("unexpected sell order at level 1 when base buy failed")
 	}
 }
+
+func TestSpotDualVolatilityPauseDefersPlacementsUntilCalm(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetVolatilityPause(time.Minute, decimal.NewFromInt(5), 2*time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), base); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if s.volatilityPaused {
+		t.Fatalf("strategy should not pause on first tick")
+	}
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(90), base.Add(20*time.Second)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if !s.volatilityPaused {
+		t.Fatalf("strategy should pause after a 10%% move within the window")
+	}
+
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	placedBefore := len(exec.placed)
+	trade := core.Trade{
+		OrderID: buy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   buy.Price,
+		Qty:     buy.Qty,
+		Time:    base.Add(30 * time.Second),
+	}
+	if err := s.OnFill(context.Background(), trade); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if len(exec.placed) != placedBefore {
+		t.Fatalf("placed orders = %d, want %d while paused", len(exec.placed), placedBefore)
+	}
+	if hasAnyOpenOrderAtLevel(s, 0) {
+		t.Fatalf("counter sell at level 0 should be deferred while paused")
+	}
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(90), base.Add(90*time.Second)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if !s.volatilityPaused {
+		t.Fatalf("strategy should stay paused during cooldown")
+	}
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(90), base.Add(3*time.Minute)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if s.volatilityPaused {
+		t.Fatalf("strategy should resume after a calm cooldown")
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 0); !ok {
+		t.Fatalf("deferred counter sell at level 0 should be placed on resume")
+	}
+}
+
+func TestSpotDualVolatilityPauseTripsOnCrashFills(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetVolatilityPause(time.Minute, decimal.NewFromInt(5), 2*time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	// No tick arrives between the fills, as in live between reconciles.
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, level := range []int{-1, -2} {
+		buy, ok := findOpenOrder(s, core.Buy, level)
+		if !ok {
+			t.Fatalf("missing buy order at level %d", level)
+		}
+		if err := s.OnFill(context.Background(), core.Trade{
+			OrderID: buy.ID,
+			Symbol:  s.Symbol,
+			Side:    core.Buy,
+			Price:   buy.Price,
+			Qty:     buy.Qty,
+			Time:    base.Add(time.Duration(i) * 10 * time.Second),
+		}); err != nil {
+			t.Fatalf("OnFill(level %d) error = %v", level, err)
+		}
+	}
+	if !s.volatilityPaused {
+		t.Fatalf("strategy should pause on fills moving more than 5%% within the window")
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 0); !ok {
+		t.Fatalf("counter sell at level 0 from the first fill should be placed")
+	}
+	if hasAnyOpenOrderAtLevel(s, -1) {
+		t.Fatalf("counter sell at level -1 should be deferred while paused")
+	}
+}
+
+func TestSpotDualBalanceFloorPausesPlacementsUntilReplenished(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetBalanceFloor(decimal.NewFromInt(500), decimal.Zero)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	ctx := context.Background()
+	low := core.Balance{Base: decimal.NewFromInt(10), Quote: decimal.NewFromInt(100)}
+	for i := 0; i < 2; i++ {
+		if err := s.ObserveBalance(ctx, low); err != nil {
+			t.Fatalf("ObserveBalance(low) error = %v", err)
+		}
+	}
+	if !s.balancePaused {
+		t.Fatalf("strategy should pause with free quote below the floor")
+	}
+
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	placedBefore := len(exec.placed)
+	if err := s.OnFill(ctx, core.Trade{
+		OrderID: buy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   buy.Price,
+		Qty:     buy.Qty,
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if len(exec.placed) != placedBefore || hasAnyOpenOrderAtLevel(s, 0) {
+		t.Fatalf("counter sell placed while below the balance floor")
+	}
+
+	if err := s.ObserveBalance(ctx, core.Balance{Base: decimal.NewFromInt(10), Quote: decimal.NewFromInt(1000)}); err != nil {
+		t.Fatalf("ObserveBalance(replenished) error = %v", err)
+	}
+	if s.balancePaused {
+		t.Fatalf("strategy should resume once the balance recovers")
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 0); !ok {
+		t.Fatalf("deferred counter sell at level 0 should be placed on resume")
+	}
+	if got := strings.Join(alerts.events, ","); got != "balance_floor_reached,balance_floor_recovered" {
+		t.Fatalf("alerts = %s, want one balance_floor_reached then balance_floor_recovered", got)
+	}
+	if alerts.fields[0]["free_quote"] != "100" || alerts.fields[0]["floor_quote"] != "500" {
+		t.Fatalf("balance_floor_reached fields = %v", alerts.fields[0])
+	}
+}
+
+func TestSpotDualMinHoldDelaysCounterOrder(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetMinHold(time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	placedBefore := len(exec.placed)
+	if err := s.OnFill(context.Background(), core.Trade{
+		OrderID: buy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   buy.Price,
+		Qty:     buy.Qty,
+		Time:    base,
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if len(exec.placed) != placedBefore || hasAnyOpenOrderAtLevel(s, 0) {
+		t.Fatalf("counter sell at level 0 should be held right after the fill")
+	}
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(99), base.Add(30*time.Second)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if hasAnyOpenOrderAtLevel(s, 0) {
+		t.Fatalf("counter sell at level 0 should stay held within min hold")
+	}
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(99), base.Add(time.Minute)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 0); !ok {
+		t.Fatalf("held counter sell at level 0 should be placed once min hold elapses")
+	}
+	if len(s.heldCounters) != 0 {
+		t.Fatalf("held counters = %d, want 0 after release", len(s.heldCounters))
+	}
+}
+
+func TestSpotDualHeldCounterSurvivesRestartAndReleasesOnFill(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetMinHold(time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := s.OnFill(context.Background(), core.Trade{
+		OrderID: buy.ID, Symbol: s.Symbol, Side: core.Buy, Price: buy.Price, Qty: buy.Qty, Time: base,
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	state := s.snapshotState()
+	if len(state.HeldCounters) != 1 || state.HeldCounters[0].Level != 0 || state.HeldCounters[0].Side != core.Sell || !state.HeldCounters[0].Until.Equal(base.Add(time.Minute)) {
+		t.Fatalf("persisted held counters = %+v, want a sell at 0 until %s", state.HeldCounters, base.Add(time.Minute))
+	}
+
+	restarted, _ := newSpotDualForTest(3, 1, "10")
+	restarted.SetMinHold(time.Minute)
+	restarted.LoadState(state)
+	restarted.openOrders = s.openOrders
+	if held, ok := restarted.heldCounters[0]; !ok || held.side != core.Sell {
+		t.Fatalf("restored held counters = %+v, want a sell at 0", restarted.heldCounters)
+	}
+
+	// A later fill releases the due counter without waiting for a tick.
+	next, ok := findOpenOrder(restarted, core.Buy, -2)
+	if !ok {
+		t.Fatalf("missing buy order at level -2")
+	}
+	if err := restarted.OnFill(context.Background(), core.Trade{
+		OrderID: next.ID, Symbol: restarted.Symbol, Side: core.Buy, Price: next.Price, Qty: next.Qty, Time: base.Add(90 * time.Second),
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if _, ok := findOpenOrder(restarted, core.Sell, 0); !ok {
+		t.Fatalf("held counter sell at level 0 should be placed by the later fill")
+	}
+	if _, ok := restarted.heldCounters[-1]; !ok || len(restarted.heldCounters) != 1 {
+		t.Fatalf("held counters = %+v, want only the new sell at -1", restarted.heldCounters)
+	}
+}
+
+func TestSpotDualSkipsSellExceedingInventory(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetSellInventoryGuard(true)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 1); !ok {
+		t.Fatalf("missing sell order at level 1")
+	}
+
+	// Inventory drifted: only the base locked in the level 1 sell is left.
+	exec.balance.Base = s.lockedSellBase()
+	s.inventoryAt = time.Time{}
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	placedBefore := len(exec.placed)
+	if err := s.OnFill(context.Background(), core.Trade{
+		OrderID: buy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   buy.Price,
+		Qty:     buy.Qty,
+		Time:    time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if len(exec.placed) != placedBefore || hasAnyOpenOrderAtLevel(s, 0) {
+		t.Fatalf("counter sell at level 0 should be skipped when it exceeds inventory")
+	}
+	found := false
+	for i, ev := range alerts.events {
+		if ev == "sell_exceeds_inventory" {
+			found = true
+			if alerts.fields[i]["level"] != "0" || alerts.fields[i]["base"] != exec.balance.Base.String() {
+				t.Fatalf("sell_exceeds_inventory fields = %v", alerts.fields[i])
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("missing sell_exceeds_inventory alert, got %v", alerts.events)
+	}
+
+	// Once the base shows up, the same level places normally.
+	exec.balance.Base = exec.balance.Base.Add(decimal.NewFromInt(1))
+	if err := s.placeLimit(context.Background(), core.Sell, 0); err != nil {
+		t.Fatalf("placeLimit() error = %v", err)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 0); !ok {
+		t.Fatalf("sell at level 0 should be placed once inventory covers it")
+	}
+}
+
+func TestSpotDualSellInventoryGuardUsesEventTime(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetSellInventoryGuard(true)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	exec.balance.Base = decimal.NewFromInt(100)
+	s.inventoryAt = time.Time{}
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.OnFill(context.Background(), core.Trade{
+		OrderID: buy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   buy.Price,
+		Qty:     buy.Qty,
+		Time:    at,
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if !s.inventoryAt.Equal(at) {
+		t.Fatalf("inventoryAt = %v, want the fill time %v", s.inventoryAt, at)
+	}
+}
+
+type batchCancelExecutor struct {
+	fakeExecutor
+	batches [][]string
+	failIDs map[string]error
+}
+
+func (f *batchCancelExecutor) CancelOrders(_ context.Context, _ string, ids []string) map[string]error {
+	f.batches = append(f.batches, append([]string(nil), ids...))
+	failed := make(map[string]error)
+	for _, id := range ids {
+		if err, ok := f.failIDs[id]; ok {
+			failed[id] = err
+		}
+	}
+	return failed
+}
+
+func TestSpotDualReconcileBatchCancelsDuplicatesAndKeepsFailedTracked(t *testing.T) {
+	exec := &batchCancelExecutor{
+		fakeExecutor: fakeExecutor{
+			balance: core.Balance{Base: decimal.NewFromInt(10), Quote: decimal.NewFromInt(1_000_000)},
+		},
+		failIDs: map[string]error{"dup-c": errors.New("cancel rejected")},
+	}
+	s := NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.1"), 2, 1, decimal.NewFromInt(1), 1, core.Rules{}, nil, exec)
+	s.LoadState(store.GridState{
+		Symbol:      "BTCUSDT",
+		Anchor:      decimal.NewFromInt(100),
+		Ratio:       decimal.RequireFromString("1.1"),
+		MinLevel:    -2,
+		MaxLevel:    1,
+		Initialized: true,
+	})
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	order := func(id string, side core.Side, idx int, age time.Duration) core.Order {
+		return core.Order{ID: id, Symbol: "BTCUSDT", Side: side, Type: core.Limit, Price: s.priceForLevel(idx), Qty: decimal.NewFromInt(1), CreatedAt: created.Add(age)}
+	}
+	open := []core.Order{
+		order("keep", core.Sell, 1, 0),
+		order("dup-a", core.Sell, 1, time.Minute),
+		order("dup-b", core.Sell, 1, 2*time.Minute),
+		order("dup-c", core.Sell, 1, 3*time.Minute),
+		order("buy-1", core.Buy, -1, 0),
+		order("buy-2", core.Buy, -2, 0),
+	}
+
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), open); err == nil {
+		t.Fatalf("Reconcile() error = nil, want the failed cancel")
+	}
+	if len(exec.batches) != 1 || len(exec.batches[0]) != 3 {
+		t.Fatalf("cancel batches = %v, want one batch of 3", exec.batches)
+	}
+	if len(exec.canceled) != 0 {
+		t.Fatalf("single cancels = %v, want none", exec.canceled)
+	}
+	for _, id := range []string{"dup-a", "dup-b"} {
+		if _, ok := s.openOrders[id]; ok {
+			t.Fatalf("canceled duplicate %s should not stay tracked", id)
+		}
+	}
+	if _, ok := s.openOrders["dup-c"]; !ok {
+		t.Fatalf("duplicate dup-c whose cancel failed should stay tracked")
+	}
+	if _, ok := s.openOrders["keep"]; !ok {
+		t.Fatalf("kept order should stay tracked")
+	}
+}
+
+func TestSpotDualSnapAnchorPersistsTickAlignedAnchor(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.store = st
+	s.rules = core.Rules{PriceTick: decimal.RequireFromString("0.01")}
+	s.SetSnapAnchor(true)
+	if err := s.Init(context.Background(), decimal.RequireFromString("100.0063")); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	state, ok, err := st.LoadGridState()
+	if err != nil || !ok {
+		t.Fatalf("LoadGridState() = %v, %v", ok, err)
+	}
+	if want := decimal.RequireFromString("100.01"); !state.Anchor.Equal(want) {
+		t.Fatalf("persisted anchor = %s, want %s", state.Anchor, want)
+	}
+	if !state.Anchor.Mod(s.rules.PriceTick).IsZero() {
+		t.Fatalf("persisted anchor %s is not aligned to tick %s", state.Anchor, s.rules.PriceTick)
+	}
+	if idx, ok := s.indexForPrice(s.priceForLevel(0)); !ok || idx != 0 {
+		t.Fatalf("indexForPrice(level 0 price) = %d, %v, want 0, true", idx, ok)
+	}
+}
+
+func TestSpotDualRebuildAlertReportsSnappedAnchor(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.rules = core.Rules{PriceTick: decimal.RequireFromString("0.01")}
+	s.SetSnapAnchor(true)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if ok, err := s.Rebuild(context.Background(), decimal.RequireFromString("120.0063"), time.Time{}, "manual"); err != nil || !ok {
+		t.Fatalf("Rebuild() = %v, %v, want true, nil", ok, err)
+	}
+	for i, event := range alerts.events {
+		if event != "grid_rebuilt" {
+			continue
+		}
+		if got := alerts.fields[i]["anchor"]; got != s.anchor.String() || got != "120.01" {
+			t.Fatalf("grid_rebuilt anchor = %q, want the snapped anchor 120.01 (grid anchor %s)", got, s.anchor)
+		}
+		return
+	}
+	t.Fatalf("alerts = %v, want grid_rebuilt", alerts.events)
+}
+
+func TestSpotDualInitUsesConfiguredAnchorPrice(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetAnchorPrice(decimal.NewFromInt(95))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if !s.anchor.Equal(decimal.NewFromInt(95)) {
+		t.Fatalf("anchor = %s, want the configured 95 rather than the first price", s.anchor)
+	}
+	sell, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok || !sell.Price.Equal(decimal.RequireFromString("104.5")) {
+		t.Fatalf("sell at level 1 = %s, %v; want 104.5 off the configured anchor", sell.Price, ok)
+	}
+
+	above, _ := newSpotDualForTest(3, 1, "10")
+	above.StopPrice = decimal.NewFromInt(120)
+	above.SetAnchorPrice(decimal.NewFromInt(130))
+	if err := above.Init(context.Background(), decimal.NewFromInt(100)); err == nil {
+		t.Fatalf("Init() with anchor above stop_price error = nil, want an error")
+	}
+}
+
+func TestSpotDualCancelAllOrdersKeepsFailedOrdersTracked(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	total := len(s.openOrders)
+	stuck, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok {
+		t.Fatalf("missing sell order at level 1")
+	}
+	exec.cancelErrByID = map[string]error{stuck.ID: errors.New("timeout")}
+
+	canceled, err := s.CancelAllOrders(context.Background())
+	if err == nil {
+		t.Fatalf("CancelAllOrders() error = nil, want the failed cancel")
+	}
+	if canceled != total-1 || len(exec.canceled) != total {
+		t.Fatalf("canceled = %d (attempted %d), want %d of %d", canceled, len(exec.canceled), total-1, total)
+	}
+	if len(s.openOrders) != 1 || s.openOrders[stuck.ID].ID != stuck.ID {
+		t.Fatalf("tracked after drain = %v, want only %s", s.openOrders, stuck.ID)
+	}
+	if !s.initialized {
+		t.Fatalf("CancelAllOrders() should keep the grid initialized for the next reconcile")
+	}
+}
+
+func TestSpotDualReconcileSkipsSuspiciousEmptySnapshot(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 2, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	tracked := len(s.openOrders)
+	placed := len(exec.placed)
+	if tracked == 0 {
+		t.Fatalf("Init() tracked no orders")
+	}
+
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), nil); err != nil {
+		t.Fatalf("first Reconcile() error = %v", err)
+	}
+	if len(exec.placed) != placed || len(s.openOrders) != tracked {
+		t.Fatalf("first empty snapshot placed %d orders, tracked %d; want no placements and %d tracked", len(exec.placed)-placed, len(s.openOrders), tracked)
+	}
+	var fields map[string]string
+	for i, event := range alerts.events {
+		if event == "suspicious_empty_snapshot" {
+			fields = alerts.fields[i]
+		}
+	}
+	if fields == nil {
+		t.Fatalf("alerts = %v, want suspicious_empty_snapshot", alerts.events)
+	}
+	if fields["action"] != "skip_reconcile" || fields["exchange_orders"] != "0" || fields["tracked_orders"] != strconv.Itoa(tracked) {
+		t.Fatalf("suspicious_empty_snapshot fields = %v", fields)
+	}
+
+	// The same empty snapshot again is treated as real and the grid is
+	// re-placed.
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), nil); err != nil {
+		t.Fatalf("second Reconcile() error = %v", err)
+	}
+	if len(exec.placed) == placed {
+		t.Fatalf("second empty snapshot placed nothing, want the grid re-placed")
+	}
+}
+
+func TestSpotDualSuspiciousSnapshotIgnoresLevelsLeftEmptyOnPurpose(t *testing.T) {
+	ctx := context.Background()
+	reconcileTracked := func(t *testing.T, s *SpotDual, alerts *recordingAlerter) {
+		t.Helper()
+		open := make([]core.Order, 0, len(s.openOrders))
+		for _, ord := range s.openOrders {
+			open = append(open, ord)
+		}
+		if err := s.Reconcile(ctx, decimal.NewFromInt(100), open); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		for _, event := range alerts.events {
+			if event == "suspicious_empty_snapshot" {
+				t.Fatalf("alerts = %v, want no suspicious_empty_snapshot for a snapshot holding every tracked order", alerts.events)
+			}
+		}
+		if !s.initialized {
+			t.Fatalf("Reconcile() skipped a snapshot holding every tracked order")
+		}
+	}
+
+	t.Run("resting_cap", func(t *testing.T) {
+		s, _ := newSpotDualForTest(4, 2, "10")
+		alerts := &recordingAlerter{}
+		s.SetAlerter(alerts)
+		s.SetMaxResting(1, 1)
+		if err := s.Init(ctx, decimal.NewFromInt(100)); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		reconcileTracked(t, s, alerts)
+	})
+
+	t.Run("held_counter_without_inventory", func(t *testing.T) {
+		s, _ := newSpotDualForTest(2, 2, "0")
+		alerts := &recordingAlerter{}
+		s.SetAlerter(alerts)
+		s.SetBootstrapMarketBuy(false)
+		s.SetMinHold(time.Hour)
+		if err := s.Init(ctx, decimal.NewFromInt(100)); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		buy, ok := findOpenOrder(s, core.Buy, -1)
+		if !ok {
+			t.Fatal("Init() placed no buy at level -1")
+		}
+		if err := s.OnFill(ctx, core.Trade{OrderID: buy.ID, Side: core.Buy, Price: buy.Price, Qty: buy.Qty, Status: core.OrderFilled, Time: time.Now().UTC()}); err != nil {
+			t.Fatalf("OnFill() error = %v", err)
+		}
+		if len(s.heldCounters) != 1 {
+			t.Fatalf("held counters = %v, want the counter sell held", s.heldCounters)
+		}
+		reconcileTracked(t, s, alerts)
+	})
+}
+
+func TestSpotDualTakeProfitHaltsBuysButKeepsSelling(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	s, exec := newSpotDualForTest(3, 2, "10")
+	s.store = st
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetTakeProfitPrice(decimal.NewFromInt(105))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if _, ok := findOpenOrder(s, core.Buy, -1); !ok {
+		t.Fatalf("Init() placed no buy below take_profit_price")
+	}
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(106), time.Now().UTC()); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if s.stopped {
+		t.Fatalf("take profit must not stop the strategy")
+	}
+	for _, ord := range s.openOrders {
+		if ord.Side == core.Buy {
+			t.Fatalf("buy %s still tracked after take profit", ord.ID)
+		}
+	}
+	if len(exec.canceled) != 3 {
+		t.Fatalf("canceled = %v, want the 3 resting buys", exec.canceled)
+	}
+	sell, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok {
+		t.Fatalf("sell at level 1 canceled by take profit")
+	}
+	found := false
+	for i, event := range alerts.events {
+		if event == "take_profit_triggered" {
+			found = alerts.fields[i]["canceled_buys"] == "3"
+		}
+	}
+	if !found {
+		t.Fatalf("alerts = %v %v, want take_profit_triggered with canceled_buys=3", alerts.events, alerts.fields)
+	}
+
+	placed := len(exec.placed)
+	if err := s.OnFill(context.Background(), core.Trade{
+		OrderID: sell.ID,
+		Symbol:  "BTCUSDT",
+		Side:    core.Sell,
+		Price:   sell.Price,
+		Qty:     sell.Qty,
+		Status:  core.OrderFilled,
+		Time:    time.Now().UTC(),
+	}); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	for _, ord := range exec.placed[placed:] {
+		if ord.Side == core.Buy {
+			t.Fatalf("placed buy %s@%s after take profit", ord.Side, ord.Price)
+		}
+	}
+
+	state, ok, err := st.LoadGridState()
+	if err != nil || !ok {
+		t.Fatalf("LoadGridState() = %v, %v", ok, err)
+	}
+	if !state.BuysHalted || !state.TakeProfitPrice.Equal(decimal.NewFromInt(105)) {
+		t.Fatalf("persisted buys_halted=%v take_profit_price=%s, want true and 105", state.BuysHalted, state.TakeProfitPrice)
+	}
+
+	// After a restart the halt holds even below take_profit_price, and buys
+	// found on the exchange are canceled.
+	restarted, restartedExec := newSpotDualForTest(3, 2, "10")
+	restarted.LoadState(state)
+	stray := core.Order{ID: "stray-buy", Symbol: "BTCUSDT", Side: core.Buy, Type: core.Limit, Price: restarted.priceForLevel(-1), Qty: decimal.NewFromInt(1)}
+	if err := restarted.Reconcile(context.Background(), decimal.NewFromInt(100), []core.Order{stray}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(restartedExec.canceled) != 1 || restartedExec.canceled[0] != "stray-buy" {
+		t.Fatalf("canceled = %v, want [stray-buy]", restartedExec.canceled)
+	}
+	for _, ord := range restartedExec.placed {
+		if ord.Side == core.Buy {
+			t.Fatalf("Reconcile placed buy %s after restart with buys halted", ord.Price)
+		}
+	}
+}
+
+func TestSpotDualStopCancelsUntrackedInstanceOrders(t *testing.T) {
+	exec := &listingExecutor{
+		fakeExecutor: fakeExecutor{
+			balance: core.Balance{Base: decimal.NewFromInt(10), Quote: decimal.NewFromInt(1_000_000)},
+		},
+	}
+	s := NewSpotDual("BTCUSDT", decimal.NewFromInt(150), decimal.RequireFromString("1.1"), 3, 1, decimal.NewFromInt(1), 1, core.Rules{}, nil, exec)
+	s.SetStopCancelUntracked(true, "bot1")
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	exec.open = []core.Order{
+		{ID: "lost-1", ClientID: "bot1-abc-1", Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(70)},
+		{ID: "other-1", ClientID: "bot2-abc-1", Symbol: "BTCUSDT", Side: core.Buy, Price: decimal.NewFromInt(70)},
+		{ID: "manual-1", ClientID: "web_123", Symbol: "BTCUSDT", Side: core.Sell, Price: decimal.NewFromInt(200)},
+	}
+
+	_ = s.OnTick(context.Background(), decimal.NewFromInt(160), time.Now().UTC())
+	if !s.stopped {
+		t.Fatalf("strategy should be stopped")
+	}
+	canceled := make(map[string]bool)
+	for _, id := range exec.canceled {
+		canceled[id] = true
+	}
+	if !canceled["lost-1"] {
+		t.Fatalf("untracked instance order lost-1 should be canceled, canceled=%v", exec.canceled)
+	}
+	if canceled["other-1"] || canceled["manual-1"] {
+		t.Fatalf("orders from other prefixes must not be canceled, canceled=%v", exec.canceled)
+	}
+}
+
+func TestSpotDualSellRallyInvertsInitialFanOutAndShiftDirection(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetBias(BiasSellRally)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if s.maxLevel != 3 || s.minLevel != -1 {
+		t.Fatalf("window = [%d,%d], want [-1,3]", s.minLevel, s.maxLevel)
+	}
+	for _, level := range []int{1, 2, 3} {
+		if _, ok := findOpenOrder(s, core.Sell, level); !ok {
+			t.Fatalf("missing sell order at level %d", level)
+		}
+	}
+	bottomBuy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy order at level -1")
+	}
+	if len(s.openOrders) != 4 {
+		t.Fatalf("open orders = %d, want 4", len(s.openOrders))
+	}
+
+	trade := core.Trade{
+		OrderID: bottomBuy.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   bottomBuy.Price,
+		Qty:     bottomBuy.Qty,
+		Time:    time.Now().UTC(),
+	}
+	if err := s.OnFill(context.Background(), trade); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if s.minLevel != -2 || s.maxLevel != 2 {
+		t.Fatalf("window after bottom buy = [%d,%d], want [-2,2]", s.minLevel, s.maxLevel)
+	}
+	if hasAnyOpenOrderAtLevel(s, 3) {
+		t.Fatalf("top sell at level 3 should be canceled on shift down")
+	}
+	if _, ok := findOpenOrder(s, core.Buy, -2); !ok {
+		t.Fatalf("missing shifted buy at level -2")
+	}
+
+	topSell, ok := findOpenOrder(s, core.Sell, s.maxLevel)
+	if !ok {
+		t.Fatalf("missing top sell at level %d", s.maxLevel)
+	}
+	trade = core.Trade{
+		OrderID: topSell.ID,
+		Symbol:  s.Symbol,
+		Side:    core.Sell,
+		Price:   topSell.Price,
+		Qty:     topSell.Qty,
+		Time:    time.Now().UTC(),
+	}
+	if err := s.OnFill(context.Background(), trade); err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if s.maxLevel != 5 || s.minLevel != -2 {
+		t.Fatalf("window after top sell = [%d,%d], want [-2,5]", s.minLevel, s.maxLevel)
+	}
+	for _, level := range []int{3, 4, 5} {
+		if _, ok := findOpenOrder(s, core.Sell, level); !ok {
+			t.Fatalf("missing extended sell at level %d", level)
+		}
+	}
+	if !s.Ratio.Equal(decimal.RequireFromString("1.1")) {
+		t.Fatalf("buy ratio = %s, want unchanged 1.1 under sell_rally", s.Ratio)
+	}
+}
+
+func TestSpotDualBiasSurvivesRestart(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetBias(BiasSellRally)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	state := s.snapshotState()
+	if state.Bias != BiasSellRally {
+		t.Fatalf("persisted bias = %q, want %q", state.Bias, BiasSellRally)
+	}
+
+	restarted, _ := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	restarted.SetAlerter(alerts)
+	restarted.LoadState(state)
+	if restarted.Bias != BiasSellRally {
+		t.Fatalf("restored bias = %q, want %q", restarted.Bias, BiasSellRally)
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "bias_changed_on_restart" || alerts.fields[0]["configured"] != BiasBuyDip {
+		t.Fatalf("alerts = %v %v, want bias_changed_on_restart from %s", alerts.events, alerts.fields, BiasBuyDip)
+	}
+}
+
+func TestSpotDualInventoryAdaptiveSellTightensWhenOverweight(t *testing.T) {
+	newStrategy := func(base string) *SpotDual {
+		s, _ := newSpotDualForTest(4, 2, base)
+		s.SetSellRatio(decimal.RequireFromString("1.02"))
+		s.SetInventoryAdaptiveSell(InventoryAdaptiveSell{Enabled: true})
+		s.SetBootstrapMarketBuy(false)
+		if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+			t.Fatalf("init: %v", err)
+		}
+		return s
+	}
+
+	_, heavySell := newStrategy("6").effectiveRatios()
+	_, lightSell := newStrategy("1").effectiveRatios()
+	if !heavySell.Equal(decimal.RequireFromString("1.01")) {
+		t.Fatalf("heavy inventory sell ratio = %s, want clamped to 1.01", heavySell)
+	}
+	if !lightSell.Equal(decimal.RequireFromString("1.025")) {
+		t.Fatalf("light inventory sell ratio = %s, want 1.025", lightSell)
+	}
+}
+
+func TestSpotDualInventoryAdaptiveSellKeepsLevelsAcrossFills(t *testing.T) {
+	ctx := context.Background()
+	s, exec := newSpotDualForTest(4, 2, "6")
+	s.SetSellRatio(decimal.RequireFromString("1.02"))
+	s.SetInventoryAdaptiveSell(InventoryAdaptiveSell{Enabled: true})
+	if err := s.Init(ctx, decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	sell1, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok {
+		t.Fatal("missing sell at level 1")
+	}
+	sell2, ok := findOpenOrder(s, core.Sell, 2)
+	if !ok {
+		t.Fatal("missing sell at level 2")
+	}
+
+	if err := s.OnFill(ctx, core.Trade{OrderID: sell1.ID, Side: core.Sell, Price: sell1.Price, Qty: sell1.Qty, Status: core.OrderFilled}); err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+	open := make([]core.Order, 0, len(s.openOrders))
+	for _, ord := range s.openOrders {
+		open = append(open, ord)
+	}
+	placedBefore := len(exec.placed)
+	if err := s.Reconcile(ctx, decimal.NewFromInt(100), open); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	if len(exec.canceled) != 0 {
+		t.Fatalf("reconcile canceled %v, want none", exec.canceled)
+	}
+	if _, ok := s.openOrders[sell2.ID]; !ok {
+		t.Fatalf("resting sell %s at level 2 dropped by reconcile", sell2.ID)
+	}
+	perLevel := make(map[int]int)
+	for _, ord := range s.openOrders {
+		perLevel[ord.GridIndex]++
+	}
+	for level, n := range perLevel {
+		if n > 1 {
+			t.Fatalf("level %d holds %d orders after reconcile", level, n)
+		}
+	}
+	for _, ord := range exec.placed[placedBefore:] {
+		if ord.Type == core.Market || ord.GridIndex == 2 {
+			t.Fatalf("reconcile placed %s %s at level %d, want only the vacated level refilled", ord.Type, ord.Side, ord.GridIndex)
+		}
+	}
+}
+
+type recordingAlerter struct {
+	events []string
+	fields []map[string]string
+}
+
+func (a *recordingAlerter) Important(event string, fields map[string]string) {
+	a.events = append(a.events, event)
+	a.fields = append(a.fields, fields)
+}
+
+func TestSpotDualLoadStateRestoresPersistedFloorPrice(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetFloorPrice(decimal.NewFromInt(90))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	state := s.snapshotState()
+	if !state.FloorPrice.Equal(decimal.NewFromInt(90)) {
+		t.Fatalf("persisted floor = %s, want 90", state.FloorPrice)
+	}
+
+	restarted, exec := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	restarted.SetAlerter(alerts)
+	restarted.LoadState(state)
+	if !restarted.FloorPrice.Equal(decimal.NewFromInt(90)) {
+		t.Fatalf("restored floor = %s, want 90", restarted.FloorPrice)
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "stop_bound_missing_on_restart" {
+		t.Fatalf("alerts = %v, want [stop_bound_missing_on_restart]", alerts.events)
+	}
+	if alerts.fields[0]["bound"] != "floor_price" {
+		t.Fatalf("alert bound = %q, want floor_price", alerts.fields[0]["bound"])
+	}
+
+	restarted.openOrders = s.openOrders
+	err := restarted.OnTick(context.Background(), decimal.NewFromInt(89), time.Now().UTC())
+	if !restarted.stopped {
+		t.Fatalf("strategy should be stopped below persisted floor, err = %v", err)
+	}
+	if restarted.hasOpenBuyOrders() {
+		t.Fatalf("open buy orders should be canceled after floor stop")
+	}
+	if len(exec.canceled) == 0 {
+		t.Fatalf("expected buy cancels after floor stop")
+	}
+	if last := alerts.events[len(alerts.events)-1]; last != "strategy_floor_price_triggered" {
+		t.Fatalf("alerts = %v, want strategy_floor_price_triggered last", alerts.events)
+	}
+	if got := alerts.fields[len(alerts.fields)-1]["price"]; got != "89" {
+		t.Fatalf("floor stop price field = %q, want 89", got)
+	}
+}
+
+func TestSpotDualLoadStatePrefersChangedConfigStopBounds(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.SetFloorPrice(decimal.NewFromInt(90))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	state := s.snapshotState()
+
+	restarted, _ := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	restarted.SetAlerter(alerts)
+	restarted.SetFloorPrice(decimal.NewFromInt(80))
+	restarted.LoadState(state)
+	if !restarted.FloorPrice.Equal(decimal.NewFromInt(80)) {
+		t.Fatalf("floor after restart = %s, want configured 80", restarted.FloorPrice)
+	}
+	if !restarted.StopPrice.Equal(state.StopPrice) {
+		t.Fatalf("stop after restart = %s, want unchanged %s", restarted.StopPrice, state.StopPrice)
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "stop_bound_changed_on_restart" {
+		t.Fatalf("alerts = %v, want [stop_bound_changed_on_restart]", alerts.events)
+	}
+	if f := alerts.fields[0]; f["bound"] != "floor_price" || f["persisted"] != "90" || f["configured"] != "80" {
+		t.Fatalf("alert fields = %v, want floor_price persisted 90 configured 80", f)
+	}
+}
+
+func TestSpotDualRecenterRebuildsAfterSustainedDrift(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetRecenter(decimal.RequireFromString("1.1"), time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// The top level is 110, so recentering needs price above 121.
+	for _, step := range []struct {
+		price string
+		at    time.Duration
+	}{
+		{"130", 0},
+		{"120", 30 * time.Second},
+		{"130", 40 * time.Second},
+		{"130", 90 * time.Second},
+	} {
+		if err := s.OnTick(ctx, decimal.RequireFromString(step.price), start.Add(step.at)); err != nil {
+			t.Fatalf("OnTick(%s) error = %v", step.price, err)
+		}
+	}
+	// Dropping back inside at 30s restarted the clock, so 90s is only 50s in.
+	if !s.anchor.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("anchor = %s before the drift was sustained, want 100", s.anchor)
+	}
+	if err := s.OnTick(ctx, decimal.NewFromInt(130), start.Add(100*time.Second)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if !s.anchor.Equal(decimal.NewFromInt(130)) {
+		t.Fatalf("anchor = %s after sustained drift, want 130", s.anchor)
+	}
+	if got := alerts.events[len(alerts.events)-1]; got != "grid_recentered" {
+		t.Fatalf("last alert = %q, want grid_recentered", got)
+	}
+	if fields := alerts.fields[len(alerts.fields)-1]; fields["old_anchor"] != "100" || fields["new_anchor"] != "130" {
+		t.Fatalf("grid_recentered fields = %v, want old_anchor 100 and new_anchor 130", fields)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 1); !ok {
+		t.Fatalf("missing sell at 1 after recentering")
+	}
+}
+
+func TestSpotDualRebuildSuppressedWithinMinInterval(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetRecenter(decimal.RequireFromString("1.1"), time.Minute)
+	s.SetRebuildMinInterval(10 * time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := func(price string, at time.Duration) {
+		t.Helper()
+		if err := s.OnTick(ctx, decimal.RequireFromString(price), start.Add(at)); err != nil {
+			t.Fatalf("OnTick(%s, +%s) error = %v", price, at, err)
+		}
+	}
+
+	// Sustained drift above the window recenters at 130.
+	tick("130", 0)
+	tick("130", 70*time.Second)
+	if !s.anchor.Equal(decimal.NewFromInt(130)) {
+		t.Fatalf("anchor = %s after first recenter, want 130", s.anchor)
+	}
+	firstRebuildAt := start.Add(70 * time.Second)
+	canceledAfterFirst := len(exec.canceled)
+	placedAfterFirst := len(exec.placed)
+
+	// Drift below the new window is sustained too, but the rebuild interval
+	// has not passed since the first rebuild.
+	tick("80", 80*time.Second)
+	tick("80", 150*time.Second)
+	if !s.anchor.Equal(decimal.NewFromInt(130)) {
+		t.Fatalf("anchor after suppressed rebuild = %s, want 130", s.anchor)
+	}
+	if len(exec.canceled) != canceledAfterFirst || len(exec.placed) != placedAfterFirst {
+		t.Fatalf("suppressed rebuild touched orders: canceled %d->%d placed %d->%d", canceledAfterFirst, len(exec.canceled), placedAfterFirst, len(exec.placed))
+	}
+	if got := alerts.events[len(alerts.events)-1]; got != "rebuild_suppressed_too_frequent" {
+		t.Fatalf("last alert = %q, want rebuild_suppressed_too_frequent", got)
+	}
+	if fields := alerts.fields[len(alerts.fields)-1]; fields["trigger"] != "recenter" {
+		t.Fatalf("rebuild_suppressed_too_frequent fields = %v, want trigger recenter", fields)
+	}
+	if !s.snapshotState().LastRebuildAt.Equal(firstRebuildAt) {
+		t.Fatalf("persisted last rebuild = %s, want %s", s.snapshotState().LastRebuildAt, firstRebuildAt)
+	}
+
+	// A reconcile in between does not rebuild either.
+	open := make([]core.Order, 0, len(s.openOrders))
+	for _, ord := range s.openOrders {
+		open = append(open, ord)
+	}
+	if err := s.Reconcile(ctx, decimal.NewFromInt(80), open); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !s.anchor.Equal(decimal.NewFromInt(130)) {
+		t.Fatalf("anchor after reconcile = %s, want 130", s.anchor)
+	}
+
+	// Once the interval has passed the still-sustained drift rebuilds.
+	tick("80", 12*time.Minute)
+	if !s.anchor.Equal(decimal.NewFromInt(80)) {
+		t.Fatalf("anchor = %s after the rebuild interval, want 80", s.anchor)
+	}
+	if got := alerts.events[len(alerts.events)-1]; got != "grid_recentered" {
+		t.Fatalf("last alert = %q, want grid_recentered", got)
+	}
+}
+
+func TestSpotDualOrderAuditRecordsAssignedOrderID(t *testing.T) {
+	root := t.TempDir()
+	st, err := store.New(root)
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.store = st
+	s.SetOrderAudit(true)
+	s.anchor = decimal.NewFromInt(100)
+	s.minLevel = -3
+	s.maxLevel = 1
+
+	if err := s.placeLimit(context.Background(), core.Buy, -1); err != nil {
+		t.Fatalf("placeLimit() error = %v", err)
+	}
+	placed, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing placed buy order")
+	}
+
+	files, err := filepath.Glob(filepath.Join(root, "order_audit", "*.jsonl"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("audit files = %v (err %v), want 1", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read audit file: %v", err)
+	}
+	var entry store.OrderAuditEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("decode audit entry: %v", err)
+	}
+	if entry.OrderID != placed.ID {
+		t.Fatalf("audit order id = %q, want %q", entry.OrderID, placed.ID)
+	}
+	if entry.Side != string(core.Buy) || entry.GridIndex != -1 || !entry.Price.Equal(placed.Price) {
+		t.Fatalf("audit entry = %+v, want buy at level -1 price %s", entry, placed.Price)
+	}
+}
+
+func TestSpotDualReconcileReplacesExternallyAmendedOrder(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetExternalAmendmentAction(AmendmentReplace)
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	amended, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy at level -1")
+	}
+
+	exchangeOrders := make([]core.Order, 0, len(s.openOrders))
+	for _, ord := range s.openOrders {
+		if ord.ID == amended.ID {
+			ord.Qty = decimal.NewFromInt(5)
+		}
+		exchangeOrders = append(exchangeOrders, ord)
+	}
+
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), exchangeOrders); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(exec.canceled) != 1 || exec.canceled[0] != amended.ID {
+		t.Fatalf("canceled = %v, want [%s]", exec.canceled, amended.ID)
+	}
+	replaced, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("level -1 should be re-placed")
+	}
+	if replaced.ID == amended.ID || !replaced.Qty.Equal(decimal.NewFromInt(1)) || !replaced.Price.Equal(amended.Price) {
+		t.Fatalf("replacement = %+v, want new order at %s qty 1", replaced, amended.Price)
+	}
+	if len(alerts.events) == 0 || alerts.events[0] != "order_amended_externally" {
+		t.Fatalf("alerts = %v, want order_amended_externally first", alerts.events)
+	}
+	if alerts.fields[0]["action"] != AmendmentReplace {
+		t.Fatalf("amendment action = %q, want replace", alerts.fields[0]["action"])
+	}
+}
+
+func TestSpotDualReconcileRealignsExternallyAmendedQty(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	amended, _ := findOpenOrder(s, core.Sell, 1)
+	exchangeOrders := make([]core.Order, 0, len(s.openOrders))
+	for _, ord := range s.openOrders {
+		if ord.ID == amended.ID {
+			ord.Qty = decimal.NewFromInt(2)
+		}
+		exchangeOrders = append(exchangeOrders, ord)
+	}
+
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), exchangeOrders); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(exec.canceled) != 0 {
+		t.Fatalf("canceled = %v, want none in realign mode", exec.canceled)
+	}
+	if got := s.openOrders[amended.ID].Qty; !got.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("tracked qty = %s, want exchange qty 2", got)
+	}
+}
+
+func TestSpotDualOnFillRejectsOversizedFill(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok {
+		t.Fatalf("missing buy at level -1")
+	}
+	openBefore := len(s.openOrders)
+	placedBefore := len(exec.placed)
+	minBefore, maxBefore := s.minLevel, s.maxLevel
+
+	err := s.OnFill(context.Background(), core.Trade{
+		OrderID: buy.ID,
+		TradeID: "t-1",
+		Symbol:  s.Symbol,
+		Side:    core.Buy,
+		Price:   buy.Price,
+		Qty:     buy.Qty.Mul(decimal.NewFromInt(10)),
+		Status:  core.OrderFilled,
+		Time:    time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("OnFill() error = %v", err)
+	}
+	if len(s.openOrders) != openBefore || len(exec.placed) != placedBefore {
+		t.Fatalf("oversized fill changed orders: open %d->%d placed %d->%d", openBefore, len(s.openOrders), placedBefore, len(exec.placed))
+	}
+	if tracked := s.openOrders[buy.ID]; !tracked.Qty.Equal(buy.Qty) {
+		t.Fatalf("tracked qty = %s, want %s", tracked.Qty, buy.Qty)
+	}
+	if s.minLevel != minBefore || s.maxLevel != maxBefore {
+		t.Fatalf("window moved: [%d,%d] -> [%d,%d]", minBefore, maxBefore, s.minLevel, s.maxLevel)
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "oversized_fill_rejected" {
+		t.Fatalf("alerts = %v, want [oversized_fill_rejected]", alerts.events)
+	}
+}
+
+func TestSpotDualAdaptiveShiftShrinksUnderRapidShifts(t *testing.T) {
+	shiftSizes := func(spacing time.Duration) []int {
+		s, _ := newSpotDualForTest(8, 4, "1000")
+		s.SetAdaptiveShift(10*time.Minute, 2)
+		if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		sizes := make([]int, 0, 3)
+		for i := 0; i < 3; i++ {
+			top, ok := findOpenOrder(s, core.Sell, s.maxLevel)
+			if !ok {
+				t.Fatalf("missing top sell at level %d", s.maxLevel)
+			}
+			before := s.maxLevel
+			if err := s.OnFill(context.Background(), core.Trade{
+				OrderID: top.ID,
+				Symbol:  s.Symbol,
+				Side:    core.Sell,
+				Price:   top.Price,
+				Qty:     top.Qty,
+				Status:  core.OrderFilled,
+				Time:    start.Add(time.Duration(i) * spacing),
+			}); err != nil {
+				t.Fatalf("OnFill() error = %v", err)
+			}
+			sizes = append(sizes, s.maxLevel-before)
+		}
+		return sizes
+	}
+
+	rapid := shiftSizes(time.Second)
+	spaced := shiftSizes(time.Hour)
+	if rapid[0] != 4 || rapid[1] != 4 || rapid[2] != 2 {
+		t.Fatalf("rapid shift sizes = %v, want [4 4 2]", rapid)
+	}
+	for i, size := range spaced {
+		if size != 4 {
+			t.Fatalf("spaced shift %d size = %d, want 4 (all %v)", i, size, spaced)
+		}
+	}
+}
+
+type crashOnCancelExecutor struct {
+	fakeExecutor
+	crash bool
+}
+
+func (f *crashOnCancelExecutor) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	if f.crash {
+		panic("crash")
+	}
+	return f.fakeExecutor.CancelOrder(ctx, symbol, orderID)
+}
+
+func TestSpotDualPendingWindowChangeRolledForwardOnRestart(t *testing.T) {
+	st, err := store.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("store.New() error = %v", err)
+	}
+	s, _ := newSpotDualForTest(3, 1, "10")
+	exec := &crashOnCancelExecutor{fakeExecutor: fakeExecutor{balance: core.Balance{Base: decimal.NewFromInt(10), Quote: decimal.NewFromInt(1_000_000)}}}
+	s.executor = exec
+	s.store = st
+	s.SetWindowChangeIntent(true)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	top, ok := findOpenOrder(s, core.Sell, s.maxLevel)
+	if !ok {
+		t.Fatalf("missing top sell")
+	}
+	stale, ok := findOpenOrder(s, core.Buy, s.minLevel)
+	if !ok {
+		t.Fatalf("missing bottom buy")
+	}
+	fromMin, fromMax := s.minLevel, s.maxLevel
+
+	exec.crash = true
+	func() {
+		defer func() { _ = recover() }()
+		_ = s.OnFill(context.Background(), core.Trade{
+			OrderID: top.ID,
+			Symbol:  s.Symbol,
+			Side:    core.Sell,
+			Price:   top.Price,
+			Qty:     top.Qty,
+			Status:  core.OrderFilled,
+		})
+		t.Fatalf("expected crash during shift")
+	}()
+	exchangeOrders := s.snapshotOrders()
+
+	state, ok, err := st.LoadGridState()
+	if err != nil || !ok {
+		t.Fatalf("LoadGridState() = %v, %v", ok, err)
+	}
+	if state.PendingWindow == nil || state.PendingWindow.Kind != "shift_up" {
+		t.Fatalf("persisted pending window = %+v, want shift_up intent", state.PendingWindow)
+	}
+	if state.MinLevel != fromMin || state.MaxLevel != fromMax {
+		t.Fatalf("persisted window = %d..%d, want pre-shift %d..%d", state.MinLevel, state.MaxLevel, fromMin, fromMax)
+	}
+
+	restarted, exec2 := newSpotDualForTest(3, 1, "10")
+	restarted.store = st
+	alerts := &recordingAlerter{}
+	restarted.SetAlerter(alerts)
+	restarted.LoadState(state)
+	if len(alerts.events) == 0 || alerts.events[0] != "pending_window_change_detected" {
+		t.Fatalf("alerts after LoadState = %v, want pending_window_change_detected", alerts.events)
+	}
+	if err := restarted.Reconcile(context.Background(), top.Price, exchangeOrders); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if restarted.minLevel != fromMin+1 || restarted.maxLevel != fromMax+1 {
+		t.Fatalf("window after restart = %d..%d, want %d..%d", restarted.minLevel, restarted.maxLevel, fromMin+1, fromMax+1)
+	}
+	if len(exec2.canceled) != 1 || exec2.canceled[0] != stale.ID {
+		t.Fatalf("canceled = %v, want vacated buy %s", exec2.canceled, stale.ID)
+	}
+	if _, ok := findOpenOrder(restarted, core.Sell, fromMax+1); !ok {
+		t.Fatalf("missing sell at new top level %d", fromMax+1)
+	}
+	state, _, err = st.LoadGridState()
+	if err != nil {
+		t.Fatalf("LoadGridState() error = %v", err)
+	}
+	if state.PendingWindow != nil {
+		t.Fatalf("pending window after reconcile = %+v, want cleared", state.PendingWindow)
+	}
+}
+
+func TestSpotDualInitWithoutBootstrapBuyPlacesSellsFromInventory(t *testing.T) {
+	s, exec := newSpotDualForTest(4, 4, "2.5")
+	s.SetBootstrapMarketBuy(false)
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	sells, buys := 0, 0
+	for _, ord := range exec.placed {
+		if ord.Type == core.Market {
+			t.Fatalf("unexpected market order %+v", ord)
+		}
+		switch ord.Side {
+		case core.Sell:
+			sells++
+		case core.Buy:
+			buys++
+		}
+	}
+	if sells != 2 {
+		t.Fatalf("sell levels placed = %d, want 2 (base 2.5 / qty 1)", sells)
+	}
+	if buys != 4 {
+		t.Fatalf("buy levels placed = %d, want 4", buys)
+	}
+	for _, idx := range []int{1, 2} {
+		if _, ok := findOpenOrder(s, core.Sell, idx); !ok {
+			t.Fatalf("missing sell at level %d", idx)
+		}
+	}
+	if len(alerts.events) == 0 || alerts.events[0] != "bootstrap_sells_limited_by_inventory" {
+		t.Fatalf("alerts = %v, want bootstrap_sells_limited_by_inventory", alerts.events)
+	}
+
+	exec.balance.Base = decimal.NewFromInt(4)
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), s.snapshotOrders()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 4); !ok {
+		t.Fatalf("missing sell at level 4 after inventory grew")
+	}
+	for _, ord := range exec.placed {
+		if ord.Type == core.Market {
+			t.Fatalf("unexpected market order after reconcile %+v", ord)
+		}
+	}
+}
+
+func TestSpotDualOrderTTLRefreshesOneOrderPerStagger(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetOrderTTL(time.Hour, time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id, ord := range s.openOrders {
+		ord.CreatedAt = start
+		s.openOrders[id] = ord
+	}
+	oldest, _ := findOpenOrder(s, core.Buy, -3)
+	oldest.CreatedAt = start.Add(-time.Minute)
+	s.openOrders[oldest.ID] = oldest
+	tracked := len(s.openOrders)
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), start.Add(30*time.Minute)); err != nil {
+		t.Fatalf("OnTick() before ttl error = %v", err)
+	}
+	if len(exec.canceled) != 0 {
+		t.Fatalf("canceled before ttl = %v, want none", exec.canceled)
+	}
+
+	at := start.Add(2 * time.Hour)
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), at); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if len(exec.canceled) != 1 || exec.canceled[0] != oldest.ID {
+		t.Fatalf("canceled = %v, want only oldest %s", exec.canceled, oldest.ID)
+	}
+	refreshed, ok := findOpenOrder(s, core.Buy, -3)
+	if !ok || refreshed.ID == oldest.ID || !refreshed.Price.Equal(oldest.Price) || !refreshed.Qty.Equal(oldest.Qty) {
+		t.Fatalf("refreshed = %+v (ok %v), want new order at level -3 price %s qty %s", refreshed, ok, oldest.Price, oldest.Qty)
+	}
+	if len(s.openOrders) != tracked {
+		t.Fatalf("tracked orders = %d, want %d", len(s.openOrders), tracked)
+	}
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), at.Add(30*time.Second)); err != nil {
+		t.Fatalf("OnTick() within stagger error = %v", err)
+	}
+	if len(exec.canceled) != 1 {
+		t.Fatalf("canceled within stagger = %v, want still 1", exec.canceled)
+	}
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), at.Add(time.Minute)); err != nil {
+		t.Fatalf("OnTick() after stagger error = %v", err)
+	}
+	if len(exec.canceled) != 2 {
+		t.Fatalf("canceled after stagger = %v, want 2", exec.canceled)
+	}
+}
+
+func TestSpotDualOrderTTLRefreshKeepsPostOnlyAndMakerRetry(t *testing.T) {
+	s, fake := newSpotDualForTest(3, 1, "10")
+	s.rules.PriceTick = decimal.RequireFromString("0.1")
+	exec := &wouldMatchExecutor{fakeExecutor: fake, rejectOnce: map[string]bool{}}
+	s.executor = exec
+	s.SetPostOnly(true)
+	s.SetMakerRetry(true)
+	s.SetOrderTTL(time.Hour, time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id, ord := range s.openOrders {
+		ord.CreatedAt = start
+		s.openOrders[id] = ord
+	}
+	oldest, _ := findOpenOrder(s, core.Buy, -1)
+	oldest.CreatedAt = start.Add(-time.Minute)
+	s.openOrders[oldest.ID] = oldest
+	exec.rejectOnce[oldest.Price.String()] = true
+
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	refreshed, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok || refreshed.ID == oldest.ID {
+		t.Fatalf("refreshed = %+v (ok %v), want a new order at level -1", refreshed, ok)
+	}
+	last := fake.placed[len(fake.placed)-1]
+	if !last.PostOnly || !last.Price.Equal(oldest.Price.Sub(s.rules.PriceTick)) {
+		t.Fatalf("replacement = %s post_only=%v, want post-only retried one tick below %s", last.Price, last.PostOnly, oldest.Price)
+	}
+}
+
+func TestSpotDualOrderTTLSurvivesReconcileAndCancelRace(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	s.SetOrderTTL(time.Hour, time.Minute)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := make([]core.Order, 0, len(s.openOrders))
+	for id, ord := range s.openOrders {
+		ord.CreatedAt = start
+		s.openOrders[id] = ord
+		ord.CreatedAt = time.Time{}
+		snapshot = append(snapshot, ord)
+	}
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), snapshot); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	for _, ord := range s.openOrders {
+		if !ord.CreatedAt.Equal(start) {
+			t.Fatalf("order %s CreatedAt = %s after reconcile, want %s", ord.ID, ord.CreatedAt, start)
+		}
+	}
+
+	oldest, _ := findOpenOrder(s, core.Buy, -3)
+	exec.cancelErrByID = map[string]error{oldest.ID: core.ErrOrderNotFound}
+	placed := len(exec.placed)
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("OnTick() with filled order error = %v, want nil", err)
+	}
+	if len(exec.canceled) != 1 || exec.canceled[0] != oldest.ID {
+		t.Fatalf("canceled = %v, want only %s", exec.canceled, oldest.ID)
+	}
+	if len(exec.placed) != placed {
+		t.Fatalf("placed %d orders after a lost cancel race, want none", len(exec.placed)-placed)
+	}
+	if _, ok := s.openOrders[oldest.ID]; !ok {
+		t.Fatalf("order %s dropped from tracking, want it kept for its fill", oldest.ID)
+	}
+}
+
+func TestSpotDualStopCancelsBuysOnRequest(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	if err := s.Stop(context.Background(), "max_drawdown"); !errors.Is(err, ErrStopped) {
+		t.Fatalf("Stop() error = %v, want ErrStopped", err)
+	}
+	if !s.stopped || s.hasOpenBuyOrders() {
+		t.Fatalf("stopped = %v open buys = %v, want stopped without buys", s.stopped, s.hasOpenBuyOrders())
+	}
+	if len(exec.canceled) == 0 {
+		t.Fatalf("no buys canceled")
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "strategy_stopped" || alerts.fields[0]["reason"] != "max_drawdown" {
+		t.Fatalf("alerts = %v %v, want strategy_stopped with reason max_drawdown", alerts.events, alerts.fields)
+	}
+}
+
+func TestSpotDualSetRulesHoldsTickChangeWhileOrdersRest(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.rules = core.Rules{PriceTick: decimal.RequireFromString("0.01"), QtyStep: decimal.RequireFromString("0.001")}
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	refreshed := core.Rules{PriceTick: decimal.RequireFromString("0.1"), QtyStep: decimal.RequireFromString("0.01")}
+
+	for i := 0; i < 2; i++ {
+		s.SetRules(refreshed)
+	}
+	if got := s.Rules(); !got.PriceTick.Equal(decimal.RequireFromString("0.01")) || !got.QtyStep.Equal(refreshed.QtyStep) {
+		t.Fatalf("rules = %+v, want tick 0.01 kept and qty step 0.01 applied", got)
+	}
+	if len(alerts.events) != 1 || alerts.events[0] != "price_tick_change_refused" || alerts.fields[0]["new_price_tick"] != "0.1" {
+		t.Fatalf("alerts = %v %v, want one price_tick_change_refused", alerts.events, alerts.fields)
+	}
+	tracked := len(s.openOrders)
+	if err := s.Reconcile(context.Background(), decimal.NewFromInt(100), s.snapshotOrders()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(s.openOrders) != tracked {
+		t.Fatalf("tracked orders after reconcile = %d, want %d", len(s.openOrders), tracked)
+	}
+
+	if _, err := s.CancelAllOrders(context.Background()); err != nil {
+		t.Fatalf("CancelAllOrders() error = %v", err)
+	}
+	s.SetRules(refreshed)
+	if got := s.Rules(); !got.PriceTick.Equal(refreshed.PriceTick) {
+		t.Fatalf("price tick = %s once the book is empty, want 0.1", got.PriceTick)
+	}
+}
+
+func TestSpotDualSweepDustOnStop(t *testing.T) {
+	cases := []struct {
+		name      string
+		extraBase string
+		wantSwept bool
+		wantAlert string
+	}{
+		{name: "sellable", extraBase: "0.5", wantSwept: true, wantAlert: "dust_swept"},
+		{name: "below_min_notional", extraBase: "0.05", wantSwept: false, wantAlert: "dust_left"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, exec := newSpotDualForTest(3, 2, "10")
+			s.rules = core.Rules{MinQty: decimal.RequireFromString("0.001"), QtyStep: decimal.RequireFromString("0.001"), MinNotional: decimal.NewFromInt(10)}
+			s.StopPrice = decimal.NewFromInt(130)
+			s.SetSweepDustOnStop(true)
+			alerts := &recordingAlerter{}
+			s.SetAlerter(alerts)
+			if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			exec.balance.Base = s.lockedSellBase().Add(decimal.RequireFromString(tc.extraBase))
+			placedBefore := len(exec.placed)
+
+			_ = s.OnTick(context.Background(), decimal.NewFromInt(140), time.Now().UTC())
+			if !s.stopped {
+				t.Fatalf("strategy not stopped")
+			}
+
+			var sweeps []core.Order
+			for _, ord := range exec.placed[placedBefore:] {
+				if ord.Type == core.Market && ord.Side == core.Sell {
+					sweeps = append(sweeps, ord)
+				}
+			}
+			if tc.wantSwept {
+				if len(sweeps) != 1 || !sweeps[0].Qty.Equal(decimal.RequireFromString(tc.extraBase)) {
+					t.Fatalf("sweep orders = %+v, want one market sell of %s", sweeps, tc.extraBase)
+				}
+			} else if len(sweeps) != 0 {
+				t.Fatalf("sweep orders = %+v, want none", sweeps)
+			}
+			found := false
+			for _, ev := range alerts.events {
+				if ev == tc.wantAlert {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("alerts = %v, want %s", alerts.events, tc.wantAlert)
+			}
+		})
+	}
+}
+
+func TestSpotDualOnStopPolicy(t *testing.T) {
+	cases := []struct {
+		name       string
+		marketSell bool
+	}{
+		{name: "hold"},
+		{name: "market_sell", marketSell: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, exec := newSpotDualForTest(3, 2, "10")
+			s.rules = core.Rules{PriceTick: decimal.RequireFromString("0.01"), QtyStep: decimal.RequireFromString("0.001")}
+			s.StopPrice = decimal.NewFromInt(130)
+			s.SetSellOnStop(tc.marketSell, decimal.NewFromInt(1))
+			alerts := &recordingAlerter{}
+			s.SetAlerter(alerts)
+			if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			// 0.5 base held besides the grid is not the grid's to sell.
+			gridBase := s.lockedSellBase()
+			exec.balance.Base = gridBase.Add(decimal.RequireFromString("0.5"))
+			placedBefore := len(exec.placed)
+
+			_ = s.OnTick(context.Background(), decimal.NewFromInt(140), time.Now().UTC())
+			if !s.stopped {
+				t.Fatalf("strategy not stopped")
+			}
+
+			var sells []core.Order
+			for _, ord := range exec.placed[placedBefore:] {
+				if ord.Side == core.Sell {
+					sells = append(sells, ord)
+				}
+			}
+			var stopFields map[string]string
+			for i, ev := range alerts.events {
+				if ev == "strategy_stop_price_triggered" {
+					stopFields = alerts.fields[i]
+				}
+			}
+			if stopFields == nil {
+				t.Fatalf("alerts = %v, want strategy_stop_price_triggered", alerts.events)
+			}
+			if stopFields["on_stop"] != tc.name {
+				t.Fatalf("stop on_stop = %q, want %q", stopFields["on_stop"], tc.name)
+			}
+			if !tc.marketSell {
+				if len(sells) != 0 {
+					t.Fatalf("hold placed sells %+v, want none", sells)
+				}
+				if s.lockedSellBase().Cmp(decimal.Zero) <= 0 {
+					t.Fatalf("hold cancelled resting sells")
+				}
+				return
+			}
+			if len(sells) != 1 {
+				t.Fatalf("liquidation orders = %+v, want one", sells)
+			}
+			liq := sells[0]
+			// Last price 140 with 1% slippage protection -> limit 138.6.
+			if liq.Type != core.Limit || !liq.Qty.Equal(gridBase) || !liq.Price.Equal(decimal.RequireFromString("138.6")) {
+				t.Fatalf("liquidation = %s %s @ %s, want LIMIT %s @ 138.6", liq.Type, liq.Qty, liq.Price, gridBase)
+			}
+			if s.lockedSellBase().Cmp(decimal.Zero) != 0 {
+				t.Fatalf("resting sells left after liquidation: %s base", s.lockedSellBase())
+			}
+			if stopFields["liquidated_qty"] != gridBase.String() || stopFields["liquidation"] != "submitted" {
+				t.Fatalf("stop summary = %v, want liquidated_qty %s", stopFields, gridBase)
+			}
+		})
+	}
+}
+
+type buyBudgetExecutor struct {
+	fakeExecutor
+	buysLeft int
+}
+
+func (f *buyBudgetExecutor) PlaceOrder(ctx context.Context, order core.Order) (core.Order, error) {
+	if order.Type == core.Limit && order.Side == core.Buy {
+		if f.buysLeft <= 0 {
+			return core.Order{}, fmt.Errorf("%w: quote balance", core.ErrInsufficientBalance)
+		}
+		f.buysLeft--
+	}
+	return f.fakeExecutor.PlaceOrder(ctx, order)
+}
+
+func TestSpotDualBootstrapIncompleteAlertsAndRetries(t *testing.T) {
+	exec := &buyBudgetExecutor{
+		fakeExecutor: fakeExecutor{balance: core.Balance{Base: decimal.NewFromInt(10), Quote: decimal.NewFromInt(1000)}},
+		buysLeft:     2,
+	}
+	s := NewSpotDual("BTCUSDT", decimal.Zero, decimal.RequireFromString("1.1"), 4, 2, decimal.NewFromInt(1), 1, core.Rules{}, nil, exec)
+	s.SetBootstrapRetry(time.Minute)
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	var fields map[string]string
+	for i, ev := range alerts.events {
+		if ev == "bootstrap_incomplete" {
+			fields = alerts.fields[i]
+		}
+	}
+	if fields == nil {
+		t.Fatalf("alerts = %v, want bootstrap_incomplete", alerts.events)
+	}
+	want := map[string]string{
+		"expected_buy_levels":  "4",
+		"placed_buy_levels":    "2",
+		"missing_buy_levels":   "2",
+		"expected_sell_levels": "2",
+		"placed_sell_levels":   "2",
+		"missing_sell_levels":  "0",
+		"next_action":          "retry_gap_fill",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Fatalf("bootstrap_incomplete[%s] = %q, want %q (%v)", k, fields[k], v, fields)
+		}
+	}
+
+	// Only one more buy fits on the first retry: the grid keeps running
+	// without claiming completion.
+	exec.buysLeft = 1
+	now := time.Now().UTC()
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), now); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if sells, buys := s.placedLevels(); sells != 2 || buys != 3 {
+		t.Fatalf("placed levels after partial retry = %d sells / %d buys, want 2 / 3", sells, buys)
+	}
+	for _, ev := range alerts.events {
+		if ev == "bootstrap_completed" {
+			t.Fatalf("alerts = %v, want no bootstrap_completed while a buy level is missing", alerts.events)
+		}
+	}
+	if !s.bootstrapIncomplete {
+		t.Fatal("bootstrapIncomplete = false after a partial retry")
+	}
+
+	// Quote frees up; the next tick past the retry interval fills the gaps.
+	exec.buysLeft = 10
+	now = now.Add(time.Minute)
+	if err := s.OnTick(context.Background(), decimal.NewFromInt(100), now); err != nil {
+		t.Fatalf("OnTick() error = %v", err)
+	}
+	if sells, buys := s.placedLevels(); sells != 2 || buys != 4 {
+		t.Fatalf("placed levels after retry = %d sells / %d buys, want 2 / 4", sells, buys)
+	}
+	if s.bootstrapIncomplete || !s.initialized {
+		t.Fatalf("bootstrapIncomplete = %v initialized = %v, want false / true", s.bootstrapIncomplete, s.initialized)
+	}
+	if alerts.events[len(alerts.events)-1] != "bootstrap_completed" {
+		t.Fatalf("last alert = %s, want bootstrap_completed", alerts.events[len(alerts.events)-1])
+	}
+	if len(exec.canceled) != 0 {
+		t.Fatalf("canceled = %v, want the retry to leave tracked orders alone", exec.canceled)
+	}
+}
+
+// wouldMatchExecutor rejects the first order at each listed price as a
+// maker-only order that would have crossed.
+type wouldMatchExecutor struct {
+	*fakeExecutor
+	rejectOnce map[string]bool
+}
+
+func (w *wouldMatchExecutor) PlaceOrder(ctx context.Context, order core.Order) (core.Order, error) {
+	if w.rejectOnce[order.Price.String()] {
+		delete(w.rejectOnce, order.Price.String())
+		return core.Order{}, fmt.Errorf("new order rejected: %w", core.ErrWouldMatch)
+	}
+	return w.fakeExecutor.PlaceOrder(ctx, order)
+}
+
+func TestSpotDualMakerRetryPlacesOneTickMorePassive(t *testing.T) {
+	s, fake := newSpotDualForTest(3, 1, "10")
+	s.rules.PriceTick = decimal.RequireFromString("0.1")
+	exec := &wouldMatchExecutor{fakeExecutor: fake, rejectOnce: map[string]bool{"110": true, "90.9": true}}
+	s.executor = exec
+	s.SetMakerRetry(true)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	sell, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok || !sell.Price.Equal(decimal.RequireFromString("110.1")) {
+		t.Fatalf("sell at level 1 = %s, %v; want the retried 110.1", sell.Price, ok)
+	}
+	buy, ok := findOpenOrder(s, core.Buy, -1)
+	if !ok || !buy.Price.Equal(decimal.RequireFromString("90.8")) {
+		t.Fatalf("buy at level -1 = %s, %v; want the retried 90.8", buy.Price, ok)
+	}
+	for _, ord := range []core.Order{sell, buy} {
+		if idx, ok := s.levelForOrder(ord); !ok || idx != ord.GridIndex {
+			t.Fatalf("levelForOrder(%s %s) = %d, %v; want level %d", ord.Side, ord.Price, idx, ok, ord.GridIndex)
+		}
+	}
+
+	off, offFake := newSpotDualForTest(3, 1, "10")
+	off.rules.PriceTick = decimal.RequireFromString("0.1")
+	off.executor = &wouldMatchExecutor{fakeExecutor: offFake, rejectOnce: map[string]bool{"110": true}}
+	if err := off.Init(context.Background(), decimal.NewFromInt(100)); !errors.Is(err, core.ErrWouldMatch) {
+		t.Fatalf("Init() without maker_retry error = %v, want ErrWouldMatch", err)
+	}
+}
+
+func TestSpotDualPostOnlySkipsOrdersThatWouldCross(t *testing.T) {
+	s, fake := newSpotDualForTest(3, 1, "10")
+	s.executor = &wouldMatchExecutor{fakeExecutor: fake, rejectOnce: map[string]bool{"110": true}}
+	alerts := &recordingAlerter{}
+	s.SetAlerter(alerts)
+	s.SetPostOnly(true)
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v, want the crossing sell skipped", err)
+	}
+	if _, ok := findOpenOrder(s, core.Sell, 1); ok {
+		t.Fatalf("sell at level 1 placed, want it skipped")
+	}
+	// bootstrap_incomplete follows for the missing level.
+	if len(alerts.events) == 0 || alerts.events[0] != "place_order_skipped_would_match" || alerts.fields[0]["level"] != "1" {
+		t.Fatalf("alerts = %v %v, want place_order_skipped_would_match for level 1 first", alerts.events, alerts.fields)
+	}
+	if len(s.openOrders) == 0 {
+		t.Fatalf("no orders placed")
+	}
+	for _, ord := range s.openOrders {
+		if !ord.PostOnly {
+			t.Fatalf("%s at level %d PostOnly = false, want true", ord.Side, ord.GridIndex)
+		}
+	}
+}
+
+func TestSpotDualBuyQtyGrowthScalesDeeperBuyLevels(t *testing.T) {
+	s, _ := newSpotDualForTest(3, 1, "10")
+	s.rules.QtyStep = decimal.RequireFromString("0.1")
+	s.SetRatioQtyMultiple(decimal.NewFromInt(1))
+	s.SetBuyQtyGrowth(decimal.RequireFromString("0.5"))
+	if !s.buyQtyGrowth.IsZero() {
+		t.Fatalf("buyQtyGrowth = %s after a factor below 1, want it ignored", s.buyQtyGrowth)
+	}
+	s.SetBuyQtyGrowth(decimal.RequireFromString("1.5"))
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := s.extendDown(context.Background(), time.Now()); err != nil {
+		t.Fatalf("extendDown() error = %v", err)
+	}
+	// 1.5^(n-1) rounded down to the 0.1 qty step; extendDown keeps growing
+	// past the deepest initial level.
+	want := map[int]string{-1: "1", -2: "1.5", -3: "2.2", -4: "3.3", -5: "5", -6: "7.5"}
+	for idx, qty := range want {
+		buy, ok := findOpenOrder(s, core.Buy, idx)
+		if !ok || !buy.Qty.Equal(decimal.RequireFromString(qty)) {
+			t.Fatalf("buy at level %d qty = %s, %v; want %s", idx, buy.Qty, ok, qty)
+		}
+	}
+	sell, ok := findOpenOrder(s, core.Sell, 1)
+	if !ok || !sell.Qty.Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("sell at level 1 qty = %s, %v; want the unscaled 1", sell.Qty, ok)
+	}
+}
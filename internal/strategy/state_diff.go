@@ -0,0 +1,159 @@
+package strategy
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"grid-trading/internal/core"
+)
+
+// ExchangeOrder is an exchange open order mapped to the loaded grid.
+type ExchangeOrder struct {
+	core.Order
+	OnGrid bool
+}
+
+type OrderMismatch struct {
+	State    core.Order
+	Exchange ExchangeOrder
+	Fields   []string
+}
+
+// StateDiff classifies persisted open orders against the exchange's open
+// orders, matched by order id the same way reconcile does.
+type StateDiff struct {
+	OnlyInState    []core.Order
+	OnlyOnExchange []ExchangeOrder
+	Mismatched     []OrderMismatch
+	Matched        int
+}
+
+// DiffState compares persisted open orders with the exchange's without
+// touching tracked state or the exchange. Exchange orders are mapped to grid
+// levels with the loaded anchor and window.
+func (s *SpotDual) DiffState(persisted, open []core.Order) StateDiff {
+	var diff StateDiff
+	mapped := make([]ExchangeOrder, 0, len(open))
+	exchangeByID := make(map[string]ExchangeOrder, len(open))
+	for _, ord := range open {
+		ex := ExchangeOrder{Order: ord}
+		ex.GridIndex, ex.OnGrid = s.levelForOrder(ord)
+		mapped = append(mapped, ex)
+		if ord.ID != "" {
+			exchangeByID[ord.ID] = ex
+		}
+	}
+	stateIDs := make(map[string]struct{}, len(persisted))
+	for _, ord := range persisted {
+		if ord.ID == "" {
+			continue
+		}
+		stateIDs[ord.ID] = struct{}{}
+		ex, ok := exchangeByID[ord.ID]
+		if !ok {
+			diff.OnlyInState = append(diff.OnlyInState, ord)
+			continue
+		}
+		fields := mismatchedFields(ord, ex)
+		if len(fields) == 0 {
+			diff.Matched++
+			continue
+		}
+		diff.Mismatched = append(diff.Mismatched, OrderMismatch{State: ord, Exchange: ex, Fields: fields})
+	}
+	for _, ex := range mapped {
+		if _, ok := stateIDs[ex.ID]; ok && ex.ID != "" {
+			continue
+		}
+		diff.OnlyOnExchange = append(diff.OnlyOnExchange, ex)
+	}
+	sort.SliceStable(diff.OnlyInState, func(i, j int) bool {
+		return diff.OnlyInState[i].Price.Cmp(diff.OnlyInState[j].Price) > 0
+	})
+	sort.SliceStable(diff.OnlyOnExchange, func(i, j int) bool {
+		return diff.OnlyOnExchange[i].Price.Cmp(diff.OnlyOnExchange[j].Price) > 0
+	})
+	sort.SliceStable(diff.Mismatched, func(i, j int) bool {
+		return diff.Mismatched[i].State.Price.Cmp(diff.Mismatched[j].State.Price) > 0
+	})
+	return diff
+}
+
+func mismatchedFields(state core.Order, exchange ExchangeOrder) []string {
+	var fields []string
+	if state.Side != exchange.Side {
+		fields = append(fields, "side")
+	}
+	if !state.Price.Equal(exchange.Price) {
+		fields = append(fields, "price")
+	}
+	if !state.Qty.Equal(exchange.Qty) {
+		fields = append(fields, "qty")
+	}
+	if !exchange.OnGrid || state.GridIndex != exchange.GridIndex {
+		fields = append(fields, "level")
+	}
+	return fields
+}
+
+func (d StateDiff) WriteReport(w io.Writer) error {
+	for _, ord := range d.OnlyInState {
+		if _, err := fmt.Fprintf(w, "only_in_state %s\n", diffOrderFields(ord, strconv.Itoa(ord.GridIndex))); err != nil {
+			return err
+		}
+	}
+	for _, ex := range d.OnlyOnExchange {
+		if _, err := fmt.Fprintf(w, "only_on_exchange %s\n", diffOrderFields(ex.Order, ex.level())); err != nil {
+			return err
+		}
+	}
+	for _, m := range d.Mismatched {
+		if _, err := fmt.Fprintf(
+			w,
+			"mismatch id=%s fields=%s state_side=%s exchange_side=%s state_price=%s exchange_price=%s state_qty=%s exchange_qty=%s state_level=%s exchange_level=%s\n",
+			m.State.ID,
+			strings.Join(m.Fields, ","),
+			m.State.Side,
+			m.Exchange.Side,
+			m.State.Price.String(),
+			m.Exchange.Price.String(),
+			m.State.Qty.String(),
+			m.Exchange.Qty.String(),
+			strconv.Itoa(m.State.GridIndex),
+			m.Exchange.level(),
+		); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(
+		w,
+		"state_diff only_in_state=%d only_on_exchange=%d mismatched=%d matched=%d\n",
+		len(d.OnlyInState),
+		len(d.OnlyOnExchange),
+		len(d.Mismatched),
+		d.Matched,
+	)
+	return err
+}
+
+func diffOrderFields(ord core.Order, level string) string {
+	return fmt.Sprintf(
+		"id=%s client_id=%s side=%s level=%s price=%s qty=%s",
+		ord.ID,
+		ord.ClientID,
+		ord.Side,
+		level,
+		ord.Price.String(),
+		ord.Qty.String(),
+	)
+}
+
+func (o ExchangeOrder) level() string {
+	if !o.OnGrid {
+		return "off_grid"
+	}
+	return strconv.Itoa(o.GridIndex)
+}
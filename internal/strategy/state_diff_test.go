@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/core"
+)
+
+func TestSpotDualDiffStateClassifiesOrders(t *testing.T) {
+	s, exec := newSpotDualForTest(3, 1, "10")
+	if err := s.Init(context.Background(), decimal.NewFromInt(100)); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	persisted := make([]core.Order, 0, len(s.openOrders))
+	for _, ord := range s.openOrders {
+		persisted = append(persisted, ord)
+	}
+	gone, ok := findOpenOrder(s, core.Buy, -2)
+	if !ok {
+		t.Fatalf("missing buy order at level -2")
+	}
+	open := make([]core.Order, 0, len(persisted))
+	for _, ord := range persisted {
+		if ord.ID != gone.ID {
+			open = append(open, ord)
+		}
+	}
+	open = append(open, core.Order{ID: "foreign", Side: core.Sell, Price: decimal.RequireFromString("123.45"), Qty: decimal.NewFromInt(1)})
+	placedBefore, canceledBefore := len(exec.placed), len(exec.canceled)
+	tracked := len(s.openOrders)
+
+	diff := s.DiffState(persisted, open)
+
+	if len(diff.OnlyInState) != 1 || diff.OnlyInState[0].ID != gone.ID {
+		t.Fatalf("only in state = %+v, want order %s", diff.OnlyInState, gone.ID)
+	}
+	if len(diff.OnlyOnExchange) != 1 || diff.OnlyOnExchange[0].ID != "foreign" || diff.OnlyOnExchange[0].OnGrid {
+		t.Fatalf("only on exchange = %+v, want off-grid order foreign", diff.OnlyOnExchange)
+	}
+	if len(diff.Mismatched) != 0 || diff.Matched != len(persisted)-1 {
+		t.Fatalf("mismatched = %d matched = %d, want 0 and %d", len(diff.Mismatched), diff.Matched, len(persisted)-1)
+	}
+	if len(exec.placed) != placedBefore || len(exec.canceled) != canceledBefore || len(s.openOrders) != tracked {
+		t.Fatalf("DiffState() must not place, cancel or untrack orders")
+	}
+
+	var buf bytes.Buffer
+	if err := diff.WriteReport(&buf); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "only_in_state id="+gone.ID+" ") || !strings.Contains(out, "level=off_grid") ||
+		!strings.Contains(out, "state_diff only_in_state=1 only_on_exchange=1 mismatched=0") {
+		t.Fatalf("unexpected diff report:\n%s", out)
+	}
+}
@@ -17,6 +17,10 @@ type Strategy interface {
 
 var ErrStopped = errors.New("strategy stopped")
 
+// ErrFatal marks errors the runner must not retry, such as a failure to
+// persist grid state.
+var ErrFatal = errors.New("strategy fatal error")
+
 type Resetter interface {
 	Reset()
 }
@@ -28,3 +32,56 @@ type Reconciler interface {
 type TickAware interface {
 	OnTick(ctx context.Context, price decimal.Decimal, at time.Time) error
 }
+
+// Bootstrapper strategies report when they last placed a fresh grid.
+type Bootstrapper interface {
+	BootstrappedAt() time.Time
+}
+
+// GridStatus is a point-in-time view of a grid for metrics and /status.
+type GridStatus struct {
+	OpenOrders int
+	MinLevel   int
+	MaxLevel   int
+	Anchor     decimal.Decimal
+	Ratio      decimal.Decimal
+	SellRatio  decimal.Decimal
+	LastPrice  decimal.Decimal
+	Paused     bool
+	Stopped    bool
+}
+
+// GridStatusReporter strategies expose their grid for metrics and /status.
+type GridStatusReporter interface {
+	GridStatus() GridStatus
+}
+
+// OrderTracker strategies look up the open order a fill belongs to, e.g. for
+// its grid level and origin.
+type OrderTracker interface {
+	TrackedOrder(orderID string) (core.Order, bool)
+}
+
+// BalanceWatcher strategies pause placements on a low account balance; the
+// live runner passes the free balance on every heartbeat.
+type BalanceWatcher interface {
+	ObserveBalance(ctx context.Context, bal core.Balance) error
+}
+
+// OrderCanceler strategies cancel all their open orders on request and
+// report how many were canceled.
+type OrderCanceler interface {
+	CancelAllOrders(ctx context.Context) (int, error)
+}
+
+// Stopper strategies halt on the runner's request as they would at their
+// stop price: resting buys are canceled and nothing new is placed.
+type Stopper interface {
+	Stop(ctx context.Context, reason string) error
+}
+
+// RulesAware strategies accept refreshed exchange filters between events.
+type RulesAware interface {
+	Rules() core.Rules
+	SetRules(rules core.Rules)
+}
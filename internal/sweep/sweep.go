@@ -0,0 +1,133 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+	"grid-trading/internal/engine"
+)
+
+// Grid lists the values to try for each swept parameter. An empty list keeps
+// the base config's value.
+type Grid struct {
+	Ratios      []decimal.Decimal
+	Levels      []int
+	ShiftLevels []int
+}
+
+// Params is one combination of swept values.
+type Params struct {
+	Ratio       decimal.Decimal
+	Levels      int
+	ShiftLevels int
+}
+
+func (p Params) String() string {
+	return fmt.Sprintf("ratio=%s levels=%d shift_levels=%d", p.Ratio, p.Levels, p.ShiftLevels)
+}
+
+// Result is the outcome of one combination. Err is set when the combination
+// is invalid or its backtest failed; Result is then zero.
+type Result struct {
+	Params Params
+	Result engine.BacktestResult
+	Err    error
+}
+
+// Combinations expands g over base, ratio first, then levels, then
+// shift_levels.
+func (g Grid) Combinations(base config.Config) []Params {
+	ratios := g.Ratios
+	if len(ratios) == 0 {
+		ratios = []decimal.Decimal{base.Grid.Ratio.Decimal}
+	}
+	levels := g.Levels
+	if len(levels) == 0 {
+		levels = []int{base.Grid.Levels}
+	}
+	shifts := g.ShiftLevels
+	if len(shifts) == 0 {
+		shifts = []int{base.Grid.ShiftLevels}
+	}
+	combos := make([]Params, 0, len(ratios)*len(levels)*len(shifts))
+	for _, ratio := range ratios {
+		for _, lv := range levels {
+			for _, shift := range shifts {
+				combos = append(combos, Params{Ratio: ratio, Levels: lv, ShiftLevels: shift})
+			}
+		}
+	}
+	return combos
+}
+
+// Run backtests every combination of g over dataPath, at most GOMAXPROCS at a
+// time. Each run opens its own feed, SimExchange and SpotDual so nothing is
+// shared between runs. Results come back sorted by TotalReturnPct, best
+// first, with failed combinations last in sweep order. Only a cancelled ctx
+// aborts the sweep.
+func Run(ctx context.Context, base config.Config, dataPath string, g Grid) ([]Result, error) {
+	combos := g.Combinations(base)
+	results := make([]Result, len(combos))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, p := range combos {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(i int, p Params) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := runOne(ctx, apply(base, p), dataPath)
+			results[i] = Result{Params: p, Result: res, Err: err}
+		}(i, p)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Err != nil {
+			return false
+		}
+		return a.Result.TotalReturnPct.Cmp(b.Result.TotalReturnPct) > 0
+	})
+	return results, nil
+}
+
+// apply returns base with p swept in. A sell_ratio that only defaulted to
+// ratio follows the swept ratio.
+func apply(base config.Config, p Params) config.Config {
+	cfg := base
+	if cfg.Grid.SellRatio.Equal(cfg.Grid.Ratio.Decimal) {
+		cfg.Grid.SellRatio = config.Decimal{Decimal: p.Ratio}
+	}
+	cfg.Grid.Ratio = config.Decimal{Decimal: p.Ratio}
+	cfg.Grid.Levels = p.Levels
+	cfg.Grid.ShiftLevels = p.ShiftLevels
+	return cfg
+}
+
+func runOne(ctx context.Context, cfg config.Config, dataPath string) (engine.BacktestResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return engine.BacktestResult{}, err
+	}
+	runner, _, err := engine.NewBacktest(cfg, engine.ConfigBacktestLeg(cfg, dataPath))
+	if err != nil {
+		return engine.BacktestResult{}, err
+	}
+	return runner.Run(ctx)
+}
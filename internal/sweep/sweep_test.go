@@ -0,0 +1,108 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"grid-trading/internal/config"
+)
+
+func writeSweepBase(t *testing.T, dir, dataPath string) config.Config {
+	t.Helper()
+	path := filepath.Join(dir, "base.yaml")
+	body := fmt.Sprintf(`
+mode: backtest
+symbol: BTCUSDT
+
+grid:
+  ratio: "1.01"
+  levels: 4
+  shift_levels: 2
+  qty: "0.01"
+
+backtest:
+  data_path: %s
+  initial_base: "1"
+  initial_quote: "1000"
+  fees:
+    maker_rate: "0"
+    taker_rate: "0"
+`, dataPath)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	return cfg
+}
+
+func TestRunSweepsEveryCombinationAndSortsByTotalReturn(t *testing.T) {
+	dir := t.TempDir()
+	dataPath := filepath.Join(dir, "ticks.jsonl")
+	var lines []string
+	for i, price := range []string{"100", "98.5", "100", "98.5", "100", "98.5", "100"} {
+		lines = append(lines, fmt.Sprintf(`{"time":%d,"close":%s}`, 1700000000+i*60, price))
+	}
+	if err := os.WriteFile(dataPath, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	base := writeSweepBase(t, dir, dataPath)
+
+	grid := Grid{
+		Ratios: []decimal.Decimal{decimal.RequireFromString("1.01"), decimal.RequireFromString("1.05")},
+		Levels: []int{2, 4},
+		// 5 > levels makes those combinations invalid.
+		ShiftLevels: []int{1, 5},
+	}
+	results, err := Run(context.Background(), base, dataPath, grid)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 8 {
+		t.Fatalf("results = %d, want 8", len(results))
+	}
+	seen := make(map[string]bool)
+	var ok, failed int
+	for i, r := range results {
+		seen[r.Params.String()] = true
+		if r.Err != nil {
+			failed++
+			if r.Params.ShiftLevels != 5 {
+				t.Fatalf("%s error = %v, want success", r.Params, r.Err)
+			}
+			continue
+		}
+		ok++
+		if failed > 0 {
+			t.Fatalf("successful run %s sorted after a failed one", r.Params)
+		}
+		if i > 0 && r.Result.TotalReturnPct.Cmp(results[i-1].Result.TotalReturnPct) > 0 {
+			t.Fatalf("results not sorted by total_return_pct at %d: %s > %s", i, r.Result.TotalReturnPct, results[i-1].Result.TotalReturnPct)
+		}
+	}
+	if len(seen) != 8 || ok != 4 || failed != 4 {
+		t.Fatalf("distinct=%d ok=%d failed=%d, want 8/4/4", len(seen), ok, failed)
+	}
+	// Only the 1% grid crosses the 1.5% swings, and each run starts from
+	// its own balances.
+	best := results[0]
+	if !best.Params.Ratio.Equal(decimal.RequireFromString("1.01")) || best.Result.Trades == 0 {
+		t.Fatalf("best = %s trades=%d, want a 1.01 run with trades", best.Params, best.Result.Trades)
+	}
+	for _, r := range results[:ok] {
+		if r.Params.Ratio.Equal(decimal.RequireFromString("1.05")) && r.Result.Trades != 0 {
+			t.Fatalf("%s trades = %d, want 0", r.Params, r.Result.Trades)
+		}
+		if !r.Result.StartEquityQuote.Equal(results[0].Result.StartEquityQuote) {
+			t.Fatalf("%s start equity = %s, want %s", r.Params, r.Result.StartEquityQuote, results[0].Result.StartEquityQuote)
+		}
+	}
+}